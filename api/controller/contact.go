@@ -35,11 +35,13 @@ func GetContactById(database moira.Database, contactID string) (*dto.Contact, *a
 	}
 
 	contactToReturn := &dto.Contact{
-		ID:     contact.ID,
-		User:   contact.User,
-		TeamID: contact.Team,
-		Type:   contact.Type,
-		Value:  contact.Value,
+		ID:       contact.ID,
+		User:     contact.User,
+		TeamID:   contact.Team,
+		Type:     contact.Type,
+		Value:    contact.Value,
+		Template: contact.Template,
+		DedupBy:  contact.DedupBy,
 	}
 
 	return contactToReturn, nil
@@ -51,11 +53,13 @@ func CreateContact(dataBase moira.Database, contact *dto.Contact, userLogin, tea
 		return api.ErrorInternalServer(fmt.Errorf("CreateContact: cannot create contact when both userLogin and teamID specified"))
 	}
 	contactData := moira.ContactData{
-		ID:    contact.ID,
-		User:  userLogin,
-		Team:  teamID,
-		Type:  contact.Type,
-		Value: contact.Value,
+		ID:       contact.ID,
+		User:     userLogin,
+		Team:     teamID,
+		Type:     contact.Type,
+		Value:    contact.Value,
+		Template: contact.Template,
+		DedupBy:  contact.DedupBy,
 	}
 	if contactData.ID == "" {
 		uuid4, err := uuid.NewV4()
@@ -86,6 +90,8 @@ func CreateContact(dataBase moira.Database, contact *dto.Contact, userLogin, tea
 func UpdateContact(dataBase moira.Database, contactDTO dto.Contact, contactData moira.ContactData) (dto.Contact, *api.ErrorResponse) {
 	contactData.Type = contactDTO.Type
 	contactData.Value = contactDTO.Value
+	contactData.Template = contactDTO.Template
+	contactData.DedupBy = contactDTO.DedupBy
 	if err := dataBase.SaveContact(&contactData); err != nil {
 		return contactDTO, api.ErrorInternalServer(err)
 	}
@@ -0,0 +1,245 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gofrs/uuid"
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/api/dto"
+	"github.com/moira-alert/moira/database"
+)
+
+// externalTriggerNamespace is used to generate stable external trigger IDs from alert names.
+var externalTriggerNamespace = uuid.Must(uuid.FromString("7d3e6c1a-61a4-4f0e-9a59-ad2f3f8b1c52"))
+
+// GetExternalTriggerID returns ID of external trigger which alerts with given name are mapped onto.
+func GetExternalTriggerID(name string) string {
+	return uuid.NewV5(externalTriggerNamespace, name).String()
+}
+
+// externalTriggerAlerts are alerts mapped onto one external trigger.
+type externalTriggerAlerts struct {
+	triggerID string
+	name      string
+	desc      string
+	tags      []string
+	alerts    []dto.ExternalAlert
+}
+
+// PushExternalAlerts maps alerts onto external triggers and pushes notification events for alerts which changed state.
+// All alerts are validated before anything is saved, so invalid request does not change any trigger.
+func PushExternalAlerts(dataBase moira.Database, alerts []dto.ExternalAlert, userLogin string, now int64) (*dto.ExternalAlertsResponse, *api.ErrorResponse) {
+	triggersAlerts, errorResponse := groupExternalAlerts(dataBase, alerts)
+	if errorResponse != nil {
+		return nil, errorResponse
+	}
+
+	response := &dto.ExternalAlertsResponse{
+		TriggerIDs: make([]string, 0, len(triggersAlerts)),
+	}
+	for _, triggerAlerts := range triggersAlerts {
+		events, errorResponse := pushExternalTriggerAlerts(dataBase, triggerAlerts, userLogin, now)
+		if errorResponse != nil {
+			return nil, errorResponse
+		}
+		response.TriggerIDs = append(response.TriggerIDs, triggerAlerts.triggerID)
+		response.Events += events
+	}
+	return response, nil
+}
+
+// groupExternalAlerts groups alerts by name and checks that every group has tags
+// and is not mapped onto trigger, which is not an external one.
+func groupExternalAlerts(dataBase moira.Database, alerts []dto.ExternalAlert) ([]*externalTriggerAlerts, *api.ErrorResponse) {
+	triggersAlerts := make([]*externalTriggerAlerts, 0)
+	triggerAlertsByName := make(map[string]*externalTriggerAlerts)
+	for _, alert := range alerts {
+		triggerAlerts, ok := triggerAlertsByName[alert.Name]
+		if !ok {
+			triggerAlerts = &externalTriggerAlerts{
+				triggerID: GetExternalTriggerID(alert.Name),
+				name:      alert.Name,
+				tags:      make([]string, 0),
+			}
+			triggerAlertsByName[alert.Name] = triggerAlerts
+			triggersAlerts = append(triggersAlerts, triggerAlerts)
+		}
+		triggerAlerts.alerts = append(triggerAlerts.alerts, alert)
+		triggerAlerts.tags = mergeTags(triggerAlerts.tags, alert.Tags)
+		if alert.Desc != "" {
+			triggerAlerts.desc = alert.Desc
+		}
+	}
+
+	triggerIDs := make([]string, 0, len(triggersAlerts))
+	for _, triggerAlerts := range triggersAlerts {
+		if len(triggerAlerts.tags) == 0 {
+			return nil, api.ErrorInvalidRequest(fmt.Errorf("alert %s has no tags, notifications can not be routed without tags", triggerAlerts.name))
+		}
+		triggerIDs = append(triggerIDs, triggerAlerts.triggerID)
+	}
+
+	triggers, err := dataBase.GetTriggers(triggerIDs)
+	if err != nil {
+		return nil, api.ErrorInternalServer(err)
+	}
+	for _, trigger := range triggers {
+		if trigger != nil && trigger.TriggerSource != moira.External {
+			return nil, api.ErrorInvalidRequest(fmt.Errorf("trigger %s is not an external trigger", trigger.ID))
+		}
+	}
+	return triggersAlerts, nil
+}
+
+// pushExternalTriggerAlerts saves external trigger and its last check and returns count of pushed events.
+func pushExternalTriggerAlerts(dataBase moira.Database, triggerAlerts *externalTriggerAlerts, userLogin string, now int64) (int, *api.ErrorResponse) {
+	triggerID, alerts := triggerAlerts.triggerID, triggerAlerts.alerts
+
+	if err := dataBase.AcquireTriggerCheckLock(triggerID, maxTriggerLockAttempts); err != nil {
+		return 0, api.ErrorInternalServer(err)
+	}
+	defer dataBase.DeleteTriggerCheckLock(triggerID) //nolint
+
+	if errorResponse := saveExternalTrigger(dataBase, triggerAlerts, userLogin, now); errorResponse != nil {
+		return 0, errorResponse
+	}
+
+	lastCheck, err := dataBase.GetTriggerLastCheck(triggerID)
+	if err != nil {
+		if !errors.Is(err, database.ErrNil) {
+			return 0, api.ErrorInternalServer(err)
+		}
+		lastCheck = moira.CheckData{State: moira.StateOK}
+	}
+	if lastCheck.Metrics == nil {
+		lastCheck.Metrics = make(map[string]moira.MetricState)
+	}
+
+	events := make([]moira.NotificationEvent, 0, len(alerts))
+	for _, alert := range alerts {
+		metric := alert.Metric
+		if metric == "" {
+			metric = alert.Name
+		}
+		timestamp := alert.Timestamp
+		if timestamp == 0 {
+			timestamp = now
+		}
+
+		metricState, ok := lastCheck.Metrics[metric]
+		oldState := moira.StateOK
+		if ok {
+			oldState = metricState.State
+		}
+		metricState.State = alert.State
+		metricState.Timestamp = timestamp
+		metricState.Value = alert.Value
+
+		if alert.State != oldState {
+			metricState.EventTimestamp = timestamp
+			lastCheck.EventTimestamp = timestamp
+			if !lastCheck.IsTriggerOnMaintenance() && !lastCheck.IsMetricOnMaintenance(metric) {
+				event := moira.NotificationEvent{
+					Timestamp: timestamp,
+					Metric:    metric,
+					Value:     alert.Value,
+					State:     alert.State,
+					OldState:  oldState,
+					TriggerID: triggerID,
+				}
+				if alert.Message != "" {
+					message := alert.Message
+					event.Message = &message
+				}
+				events = append(events, event)
+			}
+		}
+
+		// Resolved alerts are not kept unless metric maintenance has to be remembered
+		if alert.State == moira.StateOK && !lastCheck.IsMetricOnMaintenance(metric) {
+			lastCheck.RemoveMetricState(metric)
+		} else {
+			lastCheck.Metrics[metric] = metricState
+		}
+	}
+
+	lastCheck.Timestamp = now
+	lastCheck.LastSuccessfulCheckTimestamp = now
+	lastCheck.UpdateScore()
+	if err = dataBase.SetTriggerLastCheck(triggerID, &lastCheck, moira.DefaultExternalCluster); err != nil {
+		return 0, api.ErrorInternalServer(err)
+	}
+
+	for i := range events {
+		if err = dataBase.PushNotificationEvent(&events[i], true); err != nil {
+			return 0, api.ErrorInternalServer(err)
+		}
+	}
+	return len(events), nil
+}
+
+// saveExternalTrigger creates external trigger or updates it if name, description or tags were changed.
+// Tags of alerts are added to the tags of existing trigger, so tags of earlier alerts are kept.
+// External triggers are read-only in API, so they are changed only here.
+func saveExternalTrigger(dataBase moira.Database, triggerAlerts *externalTriggerAlerts, userLogin string, now int64) *api.ErrorResponse {
+	triggerID := triggerAlerts.triggerID
+	trigger, err := dataBase.GetTrigger(triggerID)
+	if err != nil && !errors.Is(err, database.ErrNil) {
+		return api.ErrorInternalServer(err)
+	}
+
+	desc := triggerAlerts.desc
+	tags := triggerAlerts.tags
+	if errors.Is(err, database.ErrNil) {
+		trigger = moira.Trigger{
+			ID:            triggerID,
+			Targets:       make([]string, 0),
+			Patterns:      make([]string, 0),
+			AloneMetrics:  make(map[string]bool),
+			TriggerSource: moira.External,
+			ClusterId:     moira.DefaultCluster,
+			CreatedAt:     &now,
+			CreatedBy:     userLogin,
+		}
+	} else {
+		if trigger.TriggerSource != moira.External {
+			return api.ErrorInvalidRequest(fmt.Errorf("trigger %s is not an external trigger", triggerID))
+		}
+		if desc == "" {
+			desc = moira.UseString(trigger.Desc)
+		}
+		tags = mergeTags(trigger.Tags, tags)
+		if trigger.Name == triggerAlerts.name && moira.UseString(trigger.Desc) == desc && len(tags) == len(trigger.Tags) {
+			return nil
+		}
+	}
+
+	trigger.Name = triggerAlerts.name
+	trigger.Tags = tags
+	trigger.Desc = nil
+	if desc != "" {
+		trigger.Desc = &desc
+	}
+	trigger.UpdatedAt = &now
+	trigger.UpdatedBy = userLogin
+
+	if err = dataBase.SaveTrigger(triggerID, &trigger); err != nil {
+		return api.ErrorInternalServer(err)
+	}
+	return nil
+}
+
+// mergeTags returns tags with new tags added, order of tags is kept.
+func mergeTags(tags, newTags []string) []string {
+	result := make([]string, 0, len(tags)+len(newTags))
+	seenTags := make(map[string]bool, len(tags)+len(newTags))
+	for _, tag := range append(append([]string{}, tags...), newTags...) {
+		if !seenTags[tag] {
+			seenTags[tag] = true
+			result = append(result, tag)
+		}
+	}
+	return result
+}
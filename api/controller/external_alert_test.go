@@ -0,0 +1,170 @@
+package controller
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/api/dto"
+	"github.com/moira-alert/moira/database"
+	mock_moira_alert "github.com/moira-alert/moira/mock/moira-alert"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestPushExternalAlerts(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	defer mockCtrl.Finish()
+
+	const (
+		login = "alertmanager"
+		now   = int64(1590741878)
+	)
+	triggerID := GetExternalTriggerID("Disk")
+	value := float64(95)
+	desc := "Free space"
+
+	Convey("Test push external alerts", t, func() {
+		Convey("External trigger ID is stable", func() {
+			So(GetExternalTriggerID("Disk"), ShouldEqual, triggerID)
+			So(GetExternalTriggerID("Other"), ShouldNotEqual, triggerID)
+		})
+
+		Convey("Alert without tags", func() {
+			alerts := []dto.ExternalAlert{{Name: "Disk", State: moira.StateERROR}}
+			response, err := PushExternalAlerts(dataBase, alerts, login, now)
+			So(err, ShouldNotBeNil)
+			So(err.HTTPStatusCode, ShouldEqual, 400)
+			So(response, ShouldBeNil)
+		})
+
+		Convey("New alert creates trigger and pushes event", func() {
+			alerts := []dto.ExternalAlert{{Name: "Disk", Desc: "Free space", Tags: []string{"disk"}, Metric: "host1", State: moira.StateERROR, Value: &value, Message: "full"}}
+			dataBase.EXPECT().GetTriggers([]string{triggerID}).Return([]*moira.Trigger{nil}, nil)
+			dataBase.EXPECT().AcquireTriggerCheckLock(triggerID, maxTriggerLockAttempts).Return(nil)
+			dataBase.EXPECT().DeleteTriggerCheckLock(triggerID).Return(nil)
+			dataBase.EXPECT().GetTrigger(triggerID).Return(moira.Trigger{}, database.ErrNil)
+			dataBase.EXPECT().SaveTrigger(triggerID, gomock.Any()).DoAndReturn(func(_ string, trigger *moira.Trigger) error {
+				So(trigger.Name, ShouldEqual, "Disk")
+				So(*trigger.Desc, ShouldEqual, "Free space")
+				So(trigger.Tags, ShouldResemble, []string{"disk"})
+				So(trigger.TriggerSource, ShouldEqual, moira.External)
+				So(trigger.CreatedBy, ShouldEqual, login)
+				return nil
+			})
+			dataBase.EXPECT().GetTriggerLastCheck(triggerID).Return(moira.CheckData{}, database.ErrNil)
+			dataBase.EXPECT().SetTriggerLastCheck(triggerID, gomock.Any(), moira.DefaultExternalCluster).DoAndReturn(func(_ string, checkData *moira.CheckData, _ moira.ClusterKey) error {
+				So(checkData.State, ShouldEqual, moira.StateOK)
+				So(checkData.Metrics["host1"].State, ShouldEqual, moira.StateERROR)
+				So(checkData.Metrics["host1"].EventTimestamp, ShouldEqual, now)
+				So(checkData.Score, ShouldEqual, 100)
+				return nil
+			})
+			dataBase.EXPECT().PushNotificationEvent(gomock.Any(), true).DoAndReturn(func(event *moira.NotificationEvent, _ bool) error {
+				So(event.TriggerID, ShouldEqual, triggerID)
+				So(event.Metric, ShouldEqual, "host1")
+				So(event.State, ShouldEqual, moira.StateERROR)
+				So(event.OldState, ShouldEqual, moira.StateOK)
+				So(event.Timestamp, ShouldEqual, now)
+				So(*event.Value, ShouldEqual, value)
+				So(*event.Message, ShouldEqual, "full")
+				return nil
+			})
+
+			response, err := PushExternalAlerts(dataBase, alerts, login, now)
+			So(err, ShouldBeNil)
+			So(response, ShouldResemble, &dto.ExternalAlertsResponse{TriggerIDs: []string{triggerID}, Events: 1})
+		})
+
+		Convey("Existing alerts without state change do not push events and resolved alerts are removed", func() {
+			alerts := []dto.ExternalAlert{
+				{Name: "Disk", Tags: []string{"disk"}, Metric: "host1", State: moira.StateERROR},
+				{Name: "Disk", Tags: []string{"disk"}, Metric: "host2", State: moira.StateOK, Timestamp: now - 10},
+			}
+			dataBase.EXPECT().GetTriggers([]string{triggerID}).Return([]*moira.Trigger{{ID: triggerID, TriggerSource: moira.External}}, nil)
+			dataBase.EXPECT().AcquireTriggerCheckLock(triggerID, maxTriggerLockAttempts).Return(nil)
+			dataBase.EXPECT().DeleteTriggerCheckLock(triggerID).Return(nil)
+			dataBase.EXPECT().GetTrigger(triggerID).Return(moira.Trigger{ID: triggerID, Name: "Disk", Tags: []string{"disk"}, TriggerSource: moira.External}, nil)
+			dataBase.EXPECT().GetTriggerLastCheck(triggerID).Return(moira.CheckData{
+				State: moira.StateOK,
+				Metrics: map[string]moira.MetricState{
+					"host1": {State: moira.StateERROR, EventTimestamp: now - 100},
+					"host2": {State: moira.StateWARN, EventTimestamp: now - 100},
+				},
+			}, nil)
+			dataBase.EXPECT().SetTriggerLastCheck(triggerID, gomock.Any(), moira.DefaultExternalCluster).DoAndReturn(func(_ string, checkData *moira.CheckData, _ moira.ClusterKey) error {
+				So(checkData.Metrics, ShouldHaveLength, 1)
+				So(checkData.Metrics["host1"].EventTimestamp, ShouldEqual, now-100)
+				So(checkData.EventTimestamp, ShouldEqual, now-10)
+				return nil
+			})
+			dataBase.EXPECT().PushNotificationEvent(gomock.Any(), true).DoAndReturn(func(event *moira.NotificationEvent, _ bool) error {
+				So(event.Metric, ShouldEqual, "host2")
+				So(event.State, ShouldEqual, moira.StateOK)
+				So(event.OldState, ShouldEqual, moira.StateWARN)
+				So(event.Timestamp, ShouldEqual, now-10)
+				return nil
+			})
+
+			response, err := PushExternalAlerts(dataBase, alerts, login, now)
+			So(err, ShouldBeNil)
+			So(response, ShouldResemble, &dto.ExternalAlertsResponse{TriggerIDs: []string{triggerID}, Events: 1})
+		})
+
+		Convey("Alerts of trigger in maintenance do not push events and new tags are added to trigger tags", func() {
+			alerts := []dto.ExternalAlert{{Name: "Disk", Tags: []string{"disk"}, State: moira.StateWARN}}
+			dataBase.EXPECT().GetTriggers([]string{triggerID}).Return([]*moira.Trigger{{ID: triggerID, TriggerSource: moira.External}}, nil)
+			dataBase.EXPECT().AcquireTriggerCheckLock(triggerID, maxTriggerLockAttempts).Return(nil)
+			dataBase.EXPECT().DeleteTriggerCheckLock(triggerID).Return(nil)
+			dataBase.EXPECT().GetTrigger(triggerID).Return(moira.Trigger{ID: triggerID, Name: "Disk", Desc: &desc, Tags: []string{"other"}, TriggerSource: moira.External}, nil)
+			dataBase.EXPECT().SaveTrigger(triggerID, gomock.Any()).DoAndReturn(func(_ string, trigger *moira.Trigger) error {
+				So(trigger.Tags, ShouldResemble, []string{"other", "disk"})
+				So(*trigger.Desc, ShouldEqual, desc)
+				return nil
+			})
+			dataBase.EXPECT().GetTriggerLastCheck(triggerID).Return(moira.CheckData{State: moira.StateOK, Maintenance: 1 << 40}, nil)
+			dataBase.EXPECT().SetTriggerLastCheck(triggerID, gomock.Any(), moira.DefaultExternalCluster).Return(nil)
+
+			response, err := PushExternalAlerts(dataBase, alerts, login, now)
+			So(err, ShouldBeNil)
+			So(response, ShouldResemble, &dto.ExternalAlertsResponse{TriggerIDs: []string{triggerID}, Events: 0})
+		})
+
+		Convey("Alert without tags among valid alerts does not change anything", func() {
+			alerts := []dto.ExternalAlert{
+				{Name: "Disk", Tags: []string{"disk"}, State: moira.StateWARN},
+				{Name: "Other", State: moira.StateWARN},
+			}
+			response, err := PushExternalAlerts(dataBase, alerts, login, now)
+			So(err.HTTPStatusCode, ShouldEqual, 400)
+			So(response, ShouldBeNil)
+		})
+
+		Convey("Alert name collides with not external trigger", func() {
+			alerts := []dto.ExternalAlert{
+				{Name: "Other", Tags: []string{"other"}, State: moira.StateWARN},
+				{Name: "Disk", Tags: []string{"disk"}, State: moira.StateWARN},
+			}
+			dataBase.EXPECT().GetTriggers([]string{GetExternalTriggerID("Other"), triggerID}).Return([]*moira.Trigger{nil, {ID: triggerID, TriggerSource: moira.GraphiteLocal}}, nil)
+
+			response, err := PushExternalAlerts(dataBase, alerts, login, now)
+			So(err.HTTPStatusCode, ShouldEqual, 400)
+			So(response, ShouldBeNil)
+		})
+
+		Convey("Database error", func() {
+			expected := errors.New("database error")
+			alerts := []dto.ExternalAlert{{Name: "Disk", Tags: []string{"disk"}, State: moira.StateWARN}}
+			dataBase.EXPECT().GetTriggers([]string{triggerID}).Return([]*moira.Trigger{nil}, nil)
+			dataBase.EXPECT().AcquireTriggerCheckLock(triggerID, maxTriggerLockAttempts).Return(nil)
+			dataBase.EXPECT().DeleteTriggerCheckLock(triggerID).Return(nil)
+			dataBase.EXPECT().GetTrigger(triggerID).Return(moira.Trigger{}, expected)
+
+			response, err := PushExternalAlerts(dataBase, alerts, login, now)
+			So(err, ShouldResemble, api.ErrorInternalServer(expected))
+			So(response, ShouldBeNil)
+		})
+	})
+}
@@ -0,0 +1,77 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/moira-alert/go-chart"
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/api/dto"
+	"github.com/moira-alert/moira/checker"
+	metricSource "github.com/moira-alert/moira/metric_source"
+	"github.com/moira-alert/moira/plotting"
+)
+
+// BacktestTrigger simulates trigger checks on historical data from..to, fetching it by chunks of chunkSize seconds.
+// If plotTemplate is given, simulated states of metrics are rendered to the plot.
+func BacktestTrigger(
+	dataBase moira.Database,
+	metricSourceProvider *metricSource.SourceProvider,
+	trigger *dto.TriggerModel,
+	from, to, chunkSize int64,
+	plotTemplate *plotting.Plot,
+	logger moira.Logger,
+) (*dto.TriggerBacktest, *api.ErrorResponse) {
+	if err := checker.ValidateBacktestInterval(from, to, chunkSize); err != nil {
+		return nil, api.ErrorInvalidRequest(err)
+	}
+
+	moiraTrigger := trigger.ToMoiraTrigger()
+
+	source, err := metricSourceProvider.GetTriggerMetricSource(moiraTrigger)
+	if err != nil {
+		return nil, api.ErrorInvalidRequest(err)
+	}
+
+	result, err := checker.Backtest(moiraTrigger, dataBase, source, from, to, chunkSize, logger)
+	if err != nil {
+		return nil, api.ErrorInternalServer(err)
+	}
+
+	response := &dto.TriggerBacktest{
+		Events:    result.Events,
+		FlapCount: result.FlapCount,
+		CheckData: result.CheckData,
+	}
+	if plotTemplate == nil {
+		return response, nil
+	}
+
+	plot, err := renderBacktestStates(plotTemplate, moiraTrigger, result)
+	if err != nil {
+		return nil, api.ErrorInternalServer(err)
+	}
+	response.Plot = plot
+	return response, nil
+}
+
+// renderBacktestStates draws simulated states of metrics and returns base64 encoded PNG.
+func renderBacktestStates(plotTemplate *plotting.Plot, trigger *moira.Trigger, result *checker.BacktestResult) (string, error) {
+	statesTrigger := &moira.Trigger{
+		ID:          trigger.ID,
+		Name:        fmt.Sprintf("%s (0 OK, 1 WARN, 2 ERROR, 3 NODATA, 4 EXCEPTION)", trigger.Name),
+		TriggerType: moira.ExpressionTrigger,
+	}
+	renderable, err := plotTemplate.GetRenderable("states", statesTrigger, result.StatesMetricsData())
+	if err != nil {
+		return "", err
+	}
+
+	buffer := bytes.NewBuffer(nil)
+	if err := renderable.Render(chart.PNG, buffer); err != nil {
+		return "", fmt.Errorf("can not render plot %s", err.Error())
+	}
+	return base64.StdEncoding.EncodeToString(buffer.Bytes()), nil
+}
@@ -0,0 +1,86 @@
+package controller
+
+import (
+	"encoding/base64"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	logging "github.com/moira-alert/moira/logging/zerolog_adapter"
+	metricSource "github.com/moira-alert/moira/metric_source"
+	mock_metric_source "github.com/moira-alert/moira/mock/metric_source"
+	"github.com/moira-alert/moira/plotting"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/api/dto"
+	"github.com/moira-alert/moira/checker"
+	mock_moira_alert "github.com/moira-alert/moira/mock/moira-alert"
+)
+
+func TestBacktestTrigger(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	localSource := mock_metric_source.NewMockMetricSource(mockCtrl)
+	fetchResult := mock_metric_source.NewMockFetchResult(mockCtrl)
+	sourceProvider := metricSource.CreateTestMetricSourceProvider(localSource, nil, nil)
+	logger, _ := logging.GetLogger("Test")
+
+	warnValue := float64(10)
+	trigger := &dto.TriggerModel{
+		ID:            "backtest",
+		Name:          "Backtest",
+		Targets:       []string{"metric"},
+		WarnValue:     &warnValue,
+		TriggerType:   moira.RisingTrigger,
+		TriggerSource: moira.GraphiteLocal,
+		ClusterId:     moira.DefaultCluster,
+	}
+
+	Convey("Backtest of trigger", t, func() {
+		localSource.EXPECT().Fetch("metric", int64(60), int64(240), true).Return(fetchResult, nil).AnyTimes()
+		fetchResult.EXPECT().GetMetricsData().Return([]metricSource.MetricData{*metricSource.MakeMetricData("metric", []float64{1, 20, 1, 20}, 60, 60)}).AnyTimes()
+		fetchResult.EXPECT().GetPatternMetrics().Return([]string{}, nil).AnyTimes()
+
+		Convey("Returns events and flap count", func() {
+			result, err := BacktestTrigger(dataBase, sourceProvider, trigger, 60, 240, 0, nil, logger)
+			So(err, ShouldBeNil)
+			So(result.Events, ShouldHaveLength, 4)
+			So(result.FlapCount, ShouldEqual, 2)
+			So(result.CheckData.Metrics["metric"].State, ShouldEqual, moira.StateWARN)
+			So(result.Plot, ShouldBeEmpty)
+		})
+
+		Convey("Returns plot of simulated states", func() {
+			plotTemplate, _ := plotting.GetPlotTemplate("", time.UTC)
+			result, err := BacktestTrigger(dataBase, sourceProvider, trigger, 60, 240, 0, plotTemplate, logger)
+			So(err, ShouldBeNil)
+			plot, decodeErr := base64.StdEncoding.DecodeString(result.Plot)
+			So(decodeErr, ShouldBeNil)
+			So(string(plot[1:4]), ShouldEqual, "PNG")
+		})
+
+		Convey("Returns error of unknown metric source", func() {
+			remoteTrigger := *trigger
+			remoteTrigger.TriggerSource = moira.GraphiteRemote
+			result, err := BacktestTrigger(dataBase, sourceProvider, &remoteTrigger, 60, 240, 0, nil, logger)
+			So(result, ShouldBeNil)
+			So(err, ShouldResemble, api.ErrorInvalidRequest(fmt.Errorf("unknown metric source with cluster key `graphite_remote.default`")))
+		})
+
+		Convey("Returns bad request if interval is too long", func() {
+			result, err := BacktestTrigger(dataBase, sourceProvider, trigger, 0, checker.MaxBacktestInterval+1, 0, nil, logger)
+			So(result, ShouldBeNil)
+			So(err.HTTPStatusCode, ShouldEqual, 400)
+		})
+
+		Convey("Returns bad request if interval has too many chunks", func() {
+			result, err := BacktestTrigger(dataBase, sourceProvider, trigger, 0, 60*(checker.MaxBacktestChunks+1), 60, nil, logger)
+			So(result, ShouldBeNil)
+			So(err.HTTPStatusCode, ShouldEqual, 400)
+		})
+	})
+}
@@ -0,0 +1,50 @@
+package controller
+
+import (
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/api/dto"
+	"github.com/moira-alert/moira/checker"
+	metricSource "github.com/moira-alert/moira/metric_source"
+)
+
+// DryRunTrigger checks trigger on interval from..to as the checker does, but saves nothing and sends no events.
+func DryRunTrigger(
+	dataBase moira.Database,
+	metricSourceProvider *metricSource.SourceProvider,
+	trigger *dto.TriggerModel,
+	from, to int64,
+	logger moira.Logger,
+) (*dto.TriggerDryRun, *api.ErrorResponse) {
+	moiraTrigger := trigger.ToMoiraTrigger()
+
+	source, err := metricSourceProvider.GetTriggerMetricSource(moiraTrigger)
+	if err != nil {
+		return nil, api.ErrorInvalidRequest(err)
+	}
+
+	result, err := checker.DryRun(moiraTrigger, dataBase, source, from, to, logger)
+	if err != nil {
+		return nil, api.ErrorInternalServer(err)
+	}
+
+	metricsData := make(dto.TriggerMetrics, len(result.MetricsData))
+	for targetName, target := range result.MetricsData {
+		metricsData[targetName] = getMetricsValues(target)
+	}
+
+	steps := make(map[string][]dto.TriggerDryRunStep, len(result.Steps))
+	for metricName, metricSteps := range result.Steps {
+		steps[metricName] = make([]dto.TriggerDryRunStep, 0, len(metricSteps))
+		for _, step := range metricSteps {
+			steps[metricName] = append(steps[metricName], dto.TriggerDryRunStep(step))
+		}
+	}
+
+	return &dto.TriggerDryRun{
+		MetricsData: metricsData,
+		Steps:       steps,
+		Events:      result.Events,
+		CheckData:   result.CheckData,
+	}, nil
+}
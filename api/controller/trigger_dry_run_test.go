@@ -0,0 +1,63 @@
+package controller
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	logging "github.com/moira-alert/moira/logging/zerolog_adapter"
+	metricSource "github.com/moira-alert/moira/metric_source"
+	mock_metric_source "github.com/moira-alert/moira/mock/metric_source"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/api/dto"
+	mock_moira_alert "github.com/moira-alert/moira/mock/moira-alert"
+)
+
+func TestDryRunTrigger(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	localSource := mock_metric_source.NewMockMetricSource(mockCtrl)
+	fetchResult := mock_metric_source.NewMockFetchResult(mockCtrl)
+	sourceProvider := metricSource.CreateTestMetricSourceProvider(localSource, nil, nil)
+	logger, _ := logging.GetLogger("Test")
+
+	warnValue := float64(10)
+	trigger := &dto.TriggerModel{
+		ID:            "dry-run",
+		Targets:       []string{"metric"},
+		WarnValue:     &warnValue,
+		TriggerType:   moira.RisingTrigger,
+		TriggerSource: moira.GraphiteLocal,
+		ClusterId:     moira.DefaultCluster,
+	}
+
+	Convey("Dry run of trigger", t, func() {
+		Convey("Returns values, steps and events without saving", func() {
+			localSource.EXPECT().Fetch("metric", int64(60), int64(180), true).Return(fetchResult, nil)
+			fetchResult.EXPECT().GetMetricsData().Return([]metricSource.MetricData{*metricSource.MakeMetricData("metric", []float64{1, 20}, 60, 60)}).AnyTimes()
+			fetchResult.EXPECT().GetPatternMetrics().Return([]string{}, nil)
+
+			result, err := DryRunTrigger(dataBase, sourceProvider, trigger, 60, 180, logger)
+			So(err, ShouldBeNil)
+			So(result.MetricsData, ShouldResemble, dto.TriggerMetrics{
+				"t1": {"metric": {{Timestamp: 60, Value: 1}, {Timestamp: 120, Value: 20}}},
+			})
+			So(result.Steps["metric"], ShouldHaveLength, 2)
+			So(result.Steps["metric"][1].State, ShouldEqual, moira.StateWARN)
+			So(result.Events, ShouldHaveLength, 2)
+			So(result.CheckData.Metrics["metric"].State, ShouldEqual, moira.StateWARN)
+		})
+
+		Convey("Returns error of unknown metric source", func() {
+			remoteTrigger := *trigger
+			remoteTrigger.TriggerSource = moira.GraphiteRemote
+			result, err := DryRunTrigger(dataBase, sourceProvider, &remoteTrigger, 60, 180, logger)
+			So(result, ShouldBeNil)
+			So(err, ShouldResemble, api.ErrorInvalidRequest(fmt.Errorf("unknown metric source with cluster key `graphite_remote.default`")))
+		})
+	})
+}
@@ -25,7 +25,7 @@ func GetTriggerEvaluationResult(dataBase moira.Database, metricSourceProvider *m
 	}
 	for i, target := range trigger.Targets {
 		i++ // Increase counter to have trigger names start from t1
-		fetchResult, err := metricsSource.Fetch(target, from, to, fetchRealtimeData)
+		fetchResult, err := metricSource.FetchWithOptions(metricsSource, target, from, to, fetchRealtimeData, trigger.FetchOptions.WithoutInstant())
 		if err != nil {
 			return nil, &trigger, err
 		}
@@ -60,21 +60,26 @@ func GetTriggerMetrics(dataBase moira.Database, metricSourceProvider *metricSour
 	triggerMetrics := make(dto.TriggerMetrics)
 
 	for targetName, target := range tts {
-		targetMetrics := make(map[string][]moira.MetricValue)
-		for _, timeSeries := range target {
-			values := make([]moira.MetricValue, 0)
-			for i, l := 0, len(timeSeries.Values); i < l; i++ {
-				timestamp := timeSeries.StartTime + int64(i)*timeSeries.StepTime
-				value := timeSeries.GetTimestampValue(timestamp)
-				if moira.IsFiniteNumber(value) {
-					values = append(values, moira.MetricValue{Value: value, Timestamp: timestamp})
-				}
+		triggerMetrics[targetName] = getMetricsValues(target)
+	}
+	return &triggerMetrics, nil
+}
+
+// getMetricsValues converts metrics data to values by metric name, empty values are skipped.
+func getMetricsValues(metricsData []metricSource.MetricData) map[string][]moira.MetricValue {
+	targetMetrics := make(map[string][]moira.MetricValue)
+	for _, timeSeries := range metricsData {
+		values := make([]moira.MetricValue, 0)
+		for i, l := 0, len(timeSeries.Values); i < l; i++ {
+			timestamp := timeSeries.StartTime + int64(i)*timeSeries.StepTime
+			value := timeSeries.GetTimestampValue(timestamp)
+			if moira.IsFiniteNumber(value) {
+				values = append(values, moira.MetricValue{Value: value, Timestamp: timestamp})
 			}
-			targetMetrics[timeSeries.Name] = values
 		}
-		triggerMetrics[targetName] = targetMetrics
+		targetMetrics[timeSeries.Name] = values
 	}
-	return &triggerMetrics, nil
+	return targetMetrics
 }
 
 func deleteTriggerMetrics(dataBase moira.Database, metricName string, triggerID string, removeAllNodataMetrics bool) *api.ErrorResponse {
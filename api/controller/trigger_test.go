@@ -42,6 +42,14 @@ func TestUpdateTrigger(t *testing.T) {
 		So(resp, ShouldBeNil)
 	})
 
+	Convey("External trigger is read-only", t, func() {
+		trigger := dto.TriggerModel{ID: uuid.Must(uuid.NewV4()).String()}
+		dataBase.EXPECT().GetTrigger(trigger.ID).Return(moira.Trigger{ID: trigger.ID, TriggerSource: moira.External}, nil)
+		resp, err := UpdateTrigger(dataBase, &trigger, trigger.ID, make(map[string]bool))
+		So(err, ShouldResemble, api.ErrorInvalidRequest(dto.ErrExternalTriggerReadOnly))
+		So(resp, ShouldBeNil)
+	})
+
 	Convey("Get trigger error", t, func() {
 		trigger := dto.TriggerModel{ID: uuid.Must(uuid.NewV4()).String()}
 		expected := fmt.Errorf("soo bad trigger")
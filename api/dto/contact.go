@@ -6,6 +6,8 @@ import (
 	"net/http"
 
 	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/senders"
+	"github.com/moira-alert/moira/templating"
 )
 
 type ContactList struct {
@@ -22,6 +24,10 @@ type Contact struct {
 	ID     string `json:"id,omitempty" example:"1dd38765-c5be-418d-81fa-7a5f879c2315"`
 	User   string `json:"user,omitempty" example:""`
 	TeamID string `json:"team_id,omitempty"`
+	// Template is an optional message template which overrides the sender message format.
+	Template string `json:"template,omitempty" example:"{{ .State }} {{ .Trigger.Name }}"`
+	// DedupBy is an optional incident dedup mode of on-call senders, "trigger" or "metric".
+	DedupBy string `json:"dedup_by,omitempty" example:"trigger"`
 }
 
 func (*Contact) Render(w http.ResponseWriter, r *http.Request) error {
@@ -35,5 +41,13 @@ func (contact *Contact) Bind(r *http.Request) error {
 	if contact.Value == "" {
 		return fmt.Errorf("contact value of type %s can not be empty", contact.Type)
 	}
+	if contact.Template != "" {
+		if err := templating.ValidateMessageTemplate(contact.Template); err != nil {
+			return fmt.Errorf("contact template is invalid: %w", err)
+		}
+	}
+	if _, err := senders.ParseDedupMode(contact.DedupBy); err != nil {
+		return fmt.Errorf("contact dedup_by is invalid: %w", err)
+	}
 	return nil
 }
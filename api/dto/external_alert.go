@@ -0,0 +1,173 @@
+// nolint
+package dto
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/moira-alert/moira"
+)
+
+const (
+	alertmanagerStatusResolved = "resolved"
+	alertmanagerNameLabel      = "alertname"
+	alertmanagerSeverityLabel  = "severity"
+	// alertmanagerTagsLabel holds comma separated moira tags of the alert
+	alertmanagerTagsLabel = "moira_tags"
+)
+
+// ExternalAlert is an alert of external alerting system, it is mapped onto metric of external trigger with the same name.
+type ExternalAlert struct {
+	// Name of external trigger, alerts with the same name share the trigger
+	Name string `json:"name" example:"Disk is almost full"`
+	Desc string `json:"desc,omitempty" example:"Free space is less than 10%"`
+	// Tags of external trigger, they are used to find subscriptions
+	Tags []string `json:"tags" example:"server,disk"`
+	// Metric name, trigger name is used if empty
+	Metric    string      `json:"metric,omitempty" example:"host1"`
+	State     moira.State `json:"state" example:"ERROR"`
+	Value     *float64    `json:"value,omitempty" example:"95" extensions:"x-nullable"`
+	Message   string      `json:"msg,omitempty" example:"Disk /var is 95% full"`
+	Timestamp int64       `json:"timestamp,omitempty" example:"1590741878" format:"int64"`
+}
+
+func (alert *ExternalAlert) Bind(r *http.Request) error {
+	if alert.Name == "" {
+		return fmt.Errorf("alert name can not be empty")
+	}
+	switch alert.State {
+	case moira.StateOK, moira.StateWARN, moira.StateERROR, moira.StateNODATA:
+	default:
+		return fmt.Errorf("alert %s has invalid state '%s', allowed states are OK, WARN, ERROR, NODATA", alert.Name, alert.State)
+	}
+	return nil
+}
+
+type ExternalAlerts struct {
+	Alerts []ExternalAlert `json:"alerts"`
+}
+
+func (alerts *ExternalAlerts) Bind(r *http.Request) error {
+	if len(alerts.Alerts) == 0 {
+		return fmt.Errorf("alerts can not be empty")
+	}
+	for i := range alerts.Alerts {
+		if err := alerts.Alerts[i].Bind(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AlertmanagerWebhook is a payload of Alertmanager webhook receiver.
+type AlertmanagerWebhook struct {
+	Version           string              `json:"version" example:"4"`
+	GroupKey          string              `json:"groupKey"`
+	Status            string              `json:"status" example:"firing"`
+	Receiver          string              `json:"receiver" example:"moira"`
+	GroupLabels       map[string]string   `json:"groupLabels"`
+	CommonLabels      map[string]string   `json:"commonLabels"`
+	CommonAnnotations map[string]string   `json:"commonAnnotations"`
+	ExternalURL       string              `json:"externalURL"`
+	Alerts            []AlertmanagerAlert `json:"alerts"`
+}
+
+// AlertmanagerAlert is a single alert of Alertmanager webhook payload.
+type AlertmanagerAlert struct {
+	Status       string            `json:"status" example:"firing"`
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations"`
+	StartsAt     time.Time         `json:"startsAt"`
+	EndsAt       time.Time         `json:"endsAt"`
+	GeneratorURL string            `json:"generatorURL"`
+	Fingerprint  string            `json:"fingerprint"`
+}
+
+func (webhook *AlertmanagerWebhook) Bind(r *http.Request) error {
+	if len(webhook.Alerts) == 0 {
+		return fmt.Errorf("alerts can not be empty")
+	}
+	for _, alert := range webhook.Alerts {
+		if alert.Labels[alertmanagerNameLabel] == "" {
+			return fmt.Errorf("alert label %s can not be empty", alertmanagerNameLabel)
+		}
+	}
+	return nil
+}
+
+// ToExternalAlerts maps Alertmanager alerts onto external alerts, given tags are added to tags from moira_tags label.
+func (webhook *AlertmanagerWebhook) ToExternalAlerts(tags []string) []ExternalAlert {
+	alerts := make([]ExternalAlert, 0, len(webhook.Alerts))
+	for _, alert := range webhook.Alerts {
+		externalAlert := ExternalAlert{
+			Name:    alert.Labels[alertmanagerNameLabel],
+			Desc:    alert.Annotations["description"],
+			Tags:    append(append([]string{}, tags...), splitTags(alert.Labels[alertmanagerTagsLabel])...),
+			Metric:  alertmanagerMetricName(alert.Labels),
+			State:   alertmanagerAlertState(alert),
+			Message: alert.Annotations["summary"],
+		}
+		timestamp := alert.StartsAt
+		if externalAlert.State == moira.StateOK {
+			timestamp = alert.EndsAt
+		}
+		if !timestamp.IsZero() {
+			externalAlert.Timestamp = timestamp.Unix()
+		}
+		alerts = append(alerts, externalAlert)
+	}
+	return alerts
+}
+
+// alertmanagerAlertState returns OK for resolved alerts and WARN or ERROR for firing alerts depending on severity label.
+func alertmanagerAlertState(alert AlertmanagerAlert) moira.State {
+	if alert.Status == alertmanagerStatusResolved {
+		return moira.StateOK
+	}
+	switch strings.ToLower(alert.Labels[alertmanagerSeverityLabel]) {
+	case "warning", "warn", "info":
+		return moira.StateWARN
+	default:
+		return moira.StateERROR
+	}
+}
+
+// alertmanagerMetricName joins sorted alert labels except alertname and moira_tags, alertname is used if there are no labels left.
+func alertmanagerMetricName(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for name, value := range labels {
+		if name == alertmanagerNameLabel || name == alertmanagerTagsLabel {
+			continue
+		}
+		pairs = append(pairs, name+"="+value)
+	}
+	if len(pairs) == 0 {
+		return labels[alertmanagerNameLabel]
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func splitTags(value string) []string {
+	tags := make([]string, 0)
+	for _, tag := range strings.Split(value, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+type ExternalAlertsResponse struct {
+	// IDs of external triggers the alerts were mapped onto
+	TriggerIDs []string `json:"trigger_ids" example:"bcba82f5-48cf-44c0-b7d6-e1d32c64a88c"`
+	// Count of pushed notification events, alerts without state change do not produce events
+	Events int `json:"events" example:"1"`
+}
+
+func (*ExternalAlertsResponse) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
@@ -0,0 +1,109 @@
+package dto
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/moira-alert/moira"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestExternalAlertsBind(t *testing.T) {
+	request, _ := http.NewRequest(http.MethodPost, "/api/external-alert", nil)
+
+	Convey("Test external alerts bind", t, func() {
+		Convey("Valid alerts", func() {
+			alerts := ExternalAlerts{Alerts: []ExternalAlert{{Name: "Disk", State: moira.StateWARN}}}
+			So(alerts.Bind(request), ShouldBeNil)
+		})
+
+		Convey("Empty alerts", func() {
+			alerts := ExternalAlerts{}
+			So(alerts.Bind(request), ShouldNotBeNil)
+		})
+
+		Convey("Alert without name", func() {
+			alerts := ExternalAlerts{Alerts: []ExternalAlert{{State: moira.StateOK}}}
+			So(alerts.Bind(request), ShouldNotBeNil)
+		})
+
+		Convey("Alert with invalid state", func() {
+			alerts := ExternalAlerts{Alerts: []ExternalAlert{{Name: "Disk", State: moira.StateEXCEPTION}}}
+			So(alerts.Bind(request), ShouldNotBeNil)
+		})
+	})
+}
+
+func TestAlertmanagerWebhookToExternalAlerts(t *testing.T) {
+	startsAt := time.Unix(1590741000, 0)
+	endsAt := time.Unix(1590741878, 0)
+
+	Convey("Test alertmanager webhook mapping", t, func() {
+		webhook := AlertmanagerWebhook{
+			Alerts: []AlertmanagerAlert{
+				{
+					Status: "firing",
+					Labels: map[string]string{
+						"alertname":  "DiskFull",
+						"instance":   "host1",
+						"job":        "node",
+						"severity":   "warning",
+						"moira_tags": "disk, ops",
+					},
+					Annotations: map[string]string{"summary": "Disk is 95% full", "description": "Free space is low"},
+					StartsAt:    startsAt,
+				},
+				{
+					Status:   "resolved",
+					Labels:   map[string]string{"alertname": "Down", "severity": "critical"},
+					StartsAt: startsAt,
+					EndsAt:   endsAt,
+				},
+				{
+					Status:   "firing",
+					Labels:   map[string]string{"alertname": "Down", "instance": "host2", "severity": "critical"},
+					StartsAt: startsAt,
+				},
+			},
+		}
+
+		Convey("Bind requires alertname", func() {
+			So(webhook.Bind(nil), ShouldBeNil)
+			invalid := AlertmanagerWebhook{Alerts: []AlertmanagerAlert{{Labels: map[string]string{"instance": "host1"}}}}
+			So(invalid.Bind(nil), ShouldNotBeNil)
+		})
+
+		Convey("Alerts are mapped with query tags", func() {
+			So(webhook.ToExternalAlerts([]string{"prod"}), ShouldResemble, []ExternalAlert{
+				{
+					Name:      "DiskFull",
+					Desc:      "Free space is low",
+					Tags:      []string{"prod", "disk", "ops"},
+					Metric:    "instance=host1,job=node,severity=warning",
+					State:     moira.StateWARN,
+					Message:   "Disk is 95% full",
+					Timestamp: startsAt.Unix(),
+				},
+				{
+					Name:      "Down",
+					Tags:      []string{"prod"},
+					Metric:    "severity=critical",
+					State:     moira.StateOK,
+					Timestamp: endsAt.Unix(),
+				},
+				{
+					Name:      "Down",
+					Tags:      []string{"prod"},
+					Metric:    "instance=host2,severity=critical",
+					State:     moira.StateERROR,
+					Timestamp: startsAt.Unix(),
+				},
+			})
+		})
+
+		Convey("Alert without labels uses alertname as metric", func() {
+			So(alertmanagerMetricName(map[string]string{"alertname": "Watchdog"}), ShouldEqual, "Watchdog")
+		})
+	})
+}
@@ -1,6 +1,7 @@
 package dto
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -10,6 +11,7 @@ import (
 	"github.com/go-graphite/carbonapi/pkg/parser"
 	"github.com/moira-alert/moira"
 	"github.com/moira-alert/moira/filter"
+	"github.com/moira-alert/moira/metric_source/loki"
 )
 
 func init() {
@@ -132,11 +134,14 @@ type TreeOfProblems struct {
 // TargetVerification validates trigger targets.
 func TargetVerification(targets []string, ttl time.Duration, triggerSource moira.TriggerSource) ([]TreeOfProblems, error) {
 	switch triggerSource {
-	case moira.PrometheusRemote:
+	case moira.PrometheusRemote, moira.TenantRemote, moira.SQLRemote:
 		return []TreeOfProblems{{SyntaxOk: true}}, nil
 
 	case moira.GraphiteLocal, moira.GraphiteRemote:
 		return graphiteTargetVerification(targets, ttl, triggerSource), nil
+
+	case moira.LokiRemote:
+		return logQLTargetVerification(targets, ttl), nil
 	}
 
 	return nil, fmt.Errorf("unknown trigger source '%s'", triggerSource)
@@ -168,6 +173,46 @@ func graphiteTargetVerification(targets []string, ttl time.Duration, triggerSour
 	return functionsOfTargets
 }
 
+func logQLTargetVerification(targets []string, ttl time.Duration) []TreeOfProblems {
+	functionsOfTargets := make([]TreeOfProblems, 0, len(targets))
+
+	for _, target := range targets {
+		functionsOfTarget := TreeOfProblems{SyntaxOk: true}
+
+		ranges, err := loki.ValidateTarget(target)
+		if err != nil {
+			if errors.Is(err, loki.ErrNotMetricQuery) {
+				functionsOfTarget.TreeOfProblems = &ProblemOfTarget{
+					Argument:    target,
+					Type:        isBad,
+					Description: err.Error(),
+				}
+			} else {
+				functionsOfTarget.SyntaxOk = false
+			}
+			functionsOfTargets = append(functionsOfTargets, functionsOfTarget)
+			continue
+		}
+
+		for _, duration := range ranges {
+			if ttl != 0 && duration > ttl {
+				functionsOfTarget.TreeOfProblems = &ProblemOfTarget{
+					Argument: target,
+					Type:     isBad,
+					Description: fmt.Sprintf(
+						"The query has a range %s larger than allowed by the config:%s",
+						duration.String(), ttl.String()),
+				}
+				break
+			}
+		}
+
+		functionsOfTargets = append(functionsOfTargets, functionsOfTarget)
+	}
+
+	return functionsOfTargets
+}
+
 // DoesAnyTreeHaveError checks that at least one node of tree has a problem with error type.
 // It is wrapper to handle slice of trees.
 func DoesAnyTreeHaveError(trees []TreeOfProblems) bool {
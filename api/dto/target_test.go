@@ -168,6 +168,49 @@ func TestTargetVerification(t *testing.T) {
 	})
 }
 
+func TestSQLTargetVerification(t *testing.T) {
+	Convey("SQL target is passed to query as parameter, so it is always valid", t, func() {
+		problems, err := TargetVerification([]string{"orders.%"}, time.Hour, moira.SQLRemote)
+		So(err, ShouldBeNil)
+		So(problems, ShouldResemble, []TreeOfProblems{{SyntaxOk: true}})
+	})
+}
+
+func TestLogQLTargetVerification(t *testing.T) {
+	Convey("LogQL target verification", t, func() {
+		Convey("Check correct metric query", func() {
+			targets := []string{`sum by (level) (count_over_time({app="api"} |= "error" [5m]))`}
+			problems, err := TargetVerification(targets, time.Hour, moira.LokiRemote)
+			So(err, ShouldBeNil)
+			So(problems[0].SyntaxOk, ShouldBeTrue)
+			So(problems[0].TreeOfProblems, ShouldBeNil)
+		})
+
+		Convey("Check query with syntax error", func() {
+			targets := []string{`count_over_time({app="api"}[5m]`}
+			problems, err := TargetVerification(targets, time.Hour, moira.LokiRemote)
+			So(err, ShouldBeNil)
+			So(problems[0].SyntaxOk, ShouldBeFalse)
+		})
+
+		Convey("Check log query", func() {
+			targets := []string{`{app="api"} |= "error"`}
+			problems, err := TargetVerification(targets, time.Hour, moira.LokiRemote)
+			So(err, ShouldBeNil)
+			So(problems[0].SyntaxOk, ShouldBeTrue)
+			So(problems[0].TreeOfProblems.Type, ShouldEqual, isBad)
+		})
+
+		Convey("Check range larger than TTL", func() {
+			targets := []string{`rate({app="api"}[2h])`}
+			problems, err := TargetVerification(targets, time.Hour, moira.LokiRemote)
+			So(err, ShouldBeNil)
+			So(problems[0].SyntaxOk, ShouldBeTrue)
+			So(problems[0].TreeOfProblems.Type, ShouldEqual, isBad)
+		})
+	})
+}
+
 func TestConvertGraphiteTimeToTimeDuration(t *testing.T) {
 	Convey("Test graphite time functions", t, func() {
 		for _, data := range getTestDataTargetWithTimeInterval() {
@@ -2,6 +2,7 @@
 package dto
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"regexp"
@@ -22,6 +23,10 @@ var targetNameRegex = regexp.MustCompile("t(\\d+)")
 // TODO(litleleprikon): Remove after https://github.com/moira-alert/moira/issues/550 will be resolved.
 var asteriskPattern = "*"
 
+// ErrExternalTriggerReadOnly is returned on attempt to save external trigger via API,
+// external triggers are created and updated only by pushed external alerts.
+var ErrExternalTriggerReadOnly = errors.New("external triggers are read-only, they are changed only by pushed external alerts")
+
 type TriggersList struct {
 	Page  *int64               `json:"page,omitempty" format:"int64" extensions:"x-nullable"`
 	Size  *int64               `json:"size,omitempty" format:"int64" extensions:"x-nullable"`
@@ -79,6 +84,10 @@ type TriggerModel struct {
 	MuteNewMetrics bool `json:"mute_new_metrics" example:"false"`
 	// A list of targets that have only alone metrics
 	AloneMetrics map[string]bool `json:"alone_metrics" example:"t1:true"`
+	// Trigger specific settings of metrics fetching, e.g. step of prometheus range queries
+	FetchOptions *moira.FetchOptions `json:"fetch_options,omitempty" extensions:"x-nullable"`
+	// Thresholds or expression of metrics matching name glob or tags, the first matching override is used
+	ThresholdOverrides []moira.ThresholdOverride `json:"threshold_overrides,omitempty"`
 	// Datetime when the trigger was created
 	CreatedAt *time.Time `json:"created_at" extensions:"x-nullable"`
 	// Datetime  when the trigger was updated
@@ -97,56 +106,63 @@ func (trigger *TriggerModel) ClusterKey() moira.ClusterKey {
 // ToMoiraTrigger transforms TriggerModel to moira.Trigger.
 func (model *TriggerModel) ToMoiraTrigger() *moira.Trigger {
 	return &moira.Trigger{
-		ID:             model.ID,
-		Name:           model.Name,
-		Desc:           model.Desc,
-		Targets:        model.Targets,
-		WarnValue:      model.WarnValue,
-		ErrorValue:     model.ErrorValue,
-		TriggerType:    model.TriggerType,
-		Tags:           model.Tags,
-		TTLState:       model.TTLState,
-		TTL:            model.TTL,
-		Schedule:       model.Schedule,
-		Expression:     &model.Expression,
-		Patterns:       model.Patterns,
-		TriggerSource:  model.TriggerSource,
-		ClusterId:      model.ClusterId,
-		MuteNewMetrics: model.MuteNewMetrics,
-		AloneMetrics:   model.AloneMetrics,
-		UpdatedBy:      model.UpdatedBy,
+		ID:                 model.ID,
+		Name:               model.Name,
+		Desc:               model.Desc,
+		Targets:            model.Targets,
+		WarnValue:          model.WarnValue,
+		ErrorValue:         model.ErrorValue,
+		TriggerType:        model.TriggerType,
+		Tags:               model.Tags,
+		TTLState:           model.TTLState,
+		TTL:                model.TTL,
+		Schedule:           model.Schedule,
+		Expression:         &model.Expression,
+		Patterns:           model.Patterns,
+		TriggerSource:      model.TriggerSource,
+		ClusterId:          model.ClusterId,
+		MuteNewMetrics:     model.MuteNewMetrics,
+		AloneMetrics:       model.AloneMetrics,
+		FetchOptions:       model.FetchOptions,
+		ThresholdOverrides: model.ThresholdOverrides,
+		UpdatedBy:          model.UpdatedBy,
 	}
 }
 
 // CreateTriggerModel transforms moira.Trigger to TriggerModel.
 func CreateTriggerModel(trigger *moira.Trigger) TriggerModel {
 	return TriggerModel{
-		ID:             trigger.ID,
-		Name:           trigger.Name,
-		Desc:           trigger.Desc,
-		Targets:        trigger.Targets,
-		WarnValue:      trigger.WarnValue,
-		ErrorValue:     trigger.ErrorValue,
-		TriggerType:    trigger.TriggerType,
-		Tags:           trigger.Tags,
-		TTLState:       trigger.TTLState,
-		TTL:            trigger.TTL,
-		Schedule:       trigger.Schedule,
-		Expression:     moira.UseString(trigger.Expression),
-		Patterns:       trigger.Patterns,
-		IsRemote:       trigger.TriggerSource == moira.GraphiteRemote,
-		TriggerSource:  trigger.TriggerSource,
-		ClusterId:      trigger.ClusterId,
-		MuteNewMetrics: trigger.MuteNewMetrics,
-		AloneMetrics:   trigger.AloneMetrics,
-		CreatedAt:      getDateTime(trigger.CreatedAt),
-		UpdatedAt:      getDateTime(trigger.UpdatedAt),
-		CreatedBy:      trigger.CreatedBy,
-		UpdatedBy:      trigger.UpdatedBy,
+		ID:                 trigger.ID,
+		Name:               trigger.Name,
+		Desc:               trigger.Desc,
+		Targets:            trigger.Targets,
+		WarnValue:          trigger.WarnValue,
+		ErrorValue:         trigger.ErrorValue,
+		TriggerType:        trigger.TriggerType,
+		Tags:               trigger.Tags,
+		TTLState:           trigger.TTLState,
+		TTL:                trigger.TTL,
+		Schedule:           trigger.Schedule,
+		Expression:         moira.UseString(trigger.Expression),
+		Patterns:           trigger.Patterns,
+		IsRemote:           trigger.TriggerSource == moira.GraphiteRemote,
+		TriggerSource:      trigger.TriggerSource,
+		ClusterId:          trigger.ClusterId,
+		MuteNewMetrics:     trigger.MuteNewMetrics,
+		AloneMetrics:       trigger.AloneMetrics,
+		FetchOptions:       trigger.FetchOptions,
+		ThresholdOverrides: trigger.ThresholdOverrides,
+		CreatedAt:          getDateTime(trigger.CreatedAt),
+		UpdatedAt:          getDateTime(trigger.UpdatedAt),
+		CreatedBy:          trigger.CreatedBy,
+		UpdatedBy:          trigger.UpdatedBy,
 	}
 }
 
 func (trigger *Trigger) Bind(request *http.Request) error {
+	if trigger.TriggerSource == moira.External {
+		return api.ErrInvalidRequestContent{ValidationError: ErrExternalTriggerReadOnly}
+	}
 	trigger.Tags = normalizeTags(trigger.Tags)
 	if len(trigger.Targets) == 0 {
 		return api.ErrInvalidRequestContent{ValidationError: fmt.Errorf("targets is required")}
@@ -164,6 +180,14 @@ func (trigger *Trigger) Bind(request *http.Request) error {
 		return api.ErrInvalidRequestContent{ValidationError: err}
 	}
 
+	if err := checkThresholdOverrides(trigger); err != nil {
+		return api.ErrInvalidRequestContent{ValidationError: err}
+	}
+
+	if trigger.FetchOptions != nil && trigger.FetchOptions.Step < 0 {
+		return api.ErrInvalidRequestContent{ValidationError: fmt.Errorf("fetch_options.step should not be negative")}
+	}
+
 	if len(trigger.Targets) <= 1 { // we should have empty alone metrics dictionary when there is only one target
 		trigger.AloneMetrics = map[string]bool{}
 	}
@@ -206,6 +230,10 @@ func (trigger *Trigger) Bind(request *http.Request) error {
 		return api.ErrInvalidRequestContent{ValidationError: err}
 	}
 
+	if err := checkExpressionWindowSanity(trigger, metricsSource); err != nil {
+		return api.ErrInvalidRequestContent{ValidationError: err}
+	}
+
 	metricsDataNames, err := resolvePatterns(trigger, &triggerExpression, metricsSource)
 	if err != nil {
 		return err
@@ -224,6 +252,17 @@ func (trigger *Trigger) Bind(request *http.Request) error {
 		return err
 	}
 
+	for _, override := range trigger.ThresholdOverrides {
+		if override.Expression == nil {
+			continue
+		}
+		overrideExpression := triggerExpression
+		overrideExpression.Expression = override.Expression
+		if _, err := overrideExpression.Evaluate(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -252,6 +291,15 @@ func checkTTLSanity(trigger *Trigger, metricsSource metricSource.MetricSource) e
 
 		case moira.PrometheusRemote:
 			triggerType = "prometheus remote"
+
+		case moira.TenantRemote:
+			triggerType = "tenant remote"
+
+		case moira.LokiRemote:
+			triggerType = "loki remote"
+
+		case moira.SQLRemote:
+			triggerType = "sql remote"
 		}
 
 		return fmt.Errorf("TTL for %s trigger can't be more than %d seconds", triggerType, maximumAllowedTTL)
@@ -259,6 +307,25 @@ func checkTTLSanity(trigger *Trigger, metricsSource metricSource.MetricSource) e
 	return nil
 }
 
+// checkExpressionWindowSanity checks that windows of aggregate functions in expression
+// and in expressions of threshold overrides fit into metrics TTL.
+func checkExpressionWindowSanity(trigger *Trigger, metricsSource metricSource.MetricSource) error {
+	if trigger.TriggerType != moira.ExpressionTrigger {
+		return nil
+	}
+
+	window, err := expression.MaxTriggerWindow(trigger.Expression, trigger.ThresholdOverrides)
+	if err != nil {
+		return err
+	}
+
+	maximumAllowedTTL := metricsSource.GetMetricsTTLSeconds()
+	if maximumAllowedTTL != 0 && int64(window.Seconds()) > maximumAllowedTTL {
+		return fmt.Errorf("window of expression functions can't be more than %d seconds", maximumAllowedTTL)
+	}
+	return nil
+}
+
 func resolvePatterns(trigger *Trigger, expressionValues *expression.TriggerExpression, metricsSource metricSource.MetricSource) (map[string]bool, error) {
 	now := time.Now().Unix()
 	targetNum := 1
@@ -363,6 +430,46 @@ func checkWarnErrorExpression(trigger *Trigger) error {
 	return nil
 }
 
+// checkThresholdOverrides checks that overrides match metrics and their thresholds fit trigger type.
+func checkThresholdOverrides(trigger *Trigger) error {
+	for i, override := range trigger.ThresholdOverrides {
+		if err := override.Validate(); err != nil {
+			return fmt.Errorf("threshold_overrides[%d]: %w", i, err)
+		}
+		if override.WarnValue == nil && override.ErrorValue == nil && override.Expression == nil {
+			return fmt.Errorf("threshold_overrides[%d]: at least one of error_value, warn_value or expression is required", i)
+		}
+
+		if trigger.TriggerType == moira.ExpressionTrigger {
+			if override.WarnValue != nil || override.ErrorValue != nil {
+				return fmt.Errorf("threshold_overrides[%d]: can't use 'warn_value' and 'error_value' on trigger_type: '%v'", i, moira.ExpressionTrigger)
+			}
+			continue
+		}
+
+		if override.Expression != nil {
+			return fmt.Errorf("threshold_overrides[%d]: can't use 'expression' on trigger_type: '%v'", i, trigger.TriggerType)
+		}
+		warnValue, errorValue := trigger.WarnValue, trigger.ErrorValue
+		if override.WarnValue != nil {
+			warnValue = override.WarnValue
+		}
+		if override.ErrorValue != nil {
+			errorValue = override.ErrorValue
+		}
+		if warnValue == nil || errorValue == nil {
+			continue
+		}
+		if trigger.TriggerType == moira.RisingTrigger && *warnValue > *errorValue {
+			return fmt.Errorf("threshold_overrides[%d]: error_value should be greater than warn_value", i)
+		}
+		if trigger.TriggerType == moira.FallingTrigger && *warnValue < *errorValue {
+			return fmt.Errorf("threshold_overrides[%d]: warn_value should be greater than error_value", i)
+		}
+	}
+	return nil
+}
+
 func checkSimpleModeFields(trigger *Trigger) error {
 	if len(trigger.Targets) > 1 {
 		return fmt.Errorf("can't use trigger_type not '%v' for with multiple targets", trigger.TriggerType)
@@ -446,6 +553,46 @@ func (*TriggerMetrics) Render(http.ResponseWriter, *http.Request) error {
 	return nil
 }
 
+// TriggerDryRun is a result of trigger check, which was neither saved nor sent to notifier.
+type TriggerDryRun struct {
+	// Fetched metrics values by target name and metric name
+	MetricsData TriggerMetrics `json:"metrics_data"`
+	// Expression evaluations by metric name
+	Steps map[string][]TriggerDryRunStep `json:"steps"`
+	// Events which would be sent to notifier
+	Events []moira.NotificationEvent `json:"events"`
+	// Trigger state which would be saved as last check
+	CheckData *moira.CheckData `json:"check_data"`
+}
+
+func (*TriggerDryRun) Render(http.ResponseWriter, *http.Request) error {
+	return nil
+}
+
+// TriggerDryRunStep is a result of trigger expression evaluation for one metric at one timestamp.
+type TriggerDryRunStep struct {
+	Timestamp int64                  `json:"timestamp" example:"1590741878" format:"int64"`
+	Values    map[string]float64     `json:"values"`
+	Variables map[string]interface{} `json:"variables"`
+	State     moira.State            `json:"state" example:"OK"`
+}
+
+// TriggerBacktest is a result of trigger checks simulated on historical data.
+type TriggerBacktest struct {
+	// Events which would have been sent to notifier
+	Events []moira.NotificationEvent `json:"events"`
+	// Number of events which return metric to the state it had before the previous event
+	FlapCount int `json:"flap_count" example:"3"`
+	// Trigger state at the end of the interval
+	CheckData *moira.CheckData `json:"check_data"`
+	// Base64 encoded PNG plot of simulated metrics states, if it was requested
+	Plot string `json:"plot,omitempty"`
+}
+
+func (*TriggerBacktest) Render(http.ResponseWriter, *http.Request) error {
+	return nil
+}
+
 type PatternMetrics struct {
 	Pattern    string                          `json:"pattern"`
 	Metrics    map[string][]*moira.MetricValue `json:"metrics"`
@@ -51,6 +51,13 @@ func TestTriggerValidation(t *testing.T) {
 			MuteNewMetrics: false,
 		}
 
+		Convey("Test external trigger", func() {
+			trigger.TriggerSource = moira.External
+			tr := Trigger{trigger, throttling}
+			err := tr.Bind(request)
+			So(err, ShouldResemble, api.ErrInvalidRequestContent{ValidationError: ErrExternalTriggerReadOnly})
+		})
+
 		Convey("Test FallingTrigger", func() {
 			localSource.EXPECT().GetMetricsTTLSeconds().Return(int64(3600)).AnyTimes()
 			localSource.EXPECT().Fetch(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(fetchResult, nil).AnyTimes()
@@ -233,6 +240,118 @@ func TestTriggerValidation(t *testing.T) {
 				So(err, ShouldResemble, api.ErrInvalidRequestContent{ValidationError: fmt.Errorf("pattern \"*\" is not allowed to use")})
 			})
 		})
+
+		Convey("Test fetch options", func() {
+			localSource.EXPECT().GetMetricsTTLSeconds().Return(int64(3600)).AnyTimes()
+			localSource.EXPECT().Fetch(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(fetchResult, nil).AnyTimes()
+			fetchResult.EXPECT().GetPatterns().Return(make([]string, 0), nil).AnyTimes()
+			fetchResult.EXPECT().GetMetricsData().Return([]metricSource.MetricData{*metricSource.MakeMetricData("", []float64{}, 0, 0)}).AnyTimes()
+
+			trigger.Targets = []string{"test target"}
+			trigger.Expression = "OK"
+			Convey("have valid step", func() {
+				trigger.FetchOptions = &moira.FetchOptions{Step: 15, Instant: true}
+				tr := Trigger{trigger, throttling}
+				err := tr.Bind(request)
+				So(err, ShouldBeNil)
+			})
+			Convey("have negative step", func() {
+				trigger.FetchOptions = &moira.FetchOptions{Step: -15}
+				tr := Trigger{trigger, throttling}
+				err := tr.Bind(request)
+				So(err, ShouldResemble, api.ErrInvalidRequestContent{ValidationError: fmt.Errorf("fetch_options.step should not be negative")})
+			})
+		})
+
+		Convey("Test expression functions", func() {
+			localSource.EXPECT().GetMetricsTTLSeconds().Return(int64(3600)).AnyTimes()
+			localSource.EXPECT().Fetch(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(fetchResult, nil).AnyTimes()
+			fetchResult.EXPECT().GetPatterns().Return(make([]string, 0), nil).AnyTimes()
+			fetchResult.EXPECT().GetMetricsData().Return([]metricSource.MetricData{*metricSource.MakeMetricData("", []float64{}, 0, 0)}).AnyTimes()
+
+			trigger.Targets = []string{"test target"}
+			Convey("have valid functions", func() {
+				trigger.Expression = "avg(t1, 5m) > 2*avg(t1, 1h) && abs(t1 - prev(t1)) > 10 ? ERROR : OK"
+				tr := Trigger{trigger, throttling}
+				err := tr.Bind(request)
+				So(err, ShouldBeNil)
+			})
+			Convey("have window larger than metrics ttl", func() {
+				trigger.Expression = "avg(t1, 2h) > 10 ? ERROR : OK"
+				tr := Trigger{trigger, throttling}
+				err := tr.Bind(request)
+				So(err, ShouldResemble, api.ErrInvalidRequestContent{ValidationError: fmt.Errorf("window of expression functions can't be more than 3600 seconds")})
+			})
+			Convey("have window of threshold override expression larger than metrics ttl", func() {
+				trigger.Expression = "avg(t1, 5m) > 10 ? ERROR : OK"
+				overrideExpression := "avg(t1, 2h) > 10 ? ERROR : OK"
+				trigger.ThresholdOverrides = []moira.ThresholdOverride{{Metric: "test.*", Expression: &overrideExpression}}
+				tr := Trigger{trigger, throttling}
+				err := tr.Bind(request)
+				So(err, ShouldResemble, api.ErrInvalidRequestContent{ValidationError: fmt.Errorf("window of expression functions can't be more than 3600 seconds")})
+			})
+			Convey("have function with unknown target", func() {
+				trigger.Expression = "avg(t2, 5m) > 10 ? ERROR : OK"
+				tr := Trigger{trigger, throttling}
+				err := tr.Bind(request)
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("Test threshold overrides", func() {
+			localSource.EXPECT().GetMetricsTTLSeconds().Return(int64(3600)).AnyTimes()
+			localSource.EXPECT().Fetch(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(fetchResult, nil).AnyTimes()
+			fetchResult.EXPECT().GetPatterns().Return(make([]string, 0), nil).AnyTimes()
+			fetchResult.EXPECT().GetMetricsData().Return([]metricSource.MetricData{*metricSource.MakeMetricData("", []float64{}, 0, 0)}).AnyTimes()
+
+			trigger.Targets = []string{"servers.*.cpu"}
+			trigger.TriggerType = moira.RisingTrigger
+			trigger.WarnValue = &errorValue
+			trigger.ErrorValue = &warnValue
+			overrideValue := float64(50)
+
+			Convey("have valid override", func() {
+				trigger.ThresholdOverrides = []moira.ThresholdOverride{{Metric: "servers.db-*.cpu", ErrorValue: &overrideValue}}
+				tr := Trigger{trigger, throttling}
+				err := tr.Bind(request)
+				So(err, ShouldBeNil)
+			})
+			Convey("have override without metric and tags", func() {
+				trigger.ThresholdOverrides = []moira.ThresholdOverride{{ErrorValue: &overrideValue}}
+				tr := Trigger{trigger, throttling}
+				err := tr.Bind(request)
+				So(err.Error(), ShouldEqual, "threshold_overrides[0]: metric or tags is required")
+			})
+			Convey("have override without values", func() {
+				trigger.ThresholdOverrides = []moira.ThresholdOverride{{Tags: []string{"host=db-1"}}}
+				tr := Trigger{trigger, throttling}
+				err := tr.Bind(request)
+				So(err, ShouldResemble, api.ErrInvalidRequestContent{ValidationError: fmt.Errorf("threshold_overrides[0]: at least one of error_value, warn_value or expression is required")})
+			})
+			Convey("have override with warn value greater than trigger error value", func() {
+				trigger.ThresholdOverrides = []moira.ThresholdOverride{{Tags: []string{"host=db-1"}, WarnValue: &overrideValue}}
+				tr := Trigger{trigger, throttling}
+				err := tr.Bind(request)
+				So(err, ShouldResemble, api.ErrInvalidRequestContent{ValidationError: fmt.Errorf("threshold_overrides[0]: error_value should be greater than warn_value")})
+			})
+			Convey("have override with expression on rising trigger", func() {
+				expression := "t1 > 10 ? ERROR : OK"
+				trigger.ThresholdOverrides = []moira.ThresholdOverride{{Metric: "servers.db-1.cpu", Expression: &expression}}
+				tr := Trigger{trigger, throttling}
+				err := tr.Bind(request)
+				So(err, ShouldResemble, api.ErrInvalidRequestContent{ValidationError: fmt.Errorf("threshold_overrides[0]: can't use 'expression' on trigger_type: 'rising'")})
+			})
+			Convey("have override with invalid expression", func() {
+				expression := "t1 > 10 ? ERROR :"
+				trigger.TriggerType = moira.ExpressionTrigger
+				trigger.WarnValue, trigger.ErrorValue = nil, nil
+				trigger.Expression = "t1 > 20 ? ERROR : OK"
+				trigger.ThresholdOverrides = []moira.ThresholdOverride{{Metric: "servers.db-1.cpu", Expression: &expression}}
+				tr := Trigger{trigger, throttling}
+				err := tr.Bind(request)
+				So(err, ShouldNotBeNil)
+			})
+		})
 	})
 }
 
@@ -367,6 +367,64 @@ func TestCreateNewContact(t *testing.T) {
 			So(actual, ShouldResemble, expected)
 			So(response.StatusCode, ShouldEqual, http.StatusInternalServerError)
 		})
+
+		Convey("Correctly create new contact with message template", func() {
+			newContactDto.Template = "{{ .State }} {{ .Trigger.Name }}"
+			defer func() {
+				newContactDto.Template = ""
+			}()
+
+			jsonContact, err := json.Marshal(newContactDto)
+			So(err, ShouldBeNil)
+
+			mockDb.EXPECT().GetContact(defaultContact).Return(moira.ContactData{}, db.ErrNil).Times(1)
+			mockDb.EXPECT().SaveContact(&moira.ContactData{
+				ID:       newContactDto.ID,
+				Type:     newContactDto.Type,
+				Value:    newContactDto.Value,
+				User:     newContactDto.User,
+				Template: newContactDto.Template,
+			}).Return(nil).Times(1)
+			database = mockDb
+
+			testRequest := httptest.NewRequest(http.MethodPut, "/contact", bytes.NewBuffer(jsonContact))
+			testRequest = testRequest.WithContext(middleware.SetContextValueForTest(testRequest.Context(), LoginKey, login))
+			testRequest.Header.Add("content-type", "application/json")
+
+			createNewContact(responseWriter, testRequest)
+
+			response := responseWriter.Result()
+			defer response.Body.Close()
+			So(response.StatusCode, ShouldEqual, http.StatusOK)
+		})
+
+		Convey("Trying to create a contact with invalid message template", func() {
+			newContactDto.Template = "{{ .Trigger.Unknown }}"
+			defer func() {
+				newContactDto.Template = ""
+			}()
+
+			jsonContact, err := json.Marshal(newContactDto)
+			So(err, ShouldBeNil)
+
+			testRequest := httptest.NewRequest(http.MethodPut, "/contact", bytes.NewBuffer(jsonContact))
+			testRequest = testRequest.WithContext(middleware.SetContextValueForTest(testRequest.Context(), LoginKey, login))
+			testRequest.Header.Add("content-type", "application/json")
+
+			createNewContact(responseWriter, testRequest)
+
+			response := responseWriter.Result()
+			defer response.Body.Close()
+			contentBytes, err := io.ReadAll(response.Body)
+			So(err, ShouldBeNil)
+			actual := &api.ErrorResponse{}
+			err = json.Unmarshal(contentBytes, actual)
+			So(err, ShouldBeNil)
+
+			So(actual.StatusText, ShouldEqual, "Invalid request")
+			So(actual.ErrorText, ShouldStartWith, "contact template is invalid: ")
+			So(response.StatusCode, ShouldEqual, http.StatusBadRequest)
+		})
 	})
 }
 
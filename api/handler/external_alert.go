@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
+	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/api/controller"
+	"github.com/moira-alert/moira/api/dto"
+	"github.com/moira-alert/moira/api/middleware"
+)
+
+func externalAlert(router chi.Router) {
+	router.Post("/", pushExternalAlerts)
+	router.Post("/alertmanager", pushAlertmanagerAlerts)
+}
+
+// nolint: gofmt,goimports
+//
+//	@summary	Push alerts of external alerting system as notification events of external triggers
+//	@id			push-external-alerts
+//	@tags		externalAlert
+//	@accept		json
+//	@produce	json
+//	@param		tags	query		string							false	"Comma separated tags added to every alert"	default(server,disk)
+//	@param		alerts	body		dto.ExternalAlerts				true	"External alerts"
+//	@success	200		{object}	dto.ExternalAlertsResponse		"Alerts pushed successfully"
+//	@failure	400		{object}	api.ErrorInvalidRequestExample	"Bad request from client"
+//	@failure	422		{object}	api.ErrorRenderExample			"Render error"
+//	@failure	500		{object}	api.ErrorInternalServerExample	"Internal server error"
+//	@router		/external-alert [post]
+func pushExternalAlerts(writer http.ResponseWriter, request *http.Request) {
+	alerts := &dto.ExternalAlerts{}
+	if err := render.Bind(request, alerts); err != nil {
+		render.Render(writer, request, api.ErrorInvalidRequest(err)) //nolint
+		return
+	}
+	tags := getExternalAlertTags(request)
+	for i := range alerts.Alerts {
+		alerts.Alerts[i].Tags = append(alerts.Alerts[i].Tags, tags...)
+	}
+	renderExternalAlertsResponse(writer, request, alerts.Alerts)
+}
+
+// nolint: gofmt,goimports
+//
+//	@summary	Push alerts of Alertmanager webhook receiver as notification events of external triggers
+//	@id			push-alertmanager-alerts
+//	@tags		externalAlert
+//	@accept		json
+//	@produce	json
+//	@param		tags	query		string							false	"Comma separated tags added to every alert"	default(server,disk)
+//	@param		webhook	body		dto.AlertmanagerWebhook			true	"Alertmanager webhook payload"
+//	@success	200		{object}	dto.ExternalAlertsResponse		"Alerts pushed successfully"
+//	@failure	400		{object}	api.ErrorInvalidRequestExample	"Bad request from client"
+//	@failure	422		{object}	api.ErrorRenderExample			"Render error"
+//	@failure	500		{object}	api.ErrorInternalServerExample	"Internal server error"
+//	@router		/external-alert/alertmanager [post]
+func pushAlertmanagerAlerts(writer http.ResponseWriter, request *http.Request) {
+	webhook := &dto.AlertmanagerWebhook{}
+	if err := render.Bind(request, webhook); err != nil {
+		render.Render(writer, request, api.ErrorInvalidRequest(err)) //nolint
+		return
+	}
+	renderExternalAlertsResponse(writer, request, webhook.ToExternalAlerts(getExternalAlertTags(request)))
+}
+
+func renderExternalAlertsResponse(writer http.ResponseWriter, request *http.Request, alerts []dto.ExternalAlert) {
+	userLogin := middleware.GetLogin(request)
+	response, err := controller.PushExternalAlerts(database, alerts, userLogin, time.Now().Unix())
+	if err != nil {
+		render.Render(writer, request, err) //nolint
+		return
+	}
+	if err := render.Render(writer, request, response); err != nil {
+		render.Render(writer, request, api.ErrorRender(err)) //nolint
+	}
+}
+
+// getExternalAlertTags returns tags from comma separated tags query parameter.
+func getExternalAlertTags(request *http.Request) []string {
+	tags := make([]string, 0)
+	for _, tag := range strings.Split(request.URL.Query().Get("tags"), ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
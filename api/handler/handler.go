@@ -94,6 +94,9 @@ func NewHandler(
 	//
 	//	@tag.name			user
 	//	@tag.description	APIs for interacting with Moira users
+	//
+	//	@tag.name			externalAlert
+	//	@tag.description	APIs for pushing alerts of external alerting systems, e.g. Alertmanager
 	router.Route("/api", func(router chi.Router) {
 		router.Use(moiramiddle.DatabaseContext(database))
 		router.Use(moiramiddle.AuthorizationContext(&apiConfig.Authorization))
@@ -108,6 +111,7 @@ func NewHandler(
 			router.Route("/tag", tag)
 			router.Route("/pattern", pattern)
 			router.Route("/event", event)
+			router.Route("/external-alert", externalAlert)
 			router.Route("/subscription", subscription)
 			router.Route("/notification", notification)
 			router.Route("/teams", teams)
@@ -28,24 +28,26 @@ func trigger(router chi.Router) {
 	router.Put("/setMaintenance", setTriggerMaintenance)
 	router.With(middleware.DateRange("-1hour", "now")).With(middleware.TargetName("t1")).Get("/render", renderTrigger)
 	router.Get("/dump", triggerDump)
+	router.With(middleware.DateRange("-7days", "now")).Get("/backtest", backtestTrigger)
 }
 
 // nolint: gofmt,goimports
 //
-//	@summary	Update existing trigger
-//	@id			update-trigger
-//	@tags		trigger
-//	@produce	json
-//	@param		triggerID	path		string									true	"Trigger ID"	default(bcba82f5-48cf-44c0-b7d6-e1d32c64a88c)
-//	@param		validate	query		bool									false	"For validating targets"
-//	@param		body		body		dto.Trigger								true	"Trigger data"
-//	@success	200			{object}	dto.SaveTriggerResponse					"Updated trigger"
-//	@failure	400			{object}	api.ErrorInvalidRequestExample			"Bad request from client"
-//	@failure	404			{object}	api.ErrorNotFoundExample				"Resource not found"
-//	@failure	422			{object}	api.ErrorRenderExample					"Render error"
-//	@failure	500			{object}	api.ErrorInternalServerExample			"Internal server error"
-//	@failure	503			{object}	api.ErrorRemoteServerUnavailableExample	"Remote server unavailable"
-//	@router		/trigger/{triggerID} [put]
+//	@summary		Update existing trigger
+//	@description	External triggers are read-only, they are changed only by pushed external alerts.
+//	@id				update-trigger
+//	@tags			trigger
+//	@produce		json
+//	@param			triggerID	path		string									true	"Trigger ID"	default(bcba82f5-48cf-44c0-b7d6-e1d32c64a88c)
+//	@param			validate	query		bool									false	"For validating targets"
+//	@param			body		body		dto.Trigger								true	"Trigger data"
+//	@success		200			{object}	dto.SaveTriggerResponse					"Updated trigger"
+//	@failure		400			{object}	api.ErrorInvalidRequestExample			"Bad request from client"
+//	@failure		404			{object}	api.ErrorNotFoundExample				"Resource not found"
+//	@failure		422			{object}	api.ErrorRenderExample					"Render error"
+//	@failure		500			{object}	api.ErrorInternalServerExample			"Internal server error"
+//	@failure		503			{object}	api.ErrorRemoteServerUnavailableExample	"Remote server unavailable"
+//	@router			/trigger/{triggerID} [put]
 func updateTrigger(writer http.ResponseWriter, request *http.Request) {
 	triggerID := middleware.GetTriggerID(request)
 
@@ -0,0 +1,141 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/render"
+	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/api/controller"
+	"github.com/moira-alert/moira/api/dto"
+	"github.com/moira-alert/moira/api/middleware"
+	"github.com/moira-alert/moira/plotting"
+)
+
+// nolint: gofmt,goimports
+//
+//	@summary		Backtest new trigger on historical data
+//	@description	Replays checks of the trigger on the given time range as if the checker had been checking it all that time.
+//	@description	Returns events that would have been generated and the number of flaps, nothing is saved and no events are sent
+//	@description	The time range is limited to 31 days and to 1000 chunks
+//	@id				backtest-new-trigger
+//	@tags			trigger
+//	@accept			json
+//	@produce		json
+//	@param			from		query		string									false	"Start time of the backtest"						default(-7days)
+//	@param			to			query		string									false	"End time of the backtest"							default(now)
+//	@param			chunk		query		string									false	"Interval of history fetched at once"				default(24h)
+//	@param			plot		query		bool									false	"Render plot of simulated states"					default(false)
+//	@param			timezone	query		string									false	"Timezone for rendering"							default(UTC)
+//	@param			theme		query		string									false	"Plot theme"										default(light)
+//	@param			trigger		body		dto.Trigger								true	"Trigger data"
+//	@success		200			{object}	dto.TriggerBacktest						"Trigger backtested successfully"
+//	@failure		400			{object}	api.ErrorInvalidRequestExample			"Bad request from client"
+//	@failure		422			{object}	api.ErrorRenderExample					"Render error"
+//	@failure		500			{object}	api.ErrorInternalServerExample			"Internal server error"
+//	@failure		503			{object}	api.ErrorRemoteServerUnavailableExample	"Remote server unavailable"
+//	@router			/trigger/backtest [put]
+func backtestNewTrigger(writer http.ResponseWriter, request *http.Request) {
+	trigger, errorResponse := getTriggerFromRequest(request)
+	if errorResponse != nil {
+		render.Render(writer, request, errorResponse) //nolint
+		return
+	}
+
+	renderBacktest(writer, request, &trigger.TriggerModel)
+}
+
+// nolint: gofmt,goimports
+//
+//	@summary		Backtest existing trigger on historical data
+//	@description	Replays checks of the trigger on the given time range as if the checker had been checking it all that time.
+//	@description	Returns events that would have been generated and the number of flaps, nothing is saved and no events are sent
+//	@description	The time range is limited to 31 days and to 1000 chunks
+//	@id				backtest-trigger
+//	@tags			trigger
+//	@produce		json
+//	@param			triggerID	path		string									true	"Trigger ID"										default(bcba82f5-48cf-44c0-b7d6-e1d32c64a88c)
+//	@param			from		query		string									false	"Start time of the backtest"						default(-7days)
+//	@param			to			query		string									false	"End time of the backtest"							default(now)
+//	@param			chunk		query		string									false	"Interval of history fetched at once"				default(24h)
+//	@param			plot		query		bool									false	"Render plot of simulated states"					default(false)
+//	@param			timezone	query		string									false	"Timezone for rendering"							default(UTC)
+//	@param			theme		query		string									false	"Plot theme"										default(light)
+//	@success		200			{object}	dto.TriggerBacktest						"Trigger backtested successfully"
+//	@failure		400			{object}	api.ErrorInvalidRequestExample			"Bad request from client"
+//	@failure		404			{object}	api.ErrorNotFoundExample				"Resource not found"
+//	@failure		422			{object}	api.ErrorRenderExample					"Render error"
+//	@failure		500			{object}	api.ErrorInternalServerExample			"Internal server error"
+//	@failure		503			{object}	api.ErrorRemoteServerUnavailableExample	"Remote server unavailable"
+//	@router			/trigger/{triggerID}/backtest [get]
+func backtestTrigger(writer http.ResponseWriter, request *http.Request) {
+	triggerID := middleware.GetTriggerID(request)
+	trigger, errorResponse := controller.GetTrigger(database, triggerID)
+	if errorResponse != nil {
+		render.Render(writer, request, errorResponse) //nolint
+		return
+	}
+
+	renderBacktest(writer, request, &trigger.TriggerModel)
+}
+
+func renderBacktest(writer http.ResponseWriter, request *http.Request, trigger *dto.TriggerModel) {
+	from, to, err := getDateRange(request)
+	if err != nil {
+		render.Render(writer, request, api.ErrorInvalidRequest(err)) //nolint
+		return
+	}
+
+	chunkSize, plotTemplate, err := getBacktestParameters(request)
+	if err != nil {
+		render.Render(writer, request, api.ErrorInvalidRequest(err)) //nolint
+		return
+	}
+
+	metricSourceProvider := middleware.GetTriggerTargetsSourceProvider(request)
+	logger := middleware.GetLoggerEntry(request)
+
+	response, errorResponse := controller.BacktestTrigger(database, metricSourceProvider, trigger, from, to, chunkSize, plotTemplate, logger)
+	if errorResponse != nil {
+		render.Render(writer, request, errorResponse) //nolint
+		return
+	}
+
+	if err := render.Render(writer, request, response); err != nil {
+		render.Render(writer, request, api.ErrorRender(err)) //nolint
+	}
+}
+
+// getBacktestParameters parses chunk size and returns plot template if plot of simulated states is requested.
+func getBacktestParameters(request *http.Request) (chunkSize int64, plotTemplate *plotting.Plot, err error) {
+	urlValues, err := url.ParseQuery(request.URL.RawQuery)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to parse query string: %w", err)
+	}
+
+	if chunk := urlValues.Get("chunk"); chunk != "" {
+		duration, err := time.ParseDuration(chunk)
+		if err != nil || duration < time.Minute {
+			return 0, nil, fmt.Errorf("invalid chunk param %s, it should be duration not less than 1m", chunk)
+		}
+		chunkSize = int64(duration.Seconds())
+	}
+
+	plot := urlValues.Get("plot")
+	if plot == "" {
+		return chunkSize, nil, nil
+	}
+	needPlot, err := strconv.ParseBool(plot)
+	if err != nil {
+		return 0, nil, fmt.Errorf("invalid plot param: %s", err.Error())
+	}
+	if !needPlot {
+		return chunkSize, nil, nil
+	}
+
+	plotTemplate, err = getPlotTemplate(request)
+	return chunkSize, plotTemplate, err
+}
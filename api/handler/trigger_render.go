@@ -114,6 +114,20 @@ func evaluateTargetMetrics(metricSourceProvider *metricSource.SourceProvider, fr
 }
 
 func buildRenderable(request *http.Request, trigger *moira.Trigger, metricsData []metricSource.MetricData, targetName string) (*chart.Chart, error) {
+	plotTemplate, err := getPlotTemplate(request)
+	if err != nil {
+		return nil, err
+	}
+
+	renderable, err := plotTemplate.GetRenderable(targetName, trigger, metricsData)
+	if err != nil {
+		return nil, err
+	}
+
+	return &renderable, err
+}
+
+func getPlotTemplate(request *http.Request) (*plotting.Plot, error) {
 	urlValues, err := url.ParseQuery(request.URL.RawQuery)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse query string: %w", err)
@@ -131,10 +145,5 @@ func buildRenderable(request *http.Request, trigger *moira.Trigger, metricsData
 		return nil, fmt.Errorf("can not initialize plot theme %s", err.Error())
 	}
 
-	renderable, err := plotTemplate.GetRenderable(targetName, trigger, metricsData)
-	if err != nil {
-		return nil, err
-	}
-
-	return &renderable, err
+	return plotTemplate, nil
 }
@@ -11,6 +11,7 @@ import (
 
 	"github.com/go-chi/chi"
 	"github.com/go-chi/render"
+	"github.com/go-graphite/carbonapi/date"
 	"github.com/moira-alert/moira"
 	metricSource "github.com/moira-alert/moira/metric_source"
 	"github.com/moira-alert/moira/metric_source/local"
@@ -33,6 +34,8 @@ func triggers(metricSourceProvider *metricSource.SourceProvider, searcher moira.
 
 		router.Put("/", createTrigger)
 		router.Put("/check", triggerCheck)
+		router.With(middleware.DateRange("-1hour", "now")).Put("/dry-run", triggerDryRun)
+		router.With(middleware.DateRange("-7days", "now")).Put("/backtest", backtestNewTrigger)
 		router.Route("/{triggerId}", trigger)
 		router.With(middleware.Paginate(0, 10)).With(middleware.Pager(false, "")).Get("/search", searchTriggers)
 		router.With(middleware.Pager(false, "")).Delete("/search/pager", deletePager)
@@ -236,6 +239,72 @@ func triggerCheck(writer http.ResponseWriter, request *http.Request) {
 	render.JSON(writer, request, response)
 }
 
+// nolint: gofmt,goimports
+//
+//	@summary		Check trigger without saving its state
+//	@description	Runs the trigger check on the given time range as the checker does, but nothing is saved and no events are sent.
+//	@description	Returns fetched values, evaluated expression variables and states for every metric and timestamp, and events that would be generated
+//	@id				trigger-dry-run
+//	@tags			trigger
+//	@accept			json
+//	@produce		json
+//	@param			from	query		string									false	"Start time of the check"	default(-1hour)
+//	@param			to		query		string									false	"End time of the check"		default(now)
+//	@param			trigger	body		dto.Trigger								true	"Trigger data"
+//	@success		200		{object}	dto.TriggerDryRun						"Trigger checked successfully"
+//	@failure		400		{object}	api.ErrorInvalidRequestExample			"Bad request from client"
+//	@failure		422		{object}	api.ErrorRenderExample					"Render error"
+//	@failure		500		{object}	api.ErrorInternalServerExample			"Internal server error"
+//	@failure		503		{object}	api.ErrorRemoteServerUnavailableExample	"Remote server unavailable"
+//	@router			/trigger/dry-run [put]
+func triggerDryRun(writer http.ResponseWriter, request *http.Request) {
+	trigger, errorResponse := getTriggerFromRequest(request)
+	if errorResponse != nil {
+		render.Render(writer, request, errorResponse) //nolint
+		return
+	}
+
+	from, to, err := getDateRange(request)
+	if err != nil {
+		render.Render(writer, request, api.ErrorInvalidRequest(err)) //nolint
+		return
+	}
+
+	metricSourceProvider := middleware.GetTriggerTargetsSourceProvider(request)
+	logger := middleware.GetLoggerEntry(request)
+
+	response, errorResponse := controller.DryRunTrigger(database, metricSourceProvider, &trigger.TriggerModel, from, to, logger)
+	if errorResponse != nil {
+		render.Render(writer, request, errorResponse) //nolint
+		return
+	}
+
+	if err := render.Render(writer, request, response); err != nil {
+		render.Render(writer, request, api.ErrorRender(err)) //nolint
+	}
+}
+
+// getDateRange parses from and to of the request, from should be less than to.
+func getDateRange(request *http.Request) (from, to int64, err error) {
+	fromStr := middleware.GetFromStr(request)
+	from = date.DateParamToEpoch(fromStr, "UTC", 0, time.UTC)
+	if from == 0 {
+		return 0, 0, fmt.Errorf("can not parse from: %s", fromStr)
+	}
+
+	toStr := middleware.GetToStr(request)
+	to = date.DateParamToEpoch(toStr, "UTC", 0, time.UTC)
+	if to == 0 {
+		return 0, 0, fmt.Errorf("can not parse to: %s", toStr)
+	}
+
+	if from >= to {
+		return 0, 0, fmt.Errorf("from should be less than to")
+	}
+
+	return from, to, nil
+}
+
 // nolint: gofmt,goimports
 //
 //	@summary		Search triggers. Replaces the deprecated `page` path
@@ -0,0 +1,184 @@
+package checker
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/moira-alert/moira"
+	metricSource "github.com/moira-alert/moira/metric_source"
+)
+
+// DefaultBacktestChunkSize is the interval of history, which is fetched and checked at once during backtest.
+const DefaultBacktestChunkSize int64 = 24 * 60 * 60
+
+const (
+	// MaxBacktestInterval is the maximum length of history in seconds, which can be backtested at once.
+	MaxBacktestInterval int64 = 31 * 24 * 60 * 60
+	// MaxBacktestChunks is the maximum number of chunks, every chunk is a separate fetch and check of the trigger.
+	MaxBacktestChunks int64 = 1000
+)
+
+// stateLevels are used to draw simulated states of metrics on plot.
+var stateLevels = map[moira.State]float64{
+	moira.StateOK:        0,
+	moira.StateWARN:      1,
+	moira.StateERROR:     2,
+	moira.StateNODATA:    3,
+	moira.StateEXCEPTION: 4,
+}
+
+// BacktestState is a simulated state of metric at timestamp.
+type BacktestState struct {
+	Timestamp int64       `json:"timestamp" example:"1590741878" format:"int64"`
+	State     moira.State `json:"state" example:"OK"`
+}
+
+// BacktestResult contains events, which the trigger would have produced on historical data.
+type BacktestResult struct {
+	// Events would be sent to notifier
+	Events []moira.NotificationEvent
+	// FlapCount is the number of events, which return metric to the state it had before the previous event
+	FlapCount int
+	// States are simulated states by metric name
+	States map[string][]BacktestState
+	// CheckData is the trigger state at the end of backtest
+	CheckData *moira.CheckData
+}
+
+// Backtest replays trigger checks on interval from..until as if the checker had been checking the trigger all that time.
+// History is fetched and checked by chunks of chunkSize seconds, the state of every chunk check is passed to the next one.
+// Database is used only for reading, nothing is saved.
+func Backtest(
+	trigger *moira.Trigger,
+	dataBase moira.Database,
+	source metricSource.MetricSource,
+	from, until, chunkSize int64,
+	logger moira.Logger,
+) (*BacktestResult, error) {
+	if chunkSize <= 0 {
+		chunkSize = DefaultBacktestChunkSize
+	}
+	if err := ValidateBacktestInterval(from, until, chunkSize); err != nil {
+		return nil, err
+	}
+
+	result := &BacktestResult{
+		Events: make([]moira.NotificationEvent, 0),
+		States: make(map[string][]BacktestState),
+	}
+	lastCheck := &moira.CheckData{
+		Metrics:                 make(map[string]moira.MetricState),
+		MetricsToTargetRelation: make(map[string]string),
+		State:                   moira.StateOK,
+	}
+	fetchFrom := from
+
+	for chunkFrom := from; chunkFrom < until; chunkFrom += chunkSize {
+		chunkUntil := chunkFrom + chunkSize
+		if chunkUntil > until {
+			chunkUntil = until
+		}
+		if lastCheck.Timestamp == 0 {
+			lastCheck.Timestamp = chunkUntil
+		}
+
+		chunkResult := &DryRunResult{
+			Steps:  make(map[string][]DryRunStep),
+			Events: make([]moira.NotificationEvent, 0),
+		}
+		triggerChecker := newDryRunChecker(trigger, dataBase, source, lastCheck, fetchFrom, chunkUntil, logger, chunkResult)
+		if err := triggerChecker.Check(); err != nil {
+			return nil, fmt.Errorf("failed to check trigger on interval %d..%d: %w", chunkFrom, chunkUntil, err)
+		}
+
+		result.Events = append(result.Events, chunkResult.Events...)
+		for metricName, steps := range chunkResult.Steps {
+			result.appendStates(metricName, steps)
+		}
+		if chunkResult.CheckData != nil {
+			lastCheck = chunkResult.CheckData
+			result.CheckData = chunkResult.CheckData
+		}
+		fetchFrom = calculateFrom(lastCheck.Timestamp, trigger.TTL)
+	}
+
+	result.FlapCount = countFlaps(result.Events)
+	return result, nil
+}
+
+// ValidateBacktestInterval checks that interval from..until and the number of its chunks do not exceed the limits.
+// Zero or negative chunkSize means DefaultBacktestChunkSize.
+func ValidateBacktestInterval(from, until, chunkSize int64) error {
+	if chunkSize <= 0 {
+		chunkSize = DefaultBacktestChunkSize
+	}
+	if until-from > MaxBacktestInterval {
+		return fmt.Errorf("backtest interval is %d seconds, it should not be longer than %d seconds", until-from, MaxBacktestInterval)
+	}
+	if chunks := (until - from + chunkSize - 1) / chunkSize; chunks > MaxBacktestChunks {
+		return fmt.Errorf("backtest interval is split into %d chunks, it should not be more than %d, increase chunk size", chunks, MaxBacktestChunks)
+	}
+	return nil
+}
+
+// appendStates saves simulated states of metric, steps checked again in the next chunk are skipped.
+func (result *BacktestResult) appendStates(metricName string, steps []DryRunStep) {
+	states := result.States[metricName]
+	for _, step := range steps {
+		if len(states) > 0 && step.Timestamp <= states[len(states)-1].Timestamp {
+			continue
+		}
+		states = append(states, BacktestState{Timestamp: step.Timestamp, State: step.State})
+	}
+	result.States[metricName] = states
+}
+
+// countFlaps counts events, which return metric to the state it had before the previous event of the same metric.
+func countFlaps(events []moira.NotificationEvent) int {
+	flaps := 0
+	previousEvents := make(map[string]moira.NotificationEvent)
+	for _, event := range events {
+		previous, ok := previousEvents[event.Metric]
+		if ok && event.State == previous.OldState && event.OldState == previous.State {
+			flaps++
+		}
+		previousEvents[event.Metric] = event
+	}
+	return flaps
+}
+
+// StatesMetricsData converts simulated states to metrics data to draw them on plot:
+// OK is 0, WARN is 1, ERROR is 2, NODATA is 3 and EXCEPTION is 4.
+func (result *BacktestResult) StatesMetricsData() []metricSource.MetricData {
+	metricNames := make([]string, 0, len(result.States))
+	for metricName := range result.States {
+		metricNames = append(metricNames, metricName)
+	}
+	sort.Strings(metricNames)
+
+	metricsData := make([]metricSource.MetricData, 0, len(metricNames))
+	for _, metricName := range metricNames {
+		states := result.States[metricName]
+		if len(states) == 0 {
+			continue
+		}
+
+		stepTime := int64(60) //nolint
+		if len(states) > 1 && states[1].Timestamp > states[0].Timestamp {
+			stepTime = states[1].Timestamp - states[0].Timestamp
+		}
+		startTime := states[0].Timestamp
+		values := make([]float64, (states[len(states)-1].Timestamp-startTime)/stepTime+1)
+		for i := range values {
+			values[i] = math.NaN()
+		}
+		for _, state := range states {
+			if index := (state.Timestamp - startTime) / stepTime; index < int64(len(values)) {
+				values[index] = stateLevels[state.State]
+			}
+		}
+		metricsData = append(metricsData, *metricSource.MakeMetricData(metricName, values, stepTime, startTime))
+	}
+	return metricsData
+}
@@ -0,0 +1,104 @@
+package checker
+
+import (
+	"math"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	logging "github.com/moira-alert/moira/logging/zerolog_adapter"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/moira-alert/moira"
+	metricSource "github.com/moira-alert/moira/metric_source"
+	mock_metric_source "github.com/moira-alert/moira/mock/metric_source"
+	mock_moira_alert "github.com/moira-alert/moira/mock/moira-alert"
+)
+
+func TestBacktest(t *testing.T) {
+	Convey("Backtest of trigger on history", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+		dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+		source := mock_metric_source.NewMockMetricSource(mockCtrl)
+		fetchResult := mock_metric_source.NewMockFetchResult(mockCtrl)
+		logger, _ := logging.GetLogger("Test")
+
+		warnValue := float64(10)
+		trigger := &moira.Trigger{
+			ID:          "backtest",
+			Name:        "Backtest trigger",
+			WarnValue:   &warnValue,
+			TriggerType: moira.RisingTrigger,
+			Targets:     []string{"metric.*"},
+			TTL:         600,
+		}
+		metricData := metricSource.MakeMetricData("metric.one", []float64{5, 15, 5, 15, 5}, 60, 3600)
+
+		gomock.InOrder(
+			source.EXPECT().Fetch("metric.*", int64(3600), int64(3780), true).Return(fetchResult, nil),
+			source.EXPECT().Fetch("metric.*", int64(3180), int64(3900), true).Return(fetchResult, nil),
+		)
+		fetchResult.EXPECT().GetMetricsData().Return([]metricSource.MetricData{*metricData}).AnyTimes()
+		fetchResult.EXPECT().GetPatternMetrics().Return([]string{}, nil).AnyTimes()
+
+		result, err := Backtest(trigger, dataBase, source, 3600, 3900, 180, logger)
+		So(err, ShouldBeNil)
+
+		Convey("events of all chunks are returned", func() {
+			states := make([]moira.State, 0, len(result.Events))
+			for _, event := range result.Events {
+				states = append(states, event.State)
+			}
+			So(states, ShouldResemble, []moira.State{moira.StateOK, moira.StateWARN, moira.StateOK, moira.StateWARN, moira.StateOK})
+			So(result.CheckData.Metrics["metric.one"].State, ShouldEqual, moira.StateOK)
+		})
+
+		Convey("returns of metric to the previous state are counted as flaps", func() {
+			So(result.FlapCount, ShouldEqual, 3)
+		})
+
+		Convey("every step is simulated once", func() {
+			So(result.States["metric.one"], ShouldResemble, []BacktestState{
+				{Timestamp: 3600, State: moira.StateOK},
+				{Timestamp: 3660, State: moira.StateWARN},
+				{Timestamp: 3720, State: moira.StateOK},
+				{Timestamp: 3780, State: moira.StateWARN},
+				{Timestamp: 3840, State: moira.StateOK},
+			})
+		})
+	})
+}
+
+func TestValidateBacktestInterval(t *testing.T) {
+	Convey("Backtest interval is limited", t, func() {
+		So(ValidateBacktestInterval(0, MaxBacktestInterval, 0), ShouldBeNil)
+		So(ValidateBacktestInterval(0, MaxBacktestInterval+1, 0), ShouldNotBeNil)
+		So(ValidateBacktestInterval(0, 60*MaxBacktestChunks, 60), ShouldBeNil)
+		So(ValidateBacktestInterval(0, 60*MaxBacktestChunks+1, 60), ShouldNotBeNil)
+
+		_, err := Backtest(&moira.Trigger{}, nil, nil, 0, MaxBacktestInterval+1, 0, nil)
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestBacktestStatesMetricsData(t *testing.T) {
+	Convey("Simulated states are converted to metrics data", t, func() {
+		result := &BacktestResult{States: map[string][]BacktestState{
+			"metric.two": {{Timestamp: 60, State: moira.StateERROR}},
+			"metric.one": {
+				{Timestamp: 60, State: moira.StateOK},
+				{Timestamp: 120, State: moira.StateWARN},
+				{Timestamp: 240, State: moira.StateNODATA},
+			},
+		}}
+
+		metricsData := result.StatesMetricsData()
+		So(metricsData, ShouldHaveLength, 2)
+		So(metricsData[0].Name, ShouldEqual, "metric.one")
+		So(metricsData[0].StepTime, ShouldEqual, 60)
+		So(metricsData[0].Values[:2], ShouldResemble, []float64{0, 1})
+		So(math.IsNaN(metricsData[0].Values[2]), ShouldBeTrue)
+		So(metricsData[0].Values[3], ShouldEqual, 3)
+		So(metricsData[1].Values, ShouldResemble, []float64{2})
+	})
+}
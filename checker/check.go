@@ -490,7 +490,7 @@ func (triggerChecker *TriggerChecker) getMetricStepsStates(
 	valueTimestamp := startTime + stepTime*stepsDifference
 	endTimestamp := triggerChecker.until + stepTime
 	for ; valueTimestamp < endTimestamp; valueTimestamp += stepTime {
-		metricNewState, err := triggerChecker.getMetricDataState(metrics, &previousState, &valueTimestamp, &checkPoint, logger)
+		metricNewState, err := triggerChecker.getMetricDataState(metricName, metrics, &previousState, &valueTimestamp, &checkPoint, logger)
 		if err != nil {
 			return last, current, err
 		}
@@ -500,10 +500,14 @@ func (triggerChecker *TriggerChecker) getMetricStepsStates(
 		previousState = *metricNewState
 		current = append(current, *metricNewState)
 	}
+	if triggerChecker.dryRun != nil {
+		triggerChecker.recordDryRunSteps(metricName, last, current)
+	}
 	return last, current, nil
 }
 
 func (triggerChecker *TriggerChecker) getMetricDataState(
+	metricName string,
 	metrics map[string]metricSource.MetricData,
 	lastState *moira.MetricState,
 	valueTimestamp, checkPoint *int64,
@@ -524,11 +528,12 @@ func (triggerChecker *TriggerChecker) getMetricDataState(
 		Interface("additional_target_values", triggerExpression.AdditionalTargetsValues).
 		Msg("Getting metric data state")
 
-	triggerExpression.WarnValue = triggerChecker.trigger.WarnValue
-	triggerExpression.ErrorValue = triggerChecker.trigger.ErrorValue
+	thresholds := triggerChecker.getMetricThresholds(metricName)
+	triggerExpression.WarnValue = thresholds.WarnValue
+	triggerExpression.ErrorValue = thresholds.ErrorValue
 	triggerExpression.TriggerType = triggerChecker.trigger.TriggerType
 	triggerExpression.PreviousState = lastState.State
-	triggerExpression.Expression = triggerChecker.trigger.Expression
+	triggerExpression.Expression = thresholds.Expression
 
 	expressionState, err := triggerExpression.Evaluate()
 	if err != nil {
@@ -543,6 +548,23 @@ func (triggerChecker *TriggerChecker) getMetricDataState(
 	), nil
 }
 
+// getMetricThresholds returns thresholds of metric taking trigger overrides into account.
+// Overrides are resolved once per metric during the check.
+func (triggerChecker *TriggerChecker) getMetricThresholds(metricName string) moira.MetricThresholds {
+	if len(triggerChecker.trigger.ThresholdOverrides) == 0 {
+		return triggerChecker.trigger.GetMetricThresholds(metricName)
+	}
+	if thresholds, ok := triggerChecker.metricsThresholds[metricName]; ok {
+		return thresholds
+	}
+	if triggerChecker.metricsThresholds == nil {
+		triggerChecker.metricsThresholds = make(map[string]moira.MetricThresholds)
+	}
+	thresholds := triggerChecker.trigger.GetMetricThresholds(metricName)
+	triggerChecker.metricsThresholds[metricName] = thresholds
+	return thresholds
+}
+
 func getExpressionValues(
 	metrics map[string]metricSource.MetricData,
 	valueTimestamp *int64,
@@ -554,6 +576,8 @@ func getExpressionValues(
 ) {
 	expression := &expression.TriggerExpression{
 		AdditionalTargetsValues: make(map[string]float64, len(metrics)-1),
+		Metrics:                 metrics,
+		Timestamp:               *valueTimestamp,
 	}
 
 	values = make(map[string]float64, len(metrics))
@@ -66,7 +66,7 @@ func TestGetMetricDataState(t *testing.T) {
 	var valueTimestamp int64 = 37
 	var checkPoint int64 = 47
 	Convey("Checkpoint more than valueTimestamp", t, func() {
-		metricState, err := triggerChecker.getMetricDataState(metrics, &metricLastState, &valueTimestamp, &checkPoint, logger)
+		metricState, err := triggerChecker.getMetricDataState("main.metric", metrics, &metricLastState, &valueTimestamp, &checkPoint, logger)
 		So(err, ShouldBeNil)
 		So(metricState, ShouldBeNil)
 	})
@@ -75,7 +75,7 @@ func TestGetMetricDataState(t *testing.T) {
 		Convey("Has all value by eventTimestamp step", func() {
 			var valueTimestamp int64 = 42
 			var checkPoint int64 = 27
-			metricState, err := triggerChecker.getMetricDataState(metrics, &metricLastState, &valueTimestamp, &checkPoint, logger)
+			metricState, err := triggerChecker.getMetricDataState("main.metric", metrics, &metricLastState, &valueTimestamp, &checkPoint, logger)
 			So(err, ShouldBeNil)
 			So(metricState, ShouldResemble, &moira.MetricState{
 				State:          moira.StateOK,
@@ -90,7 +90,7 @@ func TestGetMetricDataState(t *testing.T) {
 		Convey("No value in main metric data by eventTimestamp step", func() {
 			var valueTimestamp int64 = 66
 			var checkPoint int64 = 11
-			metricState, err := triggerChecker.getMetricDataState(metrics, &metricLastState, &valueTimestamp, &checkPoint, logger)
+			metricState, err := triggerChecker.getMetricDataState("main.metric", metrics, &metricLastState, &valueTimestamp, &checkPoint, logger)
 			So(err, ShouldBeNil)
 			So(metricState, ShouldBeNil)
 		})
@@ -98,7 +98,7 @@ func TestGetMetricDataState(t *testing.T) {
 		Convey("IsAbsent in main metric data by eventTimestamp step", func() {
 			var valueTimestamp int64 = 29
 			var checkPoint int64 = 11
-			metricState, err := triggerChecker.getMetricDataState(metrics, &metricLastState, &valueTimestamp, &checkPoint, logger)
+			metricState, err := triggerChecker.getMetricDataState("main.metric", metrics, &metricLastState, &valueTimestamp, &checkPoint, logger)
 			So(err, ShouldBeNil)
 			So(metricState, ShouldBeNil)
 		})
@@ -106,18 +106,37 @@ func TestGetMetricDataState(t *testing.T) {
 		Convey("No value in additional metric data by eventTimestamp step", func() {
 			var valueTimestamp int64 = 26
 			var checkPoint int64 = 11
-			metricState, err := triggerChecker.getMetricDataState(metrics, &metricLastState, &valueTimestamp, &checkPoint, logger)
+			metricState, err := triggerChecker.getMetricDataState("main.metric", metrics, &metricLastState, &valueTimestamp, &checkPoint, logger)
 			So(err, ShouldBeNil)
 			So(metricState, ShouldBeNil)
 		})
 	})
 
+	Convey("Threshold override of metric is used", t, func() {
+		overrideWarnValue := float64(2)
+		triggerChecker.trigger.ThresholdOverrides = []moira.ThresholdOverride{{Metric: "main.*", WarnValue: &overrideWarnValue}}
+		defer func() {
+			triggerChecker.trigger.ThresholdOverrides = nil
+			triggerChecker.metricsThresholds = nil
+		}()
+
+		var valueTimestamp int64 = 42
+		var checkPoint int64 = 27
+		metricState, err := triggerChecker.getMetricDataState("main.metric", metrics, &metricLastState, &valueTimestamp, &checkPoint, logger)
+		So(err, ShouldBeNil)
+		So(metricState.State, ShouldEqual, moira.StateWARN)
+
+		metricState, err = triggerChecker.getMetricDataState("other.metric", metrics, &metricLastState, &valueTimestamp, &checkPoint, logger)
+		So(err, ShouldBeNil)
+		So(metricState.State, ShouldEqual, moira.StateOK)
+	})
+
 	Convey("No warn and error value with default expression", t, func() {
 		triggerChecker.trigger.WarnValue = nil
 		triggerChecker.trigger.ErrorValue = nil
 		var valueTimestamp int64 = 42
 		var checkPoint int64 = 27
-		metricState, err := triggerChecker.getMetricDataState(metrics, &metricLastState, &valueTimestamp, &checkPoint, logger)
+		metricState, err := triggerChecker.getMetricDataState("main.metric", metrics, &metricLastState, &valueTimestamp, &checkPoint, logger)
 		So(err.Error(), ShouldResemble, "error value and warning value can not be empty")
 		So(metricState, ShouldBeNil)
 	})
@@ -1688,6 +1707,8 @@ func TestGetExpressionValues(t *testing.T) {
 		Convey("first value is valid", func() {
 			expectedExpression := &expression.TriggerExpression{
 				AdditionalTargetsValues: make(map[string]float64),
+				Metrics:                 metrics,
+				Timestamp:               17,
 			}
 			expectedValues := map[string]float64{"t1": 0}
 
@@ -1720,6 +1741,8 @@ func TestGetExpressionValues(t *testing.T) {
 			expectedExpression := &expression.TriggerExpression{
 				MainTargetValue:         3,
 				AdditionalTargetsValues: make(map[string]float64),
+				Metrics:                 metrics,
+				Timestamp:               53,
 			}
 			expectedValues := map[string]float64{"t1": 3}
 
@@ -0,0 +1,173 @@
+package checker
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/moira-alert/moira"
+	metricSource "github.com/moira-alert/moira/metric_source"
+	"github.com/moira-alert/moira/metrics"
+)
+
+// DryRunStep is a result of trigger expression evaluation for one metric at one timestamp.
+type DryRunStep struct {
+	Timestamp int64                  `json:"timestamp" example:"1590741878" format:"int64"`
+	Values    map[string]float64     `json:"values"`
+	Variables map[string]interface{} `json:"variables"`
+	State     moira.State            `json:"state" example:"OK"`
+}
+
+// DryRunResult contains everything the trigger check would do, but nothing of it is saved.
+type DryRunResult struct {
+	// MetricsData is fetched data by target name: t1, t2, ...
+	MetricsData map[string][]metricSource.MetricData
+	// Steps are expression evaluations by metric name
+	Steps map[string][]DryRunStep
+	// Events would be sent to notifier
+	Events []moira.NotificationEvent
+	// CheckData would be saved as trigger last check
+	CheckData *moira.CheckData
+}
+
+// DryRun runs full trigger check pipeline on interval from..until for given trigger, as if trigger had no previous checks.
+// Database is used only for reading, events and last check are returned in result instead of saving.
+func DryRun(
+	trigger *moira.Trigger,
+	dataBase moira.Database,
+	source metricSource.MetricSource,
+	from, until int64,
+	logger moira.Logger,
+) (*DryRunResult, error) {
+	result := &DryRunResult{
+		MetricsData: make(map[string][]metricSource.MetricData, len(trigger.Targets)),
+		Steps:       make(map[string][]DryRunStep),
+		Events:      make([]moira.NotificationEvent, 0),
+	}
+	recordingSource := &dryRunSource{MetricSource: source, fetched: make(map[string][]metricSource.MetricData)}
+	lastCheck := &moira.CheckData{
+		Metrics:                 make(map[string]moira.MetricState),
+		MetricsToTargetRelation: make(map[string]string),
+		State:                   moira.StateOK,
+		Timestamp:               until,
+	}
+	triggerChecker := newDryRunChecker(trigger, dataBase, recordingSource, lastCheck, from, until, logger, result)
+
+	if err := triggerChecker.Check(); err != nil {
+		return nil, err
+	}
+
+	for i, target := range trigger.Targets {
+		result.MetricsData[fmt.Sprintf("t%d", i+1)] = recordingSource.fetched[target]
+	}
+	return result, nil
+}
+
+// newDryRunChecker makes trigger checker, which collects its steps, events and last check in given result
+// instead of saving them. Metrics are fetched since from, with additional history for expression functions.
+func newDryRunChecker(
+	trigger *moira.Trigger,
+	dataBase moira.Database,
+	source metricSource.MetricSource,
+	lastCheck *moira.CheckData,
+	from, until int64,
+	logger moira.Logger,
+	result *DryRunResult,
+) *TriggerChecker {
+	return &TriggerChecker{
+		database: &dryRunDatabase{Database: dataBase, result: result},
+		logger:   logger,
+		config:   &Config{},
+		metrics:  metrics.ConfigureCheckerMetrics(metrics.NewDummyRegistry(), []moira.ClusterKey{trigger.ClusterKey()}).MetricsBySource[trigger.ClusterKey()],
+		source:   source,
+
+		from:  from - expressionWindow(trigger, logger),
+		until: until,
+
+		triggerID: trigger.ID,
+		trigger:   trigger,
+		lastCheck: lastCheck,
+
+		ttl:      trigger.TTL,
+		ttlState: getTTLState(trigger.TTLState),
+		dryRun:   result,
+	}
+}
+
+// recordDryRunSteps saves expression evaluations of metric states, which were calculated since previous state.
+// It is called only if trigger checker runs in dry run mode, so regular checks do not pay for it.
+func (triggerChecker *TriggerChecker) recordDryRunSteps(metricName string, previousState moira.MetricState, states []moira.MetricState) {
+	thresholds := triggerChecker.getMetricThresholds(metricName)
+	for _, newState := range states {
+		variables := make(map[string]interface{}, len(newState.Values)+3)
+		for name, value := range newState.Values {
+			variables[name] = value
+		}
+		if thresholds.WarnValue != nil {
+			variables["WARN_VALUE"] = *thresholds.WarnValue
+		}
+		if thresholds.ErrorValue != nil {
+			variables["ERROR_VALUE"] = *thresholds.ErrorValue
+		}
+		variables["PREV_STATE"] = previousState.State
+
+		triggerChecker.dryRun.Steps[metricName] = append(triggerChecker.dryRun.Steps[metricName], DryRunStep{
+			Timestamp: newState.Timestamp,
+			Values:    newState.Values,
+			Variables: variables,
+			State:     newState.State,
+		})
+		previousState = newState
+	}
+}
+
+// dryRunSource remembers fetched data of every target.
+type dryRunSource struct {
+	metricSource.MetricSource
+	mutex   sync.Mutex
+	fetched map[string][]metricSource.MetricData
+}
+
+func (source *dryRunSource) Fetch(target string, from, until int64, allowRealTimeAlerting bool) (metricSource.FetchResult, error) {
+	fetchResult, err := source.MetricSource.Fetch(target, from, until, allowRealTimeAlerting)
+	return source.remember(target, fetchResult, err)
+}
+
+func (source *dryRunSource) FetchWithOptions(target string, from, until int64, allowRealTimeAlerting bool, options moira.FetchOptions) (metricSource.FetchResult, error) {
+	fetchResult, err := metricSource.FetchWithOptions(source.MetricSource, target, from, until, allowRealTimeAlerting, &options)
+	return source.remember(target, fetchResult, err)
+}
+
+func (source *dryRunSource) remember(target string, fetchResult metricSource.FetchResult, err error) (metricSource.FetchResult, error) {
+	if err != nil {
+		return nil, err
+	}
+
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+	source.fetched[target] = fetchResult.GetMetricsData()
+	return fetchResult, nil
+}
+
+// dryRunDatabase reads from real database, but keeps all writes of trigger checker in dry run result.
+type dryRunDatabase struct {
+	moira.Database
+	result *DryRunResult
+}
+
+func (db *dryRunDatabase) SetTriggerLastCheck(_ string, checkData *moira.CheckData, _ moira.ClusterKey) error {
+	db.result.CheckData = checkData
+	return nil
+}
+
+func (db *dryRunDatabase) PushNotificationEvent(event *moira.NotificationEvent, _ bool) error {
+	db.result.Events = append(db.result.Events, *event)
+	return nil
+}
+
+func (db *dryRunDatabase) RemovePatternsMetrics([]string) error {
+	return nil
+}
+
+func (db *dryRunDatabase) RemoveMetricsValues([]string, int64) error {
+	return nil
+}
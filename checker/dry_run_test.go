@@ -0,0 +1,72 @@
+package checker
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	logging "github.com/moira-alert/moira/logging/zerolog_adapter"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/moira-alert/moira"
+	metricSource "github.com/moira-alert/moira/metric_source"
+	mock_metric_source "github.com/moira-alert/moira/mock/metric_source"
+	mock_moira_alert "github.com/moira-alert/moira/mock/moira-alert"
+)
+
+func TestDryRun(t *testing.T) {
+	Convey("Dry run of trigger check", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+		dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+		source := mock_metric_source.NewMockMetricSource(mockCtrl)
+		fetchResult := mock_metric_source.NewMockFetchResult(mockCtrl)
+		logger, _ := logging.GetLogger("Test")
+
+		warnValue := float64(10)
+		errorValue := float64(20)
+		trigger := &moira.Trigger{
+			ID:          "dry-run",
+			Name:        "Dry run trigger",
+			WarnValue:   &warnValue,
+			ErrorValue:  &errorValue,
+			TriggerType: moira.RisingTrigger,
+			Targets:     []string{"metric.*"},
+			TTL:         600,
+		}
+		metricData := metricSource.MakeMetricData("metric.one", []float64{5, 15, 25, 5}, 60, 3600)
+
+		source.EXPECT().Fetch("metric.*", int64(3600), int64(3900), true).Return(fetchResult, nil)
+		fetchResult.EXPECT().GetMetricsData().Return([]metricSource.MetricData{*metricData}).AnyTimes()
+		fetchResult.EXPECT().GetPatternMetrics().Return([]string{}, nil)
+
+		result, err := DryRun(trigger, dataBase, source, 3600, 3900, logger)
+		So(err, ShouldBeNil)
+
+		Convey("fetched data is returned by target name", func() {
+			So(result.MetricsData, ShouldResemble, map[string][]metricSource.MetricData{"t1": {*metricData}})
+		})
+
+		Convey("every step is returned with values, variables and state", func() {
+			steps := result.Steps["metric.one"]
+			So(steps, ShouldHaveLength, 4)
+			So(steps[1], ShouldResemble, DryRunStep{
+				Timestamp: 3660,
+				Values:    map[string]float64{"t1": 15},
+				Variables: map[string]interface{}{"t1": float64(15), "WARN_VALUE": warnValue, "ERROR_VALUE": errorValue, "PREV_STATE": moira.StateOK},
+				State:     moira.StateWARN,
+			})
+			So(steps[2].State, ShouldEqual, moira.StateERROR)
+			So(steps[3].State, ShouldEqual, moira.StateOK)
+		})
+
+		Convey("events and last check are returned instead of saving", func() {
+			So(result.Events, ShouldHaveLength, 4)
+			So(result.Events[0].OldState, ShouldEqual, moira.StateNODATA)
+			So(result.Events[0].State, ShouldEqual, moira.StateOK)
+			So(result.Events[1].State, ShouldEqual, moira.StateWARN)
+			So(result.Events[2].State, ShouldEqual, moira.StateERROR)
+			So(result.Events[3].State, ShouldEqual, moira.StateOK)
+			So(result.CheckData.Metrics["metric.one"].State, ShouldEqual, moira.StateOK)
+		})
+	})
+}
@@ -34,7 +34,7 @@ func (triggerChecker *TriggerChecker) fetch() (map[string][]metricSource.MetricD
 	isSimpleTrigger := triggerChecker.trigger.IsSimple()
 	for targetIndex, target := range triggerChecker.trigger.Targets {
 		targetIndex++ // increasing target index to have target names started from 1 instead of 0
-		fetchResult, err := triggerChecker.source.Fetch(target, triggerChecker.from, triggerChecker.until, isSimpleTrigger)
+		fetchResult, err := metricSource.FetchWithOptions(triggerChecker.source, target, triggerChecker.from, triggerChecker.until, isSimpleTrigger, triggerChecker.trigger.FetchOptions)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/moira-alert/moira"
 	"github.com/moira-alert/moira/database"
+	"github.com/moira-alert/moira/expression"
 	metricSource "github.com/moira-alert/moira/metric_source"
 	"github.com/moira-alert/moira/metrics"
 )
@@ -27,6 +28,12 @@ type TriggerChecker struct {
 
 	ttl      int64
 	ttlState moira.TTLState
+
+	// metricsThresholds are thresholds of metrics resolved from trigger overrides
+	metricsThresholds map[string]moira.MetricThresholds
+
+	// dryRun collects expression evaluations if trigger is checked in dry run mode, it is nil for regular checks
+	dryRun *DryRunResult
 }
 
 // MakeTriggerChecker initialize new triggerChecker data.
@@ -80,7 +87,7 @@ func MakeTriggerChecker(
 		metrics:  triggerMetrics,
 		source:   source,
 
-		from:  calculateFrom(lastCheck.Timestamp, trigger.TTL),
+		from:  calculateFrom(lastCheck.Timestamp, trigger.TTL) - expressionWindow(&trigger, triggerLogger),
 		until: until,
 
 		triggerID: triggerID,
@@ -121,6 +128,22 @@ func getTTLState(triggerTTLState *moira.TTLState) moira.TTLState {
 	return moira.TTLStateNODATA
 }
 
+// expressionWindow returns window of aggregate functions in trigger expression and expressions of threshold overrides
+// in seconds, the checker has to fetch that much history in addition to the usual interval.
+func expressionWindow(trigger *moira.Trigger, logger moira.Logger) int64 {
+	if trigger.TriggerType != moira.ExpressionTrigger {
+		return 0
+	}
+	window, err := expression.MaxTriggerWindow(moira.UseString(trigger.Expression), trigger.ThresholdOverrides)
+	if err != nil {
+		logger.Warning().
+			Error(err).
+			Msg("Failed to get window of trigger expression")
+		return 0
+	}
+	return int64(window.Seconds())
+}
+
 func calculateFrom(lastCheckTimestamp, triggerTTL int64) int64 {
 	if triggerTTL != 0 {
 		return lastCheckTimestamp - triggerTTL
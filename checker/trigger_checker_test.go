@@ -218,3 +218,24 @@ func TestInitTriggerChecker(t *testing.T) {
 		So(*actual, ShouldResemble, expected)
 	})
 }
+
+func TestExpressionWindow(t *testing.T) {
+	logger, _ := logging.GetLogger("Test")
+	expression := "avg(t1, 5m) > 10 ? ERROR : OK"
+	overrideExpression := "avg(t1, 1h) > 10 ? ERROR : OK"
+
+	Convey("Window of expression trigger includes windows of threshold overrides", t, func() {
+		trigger := &moira.Trigger{
+			TriggerType:        moira.ExpressionTrigger,
+			Expression:         &expression,
+			ThresholdOverrides: []moira.ThresholdOverride{{Metric: "metric.*", Expression: &overrideExpression}},
+		}
+		So(expressionWindow(trigger, logger), ShouldEqual, 3600)
+
+		trigger.ThresholdOverrides = nil
+		So(expressionWindow(trigger, logger), ShouldEqual, 300)
+
+		trigger.TriggerType = moira.RisingTrigger
+		So(expressionWindow(trigger, logger), ShouldEqual, 0)
+	})
+}
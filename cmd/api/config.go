@@ -20,6 +20,7 @@ type config struct {
 	Telemetry           cmd.TelemetryConfig           `yaml:"telemetry"`
 	Remotes             cmd.RemotesConfig             `yaml:",inline"`
 	NotificationHistory cmd.NotificationHistoryConfig `yaml:"notification_history"`
+	Plotting            cmd.PlottingConfig            `yaml:"plotting"`
 }
 
 // ClustersMetricTTL parses TTLs of all clusters provided in config.
@@ -38,6 +39,21 @@ func (config *config) ClustersMetricTTL() map[moira.ClusterKey]time.Duration {
 		result[key] = to.Duration(remote.MetricsTTL)
 	}
 
+	for _, remote := range config.Remotes.Tenant {
+		key := moira.MakeClusterKey(moira.TenantRemote, remote.ClusterId)
+		result[key] = to.Duration(remote.MetricsTTL)
+	}
+
+	for _, remote := range config.Remotes.Loki {
+		key := moira.MakeClusterKey(moira.LokiRemote, remote.ClusterId)
+		result[key] = to.Duration(remote.MetricsTTL)
+	}
+
+	for _, remote := range config.Remotes.SQL {
+		key := moira.MakeClusterKey(moira.SQLRemote, remote.ClusterId)
+		result[key] = to.Duration(remote.MetricsTTL)
+	}
+
 	return result
 }
 
@@ -167,6 +183,33 @@ func (config *webConfig) getSettings(isRemoteEnabled bool, remotes cmd.RemotesCo
 		clusters = append(clusters, cluster)
 	}
 
+	for _, remote := range remotes.Tenant {
+		cluster := api.MetricSourceCluster{
+			TriggerSource: moira.TenantRemote,
+			ClusterId:     remote.ClusterId,
+			ClusterName:   remote.ClusterName,
+		}
+		clusters = append(clusters, cluster)
+	}
+
+	for _, remote := range remotes.Loki {
+		cluster := api.MetricSourceCluster{
+			TriggerSource: moira.LokiRemote,
+			ClusterId:     remote.ClusterId,
+			ClusterName:   remote.ClusterName,
+		}
+		clusters = append(clusters, cluster)
+	}
+
+	for _, remote := range remotes.SQL {
+		cluster := api.MetricSourceCluster{
+			TriggerSource: moira.SQLRemote,
+			ClusterId:     remote.ClusterId,
+			ClusterName:   remote.ClusterName,
+		}
+		clusters = append(clusters, cluster)
+	}
+
 	return &api.WebConfig{
 		SupportEmail:         config.SupportEmail,
 		RemoteAllowed:        isRemoteEnabled,
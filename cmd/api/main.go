@@ -18,6 +18,7 @@ import (
 	"github.com/moira-alert/moira/database/stats"
 	"github.com/moira-alert/moira/index"
 	logging "github.com/moira-alert/moira/logging/zerolog_adapter"
+	"github.com/moira-alert/moira/plotting"
 	_ "go.uber.org/automaxprocs"
 )
 
@@ -78,6 +79,12 @@ func main() {
 			Error(err).
 			Msg("Can not start telemetry")
 	}
+
+	if err = plotting.RegisterThemes(applicationConfig.Plotting.GetThemes()); err != nil {
+		logger.Fatal().
+			Error(err).
+			Msg("Can not register plot themes")
+	}
 	defer telemetry.Stop()
 
 	databaseSettings := applicationConfig.Redis.GetSettings()
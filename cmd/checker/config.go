@@ -113,6 +113,51 @@ func (config *config) getSettings(logger moira.Logger) *checker.Config {
 		sourceCheckConfigs[moira.MakeClusterKey(moira.PrometheusRemote, remote.ClusterId)] = checkConfig
 	}
 
+	for _, remote := range config.Remotes.Tenant {
+		checkConfig := checker.SourceCheckConfig{
+			CheckInterval:     to.Duration(remote.CheckInterval),
+			MaxParallelChecks: remote.MaxParallelChecks,
+		}
+		if handleParallelChecks(&checkConfig.MaxParallelChecks) {
+			logger.Info().
+				Int("number_of_cpu", checkConfig.MaxParallelChecks).
+				String("trigger_source", moira.TenantRemote.String()).
+				String("cluster_id", remote.ClusterId.String()).
+				Msg("MaxParallelChecks is not configured, set it to the number of CPU")
+		}
+		sourceCheckConfigs[moira.MakeClusterKey(moira.TenantRemote, remote.ClusterId)] = checkConfig
+	}
+
+	for _, remote := range config.Remotes.Loki {
+		checkConfig := checker.SourceCheckConfig{
+			CheckInterval:     to.Duration(remote.CheckInterval),
+			MaxParallelChecks: remote.MaxParallelChecks,
+		}
+		if handleParallelChecks(&checkConfig.MaxParallelChecks) {
+			logger.Info().
+				Int("number_of_cpu", checkConfig.MaxParallelChecks).
+				String("trigger_source", moira.LokiRemote.String()).
+				String("cluster_id", remote.ClusterId.String()).
+				Msg("MaxParallelChecks is not configured, set it to the number of CPU")
+		}
+		sourceCheckConfigs[moira.MakeClusterKey(moira.LokiRemote, remote.ClusterId)] = checkConfig
+	}
+
+	for _, remote := range config.Remotes.SQL {
+		checkConfig := checker.SourceCheckConfig{
+			CheckInterval:     to.Duration(remote.CheckInterval),
+			MaxParallelChecks: remote.MaxParallelChecks,
+		}
+		if handleParallelChecks(&checkConfig.MaxParallelChecks) {
+			logger.Info().
+				Int("number_of_cpu", checkConfig.MaxParallelChecks).
+				String("trigger_source", moira.SQLRemote.String()).
+				String("cluster_id", remote.ClusterId.String()).
+				Msg("MaxParallelChecks is not configured, set it to the number of CPU")
+		}
+		sourceCheckConfigs[moira.MakeClusterKey(moira.SQLRemote, remote.ClusterId)] = checkConfig
+	}
+
 	return &checker.Config{
 		SourceCheckConfigs:              sourceCheckConfigs,
 		LazyTriggersCheckInterval:       to.Duration(config.Checker.LazyTriggersCheckInterval),
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/go-graphite/carbonapi/date"
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api/controller"
+	"github.com/moira-alert/moira/api/dto"
+	metricSource "github.com/moira-alert/moira/metric_source"
+	plots "github.com/moira-alert/moira/plotting"
+)
+
+type backtestOptions struct {
+	triggerID   string
+	triggerFile string
+	from        string
+	to          string
+	chunk       string
+	plotFile    string
+}
+
+// handleBacktest replays checks of existing trigger or trigger from file on historical data,
+// writes simulated events and flap count to output and plot of simulated states to plot file if it is given.
+func handleBacktest(
+	logger moira.Logger,
+	database moira.Database,
+	sourceProvider *metricSource.SourceProvider,
+	options backtestOptions,
+	output io.Writer,
+) error {
+	trigger, err := getBacktestTrigger(database, options)
+	if err != nil {
+		return err
+	}
+
+	from := date.DateParamToEpoch(options.from, "UTC", 0, time.UTC)
+	to := date.DateParamToEpoch(options.to, "UTC", 0, time.UTC)
+	if from == 0 || to == 0 || from >= to {
+		return fmt.Errorf("invalid backtest interval from %s to %s", options.from, options.to)
+	}
+
+	chunk, err := time.ParseDuration(options.chunk)
+	if err != nil {
+		return fmt.Errorf("invalid backtest chunk %s: %w", options.chunk, err)
+	}
+
+	var plotTemplate *plots.Plot
+	if options.plotFile != "" {
+		if plotTemplate, err = plots.GetPlotTemplate("", time.UTC); err != nil {
+			return fmt.Errorf("can't initialize plot template: %w", err)
+		}
+	}
+
+	result, errorResponse := controller.BacktestTrigger(database, sourceProvider, trigger, from, to, int64(chunk.Seconds()), plotTemplate, logger)
+	if errorResponse != nil {
+		return fmt.Errorf("failed to backtest trigger: %w", errorResponse.Err)
+	}
+
+	if options.plotFile != "" {
+		plot, err := base64.StdEncoding.DecodeString(result.Plot)
+		if err != nil {
+			return fmt.Errorf("can't decode plot: %w", err)
+		}
+		if err := os.WriteFile(options.plotFile, plot, 0644); err != nil { //nolint:gofumpt,gomnd
+			return fmt.Errorf("can't write plot to %s: %w", options.plotFile, err)
+		}
+		result.Plot = ""
+	}
+
+	encoder := json.NewEncoder(output)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(result)
+}
+
+func getBacktestTrigger(database moira.Database, options backtestOptions) (*dto.TriggerModel, error) {
+	if options.triggerFile == "" {
+		trigger, err := database.GetTrigger(options.triggerID)
+		if err != nil {
+			return nil, fmt.Errorf("can't get trigger %s: %w", options.triggerID, err)
+		}
+		triggerModel := dto.CreateTriggerModel(&trigger)
+		return &triggerModel, nil
+	}
+
+	file, err := os.Open(options.triggerFile)
+	if err != nil {
+		return nil, fmt.Errorf("can't open trigger file: %w", err)
+	}
+	defer file.Close()
+
+	trigger := &dto.Trigger{}
+	if err := json.NewDecoder(file).Decode(trigger); err != nil {
+		return nil, fmt.Errorf("can't decode trigger: %w", err)
+	}
+	return &trigger.TriggerModel, nil
+}
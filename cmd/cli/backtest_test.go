@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api/dto"
+	logging "github.com/moira-alert/moira/logging/zerolog_adapter"
+	metricSource "github.com/moira-alert/moira/metric_source"
+	mock_metric_source "github.com/moira-alert/moira/mock/metric_source"
+	mocks "github.com/moira-alert/moira/mock/moira-alert"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func Test_handleBacktest(t *testing.T) {
+	logger, _ := logging.ConfigureLog("stdout", "debug", "test", true)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	db := mocks.NewMockDatabase(mockCtrl)
+	source := mock_metric_source.NewMockMetricSource(mockCtrl)
+	fetchResult := mock_metric_source.NewMockFetchResult(mockCtrl)
+	sourceProvider := metricSource.CreateTestMetricSourceProvider(source, nil, nil)
+
+	warnValue := float64(10)
+	trigger := moira.Trigger{
+		ID:            "trigger-1",
+		Targets:       []string{"metric"},
+		WarnValue:     &warnValue,
+		TriggerType:   moira.RisingTrigger,
+		TriggerSource: moira.GraphiteLocal,
+		ClusterId:     moira.DefaultCluster,
+	}
+
+	Convey("Backtest of existing trigger prints events and flap count", t, func() {
+		db.EXPECT().GetTrigger("trigger-1").Return(trigger, nil)
+		source.EXPECT().Fetch("metric", int64(60), int64(240), true).Return(fetchResult, nil)
+		fetchResult.EXPECT().GetMetricsData().Return([]metricSource.MetricData{*metricSource.MakeMetricData("metric", []float64{1, 20, 1, 20}, 60, 60)}).AnyTimes()
+		fetchResult.EXPECT().GetPatternMetrics().Return([]string{}, nil)
+
+		output := bytes.NewBuffer(nil)
+		options := backtestOptions{triggerID: "trigger-1", from: "60", to: "240", chunk: "24h"}
+		err := handleBacktest(logger, db, sourceProvider, options, output)
+		So(err, ShouldBeNil)
+
+		result := dto.TriggerBacktest{}
+		So(json.NewDecoder(output).Decode(&result), ShouldBeNil)
+		So(result.Events, ShouldHaveLength, 4)
+		So(result.FlapCount, ShouldEqual, 2)
+	})
+
+	Convey("Invalid interval", t, func() {
+		db.EXPECT().GetTrigger("trigger-1").Return(trigger, nil)
+
+		options := backtestOptions{triggerID: "trigger-1", from: "240", to: "60", chunk: "24h"}
+		err := handleBacktest(logger, db, sourceProvider, options, bytes.NewBuffer(nil))
+		So(err.Error(), ShouldEqual, "invalid backtest interval from 240 to 60")
+	})
+}
@@ -5,11 +5,12 @@ import (
 )
 
 type config struct {
-	LogFile         string          `yaml:"log_file"`
-	LogLevel        string          `yaml:"log_level"`
-	LogPrettyFormat bool            `yaml:"log_pretty_format"`
-	Redis           cmd.RedisConfig `yaml:"redis"`
-	Cleanup         cleanupConfig   `yaml:"cleanup"`
+	LogFile         string            `yaml:"log_file"`
+	LogLevel        string            `yaml:"log_level"`
+	LogPrettyFormat bool              `yaml:"log_pretty_format"`
+	Redis           cmd.RedisConfig   `yaml:"redis"`
+	Cleanup         cleanupConfig     `yaml:"cleanup"`
+	Remotes         cmd.RemotesConfig `yaml:",inline"`
 }
 
 type cleanupConfig struct {
@@ -33,5 +34,6 @@ func getDefault() config {
 			Whitelist:              []string{},
 			CleanupMetricsDuration: "-168h",
 		},
+		Remotes: cmd.RemotesConfig{},
 	}
 }
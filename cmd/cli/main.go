@@ -71,8 +71,18 @@ var (
 	removeUnusedTriggersStartWith = flag.String("remove-unused-triggers-start-with", "", "Remove unused triggers which have ID starting with string parameter")
 )
 
+var (
+	backtestTriggerID   = flag.String("backtest-trigger", "", "Replay checks of trigger with given ID on historical data and print events it would have produced")
+	backtestTriggerFile = flag.String("backtest-trigger-file", "", "File that holds trigger JSON to replay instead of existing trigger")
+	backtestFrom        = flag.String("backtest-from", "-7days", "Start of history to replay trigger checks on")
+	backtestTo          = flag.String("backtest-to", "now", "End of history to replay trigger checks on")
+	backtestChunk       = flag.String("backtest-chunk", "24h", "Interval of history fetched from metric source at once")
+	backtestPlotFile    = flag.String("backtest-plot", "", "File to save PNG plot of simulated metrics states")
+)
+
 func main() { //nolint
-	confCleanup, logger, database := initApp()
+	conf, logger, database := initApp()
+	confCleanup := conf.Cleanup
 
 	if *update {
 		fromVersion := checkValidVersion(logger, updateFromVersion, true)
@@ -321,6 +331,29 @@ func main() { //nolint
 		logger.Info().Msg("Dump was pushed")
 	}
 
+	if *backtestTriggerID != "" || *backtestTriggerFile != "" {
+		sourceProvider, err := cmd.InitMetricSources(conf.Remotes, database, logger)
+		if err != nil {
+			logger.Fatal().
+				Error(err).
+				Msg("Failed to initialize metric sources")
+		}
+
+		options := backtestOptions{
+			triggerID:   *backtestTriggerID,
+			triggerFile: *backtestTriggerFile,
+			from:        *backtestFrom,
+			to:          *backtestTo,
+			chunk:       *backtestChunk,
+			plotFile:    *backtestPlotFile,
+		}
+		if err := handleBacktest(logger, database, sourceProvider, options, os.Stdout); err != nil {
+			logger.Fatal().
+				Error(err).
+				Msg("Failed to backtest trigger")
+		}
+	}
+
 	if *removeSubscriptions != "" {
 		logger.Info().Msg("Start deletion of subscriptions")
 		subscriptionIDs := strings.Split(*removeSubscriptions, ";")
@@ -356,7 +389,7 @@ func GetDumpBriefInfo(dump *dto.TriggerDump) string {
 		dump.Created, dump.Trigger.ID, len(dump.Metrics), dump.LastCheck.LastSuccessfulCheckTimestamp)
 }
 
-func initApp() (cleanupConfig, moira.Logger, moira.Database) {
+func initApp() (config, moira.Logger, moira.Database) {
 	flag.Parse()
 	if *printVersion {
 		fmt.Println("Moira - alerting system based on graphite or prometheus data")
@@ -385,7 +418,7 @@ func initApp() (cleanupConfig, moira.Logger, moira.Database) {
 
 	databaseSettings := config.Redis.GetSettings()
 	dataBase := redis.NewDatabase(logger, databaseSettings, redis.NotificationHistoryConfig{}, redis.NotificationConfig{}, redis.Cli)
-	return config.Cleanup, logger, dataBase
+	return config, logger, dataBase
 }
 
 func checkValidVersion(logger moira.Logger, updateFromVersion *string, isUpdate bool) string {
@@ -10,8 +10,12 @@ import (
 	"github.com/moira-alert/moira/metrics"
 
 	"github.com/moira-alert/moira/image_store/s3"
+	lokiRemoteSource "github.com/moira-alert/moira/metric_source/loki"
 	prometheusRemoteSource "github.com/moira-alert/moira/metric_source/prometheus"
 	graphiteRemoteSource "github.com/moira-alert/moira/metric_source/remote"
+	sqlRemoteSource "github.com/moira-alert/moira/metric_source/sql"
+	tenantRemoteSource "github.com/moira-alert/moira/metric_source/tenant"
+	"github.com/moira-alert/moira/plotting/themes/custom"
 	"github.com/xiam/to"
 	"gopkg.in/yaml.v2"
 
@@ -157,6 +161,9 @@ type ProfilerConfig struct {
 type RemotesConfig struct {
 	Graphite   []GraphiteRemoteConfig   `yaml:"graphite_remote"`
 	Prometheus []PrometheusRemoteConfig `yaml:"prometheus_remote"`
+	Tenant     []TenantRemoteConfig     `yaml:"tenant_remote"`
+	Loki       []LokiRemoteConfig       `yaml:"loki_remote"`
+	SQL        []SQLRemoteConfig        `yaml:"sql_remote"`
 }
 
 // Validate returns nil if config is valid, or error if it is malformed.
@@ -165,6 +172,9 @@ func (remotes *RemotesConfig) Validate() error {
 
 	errs = append(errs, validateRemotes[GraphiteRemoteConfig](remotes.Graphite)...)
 	errs = append(errs, validateRemotes[PrometheusRemoteConfig](remotes.Prometheus)...)
+	errs = append(errs, validateRemotes[TenantRemoteConfig](remotes.Tenant)...)
+	errs = append(errs, validateRemotes[LokiRemoteConfig](remotes.Loki)...)
+	errs = append(errs, validateRemotes[SQLRemoteConfig](remotes.SQL)...)
 
 	if len(errs) == 0 {
 		return nil
@@ -229,6 +239,26 @@ type GraphiteRemoteConfig struct {
 	User string `yaml:"user"`
 	// Password for basic auth
 	Password string `yaml:"password"`
+	// Headers added to every remote request, e.g. Authorization: Bearer <token>
+	Headers map[string]string `yaml:"headers"`
+	// Render response format: json (default), msgpack, protobuf, carbonapi_v2_pb or carbonapi_v3_pb
+	Format string `yaml:"format"`
+	// TLS settings for remote requests
+	TLS TLSConfig `yaml:"tls"`
+}
+
+// TLSConfig is TLS settings structure for connections to remote sources.
+type TLSConfig struct {
+	// Path to PEM-encoded CA bundle used to verify server certificate
+	CAFile string `yaml:"ca_file"`
+	// Path to PEM-encoded client certificate
+	CertFile string `yaml:"cert_file"`
+	// Path to PEM-encoded client certificate key
+	KeyFile string `yaml:"key_file"`
+	// Server name used to verify server certificate
+	ServerName string `yaml:"server_name"`
+	// If true, server certificate will not be verified
+	InsecureSkipVerify bool `yaml:"insecure_skip_verify"`
 }
 
 func (config GraphiteRemoteConfig) getRemoteCommon() *RemoteCommonConfig {
@@ -244,6 +274,15 @@ func (config *GraphiteRemoteConfig) GetRemoteSourceSettings() *graphiteRemoteSou
 		Timeout:       to.Duration(config.Timeout),
 		User:          config.User,
 		Password:      config.Password,
+		Headers:       config.Headers,
+		Format:        config.Format,
+		TLS: graphiteRemoteSource.TLSConfig{
+			CAFile:             config.TLS.CAFile,
+			CertFile:           config.TLS.CertFile,
+			KeyFile:            config.TLS.KeyFile,
+			ServerName:         config.TLS.ServerName,
+			InsecureSkipVerify: config.TLS.InsecureSkipVerify,
+		},
 	}
 }
 
@@ -256,6 +295,8 @@ type PrometheusRemoteConfig struct {
 	Retries int `yaml:"retries"`
 	// Timeout between retries for prometheus api requests
 	RetryTimeout string `yaml:"retry_timeout"`
+	// Default step of range queries, could be overridden in trigger. Default is 60s
+	Step string `yaml:"step"`
 	// Username for basic auth
 	User string `yaml:"user"`
 	// Password for basic auth
@@ -277,6 +318,139 @@ func (config *PrometheusRemoteConfig) GetPrometheusSourceSettings() *prometheusR
 		RequestTimeout: to.Duration(config.Timeout),
 		Retries:        config.Retries,
 		RetryTimeout:   to.Duration(config.RetryTimeout),
+		Step:           to.Duration(config.Step),
+	}
+}
+
+// TenantRemoteConfig is remote multi-tenant storage (VictoriaMetrics, Thanos, Mimir) settings structure.
+type TenantRemoteConfig struct {
+	RemoteCommonConfig `yaml:",inline"`
+	// Query API of storage: prometheus (default) or graphite
+	API string `yaml:"api"`
+	// Tenant id passed with every request
+	Tenant string `yaml:"tenant"`
+	// Header used to pass tenant id. Default is X-Scope-OrgID
+	TenantHeader string `yaml:"tenant_header"`
+	// Additional headers passed with every request
+	Headers map[string]string `yaml:"headers"`
+	// Timeout for remote requests
+	Timeout string `yaml:"timeout"`
+	// Number of retries for prometheus api requests
+	Retries int `yaml:"retries"`
+	// Timeout between retries for prometheus api requests
+	RetryTimeout string `yaml:"retry_timeout"`
+	// Default step of prometheus range queries, could be overridden in trigger. Default is 60s
+	Step string `yaml:"step"`
+	// Username for basic auth
+	User string `yaml:"user"`
+	// Password for basic auth
+	Password string `yaml:"password"`
+}
+
+func (config TenantRemoteConfig) getRemoteCommon() *RemoteCommonConfig {
+	return &config.RemoteCommonConfig
+}
+
+// GetTenantSourceSettings returns multi-tenant remote config parsed from moira config files.
+func (config *TenantRemoteConfig) GetTenantSourceSettings() *tenantRemoteSource.Config {
+	return &tenantRemoteSource.Config{
+		API:            config.API,
+		Tenant:         config.Tenant,
+		TenantHeader:   config.TenantHeader,
+		Headers:        config.Headers,
+		URL:            config.URL,
+		CheckInterval:  to.Duration(config.CheckInterval),
+		MetricsTTL:     to.Duration(config.MetricsTTL),
+		RequestTimeout: to.Duration(config.Timeout),
+		Retries:        config.Retries,
+		RetryTimeout:   to.Duration(config.RetryTimeout),
+		Step:           to.Duration(config.Step),
+		User:           config.User,
+		Password:       config.Password,
+	}
+}
+
+// LokiRemoteConfig is Loki storage settings structure.
+type LokiRemoteConfig struct {
+	RemoteCommonConfig `yaml:",inline"`
+	// Timeout for loki api requests
+	Timeout string `yaml:"timeout"`
+	// Default step of LogQL range queries, could be overridden in trigger. Default is 60s
+	Step string `yaml:"step"`
+	// Username for basic auth
+	User string `yaml:"user"`
+	// Password for basic auth
+	Password string `yaml:"password"`
+	// Tenant id passed in X-Scope-OrgID header, required by multi-tenant loki installations
+	Tenant string `yaml:"tenant"`
+	// Additional headers passed with every request
+	Headers map[string]string `yaml:"headers"`
+}
+
+func (config LokiRemoteConfig) getRemoteCommon() *RemoteCommonConfig {
+	return &config.RemoteCommonConfig
+}
+
+// GetLokiSourceSettings returns loki remote config parsed from moira config files.
+func (config *LokiRemoteConfig) GetLokiSourceSettings() *lokiRemoteSource.Config {
+	return &lokiRemoteSource.Config{
+		URL:           config.URL,
+		CheckInterval: to.Duration(config.CheckInterval),
+		MetricsTTL:    to.Duration(config.MetricsTTL),
+		Timeout:       to.Duration(config.Timeout),
+		Step:          to.Duration(config.Step),
+		User:          config.User,
+		Password:      config.Password,
+		Tenant:        config.Tenant,
+		Headers:       config.Headers,
+	}
+}
+
+// SQLRemoteConfig is SQL database (PostgreSQL, ClickHouse) metric source settings structure.
+type SQLRemoteConfig struct {
+	RemoteCommonConfig `yaml:",inline"`
+	// Database driver: postgres or clickhouse
+	Driver string `yaml:"driver"`
+	// Data source name in driver format
+	DSN string `yaml:"dsn"`
+	// Username, overrides one from dsn
+	User string `yaml:"user"`
+	// Password, overrides one from dsn
+	Password string `yaml:"password"`
+	// Query executed for every trigger target, receives target, from and until as positional parameters
+	// and returns rows of (timestamp, name, value)
+	Query string `yaml:"query"`
+	// Timeout of query execution. Default is 30s
+	QueryTimeout string `yaml:"query_timeout"`
+	// Resolution of returned metrics. Default is 60s
+	Step string `yaml:"step"`
+	// Maximum number of open connections to database, 0 means unlimited
+	MaxOpenConnections int `yaml:"max_open_connections"`
+	// Maximum number of idle connections in pool. Default is 2
+	MaxIdleConnections int `yaml:"max_idle_connections"`
+	// Maximum amount of time a connection may be reused, connections are reused forever if not set
+	ConnectionMaxLifetime string `yaml:"connection_max_lifetime"`
+}
+
+func (config SQLRemoteConfig) getRemoteCommon() *RemoteCommonConfig {
+	return &config.RemoteCommonConfig
+}
+
+// GetSQLSourceSettings returns SQL metric source config parsed from moira config files.
+func (config *SQLRemoteConfig) GetSQLSourceSettings() *sqlRemoteSource.Config {
+	return &sqlRemoteSource.Config{
+		Driver:                config.Driver,
+		DSN:                   config.DSN,
+		User:                  config.User,
+		Password:              config.Password,
+		Query:                 config.Query,
+		QueryTimeout:          to.Duration(config.QueryTimeout),
+		CheckInterval:         to.Duration(config.CheckInterval),
+		MetricsTTL:            to.Duration(config.MetricsTTL),
+		Step:                  to.Duration(config.Step),
+		MaxOpenConnections:    config.MaxOpenConnections,
+		MaxIdleConnections:    config.MaxIdleConnections,
+		ConnectionMaxLifetime: to.Duration(config.ConnectionMaxLifetime),
 	}
 }
 
@@ -285,6 +459,59 @@ type ImageStoreConfig struct {
 	S3 s3.Config `yaml:"s3"`
 }
 
+// PlotThemeConfig describes a custom plot theme. Colors are hex strings like 'ffffff'.
+type PlotThemeConfig struct {
+	// Theme name used in subscription plotting settings and render requests
+	Name string `yaml:"name"`
+	// Background color. Default is ffffff
+	BackgroundColor string `yaml:"background_color"`
+	// Color of title, legend, axes and annotations text
+	FontColor string `yaml:"font_color"`
+	// Grid stroke color. Default is 1f1d1d
+	GridColor string `yaml:"grid_color"`
+	// Grid stroke width. Default is 0.03
+	GridWidth float64 `yaml:"grid_width"`
+	// Y axis font size. Default is 10
+	FontSizePrimary float64 `yaml:"font_size_primary"`
+	// X axis, legend and annotations font size. Default is 8
+	FontSizeSecondary float64 `yaml:"font_size_secondary"`
+	// Title font size. Default is 15
+	FontSizeTitle float64 `yaml:"font_size_title"`
+	// Colors used for curves one by one
+	CurveColors []string `yaml:"curve_colors"`
+	// ERROR threshold color. Default is 8b0000
+	ErrorColor string `yaml:"error_color"`
+	// WARN threshold color. Default is cccc00
+	WarnColor string `yaml:"warn_color"`
+}
+
+// PlottingConfig is a plotting config structure shared by notifier and api.
+type PlottingConfig struct {
+	// Custom themes available in addition to built-in 'light', 'dark' and 'colorblind'
+	Themes []PlotThemeConfig `yaml:"themes"`
+}
+
+// GetThemes returns custom plot themes configuration.
+func (config *PlottingConfig) GetThemes() []custom.Config {
+	themes := make([]custom.Config, 0, len(config.Themes))
+	for _, theme := range config.Themes {
+		themes = append(themes, custom.Config{
+			Name:              theme.Name,
+			BackgroundColor:   theme.BackgroundColor,
+			FontColor:         theme.FontColor,
+			GridColor:         theme.GridColor,
+			GridWidth:         theme.GridWidth,
+			FontSizePrimary:   theme.FontSizePrimary,
+			FontSizeSecondary: theme.FontSizeSecondary,
+			FontSizeTitle:     theme.FontSizeTitle,
+			CurveColors:       theme.CurveColors,
+			ErrorColor:        theme.ErrorColor,
+			WarnColor:         theme.WarnColor,
+		})
+	}
+	return themes
+}
+
 // ReadConfig parses config file by the given path into Moira-used type.
 func ReadConfig(configFileName string, config interface{}) error {
 	configYaml, err := os.ReadFile(configFileName)
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"github.com/moira-alert/moira/filter"
+	"github.com/xiam/to"
+)
+
+// aggregationRule makes filter aggregate values of metrics into fixed windows before saving them, like carbon-aggregator does.
+type aggregationRule struct {
+	// Regular expression, which is matched against metric name including tags, e.g. name;tag=value.
+	// The first matching rule is used.
+	Pattern string `yaml:"pattern"`
+	// Values received during window are saved as one value, e.g. 1m.
+	Window string `yaml:"window"`
+	// Aggregation method: sum, avg, max, min, last or count.
+	Method string `yaml:"method"`
+}
+
+func toFilterAggregationRules(rules []aggregationRule) []filter.AggregationRule {
+	result := make([]filter.AggregationRule, 0, len(rules))
+	for _, rule := range rules {
+		result = append(result, filter.AggregationRule{
+			Pattern: rule.Pattern,
+			Window:  to.Duration(rule.Window),
+			Method:  rule.Method,
+		})
+	}
+	return result
+}
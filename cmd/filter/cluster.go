@@ -0,0 +1,16 @@
+package main
+
+// clusterConfig makes filters share patterns: every filter loads only patterns it owns by consistent hashing
+// and forwards metrics to their owners. Members are discovered through Redis.
+type clusterConfig struct {
+	// If true, filter joins cluster.
+	Enabled bool `yaml:"enabled"`
+	// Listener of metrics forwarded by other members, e.g. ":2004".
+	Listen string `yaml:"listen"`
+	// Address of this filter, which other members use to forward metrics, e.g. "filter-1:2004".
+	Address string `yaml:"address"`
+	// Period of membership heartbeat.
+	HeartbeatInterval string `yaml:"heartbeat_interval"`
+	// Member is excluded from cluster if it sent no heartbeat during this period.
+	MemberTTL string `yaml:"member_ttl"`
+}
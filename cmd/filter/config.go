@@ -16,6 +16,7 @@ type filterConfig struct {
 	Listen string `yaml:"listen"`
 	// Retentions config file path.
 	// Simply use your original storage-schemas.conf or create new if you're using Moira without existing Graphite installation.
+	// Retentions and graphite_compatibility are reloaded without restart on SIGHUP.
 	RetentionConfig string `yaml:"retention_config"`
 	// Number of metrics to cache before checking them.
 	// Note: As this value increases, Redis CPU usage decreases.
@@ -30,6 +31,15 @@ type filterConfig struct {
 	DropMetricsTTL string `yaml:"drop_metrics_ttl"`
 	// Flags for compatibility with different graphite behaviours
 	Compatibility compatibility `yaml:"graphite_compatibility"`
+	// Rules to aggregate values of frequent metrics before saving them to Redis
+	AggregationRules []aggregationRule `yaml:"aggregation_rules"`
+	// Rules to rewrite metric names before pattern matching
+	RewriteRules []rewriteRule `yaml:"rewrite_rules"`
+	// Rules to relabel tags of metrics before pattern matching, applied after rewrite rules
+	RelabelRules []relabelRule `yaml:"relabel_rules"`
+	// Sharding of patterns between filters. Metrics are routed by the first node of their names,
+	// rewrite and relabel rules are applied before routing when cluster is enabled.
+	Cluster clusterConfig `yaml:"cluster"`
 }
 
 func getDefault() config {
@@ -55,6 +65,12 @@ func getDefault() config {
 				AllowRegexLooseStartMatch: false,
 				AllowRegexMatchEmpty:      true,
 			},
+			Cluster: clusterConfig{
+				Enabled:           false,
+				Listen:            ":2004",
+				HeartbeatInterval: "5s",
+				MemberTTL:         "15s",
+			},
 		},
 		Telemetry: cmd.TelemetryConfig{
 			Listen: ":8094",
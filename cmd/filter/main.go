@@ -12,6 +12,7 @@ import (
 	"github.com/moira-alert/moira/cmd"
 	"github.com/moira-alert/moira/database/redis"
 	"github.com/moira-alert/moira/filter"
+	"github.com/moira-alert/moira/filter/cluster"
 	"github.com/moira-alert/moira/filter/connection"
 	"github.com/moira-alert/moira/filter/heartbeat"
 	matchedmetrics "github.com/moira-alert/moira/filter/matched_metrics"
@@ -112,6 +113,57 @@ func main() {
 			Msg("Failed to refresh pattern storage")
 	}
 
+	if len(config.Filter.AggregationRules) > 0 {
+		aggregator, err := filter.NewAggregator(toFilterAggregationRules(config.Filter.AggregationRules))
+		if err != nil {
+			logger.Fatal().
+				Error(err).
+				Msg("Failed to configure aggregation rules")
+		}
+		patternStorage.SetAggregator(aggregator)
+	}
+
+	var relabeler *filter.Relabeler
+	if len(config.Filter.RewriteRules) > 0 || len(config.Filter.RelabelRules) > 0 {
+		relabeler, err = filter.NewRelabeler(toFilterRewriteRules(config.Filter.RewriteRules), toFilterRelabelRules(config.Filter.RelabelRules))
+		if err != nil {
+			logger.Fatal().
+				Error(err).
+				Msg("Failed to configure rewrite and relabel rules")
+		}
+	}
+
+	var membership *cluster.Membership
+	if config.Filter.Cluster.Enabled {
+		if config.Filter.Cluster.Address == "" {
+			logger.Fatal().Msg("Address of filter is required to join filter cluster")
+		}
+		membership = cluster.NewMembership(
+			database,
+			logger,
+			config.Filter.Cluster.Address,
+			to.Duration(config.Filter.Cluster.HeartbeatInterval),
+			to.Duration(config.Filter.Cluster.MemberTTL),
+		)
+		patternStorage.SetPatternsOwner(membership.OwnsPattern)
+		// Patterns are shared between members, so they are reloaded as soon as members change
+		membership.SetMembersChangedHandler(func() {
+			if err := patternStorage.Refresh(); err != nil {
+				logger.Error().
+					Error(err).
+					Msg("Failed to refresh pattern storage after filter cluster members changed")
+			}
+		})
+		if err = membership.Start(); err != nil {
+			logger.Fatal().
+				Error(err).
+				Msg("Failed to join filter cluster")
+		}
+		defer stopMembership(membership)
+	} else {
+		patternStorage.SetRelabeler(relabeler)
+	}
+
 	// Refresh Patterns on first init
 	refreshPatternWorker := patterns.NewRefreshPatternWorker(database, filterMetrics, logger, patternStorage, to.Duration(config.Filter.PatternsUpdatePeriod))
 
@@ -136,9 +188,26 @@ func main() {
 			Error(err).
 			Msg("Failed to start listening")
 	}
-	lineChan := listener.Listen()
+	var lineChan <-chan []byte = listener.Listen()
 
 	patternMatcher := patterns.NewMatcher(logger, filterMetrics, patternStorage, to.Duration(config.Filter.DropMetricsTTL))
+	if membership != nil {
+		// Metrics forwarded by other members are owned by this filter, so they are never forwarded again
+		clusterListener, err := connection.NewListener(config.Filter.Cluster.Listen, logger, filterMetrics)
+		if err != nil {
+			logger.Fatal().
+				Error(err).
+				Msg("Failed to start listening metrics of filter cluster")
+		}
+		defer stopListener(clusterListener)
+
+		// Metrics are relabeled before routing, so they are routed to the member, which owns patterns of their new names
+		router := cluster.NewRouter(membership, filterMetrics, logger)
+		router.SetRelabeler(relabeler)
+		lineChan = router.Route(lineChan)
+		patternMatcher.SetForwardedLines(clusterListener.Listen())
+	}
+
 	metricsChan := patternMatcher.Start(config.Filter.MaxParallelMatches, lineChan)
 
 	// Start metrics matcher
@@ -152,12 +221,25 @@ func main() {
 		String("moira_version", MoiraVersion).
 		Msg("Moira Filter started")
 
+	// Reload storage schemas and graphite compatibility on SIGHUP
+	reloader := &configReloader{
+		configFileName: *configFileName,
+		cacheStorage:   cacheStorage,
+		patternStorage: patternStorage,
+		metrics:        filterMetrics,
+		logger:         logger,
+	}
+
 	ch := make(chan os.Signal, 1)
-	signal.Notify(ch, syscall.SIGINT, syscall.SIGTERM)
+	signal.Notify(ch, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
 
-	signal := fmt.Sprint(<-ch)
+	sig := <-ch
+	for sig == syscall.SIGHUP {
+		reloader.reload()
+		sig = <-ch
+	}
 	logger.Info().
-		String("signal", signal).
+		String("signal", fmt.Sprint(sig)).
 		Msg("Moira Filter shutting down.")
 }
 
@@ -169,6 +251,14 @@ func stopListener(listener *connection.MetricsListener) {
 	}
 }
 
+func stopMembership(membership *cluster.Membership) {
+	if err := membership.Stop(); err != nil {
+		logger.Error().
+			Error(err).
+			Msg("Failed to leave filter cluster")
+	}
+}
+
 func stopHeartbeatWorker(heartbeatWorker *heartbeat.Worker) {
 	if err := heartbeatWorker.Stop(); err != nil {
 		logger.Error().
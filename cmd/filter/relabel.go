@@ -0,0 +1,56 @@
+package main
+
+import (
+	"github.com/moira-alert/moira/filter"
+)
+
+// rewriteRule replaces part of metric name matching regular expression before pattern matching.
+type rewriteRule struct {
+	// Regular expression, e.g. ^servers\.([^.]+)\.example\.com\.
+	Pattern string `yaml:"pattern"`
+	// Replacement may refer to groups of pattern, e.g. servers.${1}_example_com.
+	Replacement string `yaml:"replacement"`
+}
+
+// relabelRule changes tags of metrics before pattern matching like relabel_configs of Prometheus.
+// Metric name is available as tag "name".
+type relabelRule struct {
+	// One of replace (default), keep, drop, labelmap and labeldrop.
+	Action string `yaml:"action"`
+	// Values of these tags are joined with separator and matched against regex.
+	SourceLabels []string `yaml:"source_labels"`
+	// Separator of source tags values, ";" by default.
+	Separator string `yaml:"separator"`
+	// Regular expression anchored on both ends, "(.*)" by default.
+	Regex string `yaml:"regex"`
+	// Tag to write replacement to in replace action.
+	TargetLabel string `yaml:"target_label"`
+	// Replacement may refer to groups of regex, "$1" by default.
+	Replacement string `yaml:"replacement"`
+}
+
+func toFilterRewriteRules(rules []rewriteRule) []filter.RewriteRule {
+	result := make([]filter.RewriteRule, 0, len(rules))
+	for _, rule := range rules {
+		result = append(result, filter.RewriteRule{
+			Pattern:     rule.Pattern,
+			Replacement: rule.Replacement,
+		})
+	}
+	return result
+}
+
+func toFilterRelabelRules(rules []relabelRule) []filter.RelabelRule {
+	result := make([]filter.RelabelRule, 0, len(rules))
+	for _, rule := range rules {
+		result = append(result, filter.RelabelRule{
+			Action:       rule.Action,
+			SourceLabels: rule.SourceLabels,
+			Separator:    rule.Separator,
+			Regex:        rule.Regex,
+			TargetLabel:  rule.TargetLabel,
+			Replacement:  rule.Replacement,
+		})
+	}
+	return result
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/cmd"
+	"github.com/moira-alert/moira/filter"
+	"github.com/moira-alert/moira/metrics"
+)
+
+// configReloader applies storage schemas and graphite compatibility flags from config without restart.
+type configReloader struct {
+	configFileName string
+	cacheStorage   *filter.Storage
+	patternStorage *filter.PatternStorage
+	metrics        *metrics.FilterMetrics
+	logger         moira.Logger
+}
+
+// reload reads config again and reports the result in logs and metrics, current settings are kept on failure.
+func (reloader *configReloader) reload() {
+	retentionConfig, err := reloader.reloadConfig()
+	if err != nil {
+		reloader.metrics.ConfigReloadFailed.Inc()
+		reloader.logger.Error().
+			String("config_file", reloader.configFileName).
+			Error(err).
+			Msg("Failed to reload filter config")
+		return
+	}
+
+	reloader.metrics.ConfigReloadSucceeded.Inc()
+	reloader.logger.Info().
+		String("config_file", reloader.configFileName).
+		String("retention_config", retentionConfig).
+		Msg("Filter config reloaded")
+}
+
+func (reloader *configReloader) reloadConfig() (string, error) {
+	config := getDefault()
+	if err := cmd.ReadConfig(reloader.configFileName, &config); err != nil {
+		return "", fmt.Errorf("failed to read config: %w", err)
+	}
+
+	retentionConfigFile, err := os.Open(config.Filter.RetentionConfig)
+	if err != nil {
+		return "", fmt.Errorf("failed to open retentions file: %w", err)
+	}
+	defer retentionConfigFile.Close()
+
+	retentions, err := reloader.cacheStorage.ParseRetentions(retentionConfigFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to reload retentions from %s: %w", config.Filter.RetentionConfig, err)
+	}
+
+	indexes, err := reloader.patternStorage.BuildIndexes(config.Filter.Compatibility.toFilterCompatibility())
+	if err != nil {
+		return "", fmt.Errorf("failed to refresh patterns with new graphite compatibility: %w", err)
+	}
+
+	// New settings are applied only when all of them are valid, so filter never works with half of the new config
+	reloader.cacheStorage.SetRetentions(retentions)
+	reloader.patternStorage.SetIndexes(indexes)
+
+	return config.Filter.RetentionConfig, nil
+}
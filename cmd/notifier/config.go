@@ -21,6 +21,7 @@ type config struct {
 	ImageStores         cmd.ImageStoreConfig          `yaml:"image_store"`
 	NotificationHistory cmd.NotificationHistoryConfig `yaml:"notification_history"`
 	Notification        cmd.NotificationConfig        `yaml:"notification"`
+	Plotting            cmd.PlottingConfig            `yaml:"plotting"`
 }
 
 type entityLogConfig struct {
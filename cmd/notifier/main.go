@@ -16,6 +16,7 @@ import (
 	"github.com/moira-alert/moira/notifier/events"
 	"github.com/moira-alert/moira/notifier/notifications"
 	"github.com/moira-alert/moira/notifier/selfstate"
+	"github.com/moira-alert/moira/plotting"
 	_ "go.uber.org/automaxprocs"
 )
 
@@ -74,6 +75,12 @@ func main() {
 	}
 	defer telemetry.Stop()
 
+	if err = plotting.RegisterThemes(config.Plotting.GetThemes()); err != nil {
+		logger.Fatal().
+			Error(err).
+			Msg("Can not register plot themes")
+	}
+
 	databaseSettings := config.Redis.GetSettings()
 	notificationHistorySettings := config.NotificationHistory.GetSettings()
 	notificationSettings := config.Notification.GetSettings()
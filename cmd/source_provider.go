@@ -6,8 +6,11 @@ import (
 	"github.com/moira-alert/moira"
 	metricSource "github.com/moira-alert/moira/metric_source"
 	"github.com/moira-alert/moira/metric_source/local"
+	"github.com/moira-alert/moira/metric_source/loki"
 	"github.com/moira-alert/moira/metric_source/prometheus"
 	"github.com/moira-alert/moira/metric_source/remote"
+	"github.com/moira-alert/moira/metric_source/sql"
+	"github.com/moira-alert/moira/metric_source/tenant"
 )
 
 // InitMetricSources initializes SourceProvider from given remote source configs.
@@ -38,5 +41,32 @@ func InitMetricSources(remotes RemotesConfig, database moira.Database, logger mo
 		provider.RegisterSource(moira.MakeClusterKey(moira.PrometheusRemote, prom.ClusterId), source)
 	}
 
+	for _, remoteTenant := range remotes.Tenant {
+		config := remoteTenant.GetTenantSourceSettings()
+		source, err := tenant.Create(config, logger)
+		if err != nil {
+			return nil, err
+		}
+		provider.RegisterSource(moira.MakeClusterKey(moira.TenantRemote, remoteTenant.ClusterId), source)
+	}
+
+	for _, remoteLoki := range remotes.Loki {
+		config := remoteLoki.GetLokiSourceSettings()
+		source, err := loki.Create(config)
+		if err != nil {
+			return nil, err
+		}
+		provider.RegisterSource(moira.MakeClusterKey(moira.LokiRemote, remoteLoki.ClusterId), source)
+	}
+
+	for _, remoteSQL := range remotes.SQL {
+		config := remoteSQL.GetSQLSourceSettings()
+		source, err := sql.Create(config)
+		if err != nil {
+			return nil, err
+		}
+		provider.RegisterSource(moira.MakeClusterKey(moira.SQLRemote, remoteSQL.ClusterId), source)
+	}
+
 	return provider, nil
 }
@@ -0,0 +1,39 @@
+package redis
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// UpdateFilterClusterMember saves the time of the last heartbeat of filter cluster member with given address.
+func (connector *DbConnector) UpdateFilterClusterMember(address string, timestamp int64) error {
+	c := *connector.client
+	if err := c.ZAdd(connector.context, filterClusterMembersKey, &redis.Z{Score: float64(timestamp), Member: address}).Err(); err != nil {
+		return fmt.Errorf("failed to update filter cluster member %s: %w", address, err)
+	}
+	return nil
+}
+
+// GetFilterClusterMembers returns addresses of filter cluster members, which sent heartbeat since given time.
+func (connector *DbConnector) GetFilterClusterMembers(aliveSince int64) ([]string, error) {
+	c := *connector.client
+	rng := &redis.ZRangeBy{Min: strconv.FormatInt(aliveSince, 10), Max: "+inf"}
+	members, err := c.ZRangeByScore(connector.context, filterClusterMembersKey, rng).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get filter cluster members: %w", err)
+	}
+	return members, nil
+}
+
+// RemoveFilterClusterMember removes filter cluster member, e.g. when filter is stopped.
+func (connector *DbConnector) RemoveFilterClusterMember(address string) error {
+	c := *connector.client
+	if err := c.ZRem(connector.context, filterClusterMembersKey, address).Err(); err != nil {
+		return fmt.Errorf("failed to remove filter cluster member %s: %w", address, err)
+	}
+	return nil
+}
+
+const filterClusterMembersKey = "moira-filter-cluster-members"
@@ -0,0 +1,38 @@
+package redis
+
+import (
+	"testing"
+
+	logging "github.com/moira-alert/moira/logging/zerolog_adapter"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestFilterClusterMembers(t *testing.T) {
+	logger, _ := logging.GetLogger("dataBase")
+	dataBase := NewTestDatabase(logger)
+	dataBase.Flush()
+	defer dataBase.Flush()
+
+	Convey("Test filter cluster members", t, func() {
+		err := dataBase.UpdateFilterClusterMember("filter-1:2004", 100)
+		So(err, ShouldBeNil)
+		err = dataBase.UpdateFilterClusterMember("filter-2:2004", 200)
+		So(err, ShouldBeNil)
+
+		members, err := dataBase.GetFilterClusterMembers(150)
+		So(err, ShouldBeNil)
+		So(members, ShouldResemble, []string{"filter-2:2004"})
+
+		err = dataBase.UpdateFilterClusterMember("filter-1:2004", 300)
+		So(err, ShouldBeNil)
+		members, err = dataBase.GetFilterClusterMembers(150)
+		So(err, ShouldBeNil)
+		So(members, ShouldResemble, []string{"filter-2:2004", "filter-1:2004"})
+
+		err = dataBase.RemoveFilterClusterMember("filter-2:2004")
+		So(err, ShouldBeNil)
+		members, err = dataBase.GetFilterClusterMembers(0)
+		So(err, ShouldBeNil)
+		So(members, ShouldResemble, []string{"filter-1:2004"})
+	})
+}
@@ -82,6 +82,15 @@ func (connector *DbConnector) getSelfStateCheckCountKey(clusterKey moira.Cluster
 	case moira.PrometheusRemote:
 		key = selfStatePrometheusChecksCounterKey
 
+	case moira.TenantRemote:
+		key = selfStateTenantChecksCounterKey
+
+	case moira.LokiRemote:
+		key = selfStateLokiChecksCounterKey
+
+	case moira.SQLRemote:
+		key = selfStateSQLChecksCounterKey
+
 	default:
 		return ""
 	}
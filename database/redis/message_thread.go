@@ -0,0 +1,48 @@
+package redis
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/moira-alert/moira/database"
+)
+
+// messageThreadTTL limits lifetime of threads of triggers which never recovered, e.g. were removed.
+const messageThreadTTL = 30 * 24 * time.Hour
+
+// GetMessageThread returns id of messenger thread opened for trigger in given channel.
+func (connector *DbConnector) GetMessageThread(messenger, triggerID, channel string) (string, error) {
+	c := *connector.client
+	result, err := c.Get(connector.context, messageThreadKey(messenger, triggerID, channel)).Result()
+	if errors.Is(err, redis.Nil) {
+		return result, database.ErrNil
+	}
+	if err != nil {
+		return result, fmt.Errorf("failed to get %s thread of trigger %s: %w", messenger, triggerID, err)
+	}
+	return result, nil
+}
+
+// SetMessageThread stores id of messenger thread opened for trigger in given channel.
+func (connector *DbConnector) SetMessageThread(messenger, triggerID, channel, threadID string) error {
+	c := *connector.client
+	if err := c.Set(connector.context, messageThreadKey(messenger, triggerID, channel), threadID, messageThreadTTL).Err(); err != nil {
+		return fmt.Errorf("failed to set %s thread of trigger %s: %w", messenger, triggerID, err)
+	}
+	return nil
+}
+
+// RemoveMessageThread forgets messenger thread of trigger, so the next notification starts a new one.
+func (connector *DbConnector) RemoveMessageThread(messenger, triggerID, channel string) error {
+	c := *connector.client
+	if err := c.Del(connector.context, messageThreadKey(messenger, triggerID, channel)).Err(); err != nil {
+		return fmt.Errorf("failed to remove %s thread of trigger %s: %w", messenger, triggerID, err)
+	}
+	return nil
+}
+
+func messageThreadKey(messenger, triggerID, channel string) string {
+	return fmt.Sprintf("moira-%s-threads:%s:%s", messenger, triggerID, channel)
+}
@@ -0,0 +1,38 @@
+package redis
+
+import (
+	"testing"
+
+	"github.com/moira-alert/moira/database"
+	logging "github.com/moira-alert/moira/logging/zerolog_adapter"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMessageThreads(t *testing.T) {
+	logger, _ := logging.GetLogger("dataBase")
+	dataBase := NewTestDatabase(logger)
+	dataBase.Flush()
+	defer dataBase.Flush()
+
+	Convey("Test message threads", t, func() {
+		_, err := dataBase.GetMessageThread("slack", "trigger1", "#channel")
+		So(err, ShouldResemble, database.ErrNil)
+
+		err = dataBase.SetMessageThread("slack", "trigger1", "#channel", "C123:1503435956.000247")
+		So(err, ShouldBeNil)
+
+		thread, err := dataBase.GetMessageThread("slack", "trigger1", "#channel")
+		So(err, ShouldBeNil)
+		So(thread, ShouldEqual, "C123:1503435956.000247")
+
+		_, err = dataBase.GetMessageThread("slack", "trigger1", "#other")
+		So(err, ShouldResemble, database.ErrNil)
+		_, err = dataBase.GetMessageThread("telegram", "trigger1", "#channel")
+		So(err, ShouldResemble, database.ErrNil)
+
+		err = dataBase.RemoveMessageThread("slack", "trigger1", "#channel")
+		So(err, ShouldBeNil)
+		_, err = dataBase.GetMessageThread("slack", "trigger1", "#channel")
+		So(err, ShouldResemble, database.ErrNil)
+	})
+}
@@ -1,9 +1,11 @@
 package redis
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"math/rand"
 	"strconv"
 	"strings"
@@ -95,6 +97,47 @@ func (connector *DbConnector) getMetricRetention(metric string) (int64, error) {
 	return retention, nil
 }
 
+// GetMetricArchives gets downsampled archives of metric from the most precise to the coarsest,
+// archives are empty if storage schema of metric has the only retention.
+func (connector *DbConnector) GetMetricArchives(metric string) ([]moira.Retention, error) {
+	if value, ok := connector.retentionCache.Get(metricArchivesKey(metric)); ok {
+		if archives, ok := value.([]moira.Retention); ok {
+			return archives, nil
+		}
+	}
+
+	c := *connector.client
+	archives := make([]moira.Retention, 0)
+	archivesStr, err := c.Get(connector.context, metricArchivesKey(metric)).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, fmt.Errorf("failed GET metric archives:%s, error: %w", metric, err)
+	}
+	if err == nil {
+		if err = json.Unmarshal([]byte(archivesStr), &archives); err != nil {
+			return nil, fmt.Errorf("failed to parse metric archives:%s, error: %w", metric, err)
+		}
+	}
+
+	connector.retentionCache.Set(metricArchivesKey(metric), archives, 0)
+	return archives, nil
+}
+
+// GetMetricsArchiveValues gets metrics values for given interval from archive with given step.
+func (connector *DbConnector) GetMetricsArchiveValues(metrics []string, step int64, from int64, until int64) (map[string][]*moira.MetricValue, error) {
+	c := *connector.client
+	res := make(map[string][]*moira.MetricValue, len(metrics))
+
+	for _, metric := range metrics {
+		rng := &redis.ZRangeBy{Min: strconv.FormatInt(from, 10), Max: strconv.FormatInt(until, 10)}
+		metricsValues, err := reply.MetricArchiveValues(c.ZRangeByScoreWithScores(connector.context, metricArchiveDataKey(metric, step), rng))
+		if err != nil {
+			return nil, err
+		}
+		res[metric] = metricsValues
+	}
+	return res, nil
+}
+
 // SaveMetrics saves new metrics.
 func (connector *DbConnector) SaveMetrics(metrics map[string]*moira.MatchedMetric) error {
 	if len(metrics) == 0 {
@@ -119,6 +162,15 @@ func (connector *DbConnector) SaveMetrics(metrics map[string]*moira.MatchedMetri
 			if err = c.Set(ctx, metricRetentionKey(metric.Metric), metric.Retention, redis.KeepTTL).Err(); err != nil {
 				return err
 			}
+			if err = connector.saveMetricArchives(metric); err != nil {
+				return err
+			}
+		}
+
+		if len(metric.Retentions) > 1 && !math.IsNaN(metric.Value) {
+			for _, archive := range metric.Retentions[1:] {
+				addArchiveValue(ctx, pipe, metric, archive)
+			}
 		}
 
 		for _, pattern := range metric.Patterns {
@@ -150,6 +202,77 @@ func (connector *DbConnector) SaveMetrics(metrics map[string]*moira.MatchedMetri
 	return nil
 }
 
+// saveMetricArchives saves downsampled archives of metric storage schema, so local source could read them.
+func (connector *DbConnector) saveMetricArchives(metric *moira.MatchedMetric) error {
+	c := *connector.client
+	if len(metric.Retentions) < 2 { //nolint
+		return c.Del(connector.context, metricArchivesKey(metric.Metric)).Err()
+	}
+
+	archives, err := json.Marshal(metric.Retentions[1:])
+	if err != nil {
+		return err
+	}
+	return c.Set(connector.context, metricArchivesKey(metric.Metric), archives, redis.KeepTTL).Err()
+}
+
+// addArchiveValueScript aggregates value into archive step bucket, which is stored as "bucket value count minCount",
+// and removes values, which are older than archive duration.
+// KEYS: archive key. ARGV: bucket, value, aggregation method, min count, the oldest bucket to keep.
+var addArchiveValueScript = redis.NewScript(`
+local key, bucket, method = KEYS[1], ARGV[1], ARGV[3]
+local value = tonumber(ARGV[2])
+local count = 1
+local members = redis.call('ZRANGEBYSCORE', key, bucket, bucket)
+if #members > 0 then
+	local _, _, previous, previousCount = string.find(members[1], '^%S+ (%S+) (%d+)')
+	if previous and previousCount then
+		previous = tonumber(previous)
+		count = tonumber(previousCount) + 1
+		if method == 'sum' then
+			value = previous + value
+		elseif method == 'min' then
+			value = math.min(previous, value)
+		elseif method == 'max' then
+			value = math.max(previous, value)
+		elseif method ~= 'last' then
+			value = previous + (value - previous) / count
+		end
+	end
+	redis.call('ZREMRANGEBYSCORE', key, bucket, bucket)
+end
+redis.call('ZADD', key, bucket, bucket .. ' ' .. string.format('%.17g', value) .. ' ' .. count .. ' ' .. ARGV[4])
+redis.call('ZREMRANGEBYSCORE', key, '-inf', '(' .. ARGV[5])
+return count
+`)
+
+// addArchiveValue aggregates value of metric into archive step with aggregation method of storage schema.
+// Aggregated value is read only if the ratio of values received during step is not less than xFilesFactor.
+func addArchiveValue(ctx context.Context, pipe redis.Pipeliner, metric *moira.MatchedMetric, archive moira.Retention) {
+	key := metricArchiveDataKey(metric.Metric, archive.Step)
+	bucket := metric.Timestamp - metric.Timestamp%archive.Step
+	addArchiveValueScript.Eval(
+		ctx,
+		pipe,
+		[]string{key},
+		bucket,
+		strconv.FormatFloat(metric.Value, 'g', -1, 64),
+		metric.Aggregation.Method,
+		archiveMinCount(metric, archive),
+		bucket-archive.Duration,
+	)
+}
+
+// archiveMinCount returns the number of values, which should be received during archive step to keep aggregated value.
+func archiveMinCount(metric *moira.MatchedMetric, archive moira.Retention) int64 {
+	if metric.Retention <= 0 {
+		return 0
+	}
+	// Values are rounded to avoid float errors like 0.3 * 10 = 3.0000000000000004
+	minCount := metric.Aggregation.XFilesFactor * float64(archive.Step) / float64(metric.Retention)
+	return int64(math.Ceil(math.Round(minCount*1e6) / 1e6))
+}
+
 // SubscribeMetricEvents creates subscription for new metrics and return channel for this events.
 func (connector *DbConnector) SubscribeMetricEvents(tomb *tomb.Tomb, params *moira.SubscribeMetricEventsParams) (<-chan *moira.MetricEvent, error) {
 	responseChannel := make(chan string, metricEventChannelSize)
@@ -286,8 +409,16 @@ func (connector *DbConnector) RemovePatternWithMetrics(pattern string) error {
 	pipe := (*connector.client).TxPipeline()
 	pipe.SRem(connector.context, patternsListKey, pattern)
 	for _, metric := range metrics {
+		archives, err := connector.GetMetricArchives(metric)
+		if err != nil {
+			return err
+		}
+		for _, archive := range archives {
+			pipe.Del(connector.context, metricArchiveDataKey(metric, archive.Step))
+		}
 		pipe.Del(connector.context, metricDataKey(metric))
 		pipe.Del(connector.context, metricRetentionKey(metric))
+		pipe.Del(connector.context, metricArchivesKey(metric))
 	}
 	pipe.Del(connector.context, patternMetricsKey(pattern))
 	if _, err = pipe.Exec(connector.context); err != nil {
@@ -296,12 +427,24 @@ func (connector *DbConnector) RemovePatternWithMetrics(pattern string) error {
 	return nil
 }
 
-// RemoveMetricRetention remove metric retention.
+// RemoveMetricRetention remove metric retention and downsampled archives of metric.
 func (connector *DbConnector) RemoveMetricRetention(metric string) error {
+	archives, err := connector.GetMetricArchives(metric)
+	if err != nil {
+		return err
+	}
+
 	c := *connector.client
-	if _, err := c.Del(connector.context, metricRetentionKey(metric)).Result(); err != nil {
-		return fmt.Errorf("failed to remove retention, error: %w", err)
+	keys := []string{metricRetentionKey(metric), metricArchivesKey(metric)}
+	for _, archive := range archives {
+		keys = append(keys, metricArchiveDataKey(metric, archive.Step))
 	}
+	for _, key := range keys {
+		if _, err = c.Del(connector.context, key).Result(); err != nil {
+			return fmt.Errorf("failed to remove retention, error: %w", err)
+		}
+	}
+	connector.retentionCache.Delete(metricArchivesKey(metric))
 
 	return nil
 }
@@ -375,7 +518,13 @@ func cleanUpAbandonedRetentionsOnRedisNode(connector *DbConnector, client redis.
 		if err != nil {
 			return fmt.Errorf("failed to check metric data existence, error: %w", err)
 		}
-		if isMetricExists := result == 1; !isMetricExists {
+		if result == 0 {
+			// metric is not received anymore, but its history is still kept in downsampled archives
+			if result, err = connector.trimMetricArchives(metric); err != nil {
+				return err
+			}
+		}
+		if isMetricExists := result > 0; !isMetricExists {
 			err = connector.RemoveMetricRetention(metric)
 			if err != nil {
 				return err
@@ -386,6 +535,30 @@ func cleanUpAbandonedRetentionsOnRedisNode(connector *DbConnector, client redis.
 	return nil
 }
 
+// trimMetricArchives removes values older than archive duration and returns the number of archives, which still have values.
+func (connector *DbConnector) trimMetricArchives(metric string) (int64, error) {
+	archives, err := connector.GetMetricArchives(metric)
+	if err != nil {
+		return 0, err
+	}
+
+	c := *connector.client
+	now := time.Now().Unix()
+	var notEmpty int64
+	for _, archive := range archives {
+		key := metricArchiveDataKey(metric, archive.Step)
+		if err = c.ZRemRangeByScore(connector.context, key, "-inf", "("+strconv.FormatInt(now-archive.Duration, 10)).Err(); err != nil {
+			return 0, fmt.Errorf("failed to remove outdated values of metric archive, error: %w", err)
+		}
+		exists, err := c.Exists(connector.context, key).Result()
+		if err != nil {
+			return 0, fmt.Errorf("failed to check metric archive existence, error: %w", err)
+		}
+		notEmpty += exists
+	}
+	return notEmpty, nil
+}
+
 func (connector *DbConnector) CleanUpOutdatedMetrics(duration time.Duration) error {
 	if duration >= 0 {
 		return errors.New("clean up duration value must be less than zero, otherwise all metrics will be removed")
@@ -396,7 +569,8 @@ func (connector *DbConnector) CleanUpOutdatedMetrics(duration time.Duration) err
 	})
 }
 
-// CleanUpAbandonedRetentions removes metric retention keys that have no corresponding metric data.
+// CleanUpAbandonedRetentions removes metric retention keys that have no corresponding metric data
+// neither in raw values nor in downsampled archives.
 func (connector *DbConnector) CleanUpAbandonedRetentions() error {
 	return connector.callFunc(cleanUpAbandonedRetentionsOnRedisNode)
 }
@@ -418,6 +592,16 @@ func removeMetricsByPrefixOnRedisNode(connector *DbConnector, client redis.Unive
 		}
 	}
 
+	for _, pattern := range []string{metricArchivesKey(fmt.Sprintf("%s*", prefix)), metricArchiveDataKeyPattern(fmt.Sprintf("%s*", prefix))} {
+		metricArchiveIterator := client.Scan(connector.context, 0, pattern, 0).Iterator()
+		for metricArchiveIterator.Next(connector.context) {
+			err := client.Del(connector.context, metricArchiveIterator.Val()).Err()
+			if err != nil {
+				return err
+			}
+		}
+	}
+
 	patternMetricsIterator := client.Scan(connector.context, 0, patternMetricsKey("*"), 0).Iterator()
 	for patternMetricsIterator.Next(connector.context) {
 		patternMetricsSetKey := patternMetricsIterator.Val()
@@ -459,6 +643,16 @@ func removeAllMetricsOnRedisNode(connector *DbConnector, client redis.UniversalC
 		}
 	}
 
+	for _, pattern := range []string{metricArchivesKey("*"), metricArchiveDataKeyPattern("*")} {
+		metricArchiveIterator := client.Scan(connector.context, 0, pattern, 0).Iterator()
+		for metricArchiveIterator.Next(connector.context) {
+			err := client.Del(connector.context, metricArchiveIterator.Val()).Err()
+			if err != nil {
+				return err
+			}
+		}
+	}
+
 	patternMetricsIterator := client.Scan(connector.context, 0, patternMetricsKey("*"), 0).Iterator()
 	for patternMetricsIterator.Next(connector.context) {
 		err := client.Del(connector.context, patternMetricsIterator.Val()).Err()
@@ -505,3 +699,15 @@ func metricDataKey(metric string) string {
 func metricRetentionKey(metric string) string {
 	return "moira-metric-retention:" + metric
 }
+
+func metricArchivesKey(metric string) string {
+	return "moira-metric-archives:" + metric
+}
+
+func metricArchiveDataKey(metric string, step int64) string {
+	return fmt.Sprintf("moira-metric-archive:%d:%s", step, metric)
+}
+
+func metricArchiveDataKeyPattern(metricPattern string) string {
+	return "moira-metric-archive:*:" + metricPattern
+}
@@ -184,6 +184,90 @@ func TestMetricsStoring(t *testing.T) {
 	})
 }
 
+func TestMetricArchivesStoring(t *testing.T) {
+	logger, _ := logging.ConfigureLog("stdout", "warn", "test", true)
+	dataBase := NewTestDatabase(logger)
+	dataBase.Flush()
+	defer dataBase.Flush()
+
+	Convey("Given metric with downsampled archives", t, func() {
+		const metric = "my.test.super.metric"
+		retentions := []moira.Retention{{Step: 10, Duration: 600}, {Step: 60, Duration: 180}}
+
+		for _, ts := range []int64{1000, 1015, 1070, 1200, 1260} {
+			err := dataBase.SaveMetrics(map[string]*moira.MatchedMetric{metric: {
+				Metric:             metric,
+				Value:              float64(ts),
+				Timestamp:          ts,
+				RetentionTimestamp: ts,
+				Retention:          10,
+				Retentions:         retentions,
+			}})
+			So(err, ShouldBeNil)
+		}
+
+		Convey("archives are saved", func() {
+			archives, err := dataBase.GetMetricArchives(metric)
+			So(err, ShouldBeNil)
+			So(archives, ShouldResemble, retentions[1:])
+		})
+
+		Convey("values of step are averaged and values older than archive duration are removed", func() {
+			actualValues, err := dataBase.GetMetricsArchiveValues([]string{metric}, 60, 0, 2000)
+			So(err, ShouldBeNil)
+			So(actualValues, ShouldResemble, map[string][]*moira.MetricValue{
+				metric: {
+					{RetentionTimestamp: 1200, Timestamp: 1200, Value: 1200},
+					{RetentionTimestamp: 1260, Timestamp: 1260, Value: 1260},
+				},
+			})
+		})
+
+		Convey("values of step are aggregated with aggregation method and xFilesFactor", func() {
+			const aggregatedMetric = "my.test.aggregated.metric"
+			for _, ts := range []int64{1200, 1210, 1220, 1260} {
+				err := dataBase.SaveMetrics(map[string]*moira.MatchedMetric{aggregatedMetric: {
+					Metric:             aggregatedMetric,
+					Value:              float64(ts),
+					Timestamp:          ts,
+					RetentionTimestamp: ts,
+					Retention:          10,
+					Retentions:         retentions,
+					Aggregation:        moira.RetentionAggregation{Method: moira.AggregationAverage, XFilesFactor: 0.5},
+				}})
+				So(err, ShouldBeNil)
+			}
+
+			actualValues, err := dataBase.GetMetricsArchiveValues([]string{aggregatedMetric}, 60, 0, 2000)
+			So(err, ShouldBeNil)
+			So(actualValues, ShouldResemble, map[string][]*moira.MetricValue{
+				aggregatedMetric: {
+					{RetentionTimestamp: 1200, Timestamp: 1200, Value: 1210},
+				},
+			})
+		})
+
+		Convey("archives are removed with metric retention", func() {
+			err := dataBase.RemoveMetricRetention(metric)
+			So(err, ShouldBeNil)
+
+			client := *dataBase.client
+			So(client.Exists(dataBase.context, metricArchivesKey(metric)).Val(), ShouldEqual, 0)
+			So(client.Exists(dataBase.context, metricArchiveDataKey(metric, 60)).Val(), ShouldEqual, 0)
+		})
+	})
+}
+
+func TestArchiveMinCount(t *testing.T) {
+	Convey("Test min count of values in archive step", t, func() {
+		archive := moira.Retention{Step: 600, Duration: 86400}
+		for xFilesFactor, expected := range map[float64]int64{0: 0, 0.3: 3, 0.5: 5, 0.55: 6, 1: 10} {
+			metric := &moira.MatchedMetric{Retention: 60, Aggregation: moira.RetentionAggregation{XFilesFactor: xFilesFactor}}
+			So(archiveMinCount(metric, archive), ShouldEqual, expected)
+		}
+	})
+}
+
 func TestRemoveMetricRetention(t *testing.T) {
 	logger, _ := logging.ConfigureLog("stdout", "warn", "test", true)
 	dataBase := NewTestDatabase(logger)
@@ -47,3 +47,53 @@ func MetricValues(values *redis.ZSliceCmd) ([]*moira.MetricValue, error) {
 	}
 	return metricsValues, nil
 }
+
+const (
+	archiveValueFields       = 4
+	legacyArchiveValueFields = 2
+)
+
+// MetricArchiveValues converts redis DB reply struct "Bucket Value Count MinCount" "Bucket" of downsampled archive
+// to moira.MetricValue objects. Values aggregated from less than MinCount values are skipped.
+// Values saved before aggregation was supported are stored as "Bucket Value".
+func MetricArchiveValues(values *redis.ZSliceCmd) ([]*moira.MetricValue, error) {
+	resultByMetricArr, err := values.Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return make([]*moira.MetricValue, 0), nil
+		}
+		return nil, fmt.Errorf("failed to read metric archive values: %s", err.Error())
+	}
+	metricsValues := make([]*moira.MetricValue, 0, len(resultByMetricArr))
+	for _, result := range resultByMetricArr {
+		val := result.Member.(string)
+		valuesArr := strings.Split(val, " ")
+		switch len(valuesArr) {
+		case legacyArchiveValueFields:
+		case archiveValueFields:
+			count, err := strconv.ParseInt(valuesArr[2], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("archive values count format is not valid: %s", err.Error())
+			}
+			minCount, err := strconv.ParseInt(valuesArr[3], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("archive values min count format is not valid: %s", err.Error())
+			}
+			if count < minCount {
+				continue
+			}
+		default:
+			return nil, fmt.Errorf("archive value format is not valid: %s", val)
+		}
+		value, err := strconv.ParseFloat(valuesArr[1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("metric value format is not valid: %s", err.Error())
+		}
+		metricsValues = append(metricsValues, &moira.MetricValue{
+			RetentionTimestamp: int64(result.Score),
+			Timestamp:          int64(result.Score),
+			Value:              value,
+		})
+	}
+	return metricsValues, nil
+}
@@ -0,0 +1,39 @@
+package reply
+
+import (
+	"testing"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/moira-alert/moira"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMetricArchiveValues(t *testing.T) {
+	Convey("Test metric archive values", t, func() {
+		Convey("Values aggregated from less than min count values are skipped", func() {
+			values, err := MetricArchiveValues(redis.NewZSliceCmdResult([]redis.Z{
+				{Score: 60, Member: "60 1.5 3 3"},
+				{Score: 120, Member: "120 2 1 3"},
+				{Score: 180, Member: "180 3 1 0"},
+				{Score: 240, Member: "250 4"},
+			}, nil))
+			So(err, ShouldBeNil)
+			So(values, ShouldResemble, []*moira.MetricValue{
+				{RetentionTimestamp: 60, Timestamp: 60, Value: 1.5},
+				{RetentionTimestamp: 180, Timestamp: 180, Value: 3},
+				{RetentionTimestamp: 240, Timestamp: 240, Value: 4},
+			})
+		})
+
+		Convey("Empty archive", func() {
+			values, err := MetricArchiveValues(redis.NewZSliceCmdResult(nil, redis.Nil))
+			So(err, ShouldBeNil)
+			So(values, ShouldBeEmpty)
+		})
+
+		Convey("Invalid value", func() {
+			_, err := MetricArchiveValues(redis.NewZSliceCmdResult([]redis.Z{{Score: 60, Member: "60 1.5 1"}}, nil))
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
@@ -13,29 +13,31 @@ import (
 
 // Duty hack for moira.Trigger TTL int64 and stored trigger TTL string compatibility.
 type triggerStorageElement struct {
-	ID               string              `json:"id"`
-	Name             string              `json:"name"`
-	Desc             *string             `json:"desc,omitempty"`
-	Targets          []string            `json:"targets"`
-	WarnValue        *float64            `json:"warn_value"`
-	ErrorValue       *float64            `json:"error_value"`
-	TriggerType      string              `json:"trigger_type,omitempty"`
-	Tags             []string            `json:"tags"`
-	TTLState         *moira.TTLState     `json:"ttl_state,omitempty"`
-	Schedule         *moira.ScheduleData `json:"sched,omitempty"`
-	Expression       *string             `json:"expr,omitempty"`
-	PythonExpression *string             `json:"expression,omitempty"`
-	Patterns         []string            `json:"patterns"`
-	TTL              string              `json:"ttl,omitempty"`
-	IsRemote         bool                `json:"is_remote"`
-	TriggerSource    moira.TriggerSource `json:"trigger_source,omitempty"`
-	ClusterId        moira.ClusterId     `json:"cluster_id,omitempty"`
-	MuteNewMetrics   bool                `json:"mute_new_metrics,omitempty"`
-	AloneMetrics     map[string]bool     `json:"alone_metrics"`
-	CreatedAt        *int64              `json:"created_at"`
-	UpdatedAt        *int64              `json:"updated_at"`
-	CreatedBy        string              `json:"created_by"`
-	UpdatedBy        string              `json:"updated_by"`
+	ID                 string                    `json:"id"`
+	Name               string                    `json:"name"`
+	Desc               *string                   `json:"desc,omitempty"`
+	Targets            []string                  `json:"targets"`
+	WarnValue          *float64                  `json:"warn_value"`
+	ErrorValue         *float64                  `json:"error_value"`
+	TriggerType        string                    `json:"trigger_type,omitempty"`
+	Tags               []string                  `json:"tags"`
+	TTLState           *moira.TTLState           `json:"ttl_state,omitempty"`
+	Schedule           *moira.ScheduleData       `json:"sched,omitempty"`
+	Expression         *string                   `json:"expr,omitempty"`
+	PythonExpression   *string                   `json:"expression,omitempty"`
+	Patterns           []string                  `json:"patterns"`
+	TTL                string                    `json:"ttl,omitempty"`
+	IsRemote           bool                      `json:"is_remote"`
+	TriggerSource      moira.TriggerSource       `json:"trigger_source,omitempty"`
+	ClusterId          moira.ClusterId           `json:"cluster_id,omitempty"`
+	MuteNewMetrics     bool                      `json:"mute_new_metrics,omitempty"`
+	AloneMetrics       map[string]bool           `json:"alone_metrics"`
+	FetchOptions       *moira.FetchOptions       `json:"fetch_options,omitempty"`
+	ThresholdOverrides []moira.ThresholdOverride `json:"threshold_overrides,omitempty"`
+	CreatedAt          *int64                    `json:"created_at"`
+	UpdatedAt          *int64                    `json:"updated_at"`
+	CreatedBy          string                    `json:"created_by"`
+	UpdatedBy          string                    `json:"updated_by"`
 }
 
 func (storageElement *triggerStorageElement) toTrigger() moira.Trigger {
@@ -53,56 +55,60 @@ func (storageElement *triggerStorageElement) toTrigger() moira.Trigger {
 	triggerSource := storageElement.TriggerSource.FillInIfNotSet(storageElement.IsRemote)
 	clusterId := storageElement.ClusterId.FillInIfNotSet()
 	return moira.Trigger{
-		ID:               storageElement.ID,
-		Name:             storageElement.Name,
-		Desc:             storageElement.Desc,
-		Targets:          storageElement.Targets,
-		WarnValue:        storageElement.WarnValue,
-		ErrorValue:       storageElement.ErrorValue,
-		TriggerType:      storageElement.TriggerType,
-		Tags:             storageElement.Tags,
-		TTLState:         storageElement.TTLState,
-		Schedule:         storageElement.Schedule,
-		Expression:       storageElement.Expression,
-		PythonExpression: storageElement.PythonExpression,
-		Patterns:         storageElement.Patterns,
-		TTL:              getTriggerTTL(storageElement.TTL),
-		TriggerSource:    triggerSource,
-		ClusterId:        clusterId,
-		MuteNewMetrics:   storageElement.MuteNewMetrics,
-		AloneMetrics:     storageElement.AloneMetrics,
-		CreatedAt:        storageElement.CreatedAt,
-		UpdatedAt:        storageElement.UpdatedAt,
-		CreatedBy:        storageElement.CreatedBy,
-		UpdatedBy:        storageElement.UpdatedBy,
+		ID:                 storageElement.ID,
+		Name:               storageElement.Name,
+		Desc:               storageElement.Desc,
+		Targets:            storageElement.Targets,
+		WarnValue:          storageElement.WarnValue,
+		ErrorValue:         storageElement.ErrorValue,
+		TriggerType:        storageElement.TriggerType,
+		Tags:               storageElement.Tags,
+		TTLState:           storageElement.TTLState,
+		Schedule:           storageElement.Schedule,
+		Expression:         storageElement.Expression,
+		PythonExpression:   storageElement.PythonExpression,
+		Patterns:           storageElement.Patterns,
+		TTL:                getTriggerTTL(storageElement.TTL),
+		TriggerSource:      triggerSource,
+		ClusterId:          clusterId,
+		MuteNewMetrics:     storageElement.MuteNewMetrics,
+		AloneMetrics:       storageElement.AloneMetrics,
+		FetchOptions:       storageElement.FetchOptions,
+		ThresholdOverrides: storageElement.ThresholdOverrides,
+		CreatedAt:          storageElement.CreatedAt,
+		UpdatedAt:          storageElement.UpdatedAt,
+		CreatedBy:          storageElement.CreatedBy,
+		UpdatedBy:          storageElement.UpdatedBy,
 	}
 }
 
 func toTriggerStorageElement(trigger *moira.Trigger, triggerID string) *triggerStorageElement {
 	return &triggerStorageElement{
-		ID:               triggerID,
-		Name:             trigger.Name,
-		Desc:             trigger.Desc,
-		Targets:          trigger.Targets,
-		WarnValue:        trigger.WarnValue,
-		ErrorValue:       trigger.ErrorValue,
-		TriggerType:      trigger.TriggerType,
-		Tags:             trigger.Tags,
-		TTLState:         trigger.TTLState,
-		Schedule:         trigger.Schedule,
-		Expression:       trigger.Expression,
-		PythonExpression: trigger.PythonExpression,
-		Patterns:         trigger.Patterns,
-		TTL:              getTriggerTTLString(trigger.TTL),
-		IsRemote:         trigger.TriggerSource == moira.GraphiteRemote,
-		TriggerSource:    trigger.TriggerSource,
-		ClusterId:        trigger.ClusterId,
-		MuteNewMetrics:   trigger.MuteNewMetrics,
-		AloneMetrics:     trigger.AloneMetrics,
-		CreatedAt:        trigger.CreatedAt,
-		UpdatedAt:        trigger.UpdatedAt,
-		CreatedBy:        trigger.CreatedBy,
-		UpdatedBy:        trigger.UpdatedBy,
+		ID:                 triggerID,
+		Name:               trigger.Name,
+		Desc:               trigger.Desc,
+		Targets:            trigger.Targets,
+		WarnValue:          trigger.WarnValue,
+		ErrorValue:         trigger.ErrorValue,
+		TriggerType:        trigger.TriggerType,
+		Tags:               trigger.Tags,
+		TTLState:           trigger.TTLState,
+		Schedule:           trigger.Schedule,
+		Expression:         trigger.Expression,
+		PythonExpression:   trigger.PythonExpression,
+		Patterns:           trigger.Patterns,
+		TTL:                getTriggerTTLString(trigger.TTL),
+		IsRemote:           trigger.TriggerSource == moira.GraphiteRemote,
+		TriggerSource:      trigger.TriggerSource,
+		ClusterId:          trigger.ClusterId,
+		MuteNewMetrics:     trigger.MuteNewMetrics,
+		AloneMetrics:       trigger.AloneMetrics,
+		FetchOptions:       trigger.FetchOptions,
+		ThresholdOverrides: trigger.ThresholdOverrides,
+		CreatedAt:          trigger.CreatedAt,
+		UpdatedAt:          trigger.UpdatedAt,
+		CreatedBy:          trigger.CreatedBy,
+		UpdatedBy:          trigger.UpdatedBy,
 	}
 }
 
@@ -78,5 +78,8 @@ var (
 	selfStateChecksCounterKey           = "moira-selfstate:checks-counter"
 	selfStateRemoteChecksCounterKey     = "moira-selfstate:remote-checks-counter"
 	selfStatePrometheusChecksCounterKey = "moira-selfstate:prometheus-checks-counter"
+	selfStateTenantChecksCounterKey     = "moira-selfstate:tenant-checks-counter"
+	selfStateLokiChecksCounterKey       = "moira-selfstate:loki-checks-counter"
+	selfStateSQLChecksCounterKey        = "moira-selfstate:sql-checks-counter"
 	selfStateNotifierHealth             = "moira-selfstate:notifier-health"
 )
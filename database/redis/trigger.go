@@ -428,6 +428,10 @@ var (
 	localTriggersListKey      = "{moira-triggers-list}:moira-local-triggers-list"
 	remoteTriggersListKey     = "{moira-triggers-list}:moira-remote-triggers-list"
 	prometheusTriggersListKey = "{moira-triggers-list}:moira-prometheus-triggers-list"
+	tenantTriggersListKey     = "{moira-triggers-list}:moira-tenant-triggers-list"
+	lokiTriggersListKey       = "{moira-triggers-list}:moira-loki-triggers-list"
+	sqlTriggersListKey        = "{moira-triggers-list}:moira-sql-triggers-list"
+	externalTriggersListKey   = "{moira-triggers-list}:moira-external-triggers-list"
 )
 
 func makeTriggerListKey(clusterKey moira.ClusterKey) (string, error) {
@@ -443,6 +447,18 @@ func makeTriggerListKey(clusterKey moira.ClusterKey) (string, error) {
 	case moira.PrometheusRemote:
 		key = prometheusTriggersListKey
 
+	case moira.TenantRemote:
+		key = tenantTriggersListKey
+
+	case moira.LokiRemote:
+		key = lokiTriggersListKey
+
+	case moira.SQLRemote:
+		key = sqlTriggersListKey
+
+	case moira.External:
+		key = externalTriggersListKey
+
 	default:
 		return "", fmt.Errorf("unknown trigger source %s", clusterKey.TriggerSource)
 	}
@@ -121,6 +121,9 @@ func (connector *DbConnector) getTriggersToCheckCount(key string) (int64, error)
 const (
 	remoteTriggersToCheckKey     = "moira-remote-triggers-to-check"
 	prometheusTriggersToCheckKey = "moira-prometheus-triggers-to-check"
+	tenantTriggersToCheckKey     = "moira-tenant-triggers-to-check"
+	lokiTriggersToCheckKey       = "moira-loki-triggers-to-check"
+	sqlTriggersToCheckKey        = "moira-sql-triggers-to-check"
 	localTriggersToCheckKey      = "moira-triggers-to-check"
 )
 
@@ -137,6 +140,15 @@ func makeTriggersToCheckKey(clusterKey moira.ClusterKey) (string, error) {
 	case moira.PrometheusRemote:
 		key = prometheusTriggersToCheckKey
 
+	case moira.TenantRemote:
+		key = tenantTriggersToCheckKey
+
+	case moira.LokiRemote:
+		key = lokiTriggersToCheckKey
+
+	case moira.SQLRemote:
+		key = sqlTriggersToCheckKey
+
 	default:
 		return "", fmt.Errorf("unknown trigger source `%s`", clusterKey.TriggerSource.String())
 	}
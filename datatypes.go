@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/dustin/go-humanize"
+	"github.com/moira-alert/moira/matching"
 	"github.com/moira-alert/moira/templating"
 )
 
@@ -152,7 +153,9 @@ func (events NotificationEvents) ToTemplateEvents() []templating.Event {
 			MetricElements: strings.Split(event.Metric, "."),
 			Timestamp:      event.Timestamp,
 			State:          string(event.State),
+			OldState:       string(event.OldState),
 			Value:          event.Value,
+			Values:         event.Values,
 		})
 	}
 
@@ -173,6 +176,17 @@ type TriggerData struct {
 	Tags          []string      `json:"__notifier_trigger_tags" example:"server,disk"`
 }
 
+// ToTemplateTrigger converts a TriggerData into a template MessageTrigger.
+func (trigger TriggerData) ToTemplateTrigger(frontURI string) *templating.MessageTrigger {
+	return &templating.MessageTrigger{
+		ID:   trigger.ID,
+		Name: trigger.Name,
+		Desc: trigger.Desc,
+		Tags: trigger.Tags,
+		URI:  trigger.GetTriggerURI(frontURI),
+	}
+}
+
 // GetTriggerSource returns trigger source associated with the trigger.
 func (trigger TriggerData) GetTriggerSource() TriggerSource {
 	return trigger.TriggerSource.FillInIfNotSet(trigger.IsRemote)
@@ -200,6 +214,10 @@ type ContactData struct {
 	ID    string `json:"id" example:"1dd38765-c5be-418d-81fa-7a5f879c2315"`
 	User  string `json:"user" example:""`
 	Team  string `json:"team"`
+	// Template is an optional message template which overrides the sender message format.
+	Template string `json:"template,omitempty" example:"{{ .State }} {{ .Trigger.Name }}"`
+	// DedupBy overrides sender dedup mode of on-call senders, "trigger" or "metric".
+	DedupBy string `json:"dedup_by,omitempty" example:"trigger"`
 }
 
 // ToTemplateContact converts a ContactData into a template Contact.
@@ -207,6 +225,9 @@ func (contact *ContactData) ToTemplateContact() *templating.Contact {
 	return &templating.Contact{
 		Type:  contact.Type,
 		Value: contact.Value,
+		ID:    contact.ID,
+		User:  contact.User,
+		Team:  contact.Team,
 	}
 }
 
@@ -310,6 +331,31 @@ type MatchedMetric struct {
 	Timestamp          int64
 	RetentionTimestamp int64
 	Retention          int
+	Retentions         []Retention
+	Aggregation        RetentionAggregation
+}
+
+// Retention is an archive of storage schema: metric values are kept with Step precision for Duration seconds.
+type Retention struct {
+	Step     int64 `json:"step"`
+	Duration int64 `json:"duration"`
+}
+
+// Aggregation methods of values downsampled into archives of storage schema.
+const (
+	AggregationAverage = "average"
+	AggregationSum     = "sum"
+	AggregationMin     = "min"
+	AggregationMax     = "max"
+	AggregationLast    = "last"
+)
+
+// RetentionAggregation defines how values are downsampled into archives of storage schema as carbon does.
+type RetentionAggregation struct {
+	// Method is one of average, sum, min, max and last
+	Method string
+	// XFilesFactor is the minimal ratio of values received during archive step, which keeps aggregated value
+	XFilesFactor float64
 }
 
 // MetricValue represents metric data.
@@ -330,28 +376,30 @@ const (
 
 // Trigger represents trigger data object.
 type Trigger struct {
-	ID               string          `json:"id" example:"292516ed-4924-4154-a62c-ebe312431fce"`
-	Name             string          `json:"name" example:"Not enough disk space left"`
-	Desc             *string         `json:"desc,omitempty" example:"check the size of /var/log" extensions:"x-nullable"`
-	Targets          []string        `json:"targets" example:"devOps.my_server.hdd.freespace_mbytes"`
-	WarnValue        *float64        `json:"warn_value" example:"5000" extensions:"x-nullable"`
-	ErrorValue       *float64        `json:"error_value" example:"1000" extensions:"x-nullable"`
-	TriggerType      string          `json:"trigger_type" example:"rising"`
-	Tags             []string        `json:"tags" example:"server,disk"`
-	TTLState         *TTLState       `json:"ttl_state,omitempty" example:"NODATA" extensions:"x-nullable"`
-	TTL              int64           `json:"ttl,omitempty" example:"600" format:"int64"`
-	Schedule         *ScheduleData   `json:"sched,omitempty" extensions:"x-nullable"`
-	Expression       *string         `json:"expression,omitempty" example:"" extensions:"x-nullable"`
-	PythonExpression *string         `json:"python_expression,omitempty" extensions:"x-nullable"`
-	Patterns         []string        `json:"patterns" example:""`
-	TriggerSource    TriggerSource   `json:"trigger_source,omitempty" example:"graphite_local"`
-	ClusterId        ClusterId       `json:"cluster_id,omitempty" example:"default"`
-	MuteNewMetrics   bool            `json:"mute_new_metrics" example:"false"`
-	AloneMetrics     map[string]bool `json:"alone_metrics" example:"t1:true"`
-	CreatedAt        *int64          `json:"created_at" format:"int64" extensions:"x-nullable"`
-	UpdatedAt        *int64          `json:"updated_at" format:"int64" extensions:"x-nullable"`
-	CreatedBy        string          `json:"created_by"`
-	UpdatedBy        string          `json:"updated_by"`
+	ID                 string              `json:"id" example:"292516ed-4924-4154-a62c-ebe312431fce"`
+	Name               string              `json:"name" example:"Not enough disk space left"`
+	Desc               *string             `json:"desc,omitempty" example:"check the size of /var/log" extensions:"x-nullable"`
+	Targets            []string            `json:"targets" example:"devOps.my_server.hdd.freespace_mbytes"`
+	WarnValue          *float64            `json:"warn_value" example:"5000" extensions:"x-nullable"`
+	ErrorValue         *float64            `json:"error_value" example:"1000" extensions:"x-nullable"`
+	TriggerType        string              `json:"trigger_type" example:"rising"`
+	Tags               []string            `json:"tags" example:"server,disk"`
+	TTLState           *TTLState           `json:"ttl_state,omitempty" example:"NODATA" extensions:"x-nullable"`
+	TTL                int64               `json:"ttl,omitempty" example:"600" format:"int64"`
+	Schedule           *ScheduleData       `json:"sched,omitempty" extensions:"x-nullable"`
+	Expression         *string             `json:"expression,omitempty" example:"" extensions:"x-nullable"`
+	PythonExpression   *string             `json:"python_expression,omitempty" extensions:"x-nullable"`
+	Patterns           []string            `json:"patterns" example:""`
+	TriggerSource      TriggerSource       `json:"trigger_source,omitempty" example:"graphite_local"`
+	ClusterId          ClusterId           `json:"cluster_id,omitempty" example:"default"`
+	MuteNewMetrics     bool                `json:"mute_new_metrics" example:"false"`
+	AloneMetrics       map[string]bool     `json:"alone_metrics" example:"t1:true"`
+	FetchOptions       *FetchOptions       `json:"fetch_options,omitempty" extensions:"x-nullable"`
+	ThresholdOverrides []ThresholdOverride `json:"threshold_overrides,omitempty"`
+	CreatedAt          *int64              `json:"created_at" format:"int64" extensions:"x-nullable"`
+	UpdatedAt          *int64              `json:"updated_at" format:"int64" extensions:"x-nullable"`
+	CreatedBy          string              `json:"created_by"`
+	UpdatedBy          string              `json:"updated_by"`
 }
 
 // ClusterKey returns cluster key composed of trigger source and cluster id associated with the trigger.
@@ -359,6 +407,119 @@ func (trigger *Trigger) ClusterKey() ClusterKey {
 	return MakeClusterKey(trigger.TriggerSource, trigger.ClusterId)
 }
 
+// FetchOptions represents trigger specific settings of metrics fetching.
+// Metric sources that don't support some of these settings ignore them.
+type FetchOptions struct {
+	// Step is a resolution of fetched metrics in seconds, if not set metric source default is used.
+	Step int64 `json:"step,omitempty" example:"15" format:"int64"`
+	// Instant enables fetching of the last metric values only instead of the whole range.
+	Instant bool `json:"instant,omitempty" example:"false"`
+}
+
+// WithoutInstant returns copy of fetch options with instant mode disabled.
+// It is used when the whole range of metrics is needed, e.g. to render plots.
+func (options *FetchOptions) WithoutInstant() *FetchOptions {
+	if options == nil {
+		return nil
+	}
+	return &FetchOptions{Step: options.Step}
+}
+
+// ThresholdOverride replaces trigger thresholds or expression for metrics matching the name glob and all the tag matches.
+// Values, which are not set in override, are taken from the trigger.
+type ThresholdOverride struct {
+	// Metric is a glob of metric name, e.g. servers.db-*.cpu or servers.{db-1,db-2}.cpu
+	Metric string `json:"metric,omitempty" example:"servers.db-*.cpu"`
+	// Tags are seriesByTag label matches: name=value, name!=value, name=~regex or name!=~regex
+	Tags       []string `json:"tags,omitempty" example:"host=~db-.*"`
+	WarnValue  *float64 `json:"warn_value,omitempty" example:"90" extensions:"x-nullable"`
+	ErrorValue *float64 `json:"error_value,omitempty" example:"95" extensions:"x-nullable"`
+	Expression *string  `json:"expression,omitempty" example:"" extensions:"x-nullable"`
+}
+
+// MetricThresholds are thresholds and expression, which are used to check one metric of the trigger.
+type MetricThresholds struct {
+	WarnValue  *float64
+	ErrorValue *float64
+	Expression *string
+}
+
+// GetMetricThresholds returns thresholds of metric taking overrides into account.
+// Overrides with invalid globs or tag matches are skipped, they are rejected by API validation anyway.
+func (trigger *Trigger) GetMetricThresholds(metricName string) MetricThresholds {
+	thresholds := MetricThresholds{
+		WarnValue:  trigger.WarnValue,
+		ErrorValue: trigger.ErrorValue,
+		Expression: trigger.Expression,
+	}
+
+	for _, override := range trigger.ThresholdOverrides {
+		if matched, err := override.MatchMetric(metricName); err != nil || !matched {
+			continue
+		}
+		if override.WarnValue != nil {
+			thresholds.WarnValue = override.WarnValue
+		}
+		if override.ErrorValue != nil {
+			thresholds.ErrorValue = override.ErrorValue
+		}
+		if override.Expression != nil {
+			thresholds.Expression = override.Expression
+		}
+		break
+	}
+	return thresholds
+}
+
+// MatchMetric returns true if metric name matches the glob and all the tag matches of override.
+// Name of tagged metric is in format name;tag1=value1;tag2=value2.
+func (override *ThresholdOverride) MatchMetric(metricName string) (bool, error) {
+	name, tags := SplitMetricNameAndTags(metricName)
+
+	if override.Metric != "" {
+		matched, err := matching.MatchGlob(override.Metric, name)
+		if err != nil || !matched {
+			return false, err
+		}
+	}
+
+	for _, tagMatch := range override.Tags {
+		matched, err := matching.MatchTag(tagMatch, name, tags)
+		if err != nil || !matched {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// Validate checks metric glob and tag matches of override.
+func (override *ThresholdOverride) Validate() error {
+	if override.Metric == "" && len(override.Tags) == 0 {
+		return fmt.Errorf("metric or tags is required")
+	}
+	if _, err := matching.MatchGlob(override.Metric, ""); err != nil {
+		return err
+	}
+	for _, tagMatch := range override.Tags {
+		if _, err := matching.MatchTag(tagMatch, "", map[string]string{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SplitMetricNameAndTags splits metric in format name;tag1=value1;tag2=value2 to name and tags.
+func SplitMetricNameAndTags(metricName string) (string, map[string]string) {
+	parts := strings.Split(metricName, ";")
+	tags := make(map[string]string, len(parts)-1)
+	for _, part := range parts[1:] {
+		if key, value, found := strings.Cut(part, "="); found {
+			tags[key] = value
+		}
+	}
+	return parts[0], tags
+}
+
 // TriggerSource is a enum which values correspond to types of moira's metric sources.
 type TriggerSource string
 
@@ -367,6 +528,11 @@ var (
 	GraphiteLocal       TriggerSource = "graphite_local"
 	GraphiteRemote      TriggerSource = "graphite_remote"
 	PrometheusRemote    TriggerSource = "prometheus_remote"
+	TenantRemote        TriggerSource = "tenant_remote"
+	LokiRemote          TriggerSource = "loki_remote"
+	SQLRemote           TriggerSource = "sql_remote"
+	// External triggers are not checked by moira, their events are pushed via API by external alerting systems.
+	External TriggerSource = "external"
 )
 
 func (s *TriggerSource) UnmarshalJSON(data []byte) error {
@@ -376,7 +542,9 @@ func (s *TriggerSource) UnmarshalJSON(data []byte) error {
 	}
 
 	source := TriggerSource(v)
-	if source != GraphiteLocal && source != GraphiteRemote && source != PrometheusRemote {
+	switch source {
+	case GraphiteLocal, GraphiteRemote, PrometheusRemote, TenantRemote, LokiRemote, SQLRemote, External:
+	default:
 		*s = TriggerSourceNotSet
 		return nil
 	}
@@ -431,6 +599,10 @@ var (
 	DefaultLocalCluster            = MakeClusterKey(GraphiteLocal, DefaultCluster)
 	DefaultGraphiteRemoteCluster   = MakeClusterKey(GraphiteRemote, DefaultCluster)
 	DefaultPrometheusRemoteCluster = MakeClusterKey(PrometheusRemote, DefaultCluster)
+	DefaultTenantRemoteCluster     = MakeClusterKey(TenantRemote, DefaultCluster)
+	DefaultLokiRemoteCluster       = MakeClusterKey(LokiRemote, DefaultCluster)
+	DefaultSQLRemoteCluster        = MakeClusterKey(SQLRemote, DefaultCluster)
+	DefaultExternalCluster         = MakeClusterKey(External, DefaultCluster)
 )
 
 // MakeClusterKey creates new cluster key with given trigger source and cluster id.
@@ -491,6 +491,77 @@ func TestTrigger_IsSimple(t *testing.T) {
 	})
 }
 
+func TestTrigger_GetMetricThresholds(t *testing.T) {
+	warnValue, errorValue := float64(10), float64(20)
+	dbWarnValue, dbErrorValue := float64(50), float64(90)
+	eastErrorValue := float64(30)
+	trigger := Trigger{
+		WarnValue:  &warnValue,
+		ErrorValue: &errorValue,
+		ThresholdOverrides: []ThresholdOverride{
+			{Metric: "servers.{db-1,db-2}.cpu", WarnValue: &dbWarnValue, ErrorValue: &dbErrorValue},
+			{Metric: "servers.db-*.cpu", Tags: []string{"dc=~east"}, ErrorValue: &eastErrorValue},
+			{Tags: []string{"name=cpu", "host=~db-.*", "dc!=west"}, ErrorValue: &eastErrorValue},
+		},
+	}
+
+	Convey("Metric without override has trigger thresholds", t, func() {
+		thresholds := trigger.GetMetricThresholds("servers.web-1.cpu")
+		So(thresholds, ShouldResemble, MetricThresholds{WarnValue: &warnValue, ErrorValue: &errorValue})
+	})
+
+	Convey("Metric matching glob has override thresholds", t, func() {
+		thresholds := trigger.GetMetricThresholds("servers.db-2.cpu")
+		So(thresholds, ShouldResemble, MetricThresholds{WarnValue: &dbWarnValue, ErrorValue: &dbErrorValue})
+	})
+
+	Convey("Glob star does not match dots", t, func() {
+		thresholds := trigger.GetMetricThresholds("servers.db-3.cpu.total")
+		So(thresholds, ShouldResemble, MetricThresholds{WarnValue: &warnValue, ErrorValue: &errorValue})
+	})
+
+	Convey("Values not set in override are taken from trigger", t, func() {
+		thresholds := trigger.GetMetricThresholds("servers.db-3.cpu;dc=east-1")
+		So(thresholds, ShouldResemble, MetricThresholds{WarnValue: &warnValue, ErrorValue: &eastErrorValue})
+	})
+
+	Convey("Metric should match all tags of override", t, func() {
+		thresholds := trigger.GetMetricThresholds("cpu;host=db-1;dc=north")
+		So(thresholds.ErrorValue, ShouldEqual, &eastErrorValue)
+
+		thresholds = trigger.GetMetricThresholds("cpu;host=db-1;dc=west")
+		So(thresholds.ErrorValue, ShouldEqual, &errorValue)
+	})
+
+	Convey("Absent tag has empty value in tag matches", t, func() {
+		for _, tagMatch := range []string{"dc!=west", "dc!=~west", "dc=~.*", "dc="} {
+			override := ThresholdOverride{Tags: []string{tagMatch}}
+			matched, err := override.MatchMetric("cpu;host=db-1")
+			So(err, ShouldBeNil)
+			So(matched, ShouldBeTrue)
+		}
+	})
+}
+
+func TestThresholdOverride_Validate(t *testing.T) {
+	Convey("Valid override", t, func() {
+		override := ThresholdOverride{Metric: "servers.*.cpu", Tags: []string{"dc=east", "host!=~web-.*"}}
+		So(override.Validate(), ShouldBeNil)
+	})
+
+	Convey("Override without metric and tags", t, func() {
+		So((&ThresholdOverride{}).Validate().Error(), ShouldEqual, "metric or tags is required")
+	})
+
+	Convey("Invalid tag matches", t, func() {
+		override := ThresholdOverride{Tags: []string{"host"}}
+		So(override.Validate().Error(), ShouldEqual, "invalid tag match host, it should be in format tag=value, tag!=value, tag=~regex or tag!=~regex")
+
+		override = ThresholdOverride{Tags: []string{"host=~db-("}}
+		So(override.Validate(), ShouldNotBeNil)
+	})
+}
+
 func TestCheckData_IsTriggerOnMaintenance(t *testing.T) {
 	Convey("IsTriggerOnMaintenance manipulations", t, func() {
 		checkData := &CheckData{
@@ -8,6 +8,7 @@ import (
 
 	"github.com/Knetic/govaluate"
 	"github.com/moira-alert/moira"
+	metricSource "github.com/moira-alert/moira/metric_source"
 )
 
 var (
@@ -41,6 +42,10 @@ type TriggerExpression struct {
 	MainTargetValue         float64
 	AdditionalTargetsValues map[string]float64
 	PreviousState           moira.State
+
+	// Metrics and Timestamp are used by functions, which need metric history, name or tags.
+	Metrics   map[string]metricSource.MetricData
+	Timestamp int64
 }
 
 // Get realizing govaluate.Parameters interface used in evaluable expression.
@@ -70,6 +75,10 @@ func (triggerExpression TriggerExpression) Get(name string) (interface{}, error)
 		return triggerExpression.MainTargetValue, nil
 	case "prev_state":
 		return triggerExpression.PreviousState, nil
+	case "metric_name":
+		return triggerExpression.Metrics["t1"].Name, nil
+	case contextVariable:
+		return &triggerExpression, nil
 	default:
 		value, ok := triggerExpression.AdditionalTargetsValues[name]
 		if !ok {
@@ -157,10 +166,10 @@ func getUserExpression(triggerExpression string) (*govaluate.EvaluableExpression
 		return expr.(*govaluate.EvaluableExpression), nil
 	}
 
-	expr, err := govaluate.NewEvaluableExpression(triggerExpression)
+	expr, err := govaluate.NewEvaluableExpressionWithFunctions(rewriteExpression(triggerExpression), functions)
 	if err != nil {
 		if strings.Contains(err.Error(), "Undefined function") {
-			return nil, fmt.Errorf("functions is forbidden")
+			return nil, fmt.Errorf("%w, available functions: %s", err, availableFunctions())
 		}
 		return nil, err
 	}
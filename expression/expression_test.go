@@ -79,7 +79,12 @@ func TestExpression(t *testing.T) {
 
 		expression = "min(t1, t2) > 10 ? ERROR : OK"
 		result, err = (&TriggerExpression{Expression: &expression, MainTargetValue: 11.0, AdditionalTargetsValues: map[string]float64{"t2": 4.0}, TriggerType: moira.ExpressionTrigger}).Evaluate()
-		So(err, ShouldResemble, ErrInvalidExpression{fmt.Errorf("functions is forbidden")})
+		So(err, ShouldBeNil)
+		So(result, ShouldResemble, moira.StateOK)
+
+		expression = "sin(t1) > 10 ? ERROR : OK"
+		result, err = (&TriggerExpression{Expression: &expression, MainTargetValue: 11.0, TriggerType: moira.ExpressionTrigger}).Evaluate()
+		So(err.Error(), ShouldEqual, "Undefined function sin, available functions: abs, avg, delta, max, min, percentile, prev, sum, tag")
 		So(result, ShouldBeEmpty)
 
 		expression = "PREV_STATE"
@@ -0,0 +1,319 @@
+package expression
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Knetic/govaluate"
+	"github.com/moira-alert/moira"
+	metricSource "github.com/moira-alert/moira/metric_source"
+)
+
+// contextVariable is a hidden variable which passes trigger expression to functions,
+// that need metrics data: window aggregates, previous value and tags.
+const contextVariable = "expression_context"
+
+var (
+	windowFunctionRegex = regexp.MustCompile(`\b(avg|sum|delta|percentile)\(\s*(t\d+)\s*,\s*'?(\d+[smhdw])'?`)
+	prevFunctionRegex   = regexp.MustCompile(`\bprev\(\s*(t\d+)\s*\)`)
+	tagFunctionRegex    = regexp.MustCompile(`\btag\(\s*(?:(t\d+)\s*,)?`)
+)
+
+var functions = map[string]govaluate.ExpressionFunction{
+	"abs":        abs,
+	"min":        minimum,
+	"max":        maximum,
+	"avg":        windowFunction("avg", average),
+	"sum":        windowFunction("sum", sum),
+	"delta":      windowFunction("delta", delta),
+	"percentile": percentile,
+	"prev":       prev,
+	"tag":        tag,
+}
+
+// rewriteExpression passes hidden context variable and target names to functions, which need metrics data,
+// so avg(t1, 5m) becomes avg(expression_context, 't1', '5m').
+func rewriteExpression(expression string) string {
+	expression = windowFunctionRegex.ReplaceAllString(expression, fmt.Sprintf("$1(%s, '$2', '$3'", contextVariable))
+	expression = prevFunctionRegex.ReplaceAllString(expression, fmt.Sprintf("prev(%s, '$1')", contextVariable))
+	return tagFunctionRegex.ReplaceAllStringFunc(expression, func(match string) string {
+		if submatch := tagFunctionRegex.FindStringSubmatch(match); submatch[1] != "" {
+			return fmt.Sprintf("tag(%s, '%s',", contextVariable, submatch[1])
+		}
+		// tag of main target by default
+		return fmt.Sprintf("tag(%s, 't1', ", contextVariable)
+	})
+}
+
+// MaxWindow returns the longest window of aggregate functions used in expression,
+// checker has to fetch that much history in addition to the usual check interval.
+func MaxWindow(expression string) (time.Duration, error) {
+	var result time.Duration
+	for _, match := range windowFunctionRegex.FindAllStringSubmatch(expression, -1) {
+		window, err := parseWindow(match[3])
+		if err != nil {
+			return 0, err
+		}
+		if window > result {
+			result = window
+		}
+	}
+	return result, nil
+}
+
+// MaxTriggerWindow returns the longest window of aggregate functions used in trigger expression
+// and in expressions of threshold overrides, as any of them can be used to check a metric.
+func MaxTriggerWindow(expression string, overrides []moira.ThresholdOverride) (time.Duration, error) {
+	result, err := MaxWindow(expression)
+	if err != nil {
+		return 0, err
+	}
+	for _, override := range overrides {
+		if override.Expression == nil {
+			continue
+		}
+		window, err := MaxWindow(*override.Expression)
+		if err != nil {
+			return 0, err
+		}
+		if window > result {
+			result = window
+		}
+	}
+	return result, nil
+}
+
+func parseWindow(window string) (time.Duration, error) {
+	units := map[byte]time.Duration{'s': time.Second, 'm': time.Minute, 'h': time.Hour, 'd': 24 * time.Hour, 'w': 7 * 24 * time.Hour}
+
+	if len(window) < 2 {
+		return 0, fmt.Errorf("invalid window %s", window)
+	}
+	unit, ok := units[window[len(window)-1]]
+	if !ok {
+		return 0, fmt.Errorf("invalid window %s, unit should be one of s, m, h, d, w", window)
+	}
+	count, err := strconv.ParseInt(window[:len(window)-1], 10, 64)
+	if err != nil || count <= 0 {
+		return 0, fmt.Errorf("invalid window %s, should be positive number with unit", window)
+	}
+	return time.Duration(count) * unit, nil
+}
+
+func availableFunctions() string {
+	names := make([]string, 0, len(functions))
+	for name := range functions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+func abs(arguments ...interface{}) (interface{}, error) {
+	values, err := floatArguments("abs", arguments)
+	if err != nil {
+		return nil, err
+	}
+	if len(values) != 1 {
+		return nil, fmt.Errorf("abs expects exactly one argument")
+	}
+	return math.Abs(values[0]), nil
+}
+
+func minimum(arguments ...interface{}) (interface{}, error) {
+	values, err := floatArguments("min", arguments)
+	if err != nil {
+		return nil, err
+	}
+	if len(values) == 0 {
+		return nil, fmt.Errorf("min expects at least one argument")
+	}
+	result := values[0]
+	for _, value := range values[1:] {
+		result = math.Min(result, value)
+	}
+	return result, nil
+}
+
+func maximum(arguments ...interface{}) (interface{}, error) {
+	values, err := floatArguments("max", arguments)
+	if err != nil {
+		return nil, err
+	}
+	if len(values) == 0 {
+		return nil, fmt.Errorf("max expects at least one argument")
+	}
+	result := values[0]
+	for _, value := range values[1:] {
+		result = math.Max(result, value)
+	}
+	return result, nil
+}
+
+func floatArguments(function string, arguments []interface{}) ([]float64, error) {
+	values := make([]float64, 0, len(arguments))
+	for _, argument := range arguments {
+		value, ok := argument.(float64)
+		if !ok {
+			return nil, fmt.Errorf("%s expects numeric arguments, got %v", function, argument)
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+// windowFunction makes function, which aggregates values of target over window, e.g. avg(t1, 5m).
+func windowFunction(name string, aggregate func([]float64) float64) govaluate.ExpressionFunction {
+	return func(arguments ...interface{}) (interface{}, error) {
+		if len(arguments) != 3 {
+			return nil, fmt.Errorf("%s expects target and window, e.g. %s(t1, 5m)", name, name)
+		}
+		values, err := windowValues(name, arguments)
+		if err != nil {
+			return nil, err
+		}
+		return aggregate(values), nil
+	}
+}
+
+// percentile returns percentile of target values over window, e.g. percentile(t1, 1h, 95).
+func percentile(arguments ...interface{}) (interface{}, error) {
+	if len(arguments) != 4 {
+		return nil, fmt.Errorf("percentile expects target, window and percent, e.g. percentile(t1, 1h, 95)")
+	}
+	percent, ok := arguments[3].(float64)
+	if !ok || percent < 0 || percent > 100 {
+		return nil, fmt.Errorf("percentile expects percent in range from 0 to 100, got %v", arguments[3])
+	}
+	values, err := windowValues("percentile", arguments[:3])
+	if err != nil {
+		return nil, err
+	}
+	if len(values) == 0 {
+		return math.NaN(), nil
+	}
+
+	sort.Float64s(values)
+	rank := int(math.Ceil(percent / 100 * float64(len(values))))
+	if rank > 0 {
+		rank--
+	}
+	return values[rank], nil
+}
+
+// prev returns value of target on the previous step, e.g. prev(t1).
+func prev(arguments ...interface{}) (interface{}, error) {
+	if len(arguments) != 2 {
+		return nil, fmt.Errorf("prev expects target, e.g. prev(t1)")
+	}
+	triggerExpression, metric, err := targetArguments("prev", arguments)
+	if err != nil {
+		return nil, err
+	}
+	if metric == nil {
+		return math.NaN(), nil
+	}
+	return metric.GetTimestampValue(triggerExpression.Timestamp - metric.StepTime), nil
+}
+
+// tag returns value of tag of target metric, e.g. tag('dc') for main target or tag(t2, 'dc').
+func tag(arguments ...interface{}) (interface{}, error) {
+	if len(arguments) != 3 {
+		return nil, fmt.Errorf("tag expects tag name, e.g. tag('dc') or tag(t2, 'dc')")
+	}
+	name, ok := arguments[2].(string)
+	if !ok {
+		return nil, fmt.Errorf("tag expects tag name as string, got %v", arguments[2])
+	}
+	_, metric, err := targetArguments("tag", arguments[:2])
+	if err != nil {
+		return nil, err
+	}
+	if metric == nil {
+		return "", nil
+	}
+	_, tags := moira.SplitMetricNameAndTags(metric.Name)
+	return tags[name], nil
+}
+
+func windowValues(function string, arguments []interface{}) ([]float64, error) {
+	window, ok := arguments[2].(string)
+	if !ok {
+		return nil, fmt.Errorf("%s expects window as duration, e.g. 5m", function)
+	}
+	duration, err := parseWindow(window)
+	if err != nil {
+		return nil, err
+	}
+	triggerExpression, metric, err := targetArguments(function, arguments[:2])
+	if err != nil {
+		return nil, err
+	}
+	if metric == nil || metric.StepTime <= 0 {
+		return nil, nil
+	}
+
+	values := make([]float64, 0)
+	windowStart := triggerExpression.Timestamp - int64(duration.Seconds())
+	for timestamp := triggerExpression.Timestamp; timestamp > windowStart; timestamp -= metric.StepTime {
+		if value := metric.GetTimestampValue(timestamp); !math.IsNaN(value) {
+			values = append(values, value)
+		}
+	}
+	// values are collected from the newest to the oldest
+	for i, j := 0, len(values)-1; i < j; i, j = i+1, j-1 {
+		values[i], values[j] = values[j], values[i]
+	}
+	return values, nil
+}
+
+// targetArguments unpacks hidden context and target name arguments.
+// Metric is nil if expression has no metrics data, e.g. during validation in API.
+func targetArguments(function string, arguments []interface{}) (*TriggerExpression, *metricSource.MetricData, error) {
+	triggerExpression, ok := arguments[0].(*TriggerExpression)
+	if !ok {
+		return nil, nil, fmt.Errorf("%s expects target name as first argument, e.g. %s(t1, ...)", function, function)
+	}
+	target, ok := arguments[1].(string)
+	if !ok {
+		return nil, nil, fmt.Errorf("%s expects target name as first argument, e.g. %s(t1, ...)", function, function)
+	}
+	if _, err := triggerExpression.Get(target); err != nil {
+		return nil, nil, err
+	}
+	metric, ok := triggerExpression.Metrics[target]
+	if !ok {
+		return triggerExpression, nil, nil
+	}
+	return triggerExpression, &metric, nil
+}
+
+func average(values []float64) float64 {
+	if len(values) == 0 {
+		return math.NaN()
+	}
+	return sum(values) / float64(len(values))
+}
+
+func sum(values []float64) float64 {
+	if len(values) == 0 {
+		return math.NaN()
+	}
+	var result float64
+	for _, value := range values {
+		result += value
+	}
+	return result
+}
+
+func delta(values []float64) float64 {
+	if len(values) == 0 {
+		return math.NaN()
+	}
+	return values[len(values)-1] - values[0]
+}
@@ -0,0 +1,146 @@
+package expression
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/moira-alert/moira"
+	metricSource "github.com/moira-alert/moira/metric_source"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func evaluateWithMetrics(expression string, metrics map[string]metricSource.MetricData, timestamp int64) (moira.State, error) {
+	triggerExpression := &TriggerExpression{
+		Expression:              &expression,
+		TriggerType:             moira.ExpressionTrigger,
+		AdditionalTargetsValues: make(map[string]float64),
+		Metrics:                 metrics,
+		Timestamp:               timestamp,
+	}
+	for name, metric := range metrics {
+		if name == "t1" {
+			triggerExpression.MainTargetValue = metric.GetTimestampValue(timestamp)
+			continue
+		}
+		triggerExpression.AdditionalTargetsValues[name] = metric.GetTimestampValue(timestamp)
+	}
+	return triggerExpression.Evaluate()
+}
+
+func TestFunctions(t *testing.T) {
+	metrics := map[string]metricSource.MetricData{
+		"t1": *metricSource.MakeMetricData("service.rps;dc=east;env=prod", []float64{1, 2, 3, math.NaN(), 10, 20}, 60, 0),
+		"t2": *metricSource.MakeMetricData("service.errors", []float64{5, 5, 5, 5, 5, 5}, 60, 0),
+	}
+
+	Convey("Test scalar functions", t, func() {
+		state, err := evaluateWithMetrics("abs(t1 - t2) > 10 ? ERROR : OK", metrics, 300)
+		So(err, ShouldBeNil)
+		So(state, ShouldEqual, moira.StateERROR)
+
+		state, err = evaluateWithMetrics("max(t1, t2, 30) == 30 && min(t1, t2) == 5 ? WARN : OK", metrics, 300)
+		So(err, ShouldBeNil)
+		So(state, ShouldEqual, moira.StateWARN)
+
+		_, err = evaluateWithMetrics("abs(t1, t2) > 0 ? ERROR : OK", metrics, 300)
+		So(err.Error(), ShouldEqual, "abs expects exactly one argument")
+	})
+
+	Convey("Test window functions", t, func() {
+		Convey("avg skips empty values", func() {
+			state, err := evaluateWithMetrics("avg(t1, 3m) == 15 ? ERROR : OK", metrics, 300)
+			So(err, ShouldBeNil)
+			So(state, ShouldEqual, moira.StateERROR)
+		})
+
+		Convey("avg over short and long windows", func() {
+			state, err := evaluateWithMetrics("avg(t1, 2m) > 2*avg(t1, 1h) ? ERROR : OK", metrics, 300)
+			So(err, ShouldBeNil)
+			So(state, ShouldEqual, moira.StateERROR)
+		})
+
+		Convey("sum, delta and percentile", func() {
+			state, err := evaluateWithMetrics("sum(t2, 3m) == 15 && delta(t1, 6m) == 19 && percentile(t1, 1h, 50) == 3 ? ERROR : OK", metrics, 300)
+			So(err, ShouldBeNil)
+			So(state, ShouldEqual, moira.StateERROR)
+		})
+
+		Convey("window could be quoted", func() {
+			state, err := evaluateWithMetrics("avg(t2, '5m') == 5 ? ERROR : OK", metrics, 300)
+			So(err, ShouldBeNil)
+			So(state, ShouldEqual, moira.StateERROR)
+		})
+
+		Convey("unknown target", func() {
+			_, err := evaluateWithMetrics("avg(t3, 5m) > 0 ? ERROR : OK", metrics, 300)
+			So(err.Error(), ShouldEqual, "no value with name t3")
+		})
+
+		Convey("target expression instead of target name", func() {
+			_, err := evaluateWithMetrics("avg(t1 + 1, 5m) > 0 ? ERROR : OK", metrics, 300)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("percent out of range", func() {
+			_, err := evaluateWithMetrics("percentile(t1, 5m, 101) > 0 ? ERROR : OK", metrics, 300)
+			So(err.Error(), ShouldEqual, "percentile expects percent in range from 0 to 100, got 101")
+		})
+	})
+
+	Convey("Test previous value, metric name and tags", t, func() {
+		state, err := evaluateWithMetrics("t1 > 2*prev(t1) ? ERROR : OK", metrics, 300)
+		So(err, ShouldBeNil)
+		So(state, ShouldEqual, moira.StateOK)
+
+		state, err = evaluateWithMetrics("t1 > 2*prev(t1) ? ERROR : OK", metrics, 240)
+		So(err, ShouldBeNil)
+		So(state, ShouldEqual, moira.StateOK)
+
+		state, err = evaluateWithMetrics("METRIC_NAME == 'service.rps;dc=east;env=prod' && tag('dc') == 'east' && tag(t2, 'dc') == '' ? WARN : OK", metrics, 300)
+		So(err, ShouldBeNil)
+		So(state, ShouldEqual, moira.StateWARN)
+	})
+
+	Convey("Test functions without metrics data during validation", t, func() {
+		expression := "avg(t1, 5m) > 2*avg(t1, 1h) || prev(t1) > 0 || tag('dc') == 'east' ? ERROR : OK"
+		state, err := (&TriggerExpression{Expression: &expression, TriggerType: moira.ExpressionTrigger}).Evaluate()
+		So(err, ShouldBeNil)
+		So(state, ShouldEqual, moira.StateOK)
+
+		expression = "avg(t1, 5x) > 0 ? ERROR : OK"
+		_, err = (&TriggerExpression{Expression: &expression, TriggerType: moira.ExpressionTrigger}).Evaluate()
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestMaxWindow(t *testing.T) {
+	Convey("Test max window", t, func() {
+		window, err := MaxWindow("avg(t1, 5m) > 2*avg(t1, 1h) && percentile(t2, '2d', 95) > 0 ? ERROR : OK")
+		So(err, ShouldBeNil)
+		So(window, ShouldEqual, 48*time.Hour)
+
+		window, err = MaxWindow("t1 > 10 ? ERROR : OK")
+		So(err, ShouldBeNil)
+		So(window, ShouldEqual, 0)
+	})
+
+	Convey("Test max window of trigger with threshold overrides", t, func() {
+		overrideExpression := "avg(t1, 2h) > 10 ? ERROR : OK"
+		overrides := []moira.ThresholdOverride{{Metric: "metric.*"}, {Metric: "other.*", Expression: &overrideExpression}}
+		window, err := MaxTriggerWindow("avg(t1, 5m) > 10 ? ERROR : OK", overrides)
+		So(err, ShouldBeNil)
+		So(window, ShouldEqual, 2*time.Hour)
+
+		window, err = MaxTriggerWindow("", nil)
+		So(err, ShouldBeNil)
+		So(window, ShouldEqual, 0)
+	})
+}
+
+func TestRewriteExpression(t *testing.T) {
+	Convey("Test rewrite expression", t, func() {
+		So(rewriteExpression("avg(t1, 5m) > prev(t2) && tag('dc') == tag(t2, 'dc')"), ShouldEqual,
+			"avg(expression_context, 't1', '5m') > prev(expression_context, 't2') && tag(expression_context, 't1', 'dc') == tag(expression_context, 't2', 'dc')")
+	})
+}
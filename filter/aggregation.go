@@ -0,0 +1,180 @@
+package filter
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/moira-alert/moira"
+)
+
+// AggregationRule describes how values of metrics matching pattern are aggregated into windows before saving.
+type AggregationRule struct {
+	// Pattern is a regular expression matched against metric name including tags, e.g. name;tag=value
+	Pattern string
+	// Window is the interval, values received during which are saved as one value
+	Window time.Duration
+	// Method is one of sum, avg, max, min, last or count
+	Method string
+}
+
+var aggregationMethods = map[string]func(window *aggregationWindow) float64{
+	"sum":   func(window *aggregationWindow) float64 { return window.sum },
+	"avg":   func(window *aggregationWindow) float64 { return window.sum / float64(window.count) },
+	"max":   func(window *aggregationWindow) float64 { return window.max },
+	"min":   func(window *aggregationWindow) float64 { return window.min },
+	"last":  func(window *aggregationWindow) float64 { return window.last },
+	"count": func(window *aggregationWindow) float64 { return float64(window.count) },
+}
+
+type aggregationMatcher struct {
+	pattern   *regexp.Regexp
+	window    int64
+	aggregate func(window *aggregationWindow) float64
+}
+
+type aggregationWindow struct {
+	matcher *aggregationMatcher
+	metric  *moira.MatchedMetric
+	start   int64
+	flushed bool
+	count   int
+	sum     float64
+	min     float64
+	max     float64
+	last    float64
+}
+
+// Aggregator aggregates values of metrics into fixed windows like carbon-aggregator.
+// Window is saved as soon as the first value of the next window is received,
+// windows of metrics, which stopped sending values, are saved and then forgotten by Flush.
+// Matched rule is kept in metric window only, so memory is bounded by count of active aggregated metrics.
+type Aggregator struct {
+	matchers []aggregationMatcher
+	mutex    sync.Mutex
+	windows  map[string]*aggregationWindow
+}
+
+// NewAggregator creates aggregator with given rules, the first rule matching metric is used.
+func NewAggregator(rules []AggregationRule) (*Aggregator, error) {
+	aggregator := &Aggregator{
+		matchers: make([]aggregationMatcher, 0, len(rules)),
+		windows:  make(map[string]*aggregationWindow),
+	}
+
+	for _, rule := range rules {
+		pattern, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid aggregation rule pattern %s: %w", rule.Pattern, err)
+		}
+		aggregate, ok := aggregationMethods[rule.Method]
+		if !ok {
+			return nil, fmt.Errorf("unknown aggregation method %s of pattern %s, should be one of sum, avg, max, min, last, count", rule.Method, rule.Pattern)
+		}
+		window := int64(rule.Window.Seconds())
+		if window <= 0 {
+			return nil, fmt.Errorf("invalid aggregation window %s of pattern %s, should be at least 1s", rule.Window, rule.Pattern)
+		}
+
+		aggregator.matchers = append(aggregator.matchers, aggregationMatcher{
+			pattern:   pattern,
+			window:    window,
+			aggregate: aggregate,
+		})
+	}
+
+	return aggregator, nil
+}
+
+// Aggregate adds value of metric to its window. It returns metric itself if no rule matches it,
+// aggregated value of the previous window if the new one is started and nil otherwise.
+// Values of windows, which are already saved, are dropped.
+func (aggregator *Aggregator) Aggregate(metric *moira.MatchedMetric) *moira.MatchedMetric {
+	aggregator.mutex.Lock()
+	defer aggregator.mutex.Unlock()
+
+	window, ok := aggregator.windows[metric.Metric]
+	var matcher *aggregationMatcher
+	if ok {
+		matcher = window.matcher
+	} else if matcher = aggregator.getMatcher(metric.Metric); matcher == nil {
+		return metric
+	}
+
+	start := metric.Timestamp - metric.Timestamp%matcher.window
+	switch {
+	case ok && start == window.start && !window.flushed:
+		window.add(metric)
+		return nil
+	case ok && start <= window.start:
+		return nil
+	}
+
+	aggregator.windows[metric.Metric] = newAggregationWindow(matcher, metric, start)
+	if ok && !window.flushed {
+		return window.result()
+	}
+	return nil
+}
+
+// Flush returns aggregated values of windows, which ended more than window ago and got no values of the next window.
+func (aggregator *Aggregator) Flush(now time.Time) []*moira.MatchedMetric {
+	aggregator.mutex.Lock()
+	defer aggregator.mutex.Unlock()
+
+	result := make([]*moira.MatchedMetric, 0)
+	for name, window := range aggregator.windows {
+		if window.start+2*window.matcher.window > now.Unix() {
+			continue
+		}
+		if window.flushed {
+			delete(aggregator.windows, name)
+			continue
+		}
+		window.flushed = true
+		result = append(result, window.result())
+	}
+	return result
+}
+
+func (aggregator *Aggregator) getMatcher(metric string) *aggregationMatcher {
+	for i := range aggregator.matchers {
+		if aggregator.matchers[i].pattern.MatchString(metric) {
+			return &aggregator.matchers[i]
+		}
+	}
+	return nil
+}
+
+func newAggregationWindow(matcher *aggregationMatcher, metric *moira.MatchedMetric, start int64) *aggregationWindow {
+	window := &aggregationWindow{
+		matcher: matcher,
+		start:   start,
+		min:     math.Inf(1),
+		max:     math.Inf(-1),
+	}
+	window.add(metric)
+	return window
+}
+
+func (window *aggregationWindow) add(metric *moira.MatchedMetric) {
+	window.metric = metric
+	window.count++
+	window.sum += metric.Value
+	window.min = math.Min(window.min, metric.Value)
+	window.max = math.Max(window.max, metric.Value)
+	window.last = metric.Value
+}
+
+func (window *aggregationWindow) result() *moira.MatchedMetric {
+	return &moira.MatchedMetric{
+		Metric:             window.metric.Metric,
+		Patterns:           window.metric.Patterns,
+		Value:              window.matcher.aggregate(window),
+		Timestamp:          window.start,
+		RetentionTimestamp: window.start,
+		Retention:          window.metric.Retention,
+	}
+}
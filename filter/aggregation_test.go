@@ -0,0 +1,109 @@
+package filter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/moira-alert/moira"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestAggregator(t *testing.T) {
+	rules := []AggregationRule{
+		{Pattern: `^service\.rps$`, Window: time.Minute, Method: "sum"},
+		{Pattern: `;env=prod(;|$)`, Window: time.Minute, Method: "max"},
+		{Pattern: `^service\.`, Window: 10 * time.Second, Method: "avg"},
+	}
+
+	newMetric := func(name string, value float64, timestamp int64) *moira.MatchedMetric {
+		return &moira.MatchedMetric{
+			Metric:             name,
+			Patterns:           []string{"service.*"},
+			Value:              value,
+			Timestamp:          timestamp,
+			RetentionTimestamp: timestamp,
+			Retention:          60,
+		}
+	}
+
+	Convey("Test invalid rules", t, func() {
+		_, err := NewAggregator([]AggregationRule{{Pattern: "(", Window: time.Minute, Method: "sum"}})
+		So(err, ShouldNotBeNil)
+
+		_, err = NewAggregator([]AggregationRule{{Pattern: ".*", Window: time.Minute, Method: "median"}})
+		So(err, ShouldNotBeNil)
+
+		_, err = NewAggregator([]AggregationRule{{Pattern: ".*", Window: time.Millisecond, Method: "sum"}})
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("Test aggregation", t, func() {
+		aggregator, err := NewAggregator(rules)
+		So(err, ShouldBeNil)
+
+		Convey("Metric without rule is not aggregated", func() {
+			metric := newMetric("other.metric", 1, 100)
+			So(aggregator.Aggregate(metric), ShouldEqual, metric)
+			So(aggregator.windows, ShouldBeEmpty)
+		})
+
+		Convey("Window is saved when value of the next window is received", func() {
+			So(aggregator.Aggregate(newMetric("service.rps", 1, 120)), ShouldBeNil)
+			So(aggregator.Aggregate(newMetric("service.rps", 2, 150)), ShouldBeNil)
+			So(aggregator.Aggregate(newMetric("service.rps", 3, 179)), ShouldBeNil)
+
+			So(aggregator.Aggregate(newMetric("service.rps", 10, 181)), ShouldResemble, &moira.MatchedMetric{
+				Metric:             "service.rps",
+				Patterns:           []string{"service.*"},
+				Value:              6,
+				Timestamp:          120,
+				RetentionTimestamp: 120,
+				Retention:          60,
+			})
+
+			Convey("late values of saved window are dropped", func() {
+				So(aggregator.Aggregate(newMetric("service.rps", 4, 170)), ShouldBeNil)
+				So(aggregator.windows["service.rps"].sum, ShouldEqual, 10)
+			})
+		})
+
+		Convey("The first matching rule is used", func() {
+			aggregator.Aggregate(newMetric("service.rps;env=prod", 5, 120))
+			aggregator.Aggregate(newMetric("service.rps;env=prod", 7, 130))
+			aggregator.Aggregate(newMetric("service.rps;env=prod", 6, 140))
+			So(aggregator.Aggregate(newMetric("service.rps;env=prod", 1, 180)).Value, ShouldEqual, 7)
+
+			aggregator.Aggregate(newMetric("service.errors", 1, 120))
+			aggregator.Aggregate(newMetric("service.errors", 2, 125))
+			So(aggregator.Aggregate(newMetric("service.errors", 1, 130)).Value, ShouldEqual, 1.5)
+		})
+
+		Convey("Windows without values of the next window are flushed", func() {
+			aggregator.Aggregate(newMetric("service.rps", 1, 120))
+			aggregator.Aggregate(newMetric("service.errors", 1, 120))
+
+			So(aggregator.Flush(time.Unix(200, 0)), ShouldResemble, []*moira.MatchedMetric{
+				{Metric: "service.errors", Patterns: []string{"service.*"}, Value: 1, Timestamp: 120, RetentionTimestamp: 120, Retention: 60},
+			})
+			So(aggregator.Flush(time.Unix(240, 0)), ShouldHaveLength, 1)
+
+			Convey("flushed windows are forgotten later", func() {
+				So(aggregator.Flush(time.Unix(240, 0)), ShouldBeEmpty)
+				So(aggregator.windows, ShouldBeEmpty)
+			})
+		})
+	})
+}
+
+func TestAggregationMethods(t *testing.T) {
+	Convey("Test aggregation methods", t, func() {
+		window := newAggregationWindow(nil, &moira.MatchedMetric{Value: 3}, 0)
+		window.add(&moira.MatchedMetric{Value: 1})
+		window.add(&moira.MatchedMetric{Value: 2})
+
+		expected := map[string]float64{"sum": 6, "avg": 2, "max": 3, "min": 1, "last": 2, "count": 3}
+		for method, value := range expected {
+			So(aggregationMethods[method](window), ShouldEqual, value)
+		}
+	})
+}
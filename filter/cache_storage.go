@@ -2,10 +2,12 @@ package filter
 
 import (
 	"bufio"
+	"fmt"
 	"io"
 	"regexp"
 	"strconv"
 	"strings"
+	"sync/atomic"
 
 	"github.com/moira-alert/moira"
 	"github.com/moira-alert/moira/metrics"
@@ -13,44 +15,93 @@ import (
 
 var defaultRetention = 60
 
+// defaultAggregation is used by carbon if storage aggregation is not configured.
+var defaultAggregation = moira.RetentionAggregation{Method: moira.AggregationAverage, XFilesFactor: 0.5}
+
 type retentionMatcher struct {
-	pattern   *regexp.Regexp
-	retention int
+	name        string
+	pattern     *regexp.Regexp
+	retention   int
+	retentions  []moira.Retention
+	aggregation moira.RetentionAggregation
 }
 
 type retentionCacheItem struct {
-	value     int
+	matcher   *retentionMatcher
 	timestamp int64
 }
 
 // Storage struct to store retention matchers.
 type Storage struct {
-	metrics         *metrics.FilterMetrics
-	retentions      []retentionMatcher
-	retentionsCache map[string]*retentionCacheItem
-	metricsCache    map[string]*moira.MatchedMetric
-	logger          moira.Logger
+	metrics      *metrics.FilterMetrics
+	retentions   atomic.Value
+	metricsCache map[string]*moira.MatchedMetric
+	logger       moira.Logger
 }
 
 // NewCacheStorage create new Storage.
 func NewCacheStorage(logger moira.Logger, metrics *metrics.FilterMetrics, reader io.Reader) (*Storage, error) {
 	storage := &Storage{
-		retentionsCache: make(map[string]*retentionCacheItem),
-		metricsCache:    make(map[string]*moira.MatchedMetric),
-		metrics:         metrics,
-		logger:          logger,
+		metricsCache: make(map[string]*moira.MatchedMetric),
+		metrics:      metrics,
+		logger:       logger,
 	}
 
-	if err := storage.buildRetentions(bufio.NewScanner(reader)); err != nil {
+	retentions, err := storage.ParseRetentions(reader)
+	if err != nil {
 		return nil, err
 	}
+	storage.SetRetentions(retentions)
 
 	return storage, nil
 }
 
+// Retentions are parsed storage schemas, they are applied by SetRetentions.
+type Retentions struct {
+	matchers []retentionMatcher
+	// cache of matched retentions by metric is used only by the goroutine, which enriches matched metrics
+	cache map[string]*retentionCacheItem
+}
+
+// Reload parses storage schemas again and replaces retention matchers with new ones.
+// Matchers are kept untouched if schemas are invalid.
+func (storage *Storage) Reload(reader io.Reader) error {
+	retentions, err := storage.ParseRetentions(reader)
+	if err != nil {
+		return err
+	}
+	storage.SetRetentions(retentions)
+	return nil
+}
+
+// ParseRetentions parses storage schemas without applying them.
+func (storage *Storage) ParseRetentions(reader io.Reader) (*Retentions, error) {
+	matchers, err := storage.buildRetentions(bufio.NewScanner(reader))
+	if err != nil {
+		return nil, err
+	}
+	return &Retentions{matchers: matchers, cache: make(map[string]*retentionCacheItem)}, nil
+}
+
+// SetRetentions replaces retention matchers with parsed ones, cached retentions of metrics are dropped with old matchers.
+func (storage *Storage) SetRetentions(retentions *Retentions) {
+	storage.retentions.Store(retentions)
+}
+
+func (storage *Storage) getRetentions() *Retentions {
+	return storage.retentions.Load().(*Retentions)
+}
+
 // EnrichMatchedMetric calculate retention and filter cached values.
 func (storage *Storage) EnrichMatchedMetric(batch map[string]*moira.MatchedMetric, m *moira.MatchedMetric) {
-	m.Retention = storage.getRetention(m)
+	m.Retention = defaultRetention
+	m.Retentions = nil
+	m.Aggregation = defaultAggregation
+	if matcher := storage.getRetentions().getMatcher(m); matcher != nil {
+		m.Retention = matcher.retention
+		m.Retentions = matcher.retentions
+		m.Aggregation = matcher.aggregation
+	}
 	m.RetentionTimestamp = moira.RoundToNearestRetention(m.Timestamp, int64(m.Retention))
 	if ex, ok := storage.metricsCache[m.Metric]; ok && ex.RetentionTimestamp == m.RetentionTimestamp && ex.Value == m.Value {
 		return
@@ -59,65 +110,221 @@ func (storage *Storage) EnrichMatchedMetric(batch map[string]*moira.MatchedMetri
 	batch[m.Metric] = m
 }
 
-// getRetention returns first matched retention for metric.
-func (storage *Storage) getRetention(m *moira.MatchedMetric) int {
-	if item, ok := storage.retentionsCache[m.Metric]; ok && item.timestamp+60 > m.Timestamp {
-		return item.value
+// getMatcher returns first matched storage schema for metric or nil if nothing matches.
+func (retentions *Retentions) getMatcher(m *moira.MatchedMetric) *retentionMatcher {
+	if item, ok := retentions.cache[m.Metric]; ok && item.timestamp+60 > m.Timestamp {
+		return item.matcher
 	}
 
-	for _, matcher := range storage.retentions {
+	for i := range retentions.matchers {
+		matcher := &retentions.matchers[i]
 		if matcher.pattern.MatchString(m.Metric) {
-			storage.retentionsCache[m.Metric] = &retentionCacheItem{
-				value:     matcher.retention,
+			retentions.cache[m.Metric] = &retentionCacheItem{
+				matcher:   matcher,
 				timestamp: m.Timestamp,
 			}
-			return matcher.retention
+			return matcher
 		}
 	}
 
-	return defaultRetention
+	return nil
 }
 
-func (storage *Storage) buildRetentions(retentionScanner *bufio.Scanner) error {
-	storage.retentions = make([]retentionMatcher, 0, 100)
+// buildRetentions parses storage schemas in carbon format:
+//
+//	[name]
+//	pattern = ^metric\.
+//	retentions = 60s:2d,10m:30d
+//	xFilesFactor = 0.5
+//	aggregationMethod = average
+//
+// Pattern is matched against the whole metric name including tags, e.g. name;tag=value.
+// Values are downsampled into the following archives as carbon does with storage aggregation:
+// aggregationMethod is one of average (default), sum, min, max and last, aggregated value is kept
+// only if xFilesFactor (0.5 by default) of values are received during archive step.
+// Files without section headers, where every pattern is followed by its retentions, are supported too.
+func (storage *Storage) buildRetentions(retentionScanner *bufio.Scanner) ([]retentionMatcher, error) {
+	retentions := make([]retentionMatcher, 0, 100)
+
+	var section *rawStorageSchema
+	flush := func() error {
+		if section == nil || section.pattern == "" {
+			return nil
+		}
+		matcher, err := section.build()
+		section = nil
+		if err != nil {
+			return err
+		}
+		if matcher == nil {
+			return nil
+		}
+		retentions = append(retentions, *matcher)
+		return nil
+	}
 
 	for retentionScanner.Scan() {
-		line1 := retentionScanner.Text()
-		if strings.HasPrefix(line1, "#") || strings.Count(line1, "=") != 1 {
+		line := strings.TrimSpace(retentionScanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+			section = &rawStorageSchema{name: strings.TrimSpace(line[1 : len(line)-1]), logger: storage.logger}
 			continue
 		}
 
-		patternString := strings.TrimSpace(strings.Split(line1, "=")[1])
-		pattern, err := regexp.Compile(patternString)
+		key, value, found := strings.Cut(line, "=")
+		if !found {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "pattern":
+			if section != nil && section.pattern != "" {
+				if err := flush(); err != nil {
+					return nil, err
+				}
+			}
+			if section == nil {
+				section = &rawStorageSchema{logger: storage.logger}
+			}
+			section.pattern = value
+		case "retentions":
+			if section != nil {
+				section.retentions = value
+			}
+		case "xfilesfactor":
+			if section != nil {
+				section.xFilesFactor = value
+			}
+		case "aggregationmethod":
+			if section != nil {
+				section.aggregationMethod = value
+			}
+		}
+	}
+
+	if err := retentionScanner.Err(); err != nil {
+		return nil, err
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	return retentions, nil
+}
+
+// rawStorageSchema is a storage schema section as it is written in config.
+type rawStorageSchema struct {
+	name              string
+	pattern           string
+	retentions        string
+	xFilesFactor      string
+	aggregationMethod string
+	logger            moira.Logger
+}
+
+// build compiles pattern and parses retentions of section, returns nil if section has no retentions.
+func (schema *rawStorageSchema) build() (*retentionMatcher, error) {
+	pattern, err := regexp.Compile(schema.pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	if schema.retentions == "" {
+		schema.logger.Error().
+			String("pattern", schema.pattern).
+			Msg("Invalid pattern found")
+
+		return nil, nil
+	}
+
+	retentions, err := parseRetentions(schema.retentions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse retentions of schema %s: %w", schema.name, err)
+	}
+
+	aggregation, err := schema.buildAggregation()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse aggregation of schema %s: %w", schema.name, err)
+	}
+
+	return &retentionMatcher{
+		name:        schema.name,
+		pattern:     pattern,
+		retention:   int(retentions[0].Step),
+		retentions:  retentions,
+		aggregation: aggregation,
+	}, nil
+}
+
+// buildAggregation parses downsampling settings of section, defaults are used for missing ones.
+func (schema *rawStorageSchema) buildAggregation() (moira.RetentionAggregation, error) {
+	aggregation := defaultAggregation
+
+	if schema.xFilesFactor != "" {
+		xFilesFactor, err := strconv.ParseFloat(schema.xFilesFactor, 64)
 		if err != nil {
-			return err
+			return aggregation, fmt.Errorf("invalid xFilesFactor %s: %w", schema.xFilesFactor, err)
 		}
+		if xFilesFactor < 0 || xFilesFactor > 1 {
+			return aggregation, fmt.Errorf("invalid xFilesFactor %s, it should be between 0 and 1", schema.xFilesFactor)
+		}
+		aggregation.XFilesFactor = xFilesFactor
+	}
 
-		retentionScanner.Scan()
-		line2 := retentionScanner.Text()
-		splitted := strings.Split(line2, "=")
+	if schema.aggregationMethod != "" {
+		switch method := strings.ToLower(schema.aggregationMethod); method {
+		case moira.AggregationAverage, moira.AggregationSum, moira.AggregationMin, moira.AggregationMax, moira.AggregationLast:
+			aggregation.Method = method
+		default:
+			return aggregation, fmt.Errorf("unknown aggregationMethod %s", schema.aggregationMethod)
+		}
+	}
 
-		if len(splitted) < 2 { //nolint
-			storage.logger.Error().
-				String("pattern", patternString).
-				Msg("Invalid pattern found")
+	return aggregation, nil
+}
 
-			continue
+// parseRetentions parses comma separated archives in format precision:duration, e.g. 60s:2d,10m:30d.
+// Duration without unit is the number of points as in carbon, e.g. 60:1440 is one day of minutely points.
+func parseRetentions(rawRetentions string) ([]moira.Retention, error) {
+	archives := strings.Split(rawRetentions, ",")
+	retentions := make([]moira.Retention, 0, len(archives))
+
+	for _, archive := range archives {
+		rawStep, rawDuration, found := strings.Cut(strings.TrimSpace(archive), ":")
+		if !found {
+			return nil, fmt.Errorf("invalid archive %s, should be in format precision:duration", archive)
 		}
 
-		retentions := strings.TrimSpace(splitted[1])
-		retention, err := rawRetentionToSeconds(retentions[0:strings.Index(retentions, ":")])
+		step, err := rawRetentionToSeconds(strings.TrimSpace(rawStep))
 		if err != nil {
-			return err
+			return nil, err
+		}
+		if step <= 0 {
+			return nil, fmt.Errorf("invalid archive %s, precision should be positive", archive)
+		}
+
+		rawDuration = strings.TrimSpace(rawDuration)
+		duration, err := strconv.Atoi(rawDuration)
+		if err == nil {
+			duration *= step
+		} else if duration, err = rawRetentionToSeconds(rawDuration); err != nil {
+			return nil, err
 		}
 
-		storage.retentions = append(storage.retentions, retentionMatcher{
-			pattern:   pattern,
-			retention: retention,
+		retentions = append(retentions, moira.Retention{
+			Step:     int64(step),
+			Duration: int64(duration),
 		})
 	}
 
-	return retentionScanner.Err()
+	return retentions, nil
 }
 
 func rawRetentionToSeconds(rawRetention string) (int, error) {
@@ -172,7 +172,7 @@ func TestCacheStorage(t *testing.T) {
 	Convey("Test good retentions", t, func() {
 		So(err, ShouldBeEmpty)
 		So(storage, ShouldNotBeNil)
-		for i, retention := range storage.retentions {
+		for i, retention := range storage.getRetentions().matchers {
 			So(retention.retention, ShouldEqual, expectedRetentionIntervals[i])
 		}
 	})
@@ -230,3 +230,106 @@ func TestRetentions(t *testing.T) {
 		So(metr.RetentionTimestamp, ShouldEqual, 120)
 	})
 }
+
+func TestBuildRetentions(t *testing.T) {
+	filterMetrics := metrics.ConfigureFilterMetrics(metrics.NewDummyRegistry())
+
+	Convey("Test all archives are parsed", t, func() {
+		storage, err := NewCacheStorage(nil, filterMetrics, strings.NewReader(testRetentions))
+		So(err, ShouldBeNil)
+		So(storage.getRetentions().matchers[0].name, ShouldEqual, "simple")
+		So(storage.getRetentions().matchers[0].retentions, ShouldResemble, []moira.Retention{
+			{Step: 60, Duration: 172800},
+			{Step: 600, Duration: 2592000},
+			{Step: 6000, Duration: 7776000},
+		})
+		So(storage.getRetentions().matchers[6].retentions, ShouldResemble, []moira.Retention{{Step: 120, Duration: 604800}})
+	})
+
+	Convey("Test tagged patterns, number of points and legacy format without sections", t, func() {
+		storage, err := NewCacheStorage(nil, filterMetrics, strings.NewReader(`
+pattern = ;env=prod(;|$)
+retentions = 10:360, 1m:1d
+pattern = .*
+priority = 10
+retentions = 60:1d
+`))
+		So(err, ShouldBeNil)
+		So(storage.getRetentions().matchers, ShouldHaveLength, 2)
+		So(storage.getRetentions().matchers[0].retentions, ShouldResemble, []moira.Retention{
+			{Step: 10, Duration: 3600},
+			{Step: 60, Duration: 86400},
+		})
+
+		buffer := make(map[string]*moira.MatchedMetric)
+		metric := moira.MatchedMetric{Metric: "service.rps;dc=east;env=prod", Timestamp: 125}
+		storage.EnrichMatchedMetric(buffer, &metric)
+		So(metric.Retention, ShouldEqual, 10)
+		So(metric.RetentionTimestamp, ShouldEqual, 130)
+		So(metric.Retentions, ShouldHaveLength, 2)
+	})
+
+	Convey("Test aggregation of archives", t, func() {
+		storage, err := NewCacheStorage(nil, filterMetrics, strings.NewReader(`
+[counters]
+pattern = \.count$
+retentions = 60s:1d,1h:30d
+xFilesFactor = 0
+aggregationMethod = sum
+
+[default]
+pattern = .*
+retentions = 60s:1d,1h:30d
+`))
+		So(err, ShouldBeNil)
+		So(storage.getRetentions().matchers[0].aggregation, ShouldResemble, moira.RetentionAggregation{Method: moira.AggregationSum, XFilesFactor: 0})
+		So(storage.getRetentions().matchers[1].aggregation, ShouldResemble, moira.RetentionAggregation{Method: moira.AggregationAverage, XFilesFactor: 0.5})
+
+		buffer := make(map[string]*moira.MatchedMetric)
+		metric := moira.MatchedMetric{Metric: "requests.count", Timestamp: 120}
+		storage.EnrichMatchedMetric(buffer, &metric)
+		So(metric.Aggregation, ShouldResemble, moira.RetentionAggregation{Method: moira.AggregationSum, XFilesFactor: 0})
+
+		for _, invalid := range []string{"xFilesFactor = 2", "xFilesFactor = half", "aggregationMethod = median"} {
+			_, err = NewCacheStorage(nil, filterMetrics, strings.NewReader("[broken]\npattern = .*\nretentions = 60s:1d\n"+invalid+"\n"))
+			So(err, ShouldNotBeNil)
+		}
+	})
+
+	Convey("Test invalid archive", t, func() {
+		_, err := NewCacheStorage(nil, filterMetrics, strings.NewReader(`
+[broken]
+pattern = .*
+retentions = 60s
+`))
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestReloadRetentions(t *testing.T) {
+	filterMetrics := metrics.ConfigureFilterMetrics(metrics.NewDummyRegistry())
+	storage, _ := NewCacheStorage(nil, filterMetrics, strings.NewReader(testRetentions))
+
+	buffer := make(map[string]*moira.MatchedMetric)
+
+	Convey("Invalid schemas keep current retentions", t, func() {
+		err := storage.Reload(strings.NewReader("[broken]\npattern = (\nretentions = 60s:1d\n"))
+		So(err, ShouldNotBeNil)
+		So(storage.getRetentions().matchers, ShouldHaveLength, len(expectedRetentionIntervals))
+
+		metric := matchedMetrics[14]
+		storage.EnrichMatchedMetric(buffer, &metric)
+		So(metric.Retention, ShouldEqual, 120)
+	})
+
+	Convey("Valid schemas replace retentions and cached retentions of metrics", t, func() {
+		err := storage.Reload(strings.NewReader("[default]\npattern = .*\nretentions = 10s:1d,1m:30d\n"))
+		So(err, ShouldBeNil)
+		So(storage.getRetentions().matchers, ShouldHaveLength, 1)
+
+		metric := matchedMetrics[14]
+		storage.EnrichMatchedMetric(buffer, &metric)
+		So(metric.Retention, ShouldEqual, 10)
+		So(metric.Retentions, ShouldHaveLength, 2)
+	})
+}
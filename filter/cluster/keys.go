@@ -0,0 +1,51 @@
+package cluster
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+
+	"github.com/moira-alert/moira/filter"
+)
+
+// Patterns are sharded by the first node of metric name, so metric could be routed
+// to the only member, which owns all patterns it could match. Patterns with wildcards
+// in the first node could match metrics of any member, so they are loaded by every member.
+
+// MetricKey returns routing key of metric line "<metric> <value> <timestamp>".
+func MetricKey(line []byte) string {
+	end := bytes.IndexAny(line, ".; ")
+	if end < 0 {
+		return string(line)
+	}
+	return string(line[:end])
+}
+
+// PatternKey returns routing key of pattern and false if pattern could match metrics of any key.
+func PatternKey(pattern string) (string, bool) {
+	tagSpecs, err := filter.ParseSeriesByTag(pattern)
+	if errors.Is(err, filter.ErrNotSeriesByTag) {
+		return firstNode(pattern)
+	}
+	if err != nil {
+		return "", false
+	}
+
+	for _, tagSpec := range tagSpecs {
+		if tagSpec.Name == "name" && tagSpec.Operator == filter.EqualOperator {
+			return firstNode(tagSpec.Value)
+		}
+	}
+	return "", false
+}
+
+func firstNode(name string) (string, bool) {
+	node := name
+	if end := strings.IndexByte(name, '.'); end >= 0 {
+		node = name[:end]
+	}
+	if node == "" || strings.ContainsAny(node, "*?[]{}") {
+		return "", false
+	}
+	return node, true
+}
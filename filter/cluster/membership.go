@@ -0,0 +1,153 @@
+package cluster
+
+import (
+	"sync/atomic"
+	"time"
+
+	"gopkg.in/tomb.v2"
+
+	"github.com/moira-alert/moira"
+)
+
+// Membership keeps list of alive filter cluster members in Redis and builds consistent hash ring of them.
+// Every member sends heartbeat periodically, members without heartbeat during member TTL are excluded from ring.
+type Membership struct {
+	database          moira.Database
+	logger            moira.Logger
+	address           string
+	heartbeatInterval time.Duration
+	memberTTL         time.Duration
+	ring              atomic.Value
+	membersChanged    func()
+	tomb              tomb.Tomb
+}
+
+// NewMembership creates membership of filter, which accepts forwarded metrics on given address.
+func NewMembership(database moira.Database, logger moira.Logger, address string, heartbeatInterval, memberTTL time.Duration) *Membership {
+	membership := &Membership{
+		database:          database,
+		logger:            logger,
+		address:           address,
+		heartbeatInterval: heartbeatInterval,
+		memberTTL:         memberTTL,
+	}
+	membership.ring.Store(NewRing([]string{address}))
+	return membership
+}
+
+// SetMembersChangedHandler sets handler, which is called every time members of cluster change,
+// e.g. to reload patterns owned by this member. It should be set before start.
+func (membership *Membership) SetMembersChangedHandler(membersChanged func()) {
+	membership.membersChanged = membersChanged
+}
+
+// Start joins cluster and updates members periodically.
+func (membership *Membership) Start() error {
+	if err := membership.update(time.Now()); err != nil {
+		return err
+	}
+
+	membership.tomb.Go(func() error {
+		heartbeatTicker := time.NewTicker(membership.heartbeatInterval)
+		defer heartbeatTicker.Stop()
+		for {
+			select {
+			case <-membership.tomb.Dying():
+				if err := membership.database.RemoveFilterClusterMember(membership.address); err != nil {
+					membership.logger.Error().
+						Error(err).
+						Msg("Failed to leave filter cluster")
+				}
+				membership.logger.Info().Msg("Moira Filter cluster membership stopped")
+				return nil
+			case now := <-heartbeatTicker.C:
+				if err := membership.update(now); err != nil {
+					membership.logger.Error().
+						Error(err).
+						Msg("Failed to update filter cluster members")
+				}
+			}
+		}
+	})
+
+	membership.logger.Info().
+		String("address", membership.address).
+		Msg("Moira Filter joined cluster")
+	return nil
+}
+
+// Stop leaves cluster.
+func (membership *Membership) Stop() error {
+	membership.tomb.Kill(nil)
+	return membership.tomb.Wait()
+}
+
+// Address returns address of this member, which accepts forwarded metrics.
+func (membership *Membership) Address() string {
+	return membership.address
+}
+
+// Owner returns address of member, which owns given key.
+func (membership *Membership) Owner(key string) string {
+	return membership.getRing().Owner(key)
+}
+
+// OwnsPattern checks that pattern should be loaded by this member.
+func (membership *Membership) OwnsPattern(pattern string) bool {
+	key, ok := PatternKey(pattern)
+	if !ok {
+		return true
+	}
+	return membership.Owner(key) == membership.address
+}
+
+func (membership *Membership) getRing() *Ring {
+	return membership.ring.Load().(*Ring)
+}
+
+func (membership *Membership) update(now time.Time) error {
+	if err := membership.database.UpdateFilterClusterMember(membership.address, now.Unix()); err != nil {
+		return err
+	}
+
+	members, err := membership.database.GetFilterClusterMembers(now.Add(-membership.memberTTL).Unix())
+	if err != nil {
+		return err
+	}
+	if !contains(members, membership.address) {
+		members = append(members, membership.address)
+	}
+
+	ring := NewRing(members)
+	if !equalMembers(ring.Members(), membership.getRing().Members()) {
+		membership.ring.Store(ring)
+		membership.logger.Info().
+			Interface("members", ring.Members()).
+			Msg("Filter cluster members changed")
+		if membership.membersChanged != nil {
+			membership.membersChanged()
+		}
+	}
+	return nil
+}
+
+func contains(members []string, address string) bool {
+	for _, member := range members {
+		if member == address {
+			return true
+		}
+	}
+	return false
+}
+
+func equalMembers(first, second []string) bool {
+	if len(first) != len(second) {
+		return false
+	}
+	for i := range first {
+		if first[i] != second[i] {
+			return false
+		}
+	}
+	return true
+}
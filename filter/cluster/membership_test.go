@@ -0,0 +1,51 @@
+package cluster
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	logging "github.com/moira-alert/moira/logging/zerolog_adapter"
+	mock_moira_alert "github.com/moira-alert/moira/mock/moira-alert"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMembership(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	database := mock_moira_alert.NewMockDatabase(mockCtrl)
+	logger, _ := logging.GetLogger("Cluster")
+	membership := NewMembership(database, logger, "filter-1:2004", time.Second, 15*time.Second)
+	now := time.Unix(1000, 0)
+	membersChanged := 0
+	membership.SetMembersChangedHandler(func() { membersChanged++ })
+
+	Convey("Test members are updated", t, func() {
+		database.EXPECT().UpdateFilterClusterMember("filter-1:2004", int64(1000)).Return(nil)
+		database.EXPECT().GetFilterClusterMembers(int64(985)).Return([]string{"filter-2:2004"}, nil)
+
+		err := membership.update(now)
+		So(err, ShouldBeNil)
+		So(membership.getRing().Members(), ShouldResemble, []string{"filter-1:2004", "filter-2:2004"})
+		So(membersChanged, ShouldEqual, 1)
+
+		Convey("handler is not called if members are the same", func() {
+			database.EXPECT().UpdateFilterClusterMember("filter-1:2004", int64(1000)).Return(nil)
+			database.EXPECT().GetFilterClusterMembers(int64(985)).Return([]string{"filter-2:2004"}, nil)
+
+			So(membership.update(now), ShouldBeNil)
+			So(membersChanged, ShouldEqual, 1)
+		})
+
+		Convey("patterns are shared between members", func() {
+			So(membership.OwnsPattern("*.rps"), ShouldBeTrue)
+
+			for _, node := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+				isOwner := membership.Owner(node) == "filter-1:2004"
+				So(membership.OwnsPattern(node+".*.rps"), ShouldEqual, isOwner)
+				So(membership.OwnsPattern("seriesByTag('name="+node+".rps')"), ShouldEqual, isOwner)
+			}
+		})
+	})
+}
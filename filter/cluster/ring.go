@@ -0,0 +1,62 @@
+package cluster
+
+import (
+	"hash/crc32"
+	"sort"
+	"strconv"
+)
+
+// virtualNodes is the number of points of every member on ring, they spread keys evenly between members.
+const virtualNodes = 128
+
+// Ring is a consistent hash ring, which assigns keys to cluster members.
+// Adding or removing member moves only keys of that member.
+type Ring struct {
+	members []string
+	hashes  []uint32
+	owners  map[uint32]string
+}
+
+// NewRing creates ring of given members.
+func NewRing(members []string) *Ring {
+	ring := &Ring{
+		members: make([]string, len(members)),
+		hashes:  make([]uint32, 0, len(members)*virtualNodes),
+		owners:  make(map[uint32]string, len(members)*virtualNodes),
+	}
+	copy(ring.members, members)
+	sort.Strings(ring.members)
+
+	for _, member := range ring.members {
+		for i := 0; i < virtualNodes; i++ {
+			hash := crc32.ChecksumIEEE([]byte(member + "#" + strconv.Itoa(i)))
+			if _, ok := ring.owners[hash]; ok {
+				continue
+			}
+			ring.owners[hash] = member
+			ring.hashes = append(ring.hashes, hash)
+		}
+	}
+	sort.Slice(ring.hashes, func(i, j int) bool { return ring.hashes[i] < ring.hashes[j] })
+
+	return ring
+}
+
+// Members returns sorted members of ring.
+func (ring *Ring) Members() []string {
+	return ring.members
+}
+
+// Owner returns member, which owns given key, or empty string if ring has no members.
+func (ring *Ring) Owner(key string) string {
+	if len(ring.hashes) == 0 {
+		return ""
+	}
+
+	hash := crc32.ChecksumIEEE([]byte(key))
+	index := sort.Search(len(ring.hashes), func(i int) bool { return ring.hashes[i] >= hash })
+	if index == len(ring.hashes) {
+		index = 0
+	}
+	return ring.owners[ring.hashes[index]]
+}
@@ -0,0 +1,70 @@
+package cluster
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestRing(t *testing.T) {
+	Convey("Test empty ring", t, func() {
+		So(NewRing(nil).Owner("key"), ShouldBeEmpty)
+	})
+
+	Convey("Test keys are spread between members", t, func() {
+		ring := NewRing([]string{"filter-3:2004", "filter-1:2004", "filter-2:2004"})
+		So(ring.Members(), ShouldResemble, []string{"filter-1:2004", "filter-2:2004", "filter-3:2004"})
+
+		owned := make(map[string]int)
+		for i := 0; i < 3000; i++ {
+			owned[ring.Owner(fmt.Sprintf("key%d", i))]++
+		}
+		So(owned, ShouldHaveLength, 3)
+		for _, count := range owned {
+			So(count, ShouldBeGreaterThan, 500)
+		}
+	})
+
+	Convey("Test only keys of removed member are moved", t, func() {
+		ring := NewRing([]string{"filter-1:2004", "filter-2:2004", "filter-3:2004"})
+		smallerRing := NewRing([]string{"filter-1:2004", "filter-2:2004"})
+
+		for i := 0; i < 1000; i++ {
+			key := fmt.Sprintf("key%d", i)
+			if owner := ring.Owner(key); owner != "filter-3:2004" {
+				So(smallerRing.Owner(key), ShouldEqual, owner)
+			}
+		}
+	})
+}
+
+func TestKeys(t *testing.T) {
+	Convey("Test metric keys", t, func() {
+		So(MetricKey([]byte("service.rps 1 1234567890")), ShouldEqual, "service")
+		So(MetricKey([]byte("service;dc=east 1 1234567890")), ShouldEqual, "service")
+		So(MetricKey([]byte("service 1 1234567890")), ShouldEqual, "service")
+	})
+
+	Convey("Test pattern keys", t, func() {
+		testCases := []struct {
+			pattern string
+			key     string
+			ok      bool
+		}{
+			{"service.*.rps", "service", true},
+			{"service", "service", true},
+			{"*.rps", "", false},
+			{"serv{ice,er}.rps", "", false},
+			{`seriesByTag('name=service.rps', 'dc=east')`, "service", true},
+			{`seriesByTag('name=~service', 'dc=east')`, "", false},
+			{`seriesByTag('name=*.rps')`, "", false},
+			{`seriesByTag('dc=east')`, "", false},
+		}
+		for _, testCase := range testCases {
+			key, ok := PatternKey(testCase.pattern)
+			So(key, ShouldEqual, testCase.key)
+			So(ok, ShouldEqual, testCase.ok)
+		}
+	})
+}
@@ -0,0 +1,231 @@
+package cluster
+
+import (
+	"bufio"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/filter"
+	"github.com/moira-alert/moira/metrics"
+)
+
+const (
+	forwardChannelSize = 16384
+	forwardFlushPeriod = time.Second
+	forwardDialTimeout = time.Second
+	forwardRetryDelay  = time.Second
+)
+
+// Router passes metrics owned by this member to local matching and forwards the rest to their owners.
+type Router struct {
+	membership *Membership
+	metrics    *metrics.FilterMetrics
+	logger     moira.Logger
+	relabeler  *filter.Relabeler
+	forwarders map[string]*forwarder
+	waitGroup  sync.WaitGroup
+}
+
+// NewRouter creates router of metrics between cluster members.
+func NewRouter(membership *Membership, metrics *metrics.FilterMetrics, logger moira.Logger) *Router {
+	return &Router{
+		membership: membership,
+		metrics:    metrics,
+		logger:     logger,
+		forwarders: make(map[string]*forwarder),
+	}
+}
+
+// SetRelabeler makes router rewrite and relabel metrics before routing, so metrics are routed by their new names.
+// Members should not relabel routed metrics again, it should be set before routing.
+func (router *Router) SetRelabeler(relabeler *filter.Relabeler) {
+	router.relabeler = relabeler
+}
+
+// Route reads metric lines until channel is closed and returns lines, which should be matched locally.
+func (router *Router) Route(lines <-chan []byte) <-chan []byte {
+	localLines := make(chan []byte, forwardChannelSize)
+	go func() {
+		defer close(localLines)
+		defer router.stopForwarders()
+
+		for line := range lines {
+			if router.relabeler != nil {
+				var ok bool
+				if line, ok = router.relabel(line); !ok {
+					continue
+				}
+			}
+			owner := router.membership.Owner(MetricKey(line))
+			if owner == router.membership.Address() || owner == "" {
+				localLines <- line
+				continue
+			}
+			// Forwarded lines are not matched here, but they are received by this member
+			router.metrics.TotalMetricsReceived.Inc()
+			router.forward(owner, line)
+		}
+	}()
+	return localLines
+}
+
+// relabel applies rewrite and relabel rules to metric line, it returns false if metric is dropped.
+// Lines, which can not be parsed, are routed as is and rejected by member, which matches them.
+func (router *Router) relabel(line []byte) ([]byte, bool) {
+	metric, err := filter.ParseMetric(line)
+	if err != nil {
+		return line, true
+	}
+
+	originalMetric := metric.Metric
+	if !router.relabeler.Apply(metric) {
+		// Dropped lines are never matched, but they are received by this member
+		router.metrics.TotalMetricsReceived.Inc()
+		router.metrics.DroppedMetricsReceived.Inc()
+		return nil, false
+	}
+	if metric.Metric == originalMetric {
+		return line, true
+	}
+
+	router.metrics.RewrittenMetricsReceived.Inc()
+	relabeled := make([]byte, 0, len(line)+len(metric.Metric)-len(originalMetric))
+	relabeled = append(relabeled, metric.Metric...)
+	relabeled = append(relabeled, ' ')
+	relabeled = strconv.AppendFloat(relabeled, metric.Value, 'f', -1, 64)
+	relabeled = append(relabeled, ' ')
+	relabeled = strconv.AppendInt(relabeled, metric.Timestamp, 10)
+	return relabeled, true
+}
+
+func (router *Router) forward(owner string, line []byte) {
+	fwd, ok := router.forwarders[owner]
+	if !ok {
+		fwd = &forwarder{
+			address: owner,
+			lines:   make(chan []byte, forwardChannelSize),
+			metrics: router.metrics,
+			logger:  router.logger,
+		}
+		router.forwarders[owner] = fwd
+		router.waitGroup.Add(1)
+		go func() {
+			defer router.waitGroup.Done()
+			fwd.run()
+		}()
+	}
+
+	select {
+	case fwd.lines <- line:
+	default:
+		router.metrics.ClusterForwardFailed.Inc()
+	}
+}
+
+func (router *Router) stopForwarders() {
+	for _, fwd := range router.forwarders {
+		close(fwd.lines)
+	}
+	router.waitGroup.Wait()
+}
+
+// forwarder sends metric lines to other member in graphite plaintext format.
+// Lines are dropped while member is unreachable.
+type forwarder struct {
+	address    string
+	lines      chan []byte
+	metrics    *metrics.FilterMetrics
+	logger     moira.Logger
+	connection net.Conn
+	writer     *bufio.Writer
+	retryAfter time.Time
+}
+
+func (fwd *forwarder) run() {
+	flushTicker := time.NewTicker(forwardFlushPeriod)
+	defer flushTicker.Stop()
+	defer fwd.disconnect()
+
+	for {
+		select {
+		case line, ok := <-fwd.lines:
+			if !ok {
+				fwd.flush()
+				return
+			}
+			fwd.write(line)
+		case <-flushTicker.C:
+			fwd.flush()
+		}
+	}
+}
+
+func (fwd *forwarder) write(line []byte) {
+	if !fwd.connect() {
+		fwd.metrics.ClusterForwardFailed.Inc()
+		return
+	}
+
+	if _, err := fwd.writer.Write(line); err != nil {
+		fwd.fail(err)
+		return
+	}
+	if err := fwd.writer.WriteByte('\n'); err != nil {
+		fwd.fail(err)
+		return
+	}
+	fwd.metrics.ClusterForwarded.Inc()
+}
+
+func (fwd *forwarder) flush() {
+	if fwd.writer == nil {
+		return
+	}
+	if err := fwd.writer.Flush(); err != nil {
+		fwd.fail(err)
+	}
+}
+
+func (fwd *forwarder) connect() bool {
+	if fwd.connection != nil {
+		return true
+	}
+	if time.Now().Before(fwd.retryAfter) {
+		return false
+	}
+
+	connection, err := net.DialTimeout("tcp", fwd.address, forwardDialTimeout)
+	if err != nil {
+		fwd.retryAfter = time.Now().Add(forwardRetryDelay)
+		fwd.logger.Warning().
+			String("address", fwd.address).
+			Error(err).
+			Msg("Failed to connect to filter cluster member")
+		return false
+	}
+
+	fwd.connection = connection
+	fwd.writer = bufio.NewWriter(connection)
+	return true
+}
+
+func (fwd *forwarder) fail(err error) {
+	fwd.metrics.ClusterForwardFailed.Inc()
+	fwd.logger.Warning().
+		String("address", fwd.address).
+		Error(err).
+		Msg("Failed to forward metrics to filter cluster member")
+	fwd.disconnect()
+	fwd.retryAfter = time.Now().Add(forwardRetryDelay)
+}
+
+func (fwd *forwarder) disconnect() {
+	if fwd.connection != nil {
+		fwd.connection.Close()
+	}
+	fwd.connection = nil
+	fwd.writer = nil
+}
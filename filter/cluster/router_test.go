@@ -0,0 +1,110 @@
+package cluster
+
+import (
+	"bufio"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/moira-alert/moira/filter"
+	logging "github.com/moira-alert/moira/logging/zerolog_adapter"
+	"github.com/moira-alert/moira/metrics"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestRouter(t *testing.T) {
+	logger, _ := logging.GetLogger("Cluster")
+
+	Convey("Test router", t, func() {
+		filterMetrics := metrics.ConfigureFilterMetrics(metrics.NewDummyRegistry())
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		So(err, ShouldBeNil)
+		defer listener.Close()
+
+		forwarded := make(chan string, 10)
+		go func() {
+			connection, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer connection.Close()
+			scanner := bufio.NewScanner(connection)
+			for scanner.Scan() {
+				forwarded <- scanner.Text()
+			}
+		}()
+
+		self := "127.0.0.1:1"
+		other := listener.Addr().String()
+		membership := NewMembership(nil, logger, self, time.Second, time.Second)
+		membership.ring.Store(NewRing([]string{self, other}))
+
+		var localKey, otherKey string
+		for _, key := range []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j"} {
+			if membership.Owner(key) == self {
+				localKey = key
+			} else {
+				otherKey = key
+			}
+		}
+		So(localKey, ShouldNotBeEmpty)
+		So(otherKey, ShouldNotBeEmpty)
+
+		Convey("metrics are routed to owners", func() {
+			lines := make(chan []byte, 2)
+			lines <- []byte(localKey + ".rps 1 1234567890")
+			lines <- []byte(otherKey + ".rps;dc=east 2 1234567890")
+			close(lines)
+
+			router := NewRouter(membership, filterMetrics, logger)
+			localLines := router.Route(lines)
+
+			So(string(<-localLines), ShouldEqual, localKey+".rps 1 1234567890")
+			_, ok := <-localLines
+			So(ok, ShouldBeFalse)
+
+			select {
+			case line := <-forwarded:
+				So(line, ShouldEqual, otherKey+".rps;dc=east 2 1234567890")
+			case <-time.After(5 * time.Second):
+				So("metric is not forwarded", ShouldBeEmpty)
+			}
+			So(filterMetrics.ClusterForwarded.Count(), ShouldEqual, 1)
+			So(filterMetrics.TotalMetricsReceived.Count(), ShouldEqual, 1)
+		})
+
+		Convey("metrics are routed by relabeled names", func() {
+			relabeler, err := filter.NewRelabeler(
+				[]filter.RewriteRule{{Pattern: "^" + localKey + `\.`, Replacement: otherKey + "."}},
+				[]filter.RelabelRule{{Action: filter.RelabelDrop, SourceLabels: []string{"env"}, Regex: "test"}},
+			)
+			So(err, ShouldBeNil)
+
+			lines := make(chan []byte, 3)
+			lines <- []byte(localKey + ".rps 1 1234567890")
+			lines <- []byte(localKey + ".rps;env=test 2 1234567890")
+			lines <- []byte(otherKey + ".rps 3 1234567890")
+			close(lines)
+
+			router := NewRouter(membership, filterMetrics, logger)
+			router.SetRelabeler(relabeler)
+			localLines := router.Route(lines)
+
+			_, ok := <-localLines
+			So(ok, ShouldBeFalse)
+
+			for _, expected := range []string{otherKey + ".rps 1 1234567890", otherKey + ".rps 3 1234567890"} {
+				select {
+				case line := <-forwarded:
+					So(line, ShouldEqual, expected)
+				case <-time.After(5 * time.Second):
+					So("metric is not forwarded", ShouldBeEmpty)
+				}
+			}
+			So(filterMetrics.ClusterForwarded.Count(), ShouldEqual, 2)
+			So(filterMetrics.RewrittenMetricsReceived.Count(), ShouldEqual, 1)
+			So(filterMetrics.DroppedMetricsReceived.Count(), ShouldEqual, 1)
+			So(filterMetrics.TotalMetricsReceived.Count(), ShouldEqual, 3)
+		})
+	})
+}
@@ -1,6 +1,7 @@
 package patterns
 
 import (
+	"sync"
 	"time"
 
 	"github.com/moira-alert/moira"
@@ -16,6 +17,7 @@ type Matcher struct {
 	metrics        *metrics.FilterMetrics
 	patternStorage *filter.PatternStorage
 	metricTTL      time.Duration
+	forwardedLines <-chan []byte
 }
 
 // NewMatcher creates pattern matcher.
@@ -28,38 +30,101 @@ func NewMatcher(logger moira.Logger, metrics *metrics.FilterMetrics, patternsSto
 	}
 }
 
-// Start spawns pattern matcher workers.
+// SetForwardedLines makes matcher also match lines forwarded by other filter cluster members,
+// it should be set before start.
+func (m *Matcher) SetForwardedLines(forwardedLines <-chan []byte) {
+	m.forwardedLines = forwardedLines
+}
+
+// Start spawns pattern matcher workers, they stop when lines channel and forwarded lines channel are closed.
 func (m *Matcher) Start(matchersCount int, lineChan <-chan []byte) chan *moira.MatchedMetric {
 	matchedMetricsChan := make(chan *moira.MatchedMetric, 16384) //nolint
 	m.logger.Info().
 		Int("matchers_count", matchersCount).
 		Msg("Start pattern matcher workers")
 
+	var workers sync.WaitGroup
 	for i := 0; i < matchersCount; i++ {
+		workers.Add(1)
 		m.tomb.Go(func() error {
-			return m.worker(lineChan, matchedMetricsChan)
+			defer workers.Done()
+			return m.worker(lineChan, m.forwardedLines, matchedMetricsChan)
 		})
 	}
+	m.tomb.Go(func() error { return m.checkNewMetricsChannelLen(matchedMetricsChan) })
+	m.tomb.Go(func() error { return m.flushAggregatedMetrics(matchedMetricsChan) })
+	// Stop flusher and channel len checker when workers drained closed lines channel
+	m.tomb.Go(func() error {
+		workers.Wait()
+		m.tomb.Kill(nil)
+		return nil
+	})
+
 	go func() {
 		<-m.tomb.Dying()
 		m.logger.Info().Msg("Stopping pattern matcher...")
+		// Channel is closed only after all goroutines sending to it have exited
+		m.tomb.Wait() //nolint
 		close(matchedMetricsChan)
 		m.logger.Info().Msg("Moira pattern matcher stopped")
 	}()
-
-	m.tomb.Go(func() error { return m.checkNewMetricsChannelLen(matchedMetricsChan) })
 	return matchedMetricsChan
 }
 
-func (m *Matcher) worker(metricsChan <-chan []byte, matchedMetricsChan chan<- *moira.MatchedMetric) error {
-	for line := range metricsChan {
-		if metric := m.patternStorage.ProcessIncomingMetric(line, m.metricTTL); metric != nil {
-			matchedMetricsChan <- metric
+func (m *Matcher) worker(metricsChan, forwardedChan <-chan []byte, matchedMetricsChan chan<- *moira.MatchedMetric) error {
+	// Receiving from nil channel blocks forever, so closed channels are set to nil
+	for metricsChan != nil || forwardedChan != nil {
+		var metric *moira.MatchedMetric
+		select {
+		case <-m.tomb.Dying():
+			return nil
+		case line, ok := <-metricsChan:
+			if !ok {
+				metricsChan = nil
+				continue
+			}
+			metric = m.patternStorage.ProcessIncomingMetric(line, m.metricTTL)
+		case line, ok := <-forwardedChan:
+			if !ok {
+				forwardedChan = nil
+				continue
+			}
+			metric = m.patternStorage.ProcessForwardedMetric(line, m.metricTTL)
+		}
+		if metric != nil && !m.send(matchedMetricsChan, metric) {
+			return nil
 		}
 	}
 	return nil
 }
 
+func (m *Matcher) flushAggregatedMetrics(matchedMetricsChan chan<- *moira.MatchedMetric) error {
+	flushTicker := time.NewTicker(time.Second)
+	defer flushTicker.Stop()
+	for {
+		select {
+		case <-m.tomb.Dying():
+			return nil
+		case now := <-flushTicker.C:
+			for _, metric := range m.patternStorage.FlushAggregatedMetrics(now) {
+				if !m.send(matchedMetricsChan, metric) {
+					return nil
+				}
+			}
+		}
+	}
+}
+
+// send puts metric to the channel, it returns false if matcher is stopping.
+func (m *Matcher) send(matchedMetricsChan chan<- *moira.MatchedMetric, metric *moira.MatchedMetric) bool {
+	select {
+	case matchedMetricsChan <- metric:
+		return true
+	case <-m.tomb.Dying():
+		return false
+	}
+}
+
 func (m *Matcher) checkNewMetricsChannelLen(channel <-chan *moira.MatchedMetric) error {
 	checkTicker := time.NewTicker(time.Millisecond * 100) //nolint
 	for {
@@ -3,6 +3,7 @@ package filter
 import (
 	"errors"
 	"fmt"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -20,7 +21,11 @@ type PatternStorage struct {
 	logger                  moira.Logger
 	PatternIndex            atomic.Value
 	SeriesByTagPatternIndex atomic.Value
-	compatibility           Compatibility
+	compatibility           atomic.Value
+	indexesMutex            sync.Mutex
+	aggregator              *Aggregator
+	relabeler               *Relabeler
+	ownsPattern             func(pattern string) bool
 }
 
 // NewPatternStorage creates new PatternStorage struct.
@@ -31,26 +36,85 @@ func NewPatternStorage(
 	compatibility Compatibility,
 ) (*PatternStorage, error) {
 	storage := &PatternStorage{
-		database:      database,
-		metrics:       metrics,
-		logger:        logger,
-		clock:         clock.NewSystemClock(),
-		compatibility: compatibility,
+		database: database,
+		metrics:  metrics,
+		logger:   logger,
+		clock:    clock.NewSystemClock(),
 	}
+	storage.compatibility.Store(compatibility)
 	err := storage.Refresh()
 	return storage, err
 }
 
+// PatternIndexes are pattern's indexes built with graphite compatibility flags, they are applied by SetIndexes.
+type PatternIndexes struct {
+	compatibility           Compatibility
+	patternIndex            *PatternIndex
+	seriesByTagPatternIndex *SeriesByTagPatternIndex
+}
+
+// SetCompatibility changes graphite compatibility flags and rebuilds pattern's indexes with them.
+// Flags and indexes are kept untouched if indexes can not be built.
+func (storage *PatternStorage) SetCompatibility(compatibility Compatibility) error {
+	indexes, err := storage.BuildIndexes(compatibility)
+	if err != nil {
+		return err
+	}
+	storage.SetIndexes(indexes)
+	return nil
+}
+
+// SetAggregator makes storage aggregate matched metrics before saving, it should be set before processing of metrics.
+func (storage *PatternStorage) SetAggregator(aggregator *Aggregator) {
+	storage.aggregator = aggregator
+}
+
+// SetRelabeler makes storage rewrite and relabel metrics before matching, it should be set before processing of metrics.
+func (storage *PatternStorage) SetRelabeler(relabeler *Relabeler) {
+	storage.relabeler = relabeler
+}
+
+// SetPatternsOwner makes storage load only patterns owned by this filter, e.g. in filter cluster.
+// It takes effect on the next refresh.
+func (storage *PatternStorage) SetPatternsOwner(ownsPattern func(pattern string) bool) {
+	storage.ownsPattern = ownsPattern
+}
+
+// FlushAggregatedMetrics returns aggregated metrics, which got no new values for a long time.
+func (storage *PatternStorage) FlushAggregatedMetrics(now time.Time) []*moira.MatchedMetric {
+	if storage.aggregator == nil {
+		return nil
+	}
+	return storage.aggregator.Flush(now)
+}
+
 // Refresh builds pattern's indexes from redis data.
+// Indexes are built and applied under the lock of SetIndexes, so refresh never brings back old compatibility flags.
 func (storage *PatternStorage) Refresh() error {
-	newPatterns, err := storage.database.GetPatterns()
+	storage.indexesMutex.Lock()
+	defer storage.indexesMutex.Unlock()
+
+	indexes, err := storage.BuildIndexes(storage.compatibility.Load().(Compatibility))
 	if err != nil {
 		return err
 	}
+	storage.setIndexes(indexes)
+	return nil
+}
+
+// BuildIndexes builds pattern's indexes from redis data with given graphite compatibility flags without applying them.
+func (storage *PatternStorage) BuildIndexes(compatibility Compatibility) (*PatternIndexes, error) {
+	newPatterns, err := storage.database.GetPatterns()
+	if err != nil {
+		return nil, err
+	}
 
 	seriesByTagPatterns := make(map[string][]TagSpec)
 	patterns := make([]string, 0)
 	for _, newPattern := range newPatterns {
+		if storage.ownsPattern != nil && !storage.ownsPattern(newPattern) {
+			continue
+		}
 		tagSpecs, err := ParseSeriesByTag(newPattern)
 		if errors.Is(err, ErrNotSeriesByTag) {
 			patterns = append(patterns, newPattern)
@@ -59,16 +123,41 @@ func (storage *PatternStorage) Refresh() error {
 		}
 	}
 
-	storage.PatternIndex.Store(NewPatternIndex(storage.logger, patterns, storage.compatibility))
-	storage.SeriesByTagPatternIndex.Store(NewSeriesByTagPatternIndex(storage.logger, seriesByTagPatterns, storage.compatibility))
-	return nil
+	return &PatternIndexes{
+		compatibility:           compatibility,
+		patternIndex:            NewPatternIndex(storage.logger, patterns, compatibility),
+		seriesByTagPatternIndex: NewSeriesByTagPatternIndex(storage.logger, seriesByTagPatterns, compatibility),
+	}, nil
+}
+
+// SetIndexes replaces pattern's indexes and graphite compatibility flags with built ones.
+func (storage *PatternStorage) SetIndexes(indexes *PatternIndexes) {
+	storage.indexesMutex.Lock()
+	defer storage.indexesMutex.Unlock()
+
+	storage.setIndexes(indexes)
+}
+
+func (storage *PatternStorage) setIndexes(indexes *PatternIndexes) {
+	storage.compatibility.Store(indexes.compatibility)
+	storage.PatternIndex.Store(indexes.patternIndex)
+	storage.SeriesByTagPatternIndex.Store(indexes.seriesByTagPatternIndex)
 }
 
 // ProcessIncomingMetric validates, parses and matches incoming raw string.
 func (storage *PatternStorage) ProcessIncomingMetric(lineBytes []byte, maxTTL time.Duration) *moira.MatchedMetric {
 	storage.metrics.TotalMetricsReceived.Inc()
-	count := storage.metrics.TotalMetricsReceived.Count()
+	return storage.processMetric(lineBytes, maxTTL, storage.metrics.TotalMetricsReceived.Count())
+}
 
+// ProcessForwardedMetric validates, parses and matches raw string forwarded by other filter cluster member.
+// Such metrics are already counted as received by the member, which got them, so they have their own counter.
+func (storage *PatternStorage) ProcessForwardedMetric(lineBytes []byte, maxTTL time.Duration) *moira.MatchedMetric {
+	storage.metrics.ClusterMetricsReceived.Inc()
+	return storage.processMetric(lineBytes, maxTTL, storage.metrics.ClusterMetricsReceived.Count())
+}
+
+func (storage *PatternStorage) processMetric(lineBytes []byte, maxTTL time.Duration, count int64) *moira.MatchedMetric {
 	parsedMetric, err := ParseMetric(lineBytes)
 	if err != nil {
 		storage.logger.Info().
@@ -78,6 +167,10 @@ func (storage *PatternStorage) ProcessIncomingMetric(lineBytes []byte, maxTTL ti
 		return nil
 	}
 
+	if storage.relabeler != nil && !storage.relabelMetric(parsedMetric) {
+		return nil
+	}
+
 	if parsedMetric.IsTooOld(maxTTL, storage.clock.Now()) {
 		storage.logger.Debug().
 			String(moira.LogFieldNameMetricName, parsedMetric.Name).
@@ -97,7 +190,7 @@ func (storage *PatternStorage) ProcessIncomingMetric(lineBytes []byte, maxTTL ti
 
 	if len(matchedPatterns) > 0 {
 		storage.metrics.MatchingMetricsReceived.Inc()
-		return &moira.MatchedMetric{
+		matchedMetric := &moira.MatchedMetric{
 			Metric:             parsedMetric.Metric,
 			Patterns:           matchedPatterns,
 			Value:              parsedMetric.Value,
@@ -105,6 +198,10 @@ func (storage *PatternStorage) ProcessIncomingMetric(lineBytes []byte, maxTTL ti
 			RetentionTimestamp: parsedMetric.Timestamp,
 			Retention:          60, //nolint
 		}
+		if storage.aggregator != nil {
+			return storage.aggregator.Aggregate(matchedMetric)
+		}
+		return matchedMetric
 	}
 
 	storage.logger.Debug().
@@ -114,6 +211,23 @@ func (storage *PatternStorage) ProcessIncomingMetric(lineBytes []byte, maxTTL ti
 	return nil
 }
 
+// relabelMetric applies rewrite and relabel rules to metric, it returns false if metric is dropped.
+func (storage *PatternStorage) relabelMetric(metric *ParsedMetric) bool {
+	originalMetric := metric.Metric
+	if !storage.relabeler.Apply(metric) {
+		storage.metrics.DroppedMetricsReceived.Inc()
+		storage.logger.Debug().
+			String(moira.LogFieldNameMetricName, originalMetric).
+			Msg("Metric is dropped by relabel rules")
+		return false
+	}
+
+	if metric.Metric != originalMetric {
+		storage.metrics.RewrittenMetricsReceived.Inc()
+	}
+	return true
+}
+
 func (storage *PatternStorage) matchPatterns(metric *ParsedMetric) []string {
 	if metric.IsTagged() {
 		seriesByTagPatternIndex := storage.SeriesByTagPatternIndex.Load().(*SeriesByTagPatternIndex)
@@ -2,6 +2,7 @@ package filter
 
 import (
 	"fmt"
+	"sync"
 	"testing"
 	"time"
 
@@ -143,5 +144,75 @@ func TestProcessIncomingMetric(t *testing.T) {
 		So(patternsStorage.metrics.MatchingTimer.Count(), ShouldEqual, 1)
 	})
 
+	Convey("When metric is forwarded by other filter cluster member it should be counted separately", t, func() {
+		patternsStorage.metrics = metrics.ConfigureFilterMetrics(metrics.NewDummyRegistry())
+		matchedMetric := patternsStorage.ProcessForwardedMetric([]byte("cpu.used 12 1234567890"), time.Hour)
+		So(matchedMetric, ShouldNotBeNil)
+		So(patternsStorage.metrics.TotalMetricsReceived.Count(), ShouldEqual, 0)
+		So(patternsStorage.metrics.ClusterMetricsReceived.Count(), ShouldEqual, 1)
+		So(patternsStorage.metrics.ValidMetricsReceived.Count(), ShouldEqual, 1)
+		So(patternsStorage.metrics.MatchingMetricsReceived.Count(), ShouldEqual, 1)
+	})
+
+	Convey("When relabeler is set metrics should be rewritten or dropped before matching", t, func() {
+		patternsStorage.metrics = metrics.ConfigureFilterMetrics(metrics.NewDummyRegistry())
+		relabeler, err := NewRelabeler(
+			[]RewriteRule{{Pattern: `^CPU\.`, Replacement: "cpu."}},
+			[]RelabelRule{{Action: RelabelDrop, SourceLabels: []string{"env"}, Regex: "test"}},
+		)
+		So(err, ShouldBeNil)
+		patternsStorage.SetRelabeler(relabeler)
+		defer patternsStorage.SetRelabeler(nil)
+
+		matchedMetric := patternsStorage.ProcessIncomingMetric([]byte("CPU.used 12 1234567890"), time.Hour)
+		So(matchedMetric, ShouldNotBeNil)
+		So(matchedMetric.Metric, ShouldEqual, "cpu.used")
+		So(patternsStorage.metrics.RewrittenMetricsReceived.Count(), ShouldEqual, 1)
+
+		matchedMetric = patternsStorage.ProcessIncomingMetric([]byte("cpu.used;env=test 12 1234567890"), time.Hour)
+		So(matchedMetric, ShouldBeNil)
+		So(patternsStorage.metrics.DroppedMetricsReceived.Count(), ShouldEqual, 1)
+		So(patternsStorage.metrics.ValidMetricsReceived.Count(), ShouldEqual, 1)
+	})
+
+	Convey("When compatibility is changed pattern indexes should be rebuilt", t, func() {
+		database.EXPECT().GetPatterns().Return(testPatterns, nil)
+		oldIndex := patternsStorage.PatternIndex.Load()
+
+		err := patternsStorage.SetCompatibility(Compatibility{AllowRegexMatchEmpty: true})
+		So(err, ShouldBeNil)
+		So(patternsStorage.compatibility.Load(), ShouldResemble, Compatibility{AllowRegexMatchEmpty: true})
+		So(patternsStorage.PatternIndex.Load(), ShouldNotPointTo, oldIndex)
+	})
+
+	Convey("When pattern indexes can not be rebuilt compatibility should be kept", t, func() {
+		database.EXPECT().GetPatterns().Return(nil, fmt.Errorf("some error"))
+		oldIndex := patternsStorage.PatternIndex.Load()
+
+		err := patternsStorage.SetCompatibility(Compatibility{AllowRegexLooseStartMatch: true})
+		So(err, ShouldNotBeNil)
+		So(patternsStorage.compatibility.Load(), ShouldResemble, Compatibility{AllowRegexMatchEmpty: true})
+		So(patternsStorage.PatternIndex.Load(), ShouldPointTo, oldIndex)
+	})
+
+	Convey("When patterns are refreshed during compatibility change compatibility should not be reverted", t, func() {
+		database.EXPECT().GetPatterns().Return(testPatterns, nil).AnyTimes()
+		newCompatibility := Compatibility{AllowRegexLooseStartMatch: true, AllowRegexMatchEmpty: true}
+
+		var waitGroup sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			waitGroup.Add(1)
+			go func() {
+				defer waitGroup.Done()
+				patternsStorage.Refresh() //nolint
+			}()
+		}
+		err := patternsStorage.SetCompatibility(newCompatibility)
+		waitGroup.Wait()
+
+		So(err, ShouldBeNil)
+		So(patternsStorage.compatibility.Load(), ShouldResemble, newCompatibility)
+	})
+
 	mockCtrl.Finish()
 }
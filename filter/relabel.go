@@ -0,0 +1,212 @@
+package filter
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// nameLabel is a pseudo label, which is used in relabel rules to read and change metric name.
+const nameLabel = "name"
+
+// Relabel actions.
+const (
+	RelabelReplace   = "replace"
+	RelabelKeep      = "keep"
+	RelabelDrop      = "drop"
+	RelabelLabelMap  = "labelmap"
+	RelabelLabelDrop = "labeldrop"
+)
+
+// RewriteRule replaces part of metric name matching regular expression, e.g. hostnames with dots.
+type RewriteRule struct {
+	Pattern     string
+	Replacement string
+}
+
+// RelabelRule changes labels of metric like relabel_config of Prometheus does.
+// Metric name could be read and changed as label "name".
+type RelabelRule struct {
+	// Action is one of replace (default), keep, drop, labelmap and labeldrop
+	Action string
+	// SourceLabels are labels, which values are joined with Separator and matched against Regex
+	SourceLabels []string
+	// Separator joins values of SourceLabels, it is ";" by default
+	Separator string
+	// Regex is anchored on both ends, it is "(.*)" by default
+	Regex string
+	// TargetLabel is label, which gets Replacement in replace action
+	TargetLabel string
+	// Replacement may refer to Regex groups, it is "$1" by default
+	Replacement string
+}
+
+type rewriteRule struct {
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+type relabelRule struct {
+	action       string
+	sourceLabels []string
+	separator    string
+	regex        *regexp.Regexp
+	targetLabel  string
+	replacement  string
+}
+
+// Relabeler rewrites metric names and relabels tags of incoming metrics before pattern matching.
+type Relabeler struct {
+	rewriteRules []rewriteRule
+	relabelRules []relabelRule
+}
+
+// NewRelabeler creates relabeler, rewrite rules are applied to metric name first, then relabel rules are applied in order.
+func NewRelabeler(rewriteRules []RewriteRule, relabelRules []RelabelRule) (*Relabeler, error) {
+	relabeler := &Relabeler{
+		rewriteRules: make([]rewriteRule, 0, len(rewriteRules)),
+		relabelRules: make([]relabelRule, 0, len(relabelRules)),
+	}
+
+	for _, rule := range rewriteRules {
+		pattern, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rewrite rule pattern %s: %w", rule.Pattern, err)
+		}
+		relabeler.rewriteRules = append(relabeler.rewriteRules, rewriteRule{pattern: pattern, replacement: rule.Replacement})
+	}
+
+	for i, rule := range relabelRules {
+		compiled, err := newRelabelRule(rule)
+		if err != nil {
+			return nil, fmt.Errorf("invalid relabel rule %d: %w", i, err)
+		}
+		relabeler.relabelRules = append(relabeler.relabelRules, *compiled)
+	}
+
+	return relabeler, nil
+}
+
+func newRelabelRule(rule RelabelRule) (*relabelRule, error) {
+	result := &relabelRule{
+		action:       rule.Action,
+		sourceLabels: rule.SourceLabels,
+		separator:    rule.Separator,
+		targetLabel:  rule.TargetLabel,
+		replacement:  rule.Replacement,
+	}
+	if result.action == "" {
+		result.action = RelabelReplace
+	}
+	if result.separator == "" {
+		result.separator = ";"
+	}
+	if result.replacement == "" {
+		result.replacement = "$1"
+	}
+
+	regex := rule.Regex
+	if regex == "" {
+		regex = "(.*)"
+	}
+	var err error
+	if result.regex, err = regexp.Compile("^(?:" + regex + ")$"); err != nil {
+		return nil, fmt.Errorf("invalid regex %s: %w", rule.Regex, err)
+	}
+
+	switch result.action {
+	case RelabelReplace:
+		if result.targetLabel == "" {
+			return nil, fmt.Errorf("target_label is required for action %s", result.action)
+		}
+	case RelabelKeep, RelabelDrop:
+		if len(result.sourceLabels) == 0 {
+			return nil, fmt.Errorf("source_labels are required for action %s", result.action)
+		}
+	case RelabelLabelMap, RelabelLabelDrop:
+	default:
+		return nil, fmt.Errorf("unknown action %s, should be one of replace, keep, drop, labelmap, labeldrop", result.action)
+	}
+
+	return result, nil
+}
+
+// Apply rewrites name and relabels labels of metric. It returns false if metric should be dropped.
+func (relabeler *Relabeler) Apply(metric *ParsedMetric) bool {
+	name := metric.Name
+	for _, rule := range relabeler.rewriteRules {
+		name = rule.pattern.ReplaceAllString(name, rule.replacement)
+	}
+
+	labels := metric.Labels
+	if len(relabeler.relabelRules) > 0 {
+		labels = make(map[string]string, len(metric.Labels)+1)
+		for label, value := range metric.Labels {
+			labels[label] = value
+		}
+		labels[nameLabel] = name
+
+		for _, rule := range relabeler.relabelRules {
+			if !rule.apply(labels) {
+				return false
+			}
+		}
+
+		name = labels[nameLabel]
+		delete(labels, nameLabel)
+		if name == "" {
+			return false
+		}
+	}
+
+	metric.Name = name
+	metric.Labels = labels
+	metric.Metric = restoreMetricStringByNameAndLabels(name, labels)
+	return true
+}
+
+func (rule *relabelRule) apply(labels map[string]string) bool {
+	switch rule.action {
+	case RelabelKeep:
+		return rule.regex.MatchString(rule.sourceValue(labels))
+	case RelabelDrop:
+		return !rule.regex.MatchString(rule.sourceValue(labels))
+	case RelabelLabelMap:
+		mapped := make(map[string]string)
+		for label, value := range labels {
+			if label != nameLabel && rule.regex.MatchString(label) {
+				mapped[rule.regex.ReplaceAllString(label, rule.replacement)] = value
+			}
+		}
+		for label, value := range mapped {
+			labels[label] = value
+		}
+	case RelabelLabelDrop:
+		for label := range labels {
+			if label != nameLabel && rule.regex.MatchString(label) {
+				delete(labels, label)
+			}
+		}
+	case RelabelReplace:
+		value := rule.sourceValue(labels)
+		match := rule.regex.FindStringSubmatchIndex(value)
+		if match == nil {
+			return true
+		}
+		result := string(rule.regex.ExpandString(nil, rule.replacement, value, match))
+		if result == "" {
+			delete(labels, rule.targetLabel)
+		} else {
+			labels[rule.targetLabel] = result
+		}
+	}
+	return true
+}
+
+func (rule *relabelRule) sourceValue(labels map[string]string) string {
+	values := make([]string, 0, len(rule.sourceLabels))
+	for _, label := range rule.sourceLabels {
+		values = append(values, labels[label])
+	}
+	return strings.Join(values, rule.separator)
+}
@@ -0,0 +1,90 @@
+package filter
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestRelabeler(t *testing.T) {
+	parse := func(line string) *ParsedMetric {
+		metric, err := ParseMetric([]byte(line))
+		So(err, ShouldBeNil)
+		return metric
+	}
+
+	Convey("Test invalid rules", t, func() {
+		_, err := NewRelabeler([]RewriteRule{{Pattern: "("}}, nil)
+		So(err, ShouldNotBeNil)
+
+		_, err = NewRelabeler(nil, []RelabelRule{{Action: "hashmod"}})
+		So(err.Error(), ShouldEqual, "invalid relabel rule 0: unknown action hashmod, should be one of replace, keep, drop, labelmap, labeldrop")
+
+		_, err = NewRelabeler(nil, []RelabelRule{{Action: RelabelReplace}})
+		So(err, ShouldNotBeNil)
+
+		_, err = NewRelabeler(nil, []RelabelRule{{Action: RelabelDrop}})
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("Test rewrite rules", t, func() {
+		relabeler, err := NewRelabeler([]RewriteRule{
+			{Pattern: `^servers\.([^.]+)\.example\.com\.`, Replacement: "servers.${1}_example_com."},
+			{Pattern: `\.cpu$`, Replacement: ".cpu_usage"},
+		}, nil)
+		So(err, ShouldBeNil)
+
+		metric := parse("servers.web1.example.com.cpu 12 1234567890")
+		So(relabeler.Apply(metric), ShouldBeTrue)
+		So(metric.Name, ShouldEqual, "servers.web1_example_com.cpu_usage")
+		So(metric.Metric, ShouldEqual, "servers.web1_example_com.cpu_usage")
+	})
+
+	Convey("Test relabel rules", t, func() {
+		relabeler, err := NewRelabeler(nil, []RelabelRule{
+			{Action: RelabelDrop, SourceLabels: []string{"env"}, Regex: "test|dev"},
+			{Action: RelabelKeep, SourceLabels: []string{"name"}, Regex: `service\..*`},
+			{SourceLabels: []string{"Host"}, TargetLabel: "host", Regex: "(.+)"},
+			{Action: RelabelLabelDrop, Regex: "Host"},
+			{Action: RelabelLabelMap, Regex: "k8s_(.+)", Replacement: "$1"},
+			{SourceLabels: []string{"dc", "rack"}, Separator: "-", TargetLabel: "location"},
+		})
+		So(err, ShouldBeNil)
+
+		Convey("metric is dropped by drop action", func() {
+			So(relabeler.Apply(parse("service.rps;env=test 12 1234567890")), ShouldBeFalse)
+		})
+
+		Convey("metric is dropped if keep action does not match", func() {
+			So(relabeler.Apply(parse("other.rps;env=prod 12 1234567890")), ShouldBeFalse)
+		})
+
+		Convey("labels are replaced and mapped", func() {
+			metric := parse("service.rps;Host=web1;k8s_pod=api-1;dc=east;rack=r2 12 1234567890")
+			So(relabeler.Apply(metric), ShouldBeTrue)
+			So(metric.Labels, ShouldResemble, map[string]string{
+				"host":     "web1",
+				"k8s_pod":  "api-1",
+				"pod":      "api-1",
+				"dc":       "east",
+				"rack":     "r2",
+				"location": "east-r2",
+			})
+			So(metric.Metric, ShouldEqual, "service.rps;dc=east;host=web1;k8s_pod=api-1;location=east-r2;pod=api-1;rack=r2")
+		})
+	})
+
+	Convey("Test metric name could be changed by relabel rules", t, func() {
+		relabeler, err := NewRelabeler(nil, []RelabelRule{
+			{SourceLabels: []string{"name", "env"}, Regex: `(.+);(.+)`, Replacement: "$2.$1", TargetLabel: "name"},
+			{SourceLabels: []string{"env"}, Regex: ".*", Replacement: "", TargetLabel: "env"},
+		})
+		So(err, ShouldBeNil)
+
+		metric := parse("service.rps;env=prod 12 1234567890")
+		So(relabeler.Apply(metric), ShouldBeTrue)
+		So(metric.Name, ShouldEqual, "prod.service.rps")
+		So(metric.Labels, ShouldBeEmpty)
+		So(metric.IsTagged(), ShouldBeFalse)
+	})
+}
@@ -107,11 +107,18 @@ type Database interface {
 	SaveMetrics(buffer map[string]*MatchedMetric) error
 	GetMetricRetention(metric string) (int64, error)
 	GetMetricsValues(metrics []string, from int64, until int64) (map[string][]*MetricValue, error)
+	GetMetricArchives(metric string) ([]Retention, error)
+	GetMetricsArchiveValues(metrics []string, step int64, from int64, until int64) (map[string][]*MetricValue, error)
 	RemoveMetricRetention(metric string) error
 	RemoveMetricValues(metric string, toTime int64) (int64, error)
 	RemoveMetricsValues(metrics []string, toTime int64) error
 	GetMetricsTTLSeconds() int64
 
+	// Filter cluster membership
+	UpdateFilterClusterMember(address string, timestamp int64) error
+	GetFilterClusterMembers(aliveSince int64) ([]string, error)
+	RemoveFilterClusterMember(address string) error
+
 	AddTriggersToCheck(clusterKey ClusterKey, triggerIDs []string) error
 	GetTriggersToCheck(clusterKey ClusterKey, count int) ([]string, error)
 	GetTriggersToCheckCount(clusterKey ClusterKey) (int64, error)
@@ -126,6 +133,9 @@ type Database interface {
 	GetIDByUsername(messenger, username string) (string, error)
 	SetUsernameID(messenger, username, id string) error
 	RemoveUser(messenger, username string) error
+	GetMessageThread(messenger, triggerID, channel string) (string, error)
+	SetMessageThread(messenger, triggerID, channel, threadID string) error
+	RemoveMessageThread(messenger, triggerID, channel string) error
 
 	// Triggers without subscription manipulation
 	MarkTriggersAsUnused(triggerIDs ...string) error
@@ -0,0 +1,98 @@
+// Package matching matches metric names by graphite globs and tags by seriesByTag label matches.
+package matching
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/patrickmn/go-cache"
+)
+
+// regexCache keeps compiled regular expressions of globs and tag matches, expressions which are not used anymore expire.
+var regexCache = cache.New(time.Hour, 10*time.Minute)
+
+// compileRegex returns compiled regular expression, compiled expressions are cached
+// as globs and tag matches are matched against every metric on every check. Invalid expressions are not cached.
+func compileRegex(expression string) (*regexp.Regexp, error) {
+	if cached, ok := regexCache.Get(expression); ok {
+		return cached.(*regexp.Regexp), nil
+	}
+	compiled, err := regexp.Compile(expression)
+	if err != nil {
+		return nil, err
+	}
+	regexCache.Set(expression, compiled, cache.DefaultExpiration)
+	return compiled, nil
+}
+
+// MatchGlob returns true if metric name matches graphite glob.
+func MatchGlob(glob, name string) (bool, error) {
+	globRegex, err := compileRegex(globToRegex(glob))
+	if err != nil {
+		return false, fmt.Errorf("invalid metric glob %s: %w", glob, err)
+	}
+	return globRegex.MatchString(name), nil
+}
+
+// globToRegex converts graphite glob to regular expression: * and ? don't match dots, {a,b} is alternation.
+func globToRegex(glob string) string {
+	var builder strings.Builder
+	builder.WriteString("^")
+	inAlternation := false
+	for _, char := range glob {
+		switch {
+		case char == '*':
+			builder.WriteString(`[^.]*`)
+		case char == '?':
+			builder.WriteString(`[^.]`)
+		case char == '{':
+			inAlternation = true
+			builder.WriteString("(?:")
+		case char == '}' && inAlternation:
+			inAlternation = false
+			builder.WriteString(")")
+		case char == ',' && inAlternation:
+			builder.WriteString("|")
+		case char == '[' || char == ']':
+			builder.WriteRune(char)
+		default:
+			builder.WriteString(regexp.QuoteMeta(string(char)))
+		}
+	}
+	builder.WriteString("$")
+	return builder.String()
+}
+
+// MatchTag checks tag of metric by seriesByTag label match, tag name "name" matches metric name.
+// Absent tag has empty value as in graphite. Regular expressions are matched from the start of value.
+func MatchTag(tagMatch, name string, tags map[string]string) (bool, error) {
+	index := strings.IndexAny(tagMatch, "!=")
+	if index <= 0 {
+		return false, fmt.Errorf("invalid tag match %s, it should be in format tag=value, tag!=value, tag=~regex or tag!=~regex", tagMatch)
+	}
+	tagName, rest := strings.TrimSpace(tagMatch[:index]), tagMatch[index:]
+
+	value := tags[tagName]
+	if tagName == "name" {
+		value = name
+	}
+
+	switch {
+	case strings.HasPrefix(rest, "!=~"), strings.HasPrefix(rest, "=~"):
+		negative := strings.HasPrefix(rest, "!")
+		expression := strings.TrimPrefix(strings.TrimPrefix(rest, "!"), "=~")
+		tagRegex, err := compileRegex("^(?:" + expression + ")")
+		if err != nil {
+			return false, fmt.Errorf("invalid regular expression in tag match %s: %w", tagMatch, err)
+		}
+		return tagRegex.MatchString(value) != negative, nil
+	case strings.HasPrefix(rest, "!="):
+		return value != strings.TrimPrefix(rest, "!="), nil
+	case strings.HasPrefix(rest, "="):
+		return value == strings.TrimPrefix(rest, "="), nil
+	default:
+		return false, fmt.Errorf("invalid tag match %s, it should be in format tag=value, tag!=value, tag=~regex or tag!=~regex", tagMatch)
+	}
+}
@@ -0,0 +1,91 @@
+package matching
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMatchGlob(t *testing.T) {
+	Convey("Glob matches metric name", t, func() {
+		for glob, name := range map[string]string{
+			"servers.db-*.cpu":        "servers.db-1.cpu",
+			"servers.{db-1,db-2}.cpu": "servers.db-2.cpu",
+			"servers.db-?.cpu":        "servers.db-3.cpu",
+			"servers.db-[12].cpu":     "servers.db-1.cpu",
+		} {
+			matched, err := MatchGlob(glob, name)
+			So(err, ShouldBeNil)
+			So(matched, ShouldBeTrue)
+		}
+	})
+
+	Convey("Glob star does not match dots", t, func() {
+		matched, err := MatchGlob("servers.db-*.cpu", "servers.db-1.cpu.total")
+		So(err, ShouldBeNil)
+		So(matched, ShouldBeFalse)
+	})
+
+	Convey("Invalid glob", t, func() {
+		_, err := MatchGlob("servers.[db", "servers.db")
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestMatchTag(t *testing.T) {
+	tags := map[string]string{"host": "db-1", "dc": "east-1"}
+
+	Convey("Tag matches", t, func() {
+		for tagMatch, expected := range map[string]bool{
+			"name=cpu":     true,
+			"name!=cpu":    false,
+			"host=db-1":    true,
+			"host!=db-1":   false,
+			"dc=~east":     true,
+			"dc=~1":        false,
+			"dc!=~west":    true,
+			"host!=~db-.*": false,
+		} {
+			matched, err := MatchTag(tagMatch, "cpu", tags)
+			So(err, ShouldBeNil)
+			So(matched, ShouldEqual, expected)
+		}
+	})
+
+	Convey("Absent tag has empty value", t, func() {
+		for tagMatch, expected := range map[string]bool{
+			"env=":       true,
+			"env=prod":   false,
+			"env!=prod":  true,
+			"env=~prod":  false,
+			"env!=~prod": true,
+			"env=~.*":    true,
+			"env!=~.*":   false,
+		} {
+			matched, err := MatchTag(tagMatch, "cpu", tags)
+			So(err, ShouldBeNil)
+			So(matched, ShouldEqual, expected)
+		}
+	})
+
+	Convey("Invalid tag matches", t, func() {
+		_, err := MatchTag("host", "cpu", tags)
+		So(err.Error(), ShouldEqual, "invalid tag match host, it should be in format tag=value, tag!=value, tag=~regex or tag!=~regex")
+
+		_, err = MatchTag("host=~db-(", "cpu", tags)
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("Regular expressions are compiled once", t, func() {
+		first, err := compileRegex("^(?:db-.*)")
+		So(err, ShouldBeNil)
+		second, err := compileRegex("^(?:db-.*)")
+		So(err, ShouldBeNil)
+		So(second, ShouldPointTo, first)
+
+		_, err = compileRegex("^(?:db-(")
+		So(err, ShouldNotBeNil)
+		_, cached := regexCache.Get("^(?:db-(")
+		So(cached, ShouldBeFalse)
+	})
+}
@@ -15,8 +15,16 @@ import (
 )
 
 type evalCtx struct {
-	from  int64
-	until int64
+	from     int64
+	until    int64
+	archives *archivesRequest
+}
+
+// archivesRequest asks to fetch metrics from downsampled archives since from,
+// if their storage schemas keep history longer than metrics TTL.
+type archivesRequest struct {
+	from       int64
+	metricsTTL int64
 }
 
 func (ctx *evalCtx) fetchAndEval(database moira.Database, target string, result *FetchResult) error {
@@ -110,6 +118,16 @@ func (ctx *evalCtx) getMetricsData(database moira.Database, parsedExpr parser.Ex
 			return nil, err
 		}
 
+		if ctx.archives != nil {
+			archiveFrom := mr.From + ctx.archives.from
+			if err = fetchData.selectArchive(metricNames, until-archiveFrom, ctx.archives.metricsTTL); err != nil {
+				return nil, err
+			}
+			if metricNames.archive != nil {
+				from = moira.MaxInt64(archiveFrom, until-metricNames.archive.Duration)
+			}
+		}
+
 		timer := NewTimerRoundingTimestamps(from, until, metricNames.retention)
 
 		metricsData, err := fetchData.fetchMetricValues(mr.Metric, metricNames, timer)
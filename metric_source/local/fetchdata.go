@@ -18,6 +18,8 @@ type fetchData struct {
 type metricsWithRetention struct {
 	retention int64
 	metrics   []string
+	// archive is a downsampled archive to fetch values from, raw values are fetched if it is nil
+	archive *moira.Retention
 }
 
 func (fd *fetchData) fetchMetricNames(pattern string) (*metricsWithRetention, error) {
@@ -35,7 +37,35 @@ func (fd *fetchData) fetchMetricNames(pattern string) (*metricsWithRetention, er
 		return nil, err
 	}
 
-	return &metricsWithRetention{retention, metrics}, nil
+	return &metricsWithRetention{retention: retention, metrics: metrics}, nil
+}
+
+// selectArchive picks the most precise downsampled archive, which keeps the whole requested interval,
+// or the longest one if none of them does. Raw values are used if archives keep no more than metrics TTL.
+func (fd *fetchData) selectArchive(metrics *metricsWithRetention, interval, metricsTTL int64) error {
+	if len(metrics.metrics) == 0 {
+		return nil
+	}
+
+	archives, err := fd.database.GetMetricArchives(metrics.metrics[0])
+	if err != nil {
+		return err
+	}
+
+	for i := range archives {
+		archive := &archives[i]
+		if archive.Duration <= metricsTTL {
+			continue
+		}
+		if metrics.archive == nil || metrics.archive.Duration < interval && archive.Duration > metrics.archive.Duration {
+			metrics.archive = archive
+		}
+	}
+
+	if metrics.archive != nil {
+		metrics.retention = metrics.archive.Step
+	}
+	return nil
 }
 
 func (fd *fetchData) fetchMetricValues(pattern string, metrics *metricsWithRetention, timer Timer) ([]*types.MetricData, error) {
@@ -43,7 +73,13 @@ func (fd *fetchData) fetchMetricValues(pattern string, metrics *metricsWithReten
 		return fetchDataNoMetrics(timer, pattern), nil
 	}
 
-	dataList, err := fd.database.GetMetricsValues(metrics.metrics, timer.startTime, timer.stopTime-1)
+	var dataList map[string][]*moira.MetricValue
+	var err error
+	if metrics.archive != nil {
+		dataList, err = fd.database.GetMetricsArchiveValues(metrics.metrics, metrics.archive.Step, timer.startTime, timer.stopTime-1)
+	} else {
+		dataList, err = fd.database.GetMetricsValues(metrics.metrics, timer.startTime, timer.stopTime-1)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -516,3 +516,49 @@ func TestShiftedSeries(t *testing.T) {
 		So(val["metric"], shouldEqualIfNaNsEqual, []float64{200.00, 300.00, math.NaN()})
 	})
 }
+
+func TestSelectArchive(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	fetchedData := fetchData{database: dataBase}
+
+	metric := "super-puper-metric"
+	var metricsTTL int64 = 3600
+	archives := []moira.Retention{
+		{Step: 60, Duration: 3600},
+		{Step: 600, Duration: 86400},
+		{Step: 3600, Duration: 604800},
+	}
+
+	Convey("The most precise archive keeping the whole interval is selected", t, func() {
+		dataBase.EXPECT().GetMetricArchives(metric).Return(archives, nil)
+		metrics := &metricsWithRetention{retention: 10, metrics: []string{metric}}
+
+		err := fetchedData.selectArchive(metrics, 7200, metricsTTL)
+		So(err, ShouldBeNil)
+		So(*metrics.archive, ShouldResemble, archives[1])
+		So(metrics.retention, ShouldEqual, 600)
+	})
+
+	Convey("The longest archive is selected if interval is longer than all of them", t, func() {
+		dataBase.EXPECT().GetMetricArchives(metric).Return(archives, nil)
+		metrics := &metricsWithRetention{retention: 10, metrics: []string{metric}}
+
+		err := fetchedData.selectArchive(metrics, 10*604800, metricsTTL)
+		So(err, ShouldBeNil)
+		So(*metrics.archive, ShouldResemble, archives[2])
+		So(metrics.retention, ShouldEqual, 3600)
+	})
+
+	Convey("Raw values are used if archives are not longer than metrics TTL", t, func() {
+		dataBase.EXPECT().GetMetricArchives(metric).Return(archives[:1], nil)
+		metrics := &metricsWithRetention{retention: 10, metrics: []string{metric}}
+
+		err := fetchedData.selectArchive(metrics, 7200, metricsTTL)
+		So(err, ShouldBeNil)
+		So(metrics.archive, ShouldBeNil)
+		So(metrics.retention, ShouldEqual, 10)
+	})
+}
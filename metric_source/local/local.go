@@ -42,10 +42,14 @@ func (local *Local) IsAvailable() (bool, error) {
 func (local *Local) Fetch(target string, from int64, until int64, allowRealTimeAlerting bool) (metricSource.FetchResult, error) {
 	// Don't fetch intervals larger than metrics TTL to prevent OOM errors
 	// See https://github.com/moira-alert/moira/pull/519
-	from = moira.MaxInt64(from, until-local.database.GetMetricsTTLSeconds())
+	metricsTTL := local.database.GetMetricsTTLSeconds()
+	ctx := evalCtx{from: moira.MaxInt64(from, until-metricsTTL), until: until}
+	if until-from > metricsTTL {
+		// history older than metrics TTL could be kept only in downsampled archives
+		ctx.archives = &archivesRequest{from: from, metricsTTL: metricsTTL}
+	}
 
 	result := CreateEmptyFetchResult()
-	ctx := evalCtx{from, until}
 
 	err := ctx.fetchAndEval(local.database, target, result)
 	if err != nil {
@@ -323,6 +323,7 @@ func TestLocalSourceFetch(t *testing.T) {
 
 		database.EXPECT().GetPatternMetrics(pattern1).Return([]string{metric}, nil)
 		database.EXPECT().GetMetricRetention(metric).Return(retention, nil)
+		database.EXPECT().GetMetricArchives(metric).Return(nil, nil)
 		database.EXPECT().GetMetricsValues([]string{metric}, toFuture-retention, toFuture+retention-1).Return(distantFutureDataList, nil)
 		database.EXPECT().GetMetricsTTLSeconds().Return(ttl)
 
@@ -344,6 +345,39 @@ func TestLocalSourceFetch(t *testing.T) {
 		})
 	})
 
+	Convey("Test fetch interval longer than metrics TTL from downsampled archive", t, func() {
+		archives := []moira.Retention{{Step: 60, Duration: 600}, {Step: 600, Duration: 6000}}
+		archiveDataList := map[string][]*moira.MetricValue{
+			metric: {
+				{RetentionTimestamp: 1200, Timestamp: 1250, Value: 1},
+				{RetentionTimestamp: 1800, Timestamp: 1830, Value: 2},
+			},
+		}
+
+		database.EXPECT().GetPatternMetrics(pattern1).Return([]string{metric}, nil)
+		database.EXPECT().GetMetricRetention(metric).Return(retention, nil)
+		database.EXPECT().GetMetricArchives(metric).Return(archives, nil)
+		database.EXPECT().GetMetricsArchiveValues([]string{metric}, int64(600), int64(1200), int64(2399)).Return(archiveDataList, nil)
+		database.EXPECT().GetMetricsTTLSeconds().Return(metricsTTL / 10)
+
+		result, err := localSource.Fetch("aliasByNode(super.puper.pattern, 2)", 1200, 2399, true)
+
+		So(err, ShouldBeNil)
+		So(result, shouldEqualIfNaNsEqual, &FetchResult{
+			MetricsData: []metricSource.MetricData{
+				{
+					Name:      "metric",
+					StartTime: 1200,
+					StopTime:  2400,
+					StepTime:  600,
+					Values:    []float64{1, 2},
+				},
+			},
+			Metrics:  []string{metric},
+			Patterns: []string{pattern1},
+		})
+	})
+
 	Convey("Test success evaluate pipe target", t, func() {
 		database.EXPECT().GetPatternMetrics(pattern1).Return([]string{metric}, nil)
 		database.EXPECT().GetMetricRetention(metric).Return(retention, nil)
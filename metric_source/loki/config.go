@@ -0,0 +1,22 @@
+package loki
+
+import "time"
+
+// DefaultTenantHeader is a header used by Loki to pass tenant in multi-tenant mode.
+const DefaultTenantHeader = "X-Scope-OrgID"
+
+// Config represents config of remote Loki storage.
+type Config struct {
+	URL           string
+	CheckInterval time.Duration
+	MetricsTTL    time.Duration
+	Timeout       time.Duration
+	// Step is a default step of metric queries.
+	Step     time.Duration
+	User     string
+	Password string
+	// Tenant is passed in DefaultTenantHeader if Loki works in multi-tenant mode.
+	Tenant string
+	// Headers are added to every request to Loki.
+	Headers map[string]string
+}
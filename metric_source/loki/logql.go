@@ -0,0 +1,165 @@
+package loki
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
+)
+
+// ErrNotMetricQuery is returned by ValidateTarget if LogQL query returns log lines instead of metrics.
+var ErrNotMetricQuery = errors.New("LogQL query must be a metric query, e.g. count_over_time or rate of log lines")
+
+// rangeAggregations are LogQL functions, which turn log lines into metrics.
+var rangeAggregations = []string{
+	"count_over_time",
+	"rate",
+	"rate_counter",
+	"bytes_over_time",
+	"bytes_rate",
+	"absent_over_time",
+	"sum_over_time",
+	"avg_over_time",
+	"max_over_time",
+	"min_over_time",
+	"first_over_time",
+	"last_over_time",
+	"stdvar_over_time",
+	"stddev_over_time",
+	"quantile_over_time",
+}
+
+// ErrLogQLSyntax is returned by ValidateTarget if LogQL query is malformed.
+type ErrLogQLSyntax struct {
+	Description string
+}
+
+// Error is a representation of Error interface method.
+func (err ErrLogQLSyntax) Error() string {
+	return "LogQL syntax error: " + err.Description
+}
+
+// ValidateTarget makes lightweight validation of LogQL target: it checks that brackets and quotes are balanced,
+// target has non-empty stream selector and it is a metric query. Ranges of range aggregations are returned
+// to let caller check them against metrics TTL.
+func ValidateTarget(target string) ([]time.Duration, error) {
+	scan, err := scanTarget(target)
+	if err != nil {
+		return nil, err
+	}
+	if !scan.hasSelector {
+		return nil, ErrLogQLSyntax{Description: "stream selector {label=\"value\"} is required"}
+	}
+	if !isMetricQuery(scan.unquoted) || len(scan.ranges) == 0 {
+		return nil, ErrNotMetricQuery
+	}
+	return scan.ranges, nil
+}
+
+type scanResult struct {
+	ranges      []time.Duration
+	hasSelector bool
+	// unquoted is a target without string literals
+	unquoted string
+}
+
+// scanTarget checks that brackets and quotes are balanced and collects ranges of range aggregations.
+func scanTarget(target string) (scanResult, error) {
+	var stack []rune
+	var result scanResult
+	var unquoted strings.Builder
+	rangeStart := -1
+	selectorStart := -1
+
+	pairs := map[rune]rune{')': '(', '}': '{', ']': '['}
+	runes := []rune(target)
+	for i := 0; i < len(runes); i++ {
+		switch char := runes[i]; char {
+		case '"', '`':
+			end := closingQuote(runes, i)
+			if end < 0 {
+				return result, ErrLogQLSyntax{Description: fmt.Sprintf("unclosed quote at position %d", i)}
+			}
+			i = end
+			continue
+		case '(', '{', '[':
+			stack = append(stack, char)
+			if char == '[' {
+				rangeStart = i + 1
+			}
+			if char == '{' {
+				selectorStart = i + 1
+			}
+		case ')', '}', ']':
+			if len(stack) == 0 || stack[len(stack)-1] != pairs[char] {
+				return result, ErrLogQLSyntax{Description: fmt.Sprintf("unexpected %q at position %d", char, i)}
+			}
+			stack = stack[:len(stack)-1]
+			if char == ']' {
+				duration, err := parseRange(string(runes[rangeStart:i]))
+				if err != nil {
+					return result, err
+				}
+				result.ranges = append(result.ranges, duration)
+			}
+			if char == '}' && strings.TrimSpace(string(runes[selectorStart:i])) != "" {
+				result.hasSelector = true
+			}
+		}
+		unquoted.WriteRune(runes[i])
+	}
+	if len(stack) != 0 {
+		return result, ErrLogQLSyntax{Description: fmt.Sprintf("unclosed %q", stack[len(stack)-1])}
+	}
+	result.unquoted = unquoted.String()
+	return result, nil
+}
+
+func closingQuote(runes []rune, start int) int {
+	quote := runes[start]
+	for i := start + 1; i < len(runes); i++ {
+		if quote == '"' && runes[i] == '\\' {
+			i++
+			continue
+		}
+		if runes[i] == quote {
+			return i
+		}
+	}
+	return -1
+}
+
+// parseRange parses range like [5m] or subquery-like [5m:1m], only range itself is returned.
+func parseRange(value string) (time.Duration, error) {
+	value = strings.TrimSpace(strings.SplitN(value, ":", 2)[0])
+	duration, err := model.ParseDuration(value)
+	if err != nil {
+		return 0, ErrLogQLSyntax{Description: fmt.Sprintf("invalid range [%s]", value)}
+	}
+	return time.Duration(duration), nil
+}
+
+func isMetricQuery(target string) bool {
+	for _, function := range rangeAggregations {
+		index := strings.Index(target, function)
+		for index >= 0 {
+			rest := strings.TrimSpace(target[index+len(function):])
+			isWordStart := index == 0 || !isIdentifierChar(target[index-1])
+			if isWordStart && strings.HasPrefix(rest, "(") {
+				return true
+			}
+			next := strings.Index(target[index+len(function):], function)
+			if next < 0 {
+				break
+			}
+			index += len(function) + next
+		}
+	}
+	return false
+}
+
+func isIdentifierChar(char byte) bool {
+	return char == '_' || char >= 'a' && char <= 'z' || char >= 'A' && char <= 'Z' || char >= '0' && char <= '9'
+}
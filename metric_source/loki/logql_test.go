@@ -0,0 +1,49 @@
+package loki
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestValidateTarget(t *testing.T) {
+	Convey("Valid metric queries", t, func() {
+		ranges, err := ValidateTarget(`count_over_time({app="moira"} |= "ERROR" [1m])`)
+		So(err, ShouldBeNil)
+		So(ranges, ShouldResemble, []time.Duration{time.Minute})
+
+		ranges, err = ValidateTarget(`sum by (host) (rate({app="moira", env=~"prod|stage"} | json | level="error" [5m]))`)
+		So(err, ShouldBeNil)
+		So(ranges, ShouldResemble, []time.Duration{5 * time.Minute})
+
+		ranges, err = ValidateTarget("quantile_over_time(0.99, {app=\"moira\"} | logfmt | unwrap duration [10m]) by (host)")
+		So(err, ShouldBeNil)
+		So(ranges, ShouldResemble, []time.Duration{10 * time.Minute})
+	})
+
+	Convey("Log queries are not metric queries", t, func() {
+		_, err := ValidateTarget(`{app="moira"} |= "ERROR"`)
+		So(err, ShouldEqual, ErrNotMetricQuery)
+
+		_, err = ValidateTarget(`{app="moira"} |= "count_over_time([1m])"`)
+		So(err, ShouldEqual, ErrNotMetricQuery)
+	})
+
+	Convey("Malformed queries", t, func() {
+		_, err := ValidateTarget(`count_over_time({app="moira"}[1m]`)
+		So(err, ShouldResemble, ErrLogQLSyntax{Description: `unclosed '('`})
+
+		_, err = ValidateTarget(`count_over_time({app="moira}[1m])`)
+		So(err, ShouldResemble, ErrLogQLSyntax{Description: "unclosed quote at position 21"})
+
+		_, err = ValidateTarget(`count_over_time({app="moira"}[1x])`)
+		So(err, ShouldResemble, ErrLogQLSyntax{Description: "invalid range [1x]"})
+
+		_, err = ValidateTarget(`count_over_time({}[1m])`)
+		So(err, ShouldResemble, ErrLogQLSyntax{Description: `stream selector {label="value"} is required`})
+
+		_, err = ValidateTarget(`count_over_time({app="moira"}[1m]))`)
+		So(err, ShouldResemble, ErrLogQLSyntax{Description: "unexpected ')' at position 34"})
+	})
+}
@@ -0,0 +1,108 @@
+package loki
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/moira-alert/moira"
+	metricSource "github.com/moira-alert/moira/metric_source"
+)
+
+// StepTimeSeconds is a default step of metric queries, used if step is set neither in config nor in trigger.
+const StepTimeSeconds int64 = 60
+
+// ErrLokiTriggerResponse is a custom error when Loki trigger check fails.
+type ErrLokiTriggerResponse struct {
+	InternalError error
+	Target        string
+}
+
+// Error is a representation of Error interface method.
+func (err ErrLokiTriggerResponse) Error() string {
+	return err.InternalError.Error()
+}
+
+// Loki is implementation of MetricSource interface, which fetches results of LogQL metric queries from Loki.
+type Loki struct {
+	config *Config
+	client *http.Client
+}
+
+// Create configures Loki metric source.
+func Create(config *Config) (metricSource.MetricSource, error) {
+	if config.URL == "" {
+		return nil, fmt.Errorf("loki URL should not be empty")
+	}
+	return &Loki{
+		config: config,
+		client: &http.Client{Timeout: config.Timeout},
+	}, nil
+}
+
+// Fetch fetches results of LogQL metric query and converts them to expected format.
+func (loki *Loki) Fetch(target string, from, until int64, allowRealTimeAlerting bool) (metricSource.FetchResult, error) {
+	return loki.FetchWithOptions(target, from, until, allowRealTimeAlerting, moira.FetchOptions{})
+}
+
+// FetchWithOptions fetches results of LogQL metric query using range query with step from options
+// or instant query if options require it.
+func (loki *Loki) FetchWithOptions(target string, from, until int64, allowRealTimeAlerting bool, options moira.FetchOptions) (metricSource.FetchResult, error) {
+	from = moira.MaxInt64(from, until-int64(loki.config.MetricsTTL.Seconds()))
+	step := loki.stepTime(options)
+
+	var req *http.Request
+	var err error
+	if options.Instant {
+		if !allowRealTimeAlerting {
+			until -= step
+		}
+		req, err = loki.prepareInstantRequest(target, until)
+	} else {
+		req, err = loki.prepareRangeRequest(target, from, until, step)
+	}
+	if err != nil {
+		return nil, ErrLokiTriggerResponse{InternalError: err, Target: target}
+	}
+
+	body, err := loki.makeRequest(req)
+	if err != nil {
+		return nil, ErrLokiTriggerResponse{InternalError: err, Target: target}
+	}
+
+	metricsData, err := decodeBody(body, target, step)
+	if err != nil {
+		return nil, ErrLokiTriggerResponse{InternalError: err, Target: target}
+	}
+
+	if !options.Instant && !allowRealTimeAlerting {
+		metricsData = metricSource.TrimIncompleteStep(metricsData, until)
+	}
+	return &FetchResult{MetricsData: metricsData}, nil
+}
+
+// GetMetricsTTLSeconds returns maximum time interval that we are allowed to fetch from Loki.
+func (loki *Loki) GetMetricsTTLSeconds() int64 {
+	return int64(loki.config.MetricsTTL.Seconds())
+}
+
+// IsAvailable checks if Loki API is available and returns 200 response.
+func (loki *Loki) IsAvailable() (bool, error) {
+	req, err := loki.prepareLabelsRequest(time.Now().Unix())
+	if err != nil {
+		return false, err
+	}
+	_, err = loki.makeRequest(req)
+	return err == nil, err
+}
+
+// stepTime returns step of metric queries in seconds, trigger step has priority over cluster one.
+func (loki *Loki) stepTime(options moira.FetchOptions) int64 {
+	if options.Step > 0 {
+		return options.Step
+	}
+	if step := int64(loki.config.Step.Seconds()); step > 0 {
+		return step
+	}
+	return StepTimeSeconds
+}
@@ -0,0 +1,169 @@
+package loki
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/moira-alert/moira"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const (
+	matrixResponse = `{"status":"success","data":{"resultType":"matrix","result":[` +
+		`{"metric":{"app":"moira","level":"error"},"values":[[1000,"3"],[1060,"5"],[1180,"1"]]}]}}`
+	vectorResponse = `{"status":"success","data":{"resultType":"vector","result":[` +
+		`{"metric":{},"value":[1180,"7"]}]}}`
+	streamsResponse = `{"status":"success","data":{"resultType":"streams","result":[]}}`
+)
+
+type capturedRequest struct {
+	path    string
+	params  url.Values
+	headers http.Header
+}
+
+func createServer(body string, statusCode int, captured *capturedRequest) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		captured.path = req.URL.Path
+		captured.params = req.URL.Query()
+		captured.headers = req.Header.Clone()
+		rw.WriteHeader(statusCode)
+		rw.Write([]byte(body)) //nolint
+	}))
+}
+
+func TestFetch(t *testing.T) {
+	var from int64 = 1000
+	var until int64 = 1180
+	target := `count_over_time({app="moira"} |= "ERROR" [1m])`
+
+	Convey("Range query", t, func() {
+		captured := &capturedRequest{}
+		server := createServer(matrixResponse, http.StatusOK, captured)
+		defer server.Close()
+
+		source, err := Create(&Config{URL: server.URL, MetricsTTL: time.Hour, Tenant: "team-a", Headers: map[string]string{"X-Custom": "value"}})
+		So(err, ShouldBeNil)
+
+		result, err := source.Fetch(target, from, until, true)
+		So(err, ShouldBeNil)
+
+		So(captured.path, ShouldEqual, queryRangePath)
+		So(captured.params.Get("query"), ShouldEqual, target)
+		So(captured.params.Get("start"), ShouldEqual, "1000000000000")
+		So(captured.params.Get("end"), ShouldEqual, "1180000000000")
+		So(captured.params.Get("step"), ShouldEqual, "60")
+		So(captured.headers.Get(DefaultTenantHeader), ShouldEqual, "team-a")
+		So(captured.headers.Get("X-Custom"), ShouldEqual, "value")
+
+		metricsData := result.GetMetricsData()
+		So(metricsData, ShouldHaveLength, 1)
+		So(metricsData[0].Name, ShouldEqual, "app=moira;level=error")
+		So(metricsData[0].StartTime, ShouldEqual, 1000)
+		So(metricsData[0].StopTime, ShouldEqual, 1180)
+		So(metricsData[0].StepTime, ShouldEqual, 60)
+		So(metricsData[0].Values, ShouldHaveLength, 4)
+		So(metricsData[0].Values[0], ShouldEqual, 3)
+		So(metricsData[0].Values[1], ShouldEqual, 5)
+		So(math.IsNaN(metricsData[0].Values[2]), ShouldBeTrue)
+		So(metricsData[0].Values[3], ShouldEqual, 1)
+
+		_, err = result.GetPatterns()
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("Range query without real time alerting", t, func() {
+		captured := &capturedRequest{}
+		server := createServer(matrixResponse, http.StatusOK, captured)
+		defer server.Close()
+
+		source, _ := Create(&Config{URL: server.URL, MetricsTTL: time.Hour})
+		result, err := source.Fetch(target, from, until, false)
+		So(err, ShouldBeNil)
+		So(result.GetMetricsData()[0].Values, ShouldHaveLength, 3)
+		So(result.GetMetricsData()[0].StopTime, ShouldEqual, 1120)
+	})
+
+	Convey("Range query without real time alerting and without points in the last step", t, func() {
+		captured := &capturedRequest{}
+		server := createServer(matrixResponse, http.StatusOK, captured)
+		defer server.Close()
+
+		source, _ := Create(&Config{URL: server.URL, MetricsTTL: time.Hour})
+		result, err := source.Fetch(target, from, until+120, false)
+		So(err, ShouldBeNil)
+		So(result.GetMetricsData()[0].Values, ShouldHaveLength, 4)
+		So(result.GetMetricsData()[0].StopTime, ShouldEqual, 1180)
+	})
+
+	Convey("Instant query with trigger step", t, func() {
+		captured := &capturedRequest{}
+		server := createServer(vectorResponse, http.StatusOK, captured)
+		defer server.Close()
+
+		source, _ := Create(&Config{URL: server.URL, MetricsTTL: time.Hour})
+		result, err := source.(*Loki).FetchWithOptions(target, from, until, false, moira.FetchOptions{Step: 30, Instant: true})
+		So(err, ShouldBeNil)
+
+		So(captured.path, ShouldEqual, queryPath)
+		So(captured.params.Get("time"), ShouldEqual, "1150000000000")
+		So(result.GetMetricsData()[0].Name, ShouldEqual, target)
+		So(result.GetMetricsData()[0].Values, ShouldResemble, []float64{7})
+		So(result.GetMetricsData()[0].StepTime, ShouldEqual, 30)
+	})
+
+	Convey("Log query result", t, func() {
+		captured := &capturedRequest{}
+		server := createServer(streamsResponse, http.StatusOK, captured)
+		defer server.Close()
+
+		source, _ := Create(&Config{URL: server.URL, MetricsTTL: time.Hour})
+		result, err := source.Fetch(`{app="moira"}`, from, until, true)
+		So(result, ShouldBeNil)
+		So(err.Error(), ShouldEqual, "loki returned log lines, target must be a LogQL metric query")
+	})
+
+	Convey("Bad response status", t, func() {
+		captured := &capturedRequest{}
+		server := createServer("parse error", http.StatusBadRequest, captured)
+		defer server.Close()
+
+		source, _ := Create(&Config{URL: server.URL, MetricsTTL: time.Hour})
+		result, err := source.Fetch(target, from, until, true)
+		So(result, ShouldBeNil)
+		So(err, ShouldResemble, ErrLokiTriggerResponse{
+			InternalError: fmt.Errorf("bad response status %d: %s", http.StatusBadRequest, "parse error"),
+			Target:        target,
+		})
+	})
+}
+
+func TestIsAvailable(t *testing.T) {
+	Convey("Is available", t, func() {
+		captured := &capturedRequest{}
+		server := createServer(`{"status":"success","data":[]}`, http.StatusOK, captured)
+		defer server.Close()
+
+		source, _ := Create(&Config{URL: server.URL + "/"})
+		isAvailable, err := source.IsAvailable()
+		So(isAvailable, ShouldBeTrue)
+		So(err, ShouldBeNil)
+		So(captured.path, ShouldEqual, labelsPath)
+	})
+
+	Convey("Not available", t, func() {
+		captured := &capturedRequest{}
+		server := createServer("unavailable", http.StatusServiceUnavailable, captured)
+		defer server.Close()
+
+		source, _ := Create(&Config{URL: server.URL})
+		isAvailable, err := source.IsAvailable()
+		So(isAvailable, ShouldBeFalse)
+		So(err, ShouldNotBeNil)
+	})
+}
@@ -0,0 +1,83 @@
+package loki
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	queryRangePath = "/loki/api/v1/query_range"
+	queryPath      = "/loki/api/v1/query"
+	labelsPath     = "/loki/api/v1/labels"
+)
+
+func (loki *Loki) prepareRangeRequest(target string, from, until, step int64) (*http.Request, error) {
+	params := url.Values{}
+	params.Add("query", target)
+	params.Add("start", unixNanoString(from))
+	params.Add("end", unixNanoString(until))
+	params.Add("step", strconv.FormatInt(step, 10))
+	return loki.prepareRequest(queryRangePath, params)
+}
+
+func (loki *Loki) prepareInstantRequest(target string, until int64) (*http.Request, error) {
+	params := url.Values{}
+	params.Add("query", target)
+	params.Add("time", unixNanoString(until))
+	return loki.prepareRequest(queryPath, params)
+}
+
+func (loki *Loki) prepareLabelsRequest(until int64) (*http.Request, error) {
+	params := url.Values{}
+	params.Add("start", unixNanoString(until-600)) //nolint
+	params.Add("end", unixNanoString(until))
+	return loki.prepareRequest(labelsPath, params)
+}
+
+func (loki *Loki) prepareRequest(path string, params url.Values) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, strings.TrimRight(loki.config.URL, "/")+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.URL.RawQuery = params.Encode()
+	if loki.config.User != "" && loki.config.Password != "" {
+		req.SetBasicAuth(loki.config.User, loki.config.Password)
+	}
+	if loki.config.Tenant != "" {
+		req.Header.Set(DefaultTenantHeader, loki.config.Tenant)
+	}
+	for name, value := range loki.config.Headers {
+		req.Header.Set(name, value)
+	}
+	return req, nil
+}
+
+func (loki *Loki) makeRequest(req *http.Request) ([]byte, error) {
+	resp, err := loki.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("loki is not available or the response was reset by timeout. "+
+			"TTL: %s, PATH: %s, ERROR: %w", loki.client.Timeout.String(), req.URL.Path, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return body, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return body, fmt.Errorf("bad response status %d: %s", resp.StatusCode, string(body))
+	}
+
+	return body, nil
+}
+
+func unixNanoString(timestamp int64) string {
+	return strconv.FormatInt(time.Unix(timestamp, 0).UnixNano(), 10)
+}
@@ -0,0 +1,137 @@
+package loki
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	metricSource "github.com/moira-alert/moira/metric_source"
+	"github.com/prometheus/common/model"
+)
+
+type lokiResponse struct {
+	Status string `json:"status"`
+	Data   struct {
+		ResultType string          `json:"resultType"`
+		Result     json.RawMessage `json:"result"`
+	} `json:"data"`
+}
+
+// FetchResult is implementation of metric_source.FetchResult interface,
+// which represent fetching result from Loki in moira format.
+type FetchResult struct {
+	MetricsData []metricSource.MetricData
+}
+
+// GetMetricsData return all metrics data from fetch result.
+func (fetchResult *FetchResult) GetMetricsData() []metricSource.MetricData {
+	return fetchResult.MetricsData
+}
+
+// GetPatterns always returns error, because we can't fetch target patterns from Loki.
+func (*FetchResult) GetPatterns() ([]string, error) {
+	return make([]string, 0), fmt.Errorf("loki fetch result never returns patterns")
+}
+
+// GetPatternMetrics always returns error, because Loki fetch doesn't return base pattern metrics.
+func (*FetchResult) GetPatternMetrics() ([]string, error) {
+	return make([]string, 0), fmt.Errorf("loki fetch result never returns pattern metrics")
+}
+
+func decodeBody(body []byte, target string, step int64) ([]metricSource.MetricData, error) {
+	var resp lokiResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Status != "success" {
+		return nil, fmt.Errorf("loki response status is %s", resp.Status)
+	}
+
+	switch resp.Data.ResultType {
+	case model.ValMatrix.String():
+		var matrix model.Matrix
+		if err := json.Unmarshal(resp.Data.Result, &matrix); err != nil {
+			return nil, err
+		}
+		return convertMatrix(matrix, target, step), nil
+
+	case model.ValVector.String():
+		var vector model.Vector
+		if err := json.Unmarshal(resp.Data.Result, &vector); err != nil {
+			return nil, err
+		}
+		return convertVector(vector, target, step), nil
+
+	case "streams":
+		return nil, fmt.Errorf("loki returned log lines, target must be a LogQL metric query")
+
+	default:
+		return nil, fmt.Errorf("unexpected loki result type %s", resp.Data.ResultType)
+	}
+}
+
+// convertMatrix converts Loki range query result to metrics data.
+// Loki omits steps without log lines, so the missing values are filled with NaN.
+func convertMatrix(matrix model.Matrix, target string, step int64) []metricSource.MetricData {
+	result := make([]metricSource.MetricData, 0, len(matrix))
+	for _, stream := range matrix {
+		if len(stream.Values) == 0 {
+			continue
+		}
+		start := stream.Values[0].Timestamp.Unix()
+		stop := stream.Values[len(stream.Values)-1].Timestamp.Unix()
+		values := make([]float64, (stop-start)/step+1)
+		for i := range values {
+			values[i] = math.NaN()
+		}
+		for _, pair := range stream.Values {
+			values[(pair.Timestamp.Unix()-start)/step] = float64(pair.Value)
+		}
+		result = append(result, metricSource.MetricData{
+			Name:      targetFromLabels(stream.Metric, target),
+			StartTime: start,
+			StopTime:  stop,
+			StepTime:  step,
+			Values:    values,
+		})
+	}
+	return result
+}
+
+// convertVector converts Loki instant query result to metrics data.
+func convertVector(vector model.Vector, target string, step int64) []metricSource.MetricData {
+	result := make([]metricSource.MetricData, 0, len(vector))
+	for _, sample := range vector {
+		timestamp := sample.Timestamp.Unix()
+		result = append(result, metricSource.MetricData{
+			Name:      targetFromLabels(sample.Metric, target),
+			StartTime: timestamp,
+			StopTime:  timestamp,
+			StepTime:  step,
+			Values:    []float64{float64(sample.Value)},
+		})
+	}
+	return result
+}
+
+// targetFromLabels builds metric name from sorted labels in seriesByTag format: label1=value1;label2=value2.
+// Query itself is used as a name of metrics without labels, e.g. results of aggregation without grouping.
+func targetFromLabels(labels model.Metric, target string) string {
+	if len(labels) == 0 {
+		return target
+	}
+
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, name+"="+string(labels[model.LabelName(name)]))
+	}
+	return strings.Join(parts, ";")
+}
@@ -42,6 +42,21 @@ func MakeEmptyMetricData(name string, step, start, stop int64) *MetricData {
 	}
 }
 
+// TrimIncompleteStep removes the last value of metrics data if it falls into the last step before until,
+// which could still get new points, and moves StopTime back. StopTime is a timestamp of the last value.
+// Metrics data, which ends before that step, e.g. because source omits empty steps, is kept untouched.
+func TrimIncompleteStep(metricsData []MetricData, until int64) []MetricData {
+	result := make([]MetricData, 0, len(metricsData))
+	for _, metricData := range metricsData {
+		if len(metricData.Values) > 0 && metricData.StopTime > until-metricData.StepTime {
+			metricData.Values = metricData.Values[:len(metricData.Values)-1]
+			metricData.StopTime -= metricData.StepTime
+		}
+		result = append(result, metricData)
+	}
+	return result
+}
+
 // GetTimestampValue gets value of given timestamp index, if value is Nil, then return NaN.
 func (metricData *MetricData) GetTimestampValue(valueTimestamp int64) float64 {
 	if valueTimestamp < metricData.StartTime {
@@ -56,6 +56,27 @@ func TestMakeMetricData(t *testing.T) {
 	})
 }
 
+func TestTrimIncompleteStep(t *testing.T) {
+	Convey("Trim incomplete step", t, func() {
+		Convey("Last value in the last step before until is removed", func() {
+			metricsData := []MetricData{{Name: "metric", StartTime: 0, StopTime: 120, StepTime: 60, Values: []float64{1, 2, 3}}}
+			So(TrimIncompleteStep(metricsData, 150), ShouldResemble, []MetricData{
+				{Name: "metric", StartTime: 0, StopTime: 60, StepTime: 60, Values: []float64{1, 2}},
+			})
+		})
+
+		Convey("Last value before the last step is kept", func() {
+			metricsData := []MetricData{{Name: "metric", StartTime: 0, StopTime: 120, StepTime: 60, Values: []float64{1, 2, 3}}}
+			So(TrimIncompleteStep(metricsData, 300), ShouldResemble, metricsData)
+		})
+
+		Convey("Empty values are kept", func() {
+			metricsData := []MetricData{{Name: "metric", StartTime: 0, StopTime: 0, StepTime: 60, Values: []float64{}}}
+			So(TrimIncompleteStep(metricsData, 30), ShouldResemble, metricsData)
+		})
+	})
+}
+
 func TestGetTimestampValue(t *testing.T) {
 	Convey("IsAbsent only false", t, func() {
 		metricData := MetricData{
@@ -8,7 +8,7 @@ import (
 	"github.com/prometheus/common/model"
 )
 
-func convertToFetchResult(mat model.Matrix, from, until int64, allowRealTimeAlerting bool) *FetchResult {
+func convertToFetchResult(mat model.Matrix, from, until, step int64, allowRealTimeAlerting bool) *FetchResult {
 	result := FetchResult{
 		MetricsData: make([]metricSource.MetricData, 0, len(mat)),
 	}
@@ -26,7 +26,7 @@ func convertToFetchResult(mat model.Matrix, from, until int64, allowRealTimeAler
 			Name:      targetFromTags(res.Metric),
 			StartTime: start,
 			StopTime:  stop,
-			StepTime:  StepTimeSeconds,
+			StepTime:  step,
 			Values:    values,
 			Wildcard:  false,
 		}
@@ -36,6 +36,27 @@ func convertToFetchResult(mat model.Matrix, from, until int64, allowRealTimeAler
 	return &result
 }
 
+func convertVectorToFetchResult(vec model.Vector, step int64) *FetchResult {
+	result := FetchResult{
+		MetricsData: make([]metricSource.MetricData, 0, len(vec)),
+	}
+
+	for _, sample := range vec {
+		timestamp := sample.Timestamp.Unix()
+		data := metricSource.MetricData{
+			Name:      targetFromTags(sample.Metric),
+			StartTime: timestamp,
+			StopTime:  timestamp,
+			StepTime:  step,
+			Values:    []float64{float64(sample.Value)},
+			Wildcard:  false,
+		}
+		result.MetricsData = append(result.MetricsData, data)
+	}
+
+	return &result
+}
+
 func startStopFromValues(values []model.SamplePair, from, until int64) (int64, int64) {
 	start, stop := from, until
 	if len(values) != 0 {
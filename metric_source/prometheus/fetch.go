@@ -13,12 +13,17 @@ import (
 )
 
 func (prometheus *Prometheus) Fetch(target string, from, until int64, allowRealTimeAlerting bool) (metricSource.FetchResult, error) {
+	return prometheus.FetchWithOptions(target, from, until, allowRealTimeAlerting, moira.FetchOptions{})
+}
+
+// FetchWithOptions fetches metrics using range query with step from options or instant query if options require it.
+func (prometheus *Prometheus) FetchWithOptions(target string, from, until int64, allowRealTimeAlerting bool, options moira.FetchOptions) (metricSource.FetchResult, error) {
 	from = moira.MaxInt64(from, until-int64(prometheus.config.MetricsTTL.Seconds()))
 
 	var err error
 	for i := 1; ; i++ {
 		var res metricSource.FetchResult
-		res, err = prometheus.fetch(target, from, until, allowRealTimeAlerting)
+		res, err = prometheus.fetch(target, from, until, allowRealTimeAlerting, options)
 
 		if err == nil {
 			return res, nil
@@ -40,30 +45,68 @@ func (prometheus *Prometheus) Fetch(target string, from, until int64, allowRealT
 	return nil, err
 }
 
-func (prometheus *Prometheus) fetch(target string, from, until int64, allowRealTimeAlerting bool) (metricSource.FetchResult, error) {
+func (prometheus *Prometheus) fetch(target string, from, until int64, allowRealTimeAlerting bool, options moira.FetchOptions) (metricSource.FetchResult, error) {
+	step := prometheus.stepTime(options)
+	if options.Instant {
+		return prometheus.fetchInstant(target, until, allowRealTimeAlerting, step)
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), prometheus.config.RequestTimeout)
 	defer cancel()
 
 	val, warns, err := prometheus.api.QueryRange(ctx, target, promApi.Range{
 		Start: time.Unix(from, 0),
 		End:   time.Unix(until, 0),
-		Step:  time.Second * time.Duration(StepTimeSeconds),
+		Step:  time.Second * time.Duration(step),
 	})
 
-	if len(warns) != 0 {
-		prometheus.logger.
-			Warning().
-			Interface("warns", warns).
-			Msg("Warnings when fetching metrics from remote prometheus")
+	prometheus.logWarnings(warns)
+
+	if err != nil {
+		return nil, err
+	}
+
+	mat, ok := val.(model.Matrix)
+	if !ok {
+		return nil, fmt.Errorf("unexpected prometheus range query result type %s", val.Type())
+	}
+
+	return convertToFetchResult(mat, from, until, step, allowRealTimeAlerting), nil
+}
+
+// fetchInstant fetches only the last values of metrics using instant query.
+// If real time alerting is not allowed, values are evaluated one step before until.
+func (prometheus *Prometheus) fetchInstant(target string, until int64, allowRealTimeAlerting bool, step int64) (metricSource.FetchResult, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), prometheus.config.RequestTimeout)
+	defer cancel()
+
+	if !allowRealTimeAlerting {
+		until -= step
 	}
 
+	val, warns, err := prometheus.api.Query(ctx, target, time.Unix(until, 0))
+
+	prometheus.logWarnings(warns)
+
 	if err != nil {
 		return nil, err
 	}
 
-	mat := val.(model.Matrix)
+	vec, ok := val.(model.Vector)
+	if !ok {
+		return nil, fmt.Errorf("unexpected prometheus instant query result type %s", val.Type())
+	}
+
+	return convertVectorToFetchResult(vec, step), nil
+}
 
-	return convertToFetchResult(mat, from, until, allowRealTimeAlerting), nil
+func (prometheus *Prometheus) logWarnings(warns promApi.Warnings) {
+	if len(warns) != 0 {
+		prometheus.logger.
+			Warning().
+			Interface("warns", warns).
+			Msg("Warnings when fetching metrics from remote prometheus")
+	}
 }
 
 type FetchResult struct {
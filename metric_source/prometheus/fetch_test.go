@@ -6,10 +6,12 @@ import (
 	"time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/moira-alert/moira"
 	"github.com/moira-alert/moira/logging/zerolog_adapter"
 	metricsource "github.com/moira-alert/moira/metric_source"
 	mock_moira_alert "github.com/moira-alert/moira/mock/moira-alert"
 
+	promApi "github.com/prometheus/client_golang/api/prometheus/v1"
 	"github.com/prometheus/common/model"
 	. "github.com/smartystreets/goconvey/convey"
 )
@@ -52,7 +54,7 @@ func TestPrometheusFetch(t *testing.T) {
 				nil,
 			)
 
-		res, err := prometheus.fetch("target", fromMilli, untilMilli, true)
+		res, err := prometheus.fetch("target", fromMilli, untilMilli, true, moira.FetchOptions{})
 
 		So(err, ShouldBeNil)
 		So(res, ShouldResemble, &FetchResult{
@@ -142,3 +144,107 @@ func TestPrometheusFetchRetries(t *testing.T) {
 		So(err, ShouldEqual, expectedErr)
 	})
 }
+
+func TestPrometheusFetchWithOptions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	api := mock_moira_alert.NewMockPrometheusApi(ctrl)
+
+	logger, _ := zerolog_adapter.GetLogger("Test")
+
+	var from int64 = 1000
+	var until int64 = 1060
+
+	prometheus := Prometheus{
+		config: &Config{Retries: 1, Step: time.Second * 30, MetricsTTL: time.Hour},
+		api:    api,
+		logger: logger,
+	}
+
+	Convey("Given step from cluster config", t, func() {
+		api.EXPECT().QueryRange(gomock.Any(), "target", promApi.Range{
+			Start: time.Unix(from, 0),
+			End:   time.Unix(until, 0),
+			Step:  time.Second * 30,
+		}).Return(model.Matrix{}, nil, nil)
+
+		res, err := prometheus.FetchWithOptions("target", from, until, true, moira.FetchOptions{})
+
+		So(err, ShouldBeNil)
+		So(res, ShouldResemble, &FetchResult{MetricsData: []metricsource.MetricData{}})
+	})
+
+	Convey("Given step from trigger options", t, func() {
+		api.EXPECT().QueryRange(gomock.Any(), "target", promApi.Range{
+			Start: time.Unix(from, 0),
+			End:   time.Unix(until, 0),
+			Step:  time.Second * 15,
+		}).Return(
+			model.Matrix{
+				&model.SampleStream{
+					Metric: model.Metric{"__name__": "name1"},
+					Values: []model.SamplePair{
+						{Timestamp: model.TimeFromUnix(from), Value: 1},
+						{Timestamp: model.TimeFromUnix(from + 15), Value: 2},
+					},
+				},
+			},
+			nil,
+			nil,
+		)
+
+		res, err := prometheus.FetchWithOptions("target", from, until, true, moira.FetchOptions{Step: 15})
+
+		So(err, ShouldBeNil)
+		So(res.GetMetricsData()[0].StepTime, ShouldEqual, 15)
+	})
+
+	Convey("Given instant query with real time alerting", t, func() {
+		api.EXPECT().Query(gomock.Any(), "target", time.Unix(until, 0)).Return(
+			model.Vector{
+				&model.Sample{
+					Metric:    model.Metric{"__name__": "name1", "label": "value"},
+					Timestamp: model.TimeFromUnix(until),
+					Value:     42,
+				},
+			},
+			nil,
+			nil,
+		)
+
+		res, err := prometheus.FetchWithOptions("target", from, until, true, moira.FetchOptions{Instant: true})
+
+		So(err, ShouldBeNil)
+		So(res, ShouldResemble, &FetchResult{
+			MetricsData: []metricsource.MetricData{
+				{
+					Name:      "name1;label=value",
+					StartTime: until,
+					StopTime:  until,
+					StepTime:  30,
+					Values:    []float64{42},
+					Wildcard:  false,
+				},
+			},
+		})
+	})
+
+	Convey("Given instant query without real time alerting", t, func() {
+		api.EXPECT().Query(gomock.Any(), "target", time.Unix(until-30, 0)).Return(model.Vector{}, nil, nil)
+
+		res, err := prometheus.FetchWithOptions("target", from, until, false, moira.FetchOptions{Instant: true})
+
+		So(err, ShouldBeNil)
+		So(res, ShouldResemble, &FetchResult{MetricsData: []metricsource.MetricData{}})
+	})
+
+	Convey("Given unexpected instant query result type", t, func() {
+		api.EXPECT().Query(gomock.Any(), "target", gomock.Any()).Return(model.Matrix{}, nil, nil)
+
+		res, err := prometheus.FetchWithOptions("target", from, until, true, moira.FetchOptions{Instant: true})
+
+		So(res, ShouldBeNil)
+		So(err, ShouldNotBeNil)
+	})
+}
@@ -8,6 +8,7 @@ import (
 	metricSource "github.com/moira-alert/moira/metric_source"
 )
 
+// StepTimeSeconds is a default step of range queries, used if step is set neither in config nor in trigger.
 const StepTimeSeconds int64 = 60
 
 var ErrPrometheusStorageDisabled = fmt.Errorf("remote prometheus storage is not enabled")
@@ -21,6 +22,10 @@ type Config struct {
 	URL            string
 	User           string
 	Password       string
+	// Step is a default step of range queries for the cluster.
+	Step time.Duration
+	// Headers are added to every request, e.g. tenant header of multi-tenant storages.
+	Headers map[string]string
 }
 
 func Create(config *Config, logger moira.Logger) (metricSource.MetricSource, error) {
@@ -46,6 +51,17 @@ func (prometheus *Prometheus) IsConfigured() (bool, error) {
 	return true, nil
 }
 
+// stepTime returns step of range queries in seconds, trigger step has priority over cluster one.
+func (prometheus *Prometheus) stepTime(options moira.FetchOptions) int64 {
+	if options.Step > 0 {
+		return options.Step
+	}
+	if step := int64(prometheus.config.Step.Seconds()); step > 0 {
+		return step
+	}
+	return StepTimeSeconds
+}
+
 func (prometheus *Prometheus) IsAvailable() (bool, error) {
 	now := time.Now().Unix()
 	_, err := prometheus.Fetch("1", now, now, true)
@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/base64"
 	"fmt"
+	"net/http"
+	"time"
 
 	"github.com/prometheus/client_golang/api"
 	promApi "github.com/prometheus/client_golang/api/prometheus/v1"
@@ -12,6 +14,7 @@ import (
 )
 
 type PrometheusApi interface {
+	Query(ctx context.Context, query string, ts time.Time, opts ...promApi.Option) (model.Value, promApi.Warnings, error)
 	QueryRange(ctx context.Context, query string, r promApi.Range, opts ...promApi.Option) (model.Value, promApi.Warnings, error)
 }
 
@@ -29,6 +32,13 @@ func createPrometheusApi(config *Config) (promApi.API, error) {
 		)
 	}
 
+	if len(config.Headers) != 0 {
+		roundTripper = &headersRoundTripper{
+			headers: config.Headers,
+			next:    roundTripper,
+		}
+	}
+
 	promClientConfig := api.Config{
 		Address:      config.URL,
 		RoundTripper: roundTripper,
@@ -41,3 +51,17 @@ func createPrometheusApi(config *Config) (promApi.API, error) {
 
 	return promApi.NewAPI(promCl), nil
 }
+
+// headersRoundTripper adds configured headers to every request.
+type headersRoundTripper struct {
+	headers map[string]string
+	next    http.RoundTripper
+}
+
+func (rt *headersRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	for name, value := range rt.headers {
+		req.Header.Set(name, value)
+	}
+	return rt.next.RoundTrip(req)
+}
@@ -10,4 +10,27 @@ type Config struct {
 	Timeout       time.Duration
 	User          string
 	Password      string
+	// Headers are added to every request to remote storage, e.g. Authorization: Bearer <token>.
+	Headers map[string]string
+	// Format is a render response format, requested from remote storage. Default is json.
+	Format string
+	TLS    TLSConfig
+}
+
+// TLSConfig represents TLS settings for connection to remote storage.
+type TLSConfig struct {
+	// CAFile is a path to PEM-encoded CA bundle, used to verify remote storage certificate.
+	CAFile string
+	// CertFile and KeyFile are paths to PEM-encoded client certificate and its key.
+	CertFile string
+	KeyFile  string
+	// ServerName overrides server name used to verify remote storage certificate.
+	ServerName         string
+	InsecureSkipVerify bool
+}
+
+// isEnabled returns true if any of TLS settings differs from the default ones.
+func (config *TLSConfig) isEnabled() bool {
+	return config.CAFile != "" || config.CertFile != "" || config.KeyFile != "" ||
+		config.ServerName != "" || config.InsecureSkipVerify
 }
@@ -34,9 +34,16 @@ func Create(config *Config) (metricSource.MetricSource, error) {
 	if config.URL == "" {
 		return nil, fmt.Errorf("remote graphite URL should not be empty")
 	}
+	if config.Format != "" && !isKnownFormat(config.Format) {
+		return nil, fmt.Errorf("unknown remote graphite response format `%s`", config.Format)
+	}
+	client, err := makeClient(config)
+	if err != nil {
+		return nil, err
+	}
 	return &Remote{
 		config: config,
-		client: &http.Client{Timeout: config.Timeout},
+		client: client,
 	}, nil
 }
 
@@ -60,7 +67,7 @@ func (remote *Remote) Fetch(target string, from, until int64, allowRealTimeAlert
 			Target:        target,
 		}
 	}
-	resp, err := decodeBody(body)
+	resp, err := decodeBody(body, remote.config.Format)
 	if err != nil {
 		return nil, ErrRemoteTriggerResponse{
 			InternalError: err,
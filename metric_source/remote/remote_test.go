@@ -1,8 +1,12 @@
 package remote
 
 import (
+	"encoding/pem"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 
 	metricSource "github.com/moira-alert/moira/metric_source"
@@ -64,3 +68,49 @@ func TestFetch(t *testing.T) {
 		So(err.Error(), ShouldResemble, "parse \"💩%$&TR\": invalid URL escape \"%$&\"")
 	})
 }
+
+func TestCreate(t *testing.T) {
+	Convey("Empty url", t, func() {
+		source, err := Create(&Config{})
+		So(source, ShouldBeNil)
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("Unknown format", t, func() {
+		source, err := Create(&Config{URL: "http://test/", Format: "pickle"})
+		So(source, ShouldBeNil)
+		So(err.Error(), ShouldEqual, "unknown remote graphite response format `pickle`")
+	})
+
+	Convey("Missing CA file", t, func() {
+		source, err := Create(&Config{URL: "https://test/", TLS: TLSConfig{CAFile: filepath.Join(t.TempDir(), "ca.pem")}})
+		So(source, ShouldBeNil)
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("Fetch from TLS server with custom CA", t, func() {
+		server := httptest.NewTLSServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			if req.Header.Get("Authorization") != "Bearer token" {
+				rw.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			rw.Write([]byte("[]")) //nolint
+		}))
+		defer server.Close()
+
+		caFile := filepath.Join(t.TempDir(), "ca.pem")
+		caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+		So(os.WriteFile(caFile, caPEM, 0o600), ShouldBeNil)
+
+		source, err := Create(&Config{
+			URL:     server.URL,
+			Headers: map[string]string{"Authorization": "Bearer token"},
+			TLS:     TLSConfig{CAFile: caFile},
+		})
+		So(err, ShouldBeNil)
+
+		result, err := source.Fetch("foo.bar", 300, 500, false)
+		So(err, ShouldBeNil)
+		So(result, ShouldResemble, &FetchResult{MetricsData: []metricSource.MetricData{}})
+	})
+}
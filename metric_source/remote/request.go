@@ -14,7 +14,7 @@ func (remote *Remote) prepareRequest(from, until int64, target string) (*http.Re
 		return nil, err
 	}
 	q := req.URL.Query()
-	q.Add("format", "json")
+	q.Add("format", requestFormat(remote.config.Format))
 	q.Add("from", strconv.FormatInt(from, 10))
 	q.Add("target", target)
 	q.Add("until", strconv.FormatInt(until, 10))
@@ -22,6 +22,9 @@ func (remote *Remote) prepareRequest(from, until int64, target string) (*http.Re
 	if remote.config.User != "" && remote.config.Password != "" {
 		req.SetBasicAuth(remote.config.User, remote.config.Password)
 	}
+	for name, value := range remote.config.Headers {
+		req.Header.Set(name, value)
+	}
 	return req, nil
 }
 
@@ -42,6 +42,20 @@ func TestPrepareRequest(t *testing.T) {
 			So(p, ShouldEqual, remote.config.Password)
 		})
 	})
+	Convey("Given valid params with format and headers", t, func() {
+		remote := Remote{config: &Config{
+			URL:     "http://test/",
+			Format:  FormatCarbonAPIv3PB,
+			Headers: map[string]string{"Authorization": "Bearer token", "X-Scope": "moira"},
+		}}
+		req, err := remote.prepareRequest(from, until, target)
+		Convey("url should contain format and headers should be set", func() {
+			So(err, ShouldBeNil)
+			So(req.URL.String(), ShouldEqual, "http://test/?format=carbonapi_v3_pb&from=300&target=foo.bar&until=500")
+			So(req.Header.Get("Authorization"), ShouldEqual, "Bearer token")
+			So(req.Header.Get("X-Scope"), ShouldEqual, "moira")
+		})
+	})
 }
 
 func TestMakeRequest(t *testing.T) {
@@ -2,16 +2,51 @@ package remote
 
 import (
 	"encoding/json"
+	"fmt"
 	"math"
 
+	"github.com/go-graphite/carbonapi/zipper/protocols/graphite/msgpack"
+	protov2 "github.com/go-graphite/protocol/carbonapi_v2_pb"
+	protov3 "github.com/go-graphite/protocol/carbonapi_v3_pb"
+
 	metricSource "github.com/moira-alert/moira/metric_source"
 )
 
+// Render response formats supported by remote metric source.
+const (
+	// FormatJSON is a default graphite-web json format.
+	FormatJSON = "json"
+	// FormatMsgpack is a graphite-web msgpack format.
+	FormatMsgpack = "msgpack"
+	// FormatProtobuf is a carbonapi protobuf format, alias for FormatCarbonAPIv2PB.
+	FormatProtobuf = "protobuf"
+	// FormatCarbonAPIv2PB is a carbonapi_v2_pb protobuf format.
+	FormatCarbonAPIv2PB = "carbonapi_v2_pb"
+	// FormatCarbonAPIv3PB is a carbonapi_v3_pb protobuf format.
+	FormatCarbonAPIv3PB = "carbonapi_v3_pb"
+)
+
 type graphiteMetric struct {
 	Target     string
 	DataPoints [][2]*float64
 }
 
+func isKnownFormat(format string) bool {
+	switch format {
+	case FormatJSON, FormatMsgpack, FormatProtobuf, FormatCarbonAPIv2PB, FormatCarbonAPIv3PB:
+		return true
+	default:
+		return false
+	}
+}
+
+func requestFormat(format string) string {
+	if format == "" {
+		return FormatJSON
+	}
+	return format
+}
+
 func convertResponse(metricsData []metricSource.MetricData, allowRealTimeAlerting bool) FetchResult {
 	if allowRealTimeAlerting {
 		return FetchResult{MetricsData: metricsData}
@@ -19,14 +54,31 @@ func convertResponse(metricsData []metricSource.MetricData, allowRealTimeAlertin
 
 	result := make([]metricSource.MetricData, 0, len(metricsData))
 	for _, metricData := range metricsData {
-		// remove last value
-		metricData.Values = metricData.Values[:len(metricData.Values)-1]
+		// remove last value, series without values are kept as is
+		if len(metricData.Values) > 0 {
+			metricData.Values = metricData.Values[:len(metricData.Values)-1]
+		}
 		result = append(result, metricData)
 	}
 	return FetchResult{MetricsData: result}
 }
 
-func decodeBody(body []byte) ([]metricSource.MetricData, error) {
+func decodeBody(body []byte, format string) ([]metricSource.MetricData, error) {
+	switch requestFormat(format) {
+	case FormatJSON:
+		return decodeJSONBody(body)
+	case FormatMsgpack:
+		return decodeMsgpackBody(body)
+	case FormatProtobuf, FormatCarbonAPIv2PB:
+		return decodeProtobufV2Body(body)
+	case FormatCarbonAPIv3PB:
+		return decodeProtobufV3Body(body)
+	default:
+		return nil, fmt.Errorf("unknown response format `%s`", format)
+	}
+}
+
+func decodeJSONBody(body []byte) ([]metricSource.MetricData, error) {
 	var tmp []graphiteMetric
 	err := json.Unmarshal(body, &tmp)
 	if err != nil {
@@ -34,6 +86,10 @@ func decodeBody(body []byte) ([]metricSource.MetricData, error) {
 	}
 	res := make([]metricSource.MetricData, 0, len(tmp))
 	for _, m := range tmp {
+		if len(m.DataPoints) == 0 {
+			res = append(res, makeMetricData(m.Target, 0, 0, make([]float64, 0)))
+			continue
+		}
 		var stepTime int64 = 60
 		if len(m.DataPoints) > 1 {
 			stepTime = int64(*m.DataPoints[1][1] - *m.DataPoints[0][1])
@@ -56,3 +112,85 @@ func decodeBody(body []byte) ([]metricSource.MetricData, error) {
 	}
 	return res, nil
 }
+
+func decodeMsgpackBody(body []byte) ([]metricSource.MetricData, error) {
+	var tmp msgpack.MultiGraphiteFetchResponse
+	if _, err := tmp.UnmarshalMsg(body); err != nil {
+		return nil, err
+	}
+	res := make([]metricSource.MetricData, 0, len(tmp))
+	for _, m := range tmp {
+		values := make([]float64, len(m.Values))
+		for i, v := range m.Values {
+			values[i] = msgpackValue(v)
+		}
+		res = append(res, makeMetricData(m.Name, int64(m.Start), int64(m.Step), values))
+	}
+	return res, nil
+}
+
+// msgpackValue converts msgpack datapoint to float64, null and unknown values are converted to NaN.
+func msgpackValue(value interface{}) float64 {
+	switch v := value.(type) {
+	case float64:
+		return v
+	case float32:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case uint64:
+		return float64(v)
+	default:
+		return math.NaN()
+	}
+}
+
+func decodeProtobufV2Body(body []byte) ([]metricSource.MetricData, error) {
+	var tmp protov2.MultiFetchResponse
+	if err := tmp.Unmarshal(body); err != nil {
+		return nil, err
+	}
+	res := make([]metricSource.MetricData, 0, len(tmp.Metrics))
+	for _, m := range tmp.Metrics {
+		values := make([]float64, len(m.Values))
+		for i, v := range m.Values {
+			if i < len(m.IsAbsent) && m.IsAbsent[i] {
+				values[i] = math.NaN()
+			} else {
+				values[i] = v
+			}
+		}
+		res = append(res, makeMetricData(m.Name, int64(m.StartTime), int64(m.StepTime), values))
+	}
+	return res, nil
+}
+
+func decodeProtobufV3Body(body []byte) ([]metricSource.MetricData, error) {
+	var tmp protov3.MultiFetchResponse
+	if err := tmp.Unmarshal(body); err != nil {
+		return nil, err
+	}
+	res := make([]metricSource.MetricData, 0, len(tmp.Metrics))
+	for _, m := range tmp.Metrics {
+		res = append(res, makeMetricData(m.Name, m.StartTime, m.StepTime, m.Values))
+	}
+	return res, nil
+}
+
+// makeMetricData creates metric data with stop time set to the timestamp of the last point, as in json response.
+func makeMetricData(name string, startTime, stepTime int64, values []float64) metricSource.MetricData {
+	if stepTime == 0 {
+		stepTime = 60
+	}
+	stopTime := startTime
+	if len(values) > 0 {
+		stopTime = startTime + stepTime*int64(len(values)-1)
+	}
+	return metricSource.MetricData{
+		Name:      name,
+		StartTime: startTime,
+		StopTime:  stopTime,
+		StepTime:  stepTime,
+		Values:    values,
+	}
+}
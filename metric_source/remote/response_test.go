@@ -2,8 +2,15 @@ package remote
 
 import (
 	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/go-graphite/carbonapi/zipper/protocols/graphite/msgpack"
+	protov2 "github.com/go-graphite/protocol/carbonapi_v2_pb"
+	protov3 "github.com/go-graphite/protocol/carbonapi_v3_pb"
+
 	metricSource "github.com/moira-alert/moira/metric_source"
 	. "github.com/smartystreets/goconvey/convey"
 )
@@ -11,7 +18,7 @@ import (
 func TestDecodeBody(t *testing.T) {
 	Convey("Given empty json response", t, func() {
 		body := []byte("[]")
-		resp, err := decodeBody(body)
+		resp, err := decodeBody(body, FormatJSON)
 		Convey("response should be empty and without error", func() {
 			So(err, ShouldBeNil)
 			So(len(resp), ShouldEqual, 0)
@@ -27,7 +34,7 @@ func TestDecodeBody(t *testing.T) {
 		}}}
 		body, _ := json.Marshal(r)
 
-		resp, err := decodeBody(body)
+		resp, err := decodeBody(body, FormatJSON)
 		Convey("length should be one", func() {
 			So(resp, ShouldHaveLength, 1)
 		})
@@ -49,7 +56,7 @@ func TestDecodeBody(t *testing.T) {
 		}}}
 		body, _ := json.Marshal(r)
 
-		resp, err := decodeBody(body)
+		resp, err := decodeBody(body, FormatJSON)
 		Convey("second response value should be set", func() {
 			So(err, ShouldBeNil)
 			fr := resp[0]
@@ -58,6 +65,93 @@ func TestDecodeBody(t *testing.T) {
 	})
 }
 
+func TestDecodeBodyFormats(t *testing.T) {
+	formats := []string{FormatJSON, FormatMsgpack, FormatCarbonAPIv2PB, FormatCarbonAPIv3PB}
+	expected := []struct {
+		name   string
+		values []float64
+	}{
+		{name: "foo.bar", values: []float64{1, math.NaN(), 3.5}},
+		{name: "foo.baz", values: []float64{math.NaN(), math.NaN(), 2}},
+	}
+
+	for _, format := range formats {
+		Convey("Given recorded "+format+" response", t, func() {
+			body, err := os.ReadFile(filepath.Join("testdata", "render."+format))
+			So(err, ShouldBeNil)
+
+			resp, err := decodeBody(body, format)
+			So(err, ShouldBeNil)
+			So(resp, ShouldHaveLength, len(expected))
+
+			for i, metricData := range resp {
+				So(metricData.Name, ShouldEqual, expected[i].name)
+				So(metricData.StartTime, ShouldEqual, 1522076500)
+				So(metricData.StopTime, ShouldEqual, 1522076620)
+				So(metricData.StepTime, ShouldEqual, 60)
+				So(metricData.Values, ShouldHaveLength, len(expected[i].values))
+				for j, value := range metricData.Values {
+					if math.IsNaN(expected[i].values[j]) {
+						So(math.IsNaN(value), ShouldBeTrue)
+					} else {
+						So(value, ShouldEqual, expected[i].values[j])
+					}
+				}
+			}
+		})
+	}
+
+	Convey("Given protobuf alias format", t, func() {
+		body, err := os.ReadFile(filepath.Join("testdata", "render."+FormatCarbonAPIv2PB))
+		So(err, ShouldBeNil)
+		resp, err := decodeBody(body, FormatProtobuf)
+		So(err, ShouldBeNil)
+		So(resp, ShouldHaveLength, 2)
+	})
+
+	Convey("Given invalid msgpack body", t, func() {
+		resp, err := decodeBody([]byte("Some string"), FormatMsgpack)
+		So(err, ShouldNotBeNil)
+		So(resp, ShouldBeNil)
+	})
+
+	Convey("Given unknown format", t, func() {
+		resp, err := decodeBody([]byte("[]"), "pickle")
+		So(err, ShouldNotBeNil)
+		So(resp, ShouldBeNil)
+	})
+}
+
+func TestDecodeEmptySeries(t *testing.T) {
+	jsonBody, _ := json.Marshal([]graphiteMetric{{Target: "empty", DataPoints: [][2]*float64{}}})
+	msgpackBody, _ := msgpack.MultiGraphiteFetchResponse{{Name: "empty", Start: 1522076500, Step: 60}}.MarshalMsg(nil)
+	protov2Body, _ := (&protov2.MultiFetchResponse{Metrics: []protov2.FetchResponse{{Name: "empty", StartTime: 1522076500, StepTime: 60}}}).Marshal()
+	protov3Body, _ := (&protov3.MultiFetchResponse{Metrics: []protov3.FetchResponse{{Name: "empty", StartTime: 1522076500, StepTime: 60}}}).Marshal()
+
+	bodies := map[string][]byte{
+		FormatJSON:          jsonBody,
+		FormatMsgpack:       msgpackBody,
+		FormatCarbonAPIv2PB: protov2Body,
+		FormatCarbonAPIv3PB: protov3Body,
+	}
+
+	for format, body := range bodies {
+		Convey("Given "+format+" response with empty series", t, func() {
+			resp, err := decodeBody(body, format)
+			So(err, ShouldBeNil)
+			So(resp, ShouldHaveLength, 1)
+			So(resp[0].Name, ShouldEqual, "empty")
+			So(resp[0].Values, ShouldBeEmpty)
+
+			Convey("it is converted without values", func() {
+				fetchResult := convertResponse(resp, false)
+				So(fetchResult.MetricsData, ShouldHaveLength, 1)
+				So(fetchResult.MetricsData[0].Values, ShouldBeEmpty)
+			})
+		})
+	}
+}
+
 func TestConvertResponse(t *testing.T) {
 	d := *metricSource.MakeMetricData("test", []float64{1, 2, 3}, 20, 0)
 	data := []metricSource.MetricData{d}
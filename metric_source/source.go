@@ -1,5 +1,7 @@
 package metricsource
 
+import "github.com/moira-alert/moira"
+
 // MetricSource implements graphite metrics source abstraction.
 type MetricSource interface {
 	Fetch(target string, from int64, until int64, allowRealTimeAlerting bool) (FetchResult, error)
@@ -7,6 +9,20 @@ type MetricSource interface {
 	IsAvailable() (bool, error)
 }
 
+// OptionsFetcher is implemented by metric sources, which support trigger specific fetch options.
+type OptionsFetcher interface {
+	FetchWithOptions(target string, from int64, until int64, allowRealTimeAlerting bool, options moira.FetchOptions) (FetchResult, error)
+}
+
+// FetchWithOptions fetches metrics with given options if metric source supports them.
+// Otherwise options are ignored and plain Fetch is used.
+func FetchWithOptions(source MetricSource, target string, from int64, until int64, allowRealTimeAlerting bool, options *moira.FetchOptions) (FetchResult, error) {
+	if fetcher, ok := source.(OptionsFetcher); ok && options != nil {
+		return fetcher.FetchWithOptions(target, from, until, allowRealTimeAlerting, *options)
+	}
+	return source.Fetch(target, from, until, allowRealTimeAlerting)
+}
+
 // FetchResult implements moira metric sources fetching result format.
 type FetchResult interface {
 	GetMetricsData() []MetricData
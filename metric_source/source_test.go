@@ -0,0 +1,55 @@
+package metricsource
+
+import (
+	"testing"
+
+	"github.com/moira-alert/moira"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type plainSource struct {
+	MetricSource
+	fetched bool
+}
+
+func (source *plainSource) Fetch(string, int64, int64, bool) (FetchResult, error) {
+	source.fetched = true
+	return nil, nil
+}
+
+type optionsSource struct {
+	plainSource
+	options *moira.FetchOptions
+}
+
+func (source *optionsSource) FetchWithOptions(_ string, _, _ int64, _ bool, options moira.FetchOptions) (FetchResult, error) {
+	source.options = &options
+	return nil, nil
+}
+
+func TestFetchWithOptions(t *testing.T) {
+	options := &moira.FetchOptions{Step: 15}
+
+	Convey("Source without options support should use plain fetch", t, func() {
+		source := &plainSource{}
+		_, err := FetchWithOptions(source, "target", 0, 60, true, options)
+		So(err, ShouldBeNil)
+		So(source.fetched, ShouldBeTrue)
+	})
+
+	Convey("Source with options support should receive options", t, func() {
+		source := &optionsSource{}
+		_, err := FetchWithOptions(source, "target", 0, 60, true, options)
+		So(err, ShouldBeNil)
+		So(source.fetched, ShouldBeFalse)
+		So(source.options, ShouldResemble, options)
+	})
+
+	Convey("Source with options support should use plain fetch if options are not set", t, func() {
+		source := &optionsSource{}
+		_, err := FetchWithOptions(source, "target", 0, 60, true, nil)
+		So(err, ShouldBeNil)
+		So(source.fetched, ShouldBeTrue)
+		So(source.options, ShouldBeNil)
+	})
+}
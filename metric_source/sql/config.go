@@ -0,0 +1,31 @@
+package sql
+
+import "time"
+
+// Supported drivers of SQL metric source.
+const (
+	PostgresDriver   = "postgres"
+	ClickHouseDriver = "clickhouse"
+)
+
+// Config represents config of SQL database used as metric source.
+type Config struct {
+	// Driver is a name of database/sql driver: postgres or clickhouse.
+	Driver string
+	// DSN is a data source name in format of the chosen driver.
+	DSN string
+	// User and Password override credentials of DSN, so every cluster could use its own credentials.
+	User     string
+	Password string
+	// Query is executed for every trigger target. It receives target, from and until (unix seconds)
+	// as positional parameters and must return rows of (timestamp, name, value).
+	Query         string
+	QueryTimeout  time.Duration
+	CheckInterval time.Duration
+	MetricsTTL    time.Duration
+	// Step is a resolution of returned metrics, rows inside one step are merged into one point.
+	Step                  time.Duration
+	MaxOpenConnections    int
+	MaxIdleConnections    int
+	ConnectionMaxLifetime time.Duration
+}
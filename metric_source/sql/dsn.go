@@ -0,0 +1,49 @@
+package sql
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// buildDSN adds credentials to data source name. Postgres accepts both URL and key=value DSN,
+// clickhouse accepts URL with username and password query parameters.
+func buildDSN(driver, dsn, user, password string) (string, error) {
+	if user == "" && password == "" {
+		return dsn, nil
+	}
+
+	switch driver {
+	case PostgresDriver:
+		if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+			u, err := url.Parse(dsn)
+			if err != nil {
+				return "", fmt.Errorf("failed to parse postgres dsn: %w", err)
+			}
+			u.User = url.UserPassword(user, password)
+			return u.String(), nil
+		}
+		return strings.TrimSpace(fmt.Sprintf("%s user=%s password=%s", dsn, quoteValue(user), quoteValue(password))), nil
+
+	case ClickHouseDriver:
+		u, err := url.Parse(dsn)
+		if err != nil {
+			return "", fmt.Errorf("failed to parse clickhouse dsn: %w", err)
+		}
+		query := u.Query()
+		query.Set("username", user)
+		query.Set("password", password)
+		u.RawQuery = query.Encode()
+		return u.String(), nil
+
+	default:
+		return "", fmt.Errorf("credentials are not supported for sql driver `%s`, pass them in dsn", driver)
+	}
+}
+
+// quoteValue quotes value of postgres key=value connection string.
+func quoteValue(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `'`, `\'`)
+	return "'" + value + "'"
+}
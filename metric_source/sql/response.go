@@ -0,0 +1,143 @@
+package sql
+
+import (
+	dbsql "database/sql"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	metricSource "github.com/moira-alert/moira/metric_source"
+)
+
+// FetchResult is implementation of metric_source.FetchResult interface,
+// which represent fetching result from SQL database in moira format.
+type FetchResult struct {
+	MetricsData []metricSource.MetricData
+}
+
+// GetMetricsData return all metrics data from fetch result.
+func (fetchResult *FetchResult) GetMetricsData() []metricSource.MetricData {
+	return fetchResult.MetricsData
+}
+
+// GetPatterns always returns error, because we can't fetch target patterns from SQL database.
+func (*FetchResult) GetPatterns() ([]string, error) {
+	return make([]string, 0), fmt.Errorf("sql fetch result never returns patterns")
+}
+
+// GetPatternMetrics always returns error, because SQL fetch doesn't return base pattern metrics.
+func (*FetchResult) GetPatternMetrics() ([]string, error) {
+	return make([]string, 0), fmt.Errorf("sql fetch result never returns pattern metrics")
+}
+
+type point struct {
+	timestamp int64
+	value     float64
+}
+
+// convertRows groups (timestamp, name, value) rows by name and aligns them to step.
+// Steps without rows are filled with NaN, the last row wins if several rows fall into one step.
+// Rows with empty name are named after target, rows outside of from..until are skipped.
+func convertRows(rows *dbsql.Rows, target string, from, until, step int64) ([]metricSource.MetricData, error) {
+	names := make([]string, 0)
+	points := make(map[string][]point)
+
+	for rows.Next() {
+		var rawTimestamp any
+		var name dbsql.NullString
+		var value dbsql.NullFloat64
+		if err := rows.Scan(&rawTimestamp, &name, &value); err != nil {
+			return nil, fmt.Errorf("failed to scan row, query must return (timestamp, name, value): %w", err)
+		}
+
+		timestamp, err := parseTimestamp(rawTimestamp)
+		if err != nil {
+			return nil, err
+		}
+		// Query could ignore bounds, so one stray row must not stretch metrics data
+		if timestamp < from || timestamp > until {
+			continue
+		}
+
+		metricName := target
+		if name.Valid && name.String != "" {
+			metricName = name.String
+		}
+		if _, ok := points[metricName]; !ok {
+			names = append(names, metricName)
+		}
+
+		metricValue := math.NaN()
+		if value.Valid {
+			metricValue = value.Float64
+		}
+		points[metricName] = append(points[metricName], point{timestamp: timestamp - timestamp%step, value: metricValue})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	result := make([]metricSource.MetricData, 0, len(names))
+	for _, name := range names {
+		result = append(result, makeMetricData(name, points[name], step))
+	}
+	return result, nil
+}
+
+func makeMetricData(name string, points []point, step int64) metricSource.MetricData {
+	start, stop := points[0].timestamp, points[0].timestamp
+	for _, p := range points {
+		start = min(start, p.timestamp)
+		stop = max(stop, p.timestamp)
+	}
+
+	values := make([]float64, (stop-start)/step+1)
+	for i := range values {
+		values[i] = math.NaN()
+	}
+	for _, p := range points {
+		values[(p.timestamp-start)/step] = p.value
+	}
+
+	return metricSource.MetricData{
+		Name:      name,
+		StartTime: start,
+		StopTime:  stop,
+		StepTime:  step,
+		Values:    values,
+	}
+}
+
+// parseTimestamp converts timestamp column to unix seconds.
+// Column could be of time type, unix seconds number or RFC3339 string.
+func parseTimestamp(raw any) (int64, error) {
+	switch value := raw.(type) {
+	case time.Time:
+		return value.Unix(), nil
+	case int64:
+		return value, nil
+	case float64:
+		return int64(value), nil
+	case []byte:
+		return parseTimestampString(string(value))
+	case string:
+		return parseTimestampString(value)
+	default:
+		return 0, fmt.Errorf("unsupported timestamp type %T", raw)
+	}
+}
+
+func parseTimestampString(value string) (int64, error) {
+	if timestamp, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return timestamp, nil
+	}
+	if timestamp, err := strconv.ParseFloat(value, 64); err == nil {
+		return int64(timestamp), nil
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse timestamp %s: %w", value, err)
+	}
+	return parsed.Unix(), nil
+}
@@ -0,0 +1,34 @@
+package sql
+
+import (
+	"testing"
+	"time"
+
+	metricSource "github.com/moira-alert/moira/metric_source"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestFetchResult(t *testing.T) {
+	Convey("Fetch result never returns patterns", t, func() {
+		result := &FetchResult{MetricsData: []metricSource.MetricData{}}
+		_, err := result.GetPatterns()
+		So(err, ShouldNotBeNil)
+		_, err = result.GetPatternMetrics()
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestParseTimestamp(t *testing.T) {
+	Convey("Parse timestamp", t, func() {
+		for _, raw := range []any{int64(60), float64(60.5), "60", []byte("60"), time.Unix(60, 0), "1970-01-01T00:01:00Z"} {
+			timestamp, err := parseTimestamp(raw)
+			So(err, ShouldBeNil)
+			So(timestamp, ShouldEqual, 60)
+		}
+
+		_, err := parseTimestamp(true)
+		So(err, ShouldNotBeNil)
+		_, err = parseTimestamp("yesterday")
+		So(err, ShouldNotBeNil)
+	})
+}
@@ -0,0 +1,127 @@
+package sql
+
+import (
+	"context"
+	dbsql "database/sql"
+	"fmt"
+	"slices"
+	"time"
+
+	_ "github.com/ClickHouse/clickhouse-go" // registers clickhouse driver
+	_ "github.com/lib/pq"                   // registers postgres driver
+	"github.com/moira-alert/moira"
+	metricSource "github.com/moira-alert/moira/metric_source"
+)
+
+const (
+	// StepTimeSeconds is a default resolution of metrics returned by SQL source.
+	StepTimeSeconds int64 = 60
+	// DefaultQueryTimeout is used if query timeout is not set in config.
+	DefaultQueryTimeout = 30 * time.Second
+)
+
+// ErrSQLTriggerResponse is a custom error when SQL trigger check fails.
+type ErrSQLTriggerResponse struct {
+	InternalError error
+	Target        string
+}
+
+// Error is a representation of Error interface method.
+func (err ErrSQLTriggerResponse) Error() string {
+	return err.InternalError.Error()
+}
+
+// SQL is implementation of MetricSource interface, which runs configured query in SQL database
+// and converts returned rows to metrics.
+type SQL struct {
+	config *Config
+	db     *dbsql.DB
+}
+
+// Create configures SQL metric source and its connection pool.
+func Create(config *Config) (metricSource.MetricSource, error) {
+	if config.Query == "" {
+		return nil, fmt.Errorf("sql query should not be empty")
+	}
+	if !slices.Contains(dbsql.Drivers(), config.Driver) {
+		return nil, fmt.Errorf("unknown sql driver `%s`", config.Driver)
+	}
+
+	dsn, err := buildDSN(config.Driver, config.DSN, config.User, config.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := dbsql.Open(config.Driver, dsn)
+	if err != nil {
+		return nil, err
+	}
+	// Pool settings which are not set keep database/sql defaults, e.g. 2 idle connections
+	if config.MaxOpenConnections > 0 {
+		db.SetMaxOpenConns(config.MaxOpenConnections)
+	}
+	if config.MaxIdleConnections > 0 {
+		db.SetMaxIdleConns(config.MaxIdleConnections)
+	}
+	if config.ConnectionMaxLifetime > 0 {
+		db.SetConnMaxLifetime(config.ConnectionMaxLifetime)
+	}
+
+	return &SQL{
+		config: config,
+		db:     db,
+	}, nil
+}
+
+// Fetch runs configured query for given target and converts rows to metrics data.
+func (source *SQL) Fetch(target string, from, until int64, allowRealTimeAlerting bool) (metricSource.FetchResult, error) {
+	from = moira.MaxInt64(from, until-int64(source.config.MetricsTTL.Seconds()))
+	step := source.stepTime()
+
+	ctx, cancel := context.WithTimeout(context.Background(), source.queryTimeout())
+	defer cancel()
+
+	rows, err := source.db.QueryContext(ctx, source.config.Query, target, from, until)
+	if err != nil {
+		return nil, ErrSQLTriggerResponse{InternalError: err, Target: target}
+	}
+	defer rows.Close()
+
+	metricsData, err := convertRows(rows, target, from, until, step)
+	if err != nil {
+		return nil, ErrSQLTriggerResponse{InternalError: err, Target: target}
+	}
+
+	if !allowRealTimeAlerting {
+		metricsData = metricSource.TrimIncompleteStep(metricsData, until)
+	}
+	return &FetchResult{MetricsData: metricsData}, nil
+}
+
+// GetMetricsTTLSeconds returns maximum time interval that we are allowed to fetch from database.
+func (source *SQL) GetMetricsTTLSeconds() int64 {
+	return int64(source.config.MetricsTTL.Seconds())
+}
+
+// IsAvailable checks if database accepts connections.
+func (source *SQL) IsAvailable() (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), source.queryTimeout())
+	defer cancel()
+
+	err := source.db.PingContext(ctx)
+	return err == nil, err
+}
+
+func (source *SQL) stepTime() int64 {
+	if step := int64(source.config.Step.Seconds()); step > 0 {
+		return step
+	}
+	return StepTimeSeconds
+}
+
+func (source *SQL) queryTimeout() time.Duration {
+	if source.config.QueryTimeout > 0 {
+		return source.config.QueryTimeout
+	}
+	return DefaultQueryTimeout
+}
@@ -0,0 +1,164 @@
+//go:build cgo
+
+// Tests use sqlite3 driver, which requires cgo.
+
+package sql
+
+import (
+	dbsql "database/sql"
+	"math"
+	"path/filepath"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	metricSource "github.com/moira-alert/moira/metric_source"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const testQuery = `SELECT ts, name, value FROM metrics WHERE name LIKE ? AND ts >= ? AND ts <= ? ORDER BY ts`
+
+func prepareDatabase(t *testing.T) string {
+	dsn := filepath.Join(t.TempDir(), "metrics.db")
+	db, err := dbsql.Open("sqlite3", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	statements := []string{
+		`CREATE TABLE metrics (ts INTEGER, name TEXT, value REAL)`,
+		`INSERT INTO metrics VALUES (60, 'orders.paid', 1), (120, 'orders.paid', 2), (240, 'orders.paid', 4)`,
+		`INSERT INTO metrics VALUES (90, 'orders.failed', 5), (100, 'orders.failed', 6), (150, 'orders.failed', NULL)`,
+	}
+	for _, statement := range statements {
+		if _, err = db.Exec(statement); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dsn
+}
+
+func TestCreate(t *testing.T) {
+	Convey("Create sql source", t, func() {
+		Convey("With empty query", func() {
+			source, err := Create(&Config{Driver: "sqlite3"})
+			So(err.Error(), ShouldEqual, "sql query should not be empty")
+			So(source, ShouldBeNil)
+		})
+
+		Convey("With unknown driver", func() {
+			source, err := Create(&Config{Driver: "oracle", Query: testQuery})
+			So(err.Error(), ShouldEqual, "unknown sql driver `oracle`")
+			So(source, ShouldBeNil)
+		})
+
+		Convey("With credentials unsupported by driver", func() {
+			source, err := Create(&Config{Driver: "sqlite3", Query: testQuery, User: "moira"})
+			So(err, ShouldNotBeNil)
+			So(source, ShouldBeNil)
+		})
+
+		Convey("With valid config", func() {
+			source, err := Create(&Config{Driver: "sqlite3", DSN: prepareDatabase(t), Query: testQuery})
+			So(err, ShouldBeNil)
+
+			available, err := source.IsAvailable()
+			So(err, ShouldBeNil)
+			So(available, ShouldBeTrue)
+		})
+
+		Convey("With pool settings", func() {
+			source, err := Create(&Config{Driver: "sqlite3", DSN: prepareDatabase(t), Query: testQuery, MaxOpenConnections: 5})
+			So(err, ShouldBeNil)
+			So(source.(*SQL).db.Stats().MaxOpenConnections, ShouldEqual, 5)
+		})
+	})
+}
+
+func TestFetch(t *testing.T) {
+	Convey("Fetch metrics from sql database", t, func() {
+		source, err := Create(&Config{
+			Driver:       "sqlite3",
+			DSN:          prepareDatabase(t),
+			Query:        testQuery,
+			QueryTimeout: time.Second,
+			MetricsTTL:   time.Hour,
+			Step:         time.Minute,
+		})
+		So(err, ShouldBeNil)
+
+		Convey("Rows are aligned to step and gaps are filled with NaN", func() {
+			result, err := source.Fetch("orders.paid", 0, 300, true)
+			So(err, ShouldBeNil)
+
+			metricsData := result.GetMetricsData()
+			So(metricsData, ShouldHaveLength, 1)
+			So(metricsData[0].Name, ShouldEqual, "orders.paid")
+			So(metricsData[0].StartTime, ShouldEqual, 60)
+			So(metricsData[0].StopTime, ShouldEqual, 240)
+			So(metricsData[0].StepTime, ShouldEqual, 60)
+			So(metricsData[0].Values, ShouldHaveLength, 4)
+			So(metricsData[0].Values[:2], ShouldResemble, []float64{1, 2})
+			So(math.IsNaN(metricsData[0].Values[2]), ShouldBeTrue)
+			So(metricsData[0].Values[3], ShouldEqual, 4)
+		})
+
+		Convey("Last value is removed without realtime alerting if its step is incomplete", func() {
+			result, err := source.Fetch("orders.paid", 0, 270, false)
+			So(err, ShouldBeNil)
+			So(result.GetMetricsData()[0].Values, ShouldHaveLength, 3)
+			So(result.GetMetricsData()[0].StopTime, ShouldEqual, 180)
+
+			result, err = source.Fetch("orders.paid", 0, 300, false)
+			So(err, ShouldBeNil)
+			So(result.GetMetricsData()[0].Values, ShouldHaveLength, 4)
+		})
+
+		Convey("Rows of several metrics are grouped by name, last row in step wins", func() {
+			result, err := source.Fetch("orders.%", 0, 300, true)
+			So(err, ShouldBeNil)
+
+			metricsData := result.GetMetricsData()
+			So(metricsData, ShouldHaveLength, 2)
+			So(metricsData[0].Name, ShouldEqual, "orders.paid")
+			So(metricsData[1].Name, ShouldEqual, "orders.failed")
+			So(metricsData[1].StartTime, ShouldEqual, 60)
+			So(metricsData[1].Values[0], ShouldEqual, 6)
+			So(math.IsNaN(metricsData[1].Values[1]), ShouldBeTrue)
+		})
+
+		Convey("Query bounds are passed as parameters", func() {
+			result, err := source.Fetch("orders.paid", 100, 200, true)
+			So(err, ShouldBeNil)
+			So(result.GetMetricsData()[0].Values, ShouldResemble, []float64{2})
+		})
+
+		Convey("Rows outside of query bounds are skipped", func() {
+			unboundedSource, err := Create(&Config{
+				Driver:     "sqlite3",
+				DSN:        prepareDatabase(t),
+				Query:      "SELECT ts, name, value FROM metrics WHERE name LIKE ? AND ? IS NOT NULL AND ? IS NOT NULL ORDER BY ts",
+				MetricsTTL: time.Hour,
+				Step:       time.Minute,
+			})
+			So(err, ShouldBeNil)
+
+			result, err := unboundedSource.Fetch("orders.paid", 100, 200, true)
+			So(err, ShouldBeNil)
+			So(result.GetMetricsData(), ShouldResemble, []metricSource.MetricData{
+				{Name: "orders.paid", StartTime: 120, StopTime: 120, StepTime: 60, Values: []float64{2}},
+			})
+		})
+
+		Convey("Error of query is returned with target", func() {
+			brokenSource, err := Create(&Config{Driver: "sqlite3", DSN: prepareDatabase(t), Query: "SELECT ts FROM metrics WHERE name = ? AND ts >= ? AND ts <= ?", MetricsTTL: time.Hour})
+			So(err, ShouldBeNil)
+
+			result, err := brokenSource.Fetch("orders.paid", 0, 300, true)
+			So(result, ShouldBeNil)
+			So(err, ShouldHaveSameTypeAs, ErrSQLTriggerResponse{})
+			So(err.(ErrSQLTriggerResponse).Target, ShouldEqual, "orders.paid")
+		})
+	})
+}
@@ -0,0 +1,52 @@
+package tenant
+
+import "time"
+
+// Query APIs of multi-tenant storages, supported by tenant metric source.
+const (
+	// PrometheusAPI is prometheus compatible query API, provided by VictoriaMetrics, Thanos and Mimir.
+	PrometheusAPI = "prometheus"
+	// GraphiteAPI is graphite compatible render API, provided by VictoriaMetrics.
+	GraphiteAPI = "graphite"
+)
+
+// DefaultTenantHeader is a header used to pass tenant if other one is not configured.
+const DefaultTenantHeader = "X-Scope-OrgID"
+
+// Config represents config of multi-tenant remote storage.
+type Config struct {
+	// API is one of PrometheusAPI or GraphiteAPI, default is PrometheusAPI.
+	API string
+	// Tenant is an id of tenant, passed in TenantHeader to remote storage.
+	Tenant string
+	// TenantHeader is a header used to pass tenant, default is DefaultTenantHeader.
+	TenantHeader string
+	// Headers are added to every request to remote storage.
+	Headers map[string]string
+
+	URL            string
+	CheckInterval  time.Duration
+	MetricsTTL     time.Duration
+	RequestTimeout time.Duration
+	Retries        int
+	RetryTimeout   time.Duration
+	Step           time.Duration
+	User           string
+	Password       string
+}
+
+// requestHeaders returns configured headers with tenant header.
+func (config *Config) requestHeaders() map[string]string {
+	headers := make(map[string]string, len(config.Headers)+1)
+	for name, value := range config.Headers {
+		headers[name] = value
+	}
+	if config.Tenant != "" {
+		tenantHeader := config.TenantHeader
+		if tenantHeader == "" {
+			tenantHeader = DefaultTenantHeader
+		}
+		headers[tenantHeader] = config.Tenant
+	}
+	return headers
+}
@@ -0,0 +1,49 @@
+package tenant
+
+import (
+	"fmt"
+
+	"github.com/moira-alert/moira"
+	metricSource "github.com/moira-alert/moira/metric_source"
+	"github.com/moira-alert/moira/metric_source/prometheus"
+	"github.com/moira-alert/moira/metric_source/remote"
+)
+
+// Create configures metric source for multi-tenant storages like VictoriaMetrics, Thanos or Mimir.
+// Depending on configured API, metrics are fetched using prometheus or graphite metric source,
+// which pass tenant and other configured headers with every request.
+func Create(config *Config, logger moira.Logger) (metricSource.MetricSource, error) {
+	if config.URL == "" {
+		return nil, fmt.Errorf("tenant remote URL should not be empty")
+	}
+
+	switch config.API {
+	case PrometheusAPI, "":
+		return prometheus.Create(&prometheus.Config{
+			CheckInterval:  config.CheckInterval,
+			MetricsTTL:     config.MetricsTTL,
+			RequestTimeout: config.RequestTimeout,
+			Retries:        config.Retries,
+			RetryTimeout:   config.RetryTimeout,
+			URL:            config.URL,
+			User:           config.User,
+			Password:       config.Password,
+			Step:           config.Step,
+			Headers:        config.requestHeaders(),
+		}, logger)
+
+	case GraphiteAPI:
+		return remote.Create(&remote.Config{
+			URL:           config.URL,
+			CheckInterval: config.CheckInterval,
+			MetricsTTL:    config.MetricsTTL,
+			Timeout:       config.RequestTimeout,
+			User:          config.User,
+			Password:      config.Password,
+			Headers:       config.requestHeaders(),
+		})
+
+	default:
+		return nil, fmt.Errorf("unknown tenant remote API `%s`, allowed values: %s, %s", config.API, PrometheusAPI, GraphiteAPI)
+	}
+}
@@ -0,0 +1,139 @@
+package tenant
+
+import (
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/moira-alert/moira/logging/zerolog_adapter"
+	metricSource "github.com/moira-alert/moira/metric_source"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const (
+	prometheusResponse = `{"status":"success","data":{"resultType":"matrix","result":[` +
+		`{"metric":{"__name__":"up","job":"moira"},"values":[[1000,"1"],[1060,"0"]]}]}}`
+	graphiteResponse = `[{"target":"moira.up","datapoints":[[1,1000],[null,1060]]}]`
+)
+
+type capturedRequest struct {
+	path    string
+	query   string
+	headers http.Header
+}
+
+func createServer(body string, captured *capturedRequest) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		req.ParseForm() //nolint
+		captured.path = req.URL.Path
+		captured.query = req.Form.Get("query") + req.Form.Get("target")
+		captured.headers = req.Header.Clone()
+		if req.Header.Get(DefaultTenantHeader) == "" {
+			rw.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		rw.Header().Set("Content-Type", "application/json")
+		rw.Write([]byte(body)) //nolint
+	}))
+}
+
+func TestCreate(t *testing.T) {
+	logger, _ := zerolog_adapter.GetLogger("Test")
+
+	Convey("Empty url", t, func() {
+		source, err := Create(&Config{}, logger)
+		So(source, ShouldBeNil)
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("Unknown API", t, func() {
+		source, err := Create(&Config{URL: "http://test/", API: "influx"}, logger)
+		So(source, ShouldBeNil)
+		So(err.Error(), ShouldEqual, "unknown tenant remote API `influx`, allowed values: prometheus, graphite")
+	})
+}
+
+func TestFetch(t *testing.T) {
+	logger, _ := zerolog_adapter.GetLogger("Test")
+
+	var from int64 = 1000
+	var until int64 = 1060
+
+	Convey("Fetch using prometheus API", t, func() {
+		captured := &capturedRequest{}
+		server := createServer(prometheusResponse, captured)
+		defer server.Close()
+
+		source, err := Create(&Config{
+			URL:            server.URL,
+			Tenant:         "team-a",
+			Headers:        map[string]string{"X-Custom": "value"},
+			MetricsTTL:     time.Hour,
+			RequestTimeout: time.Second,
+			Retries:        1,
+		}, logger)
+		So(err, ShouldBeNil)
+
+		result, err := source.Fetch("up", from, until, true)
+		So(err, ShouldBeNil)
+
+		So(captured.path, ShouldEqual, "/api/v1/query_range")
+		So(captured.query, ShouldEqual, "up")
+		So(captured.headers.Get(DefaultTenantHeader), ShouldEqual, "team-a")
+		So(captured.headers.Get("X-Custom"), ShouldEqual, "value")
+
+		So(result.GetMetricsData(), ShouldResemble, []metricSource.MetricData{
+			{
+				Name:      "up;job=moira",
+				StartTime: from,
+				StopTime:  until,
+				StepTime:  60,
+				Values:    []float64{1, 0},
+			},
+		})
+	})
+
+	Convey("Fetch using graphite API with custom tenant header", t, func() {
+		captured := &capturedRequest{}
+		server := createServer(graphiteResponse, captured)
+		defer server.Close()
+
+		source, err := Create(&Config{
+			API:          GraphiteAPI,
+			URL:          server.URL + "/render",
+			Tenant:       "team-b",
+			TenantHeader: "THANOS-TENANT",
+			Headers:      map[string]string{DefaultTenantHeader: "fallback"},
+			MetricsTTL:   time.Hour,
+		}, logger)
+		So(err, ShouldBeNil)
+
+		result, err := source.Fetch("moira.up", from, until, true)
+		So(err, ShouldBeNil)
+
+		So(captured.path, ShouldEqual, "/render")
+		So(captured.query, ShouldEqual, "moira.up")
+		So(captured.headers.Get("THANOS-TENANT"), ShouldEqual, "team-b")
+
+		metricsData := result.GetMetricsData()
+		So(metricsData, ShouldHaveLength, 1)
+		So(metricsData[0].Name, ShouldEqual, "moira.up")
+		So(metricsData[0].Values[0], ShouldEqual, 1)
+		So(math.IsNaN(metricsData[0].Values[1]), ShouldBeTrue)
+	})
+
+	Convey("Fetch without tenant is rejected by server", t, func() {
+		captured := &capturedRequest{}
+		server := createServer(prometheusResponse, captured)
+		defer server.Close()
+
+		source, err := Create(&Config{URL: server.URL, MetricsTTL: time.Hour, RequestTimeout: time.Second}, logger)
+		So(err, ShouldBeNil)
+
+		result, err := source.Fetch("up", from, until, true)
+		So(result, ShouldBeNil)
+		So(err, ShouldNotBeNil)
+	})
+}
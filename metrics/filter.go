@@ -2,26 +2,40 @@ package metrics
 
 // FilterMetrics is a collection of metrics used in filter.
 type FilterMetrics struct {
-	TotalMetricsReceived    Counter
-	ValidMetricsReceived    Counter
-	MatchingMetricsReceived Counter
-	MatchingTimer           Timer
-	SavingTimer             Timer
-	BuildTreeTimer          Timer
-	MetricChannelLen        Histogram
-	LineChannelLen          Histogram
+	TotalMetricsReceived     Counter
+	ValidMetricsReceived     Counter
+	MatchingMetricsReceived  Counter
+	DroppedMetricsReceived   Counter
+	RewrittenMetricsReceived Counter
+	MatchingTimer            Timer
+	SavingTimer              Timer
+	BuildTreeTimer           Timer
+	MetricChannelLen         Histogram
+	LineChannelLen           Histogram
+	ConfigReloadSucceeded    Counter
+	ConfigReloadFailed       Counter
+	ClusterForwarded         Counter
+	ClusterForwardFailed     Counter
+	ClusterMetricsReceived   Counter
 }
 
 // ConfigureFilterMetrics initialize metrics.
 func ConfigureFilterMetrics(registry Registry) *FilterMetrics {
 	return &FilterMetrics{
-		TotalMetricsReceived:    registry.NewCounter("received", "total"),
-		ValidMetricsReceived:    registry.NewCounter("received", "valid"),
-		MatchingMetricsReceived: registry.NewCounter("received", "matching"),
-		MatchingTimer:           registry.NewTimer("time", "match"),
-		SavingTimer:             registry.NewTimer("time", "save"),
-		BuildTreeTimer:          registry.NewTimer("time", "buildtree"),
-		MetricChannelLen:        registry.NewHistogram("metricsToSave"),
-		LineChannelLen:          registry.NewHistogram("linesToMatch"),
+		TotalMetricsReceived:     registry.NewCounter("received", "total"),
+		ValidMetricsReceived:     registry.NewCounter("received", "valid"),
+		MatchingMetricsReceived:  registry.NewCounter("received", "matching"),
+		DroppedMetricsReceived:   registry.NewCounter("received", "dropped"),
+		RewrittenMetricsReceived: registry.NewCounter("received", "rewritten"),
+		MatchingTimer:            registry.NewTimer("time", "match"),
+		SavingTimer:              registry.NewTimer("time", "save"),
+		BuildTreeTimer:           registry.NewTimer("time", "buildtree"),
+		MetricChannelLen:         registry.NewHistogram("metricsToSave"),
+		LineChannelLen:           registry.NewHistogram("linesToMatch"),
+		ConfigReloadSucceeded:    registry.NewCounter("reload", "succeeded"),
+		ConfigReloadFailed:       registry.NewCounter("reload", "failed"),
+		ClusterForwarded:         registry.NewCounter("cluster", "forwarded"),
+		ClusterForwardFailed:     registry.NewCounter("cluster", "forward_failed"),
+		ClusterMetricsReceived:   registry.NewCounter("cluster", "received"),
 	}
 }
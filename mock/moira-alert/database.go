@@ -353,6 +353,21 @@ func (mr *MockDatabaseMockRecorder) GetContacts(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetContacts", reflect.TypeOf((*MockDatabase)(nil).GetContacts), arg0)
 }
 
+// GetFilterClusterMembers mocks base method.
+func (m *MockDatabase) GetFilterClusterMembers(arg0 int64) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFilterClusterMembers", arg0)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFilterClusterMembers indicates an expected call of GetFilterClusterMembers.
+func (mr *MockDatabaseMockRecorder) GetFilterClusterMembers(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFilterClusterMembers", reflect.TypeOf((*MockDatabase)(nil).GetFilterClusterMembers), arg0)
+}
+
 // GetIDByUsername mocks base method.
 func (m *MockDatabase) GetIDByUsername(arg0, arg1 string) (string, error) {
 	m.ctrl.T.Helper()
@@ -368,6 +383,36 @@ func (mr *MockDatabaseMockRecorder) GetIDByUsername(arg0, arg1 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIDByUsername", reflect.TypeOf((*MockDatabase)(nil).GetIDByUsername), arg0, arg1)
 }
 
+// GetMessageThread mocks base method.
+func (m *MockDatabase) GetMessageThread(arg0, arg1, arg2 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMessageThread", arg0, arg1, arg2)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMessageThread indicates an expected call of GetMessageThread.
+func (mr *MockDatabaseMockRecorder) GetMessageThread(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMessageThread", reflect.TypeOf((*MockDatabase)(nil).GetMessageThread), arg0, arg1, arg2)
+}
+
+// GetMetricArchives mocks base method.
+func (m *MockDatabase) GetMetricArchives(arg0 string) ([]moira.Retention, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMetricArchives", arg0)
+	ret0, _ := ret[0].([]moira.Retention)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMetricArchives indicates an expected call of GetMetricArchives.
+func (mr *MockDatabaseMockRecorder) GetMetricArchives(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMetricArchives", reflect.TypeOf((*MockDatabase)(nil).GetMetricArchives), arg0)
+}
+
 // GetMetricRetention mocks base method.
 func (m *MockDatabase) GetMetricRetention(arg0 string) (int64, error) {
 	m.ctrl.T.Helper()
@@ -383,6 +428,21 @@ func (mr *MockDatabaseMockRecorder) GetMetricRetention(arg0 interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMetricRetention", reflect.TypeOf((*MockDatabase)(nil).GetMetricRetention), arg0)
 }
 
+// GetMetricsArchiveValues mocks base method.
+func (m *MockDatabase) GetMetricsArchiveValues(arg0 []string, arg1, arg2, arg3 int64) (map[string][]*moira.MetricValue, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMetricsArchiveValues", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(map[string][]*moira.MetricValue)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMetricsArchiveValues indicates an expected call of GetMetricsArchiveValues.
+func (mr *MockDatabaseMockRecorder) GetMetricsArchiveValues(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMetricsArchiveValues", reflect.TypeOf((*MockDatabase)(nil).GetMetricsArchiveValues), arg0, arg1, arg2, arg3)
+}
+
 // GetMetricsTTLSeconds mocks base method.
 func (m *MockDatabase) GetMetricsTTLSeconds() int64 {
 	m.ctrl.T.Helper()
@@ -1114,6 +1174,34 @@ func (mr *MockDatabaseMockRecorder) RemoveContact(arg0 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveContact", reflect.TypeOf((*MockDatabase)(nil).RemoveContact), arg0)
 }
 
+// RemoveFilterClusterMember mocks base method.
+func (m *MockDatabase) RemoveFilterClusterMember(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveFilterClusterMember", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveFilterClusterMember indicates an expected call of RemoveFilterClusterMember.
+func (mr *MockDatabaseMockRecorder) RemoveFilterClusterMember(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveFilterClusterMember", reflect.TypeOf((*MockDatabase)(nil).RemoveFilterClusterMember), arg0)
+}
+
+// RemoveMessageThread mocks base method.
+func (m *MockDatabase) RemoveMessageThread(arg0, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveMessageThread", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveMessageThread indicates an expected call of RemoveMessageThread.
+func (mr *MockDatabaseMockRecorder) RemoveMessageThread(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveMessageThread", reflect.TypeOf((*MockDatabase)(nil).RemoveMessageThread), arg0, arg1, arg2)
+}
+
 // RemoveMetricRetention mocks base method.
 func (m *MockDatabase) RemoveMetricRetention(arg0 string) error {
 	m.ctrl.T.Helper()
@@ -1438,6 +1526,20 @@ func (mr *MockDatabaseMockRecorder) SaveTriggersSearchResults(arg0, arg1 interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveTriggersSearchResults", reflect.TypeOf((*MockDatabase)(nil).SaveTriggersSearchResults), arg0, arg1)
 }
 
+// SetMessageThread mocks base method.
+func (m *MockDatabase) SetMessageThread(arg0, arg1, arg2, arg3 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetMessageThread", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetMessageThread indicates an expected call of SetMessageThread.
+func (mr *MockDatabaseMockRecorder) SetMessageThread(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetMessageThread", reflect.TypeOf((*MockDatabase)(nil).SetMessageThread), arg0, arg1, arg2, arg3)
+}
+
 // SetNotifierState mocks base method.
 func (m *MockDatabase) SetNotifierState(arg0 string) error {
 	m.ctrl.T.Helper()
@@ -1538,6 +1640,20 @@ func (mr *MockDatabaseMockRecorder) SubscribeMetricEvents(arg0, arg1 interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeMetricEvents", reflect.TypeOf((*MockDatabase)(nil).SubscribeMetricEvents), arg0, arg1)
 }
 
+// UpdateFilterClusterMember mocks base method.
+func (m *MockDatabase) UpdateFilterClusterMember(arg0 string, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateFilterClusterMember", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateFilterClusterMember indicates an expected call of UpdateFilterClusterMember.
+func (mr *MockDatabaseMockRecorder) UpdateFilterClusterMember(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateFilterClusterMember", reflect.TypeOf((*MockDatabase)(nil).UpdateFilterClusterMember), arg0, arg1)
+}
+
 // UpdateMetricsHeartbeat mocks base method.
 func (m *MockDatabase) UpdateMetricsHeartbeat() error {
 	m.ctrl.T.Helper()
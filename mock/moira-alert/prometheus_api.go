@@ -7,6 +7,7 @@ package mock_moira_alert
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
@@ -36,6 +37,27 @@ func (m *MockPrometheusApi) EXPECT() *MockPrometheusApiMockRecorder {
 	return m.recorder
 }
 
+// Query mocks base method.
+func (m *MockPrometheusApi) Query(arg0 context.Context, arg1 string, arg2 time.Time, arg3 ...v1.Option) (model.Value, v1.Warnings, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1, arg2}
+	for _, a := range arg3 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Query", varargs...)
+	ret0, _ := ret[0].(model.Value)
+	ret1, _ := ret[1].(v1.Warnings)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Query indicates an expected call of Query.
+func (mr *MockPrometheusApiMockRecorder) Query(arg0, arg1, arg2 interface{}, arg3 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1, arg2}, arg3...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Query", reflect.TypeOf((*MockPrometheusApi)(nil).Query), varargs...)
+}
+
 // QueryRange mocks base method.
 func (m *MockPrometheusApi) QueryRange(arg0 context.Context, arg1 string, arg2 v1.Range, arg3 ...v1.Option) (model.Value, v1.Warnings, error) {
 	m.ctrl.T.Helper()
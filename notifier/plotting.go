@@ -60,6 +60,10 @@ func (notifier *StandardNotifier) buildNotificationPackagePlots(pkg Notification
 	if pkg.Trigger.ID == "" {
 		return nil, nil
 	}
+	// external triggers have no metrics to render
+	if pkg.Trigger.TriggerSource == moira.External {
+		return nil, nil
+	}
 
 	logger.Info().Msg("Start build plots for package")
 	startTime := time.Now()
@@ -154,7 +158,7 @@ func (notifier *StandardNotifier) evaluateTriggerMetrics(from, to int64, trigger
 	for i, target := range trigger.Targets {
 		i++ // Increase
 		targetName := fmt.Sprintf("t%d", i)
-		timeSeries, fetchErr := fetchAvailableSeries(metricsSource, target, from, to)
+		timeSeries, fetchErr := fetchAvailableSeries(metricsSource, target, from, to, trigger.FetchOptions.WithoutInstant())
 		if fetchErr != nil {
 			return nil, &trigger, fetchErr
 		}
@@ -164,14 +168,14 @@ func (notifier *StandardNotifier) evaluateTriggerMetrics(from, to int64, trigger
 }
 
 // fetchAvailableSeries calls fetch function with realtime alerting and retries on fail without.
-func fetchAvailableSeries(metricsSource metricSource.MetricSource, target string, from, to int64) ([]metricSource.MetricData, error) {
-	realtimeFetchResult, realtimeErr := metricsSource.Fetch(target, from, to, true)
+func fetchAvailableSeries(metricsSource metricSource.MetricSource, target string, from, to int64, options *moira.FetchOptions) ([]metricSource.MetricData, error) {
+	realtimeFetchResult, realtimeErr := metricSource.FetchWithOptions(metricsSource, target, from, to, true, options)
 	if realtimeErr == nil {
 		return realtimeFetchResult.GetMetricsData(), nil
 	}
 	var errFailedWithPanic local.ErrEvaluateTargetFailedWithPanic
 	if ok := errors.As(realtimeErr, &errFailedWithPanic); ok {
-		fetchResult, err := metricsSource.Fetch(target, from, to, false)
+		fetchResult, err := metricSource.FetchWithOptions(metricsSource, target, from, to, false, options)
 		if err != nil {
 			return nil, errFetchAvailableSeriesFailed{realtimeErr: errFailedWithPanic.Error(), storedErr: err.Error()}
 		}
@@ -207,7 +207,7 @@ func TestFetchAvailableSeries(t *testing.T) {
 				source.EXPECT().Fetch("testTarget", int64(17), int64(67), true).Return(result, nil).Times(1),
 				result.EXPECT().GetMetricsData().Return(nil).Times(1),
 			)
-			_, err := fetchAvailableSeries(source, target, from, to)
+			_, err := fetchAvailableSeries(source, target, from, to, nil)
 			So(err, ShouldBeNil)
 		})
 
@@ -218,7 +218,7 @@ func TestFetchAvailableSeries(t *testing.T) {
 				source.EXPECT().Fetch("testTarget", int64(17), int64(67), false).Return(result, nil).Times(1),
 				result.EXPECT().GetMetricsData().Return(nil).Times(1),
 			)
-			_, err = fetchAvailableSeries(source, target, from, to)
+			_, err = fetchAvailableSeries(source, target, from, to, nil)
 			So(err, ShouldBeNil)
 		})
 
@@ -229,7 +229,7 @@ func TestFetchAvailableSeries(t *testing.T) {
 				source.EXPECT().Fetch("testTarget", int64(17), int64(67), true).Return(nil, err).Times(1),
 				source.EXPECT().Fetch("testTarget", int64(17), int64(67), false).Return(nil, secondErr).Times(1),
 			)
-			_, err = fetchAvailableSeries(source, target, from, to)
+			_, err = fetchAvailableSeries(source, target, from, to, nil)
 			So(err, ShouldNotBeNil)
 		})
 
@@ -238,7 +238,7 @@ func TestFetchAvailableSeries(t *testing.T) {
 			gomock.InOrder(
 				source.EXPECT().Fetch("testTarget", int64(17), int64(67), true).Return(nil, err).Times(1),
 			)
-			_, err = fetchAvailableSeries(source, target, from, to)
+			_, err = fetchAvailableSeries(source, target, from, to, nil)
 			So(err, ShouldNotBeNil)
 		})
 	})
@@ -6,10 +6,15 @@ import (
 	"strings"
 
 	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/senders/alertmanager"
 	"github.com/moira-alert/moira/senders/discord"
+	"github.com/moira-alert/moira/senders/googlechat"
+	"github.com/moira-alert/moira/senders/gotify"
 	"github.com/moira-alert/moira/senders/mail"
+	"github.com/moira-alert/moira/senders/matrix"
 	"github.com/moira-alert/moira/senders/mattermost"
 	"github.com/moira-alert/moira/senders/msteams"
+	"github.com/moira-alert/moira/senders/ntfy"
 	"github.com/moira-alert/moira/senders/opsgenie"
 	"github.com/moira-alert/moira/senders/pagerduty"
 	"github.com/moira-alert/moira/senders/pushover"
@@ -20,25 +25,32 @@ import (
 	"github.com/moira-alert/moira/senders/twilio"
 	"github.com/moira-alert/moira/senders/victorops"
 	"github.com/moira-alert/moira/senders/webhook"
+	"github.com/moira-alert/moira/senders/zulip"
 	// "github.com/moira-alert/moira/senders/kontur"
 )
 
 const (
-	mailSender        = "mail"
-	pushoverSender    = "pushover"
-	discordSender     = "discord"
-	scriptSender      = "script"
-	selfStateSender   = "selfstate"
-	slackSender       = "slack"
-	telegramSender    = "telegram"
-	twilioSmsSender   = "twilio sms"
-	twilioVoiceSender = "twilio voice"
-	webhookSender     = "webhook"
-	opsgenieSender    = "opsgenie"
-	victoropsSender   = "victorops"
-	pagerdutySender   = "pagerduty"
-	msTeamsSender     = "msteams"
-	mattermostSender  = "mattermost"
+	mailSender         = "mail"
+	pushoverSender     = "pushover"
+	discordSender      = "discord"
+	scriptSender       = "script"
+	selfStateSender    = "selfstate"
+	slackSender        = "slack"
+	telegramSender     = "telegram"
+	twilioSmsSender    = "twilio sms"
+	twilioVoiceSender  = "twilio voice"
+	webhookSender      = "webhook"
+	opsgenieSender     = "opsgenie"
+	victoropsSender    = "victorops"
+	pagerdutySender    = "pagerduty"
+	msTeamsSender      = "msteams"
+	mattermostSender   = "mattermost"
+	matrixSender       = "matrix"
+	googleChatSender   = "googlechat"
+	zulipSender        = "zulip"
+	ntfySender         = "ntfy"
+	gotifySender       = "gotify"
+	alertmanagerSender = "alertmanager"
 )
 
 var (
@@ -62,23 +74,35 @@ func (notifier *StandardNotifier) RegisterSenders(connector moira.Database) erro
 		case discordSender:
 			err = notifier.RegisterSender(senderSettings, &discord.Sender{DataBase: connector})
 		case slackSender:
-			err = notifier.RegisterSender(senderSettings, &slack.Sender{})
+			err = notifier.RegisterSender(senderSettings, &slack.Sender{DataBase: connector})
 		case telegramSender:
 			err = notifier.RegisterSender(senderSettings, &telegram.Sender{DataBase: connector})
 		case msTeamsSender:
 			err = notifier.RegisterSender(senderSettings, &msteams.Sender{})
 		case pagerdutySender:
-			err = notifier.RegisterSender(senderSettings, &pagerduty.Sender{ImageStores: notifier.imageStores})
+			err = notifier.RegisterSender(senderSettings, &pagerduty.Sender{DataBase: connector, ImageStores: notifier.imageStores})
 		case twilioSmsSender, twilioVoiceSender:
 			err = notifier.RegisterSender(senderSettings, &twilio.Sender{})
 		case webhookSender:
-			err = notifier.RegisterSender(senderSettings, &webhook.Sender{})
+			err = notifier.RegisterSender(senderSettings, &webhook.Sender{ImageStores: notifier.imageStores})
 		case opsgenieSender:
-			err = notifier.RegisterSender(senderSettings, &opsgenie.Sender{ImageStores: notifier.imageStores})
+			err = notifier.RegisterSender(senderSettings, &opsgenie.Sender{DataBase: connector, ImageStores: notifier.imageStores})
 		case victoropsSender:
-			err = notifier.RegisterSender(senderSettings, &victorops.Sender{ImageStores: notifier.imageStores})
+			err = notifier.RegisterSender(senderSettings, &victorops.Sender{DataBase: connector, ImageStores: notifier.imageStores})
 		case mattermostSender:
 			err = notifier.RegisterSender(senderSettings, &mattermost.Sender{})
+		case matrixSender:
+			err = notifier.RegisterSender(senderSettings, &matrix.Sender{})
+		case googleChatSender:
+			err = notifier.RegisterSender(senderSettings, &googlechat.Sender{})
+		case zulipSender:
+			err = notifier.RegisterSender(senderSettings, &zulip.Sender{})
+		case ntfySender:
+			err = notifier.RegisterSender(senderSettings, &ntfy.Sender{})
+		case gotifySender:
+			err = notifier.RegisterSender(senderSettings, &gotify.Sender{ImageStores: notifier.imageStores})
+		case alertmanagerSender:
+			err = notifier.RegisterSender(senderSettings, &alertmanager.Sender{DataBase: connector})
 		// case "email":
 		// 	err = notifier.RegisterSender(senderSettings, &kontur.MailSender{})
 		// case "phone":
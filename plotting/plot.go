@@ -65,7 +65,7 @@ func (plot *Plot) GetRenderable(targetName string, trigger *moira.Trigger, metri
 		plotSeries = append(plotSeries, curveSeries)
 	}
 
-	thresholdSeriesList := getThresholdSeriesList(trigger, plot.theme, limits)
+	thresholdSeriesList := getThresholdSeriesList(getMetricsThresholdsTrigger(trigger, metricsData), plot.theme, limits)
 	plotSeries = append(plotSeries, thresholdSeriesList...)
 
 	gridStyle := plot.theme.GetGridStyle()
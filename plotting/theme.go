@@ -1,10 +1,15 @@
 package plotting
 
 import (
+	"fmt"
+	"sync"
+
 	"github.com/golang/freetype/truetype"
 
 	"github.com/moira-alert/moira"
 	"github.com/moira-alert/moira/plotting/fonts"
+	"github.com/moira-alert/moira/plotting/themes/colorblind"
+	"github.com/moira-alert/moira/plotting/themes/custom"
 	"github.com/moira-alert/moira/plotting/themes/dark"
 	"github.com/moira-alert/moira/plotting/themes/light"
 )
@@ -14,6 +19,39 @@ const (
 	lightPlotTheme = "light"
 )
 
+var (
+	customThemesMutex sync.RWMutex
+	customThemes      = make(map[string]custom.Config)
+)
+
+// RegisterThemes validates themes defined in configuration and makes them available by name.
+// Previously registered custom themes are replaced. Built-in theme names can not be overridden.
+func RegisterThemes(configs []custom.Config) error {
+	themeFont, err := getDefaultFont()
+	if err != nil {
+		return err
+	}
+	themes := make(map[string]custom.Config, len(configs))
+	for _, config := range configs {
+		switch config.Name {
+		case darkPlotTheme, lightPlotTheme, colorblind.Name:
+			return fmt.Errorf("theme %s: can not override built-in theme", config.Name)
+		}
+		if _, ok := themes[config.Name]; ok {
+			return fmt.Errorf("theme %s: defined more than once", config.Name)
+		}
+		if _, err := custom.NewTheme(themeFont, config); err != nil {
+			return err
+		}
+		themes[config.Name] = config
+	}
+
+	customThemesMutex.Lock()
+	customThemes = themes
+	customThemesMutex.Unlock()
+	return nil
+}
+
 // getPlotTheme returns plot theme.
 func getPlotTheme(plotTheme string) (moira.PlotTheme, error) {
 	// TODO: rewrite light theme
@@ -23,12 +61,20 @@ func getPlotTheme(plotTheme string) (moira.PlotTheme, error) {
 	if err != nil {
 		return nil, err
 	}
+	if config, ok := getCustomTheme(plotTheme); ok {
+		return custom.NewTheme(themeFont, config)
+	}
 	switch plotTheme {
 	case darkPlotTheme:
 		theme, err = dark.NewTheme(themeFont)
 		if err != nil {
 			return nil, err
 		}
+	case colorblind.Name:
+		theme, err = colorblind.NewTheme(themeFont)
+		if err != nil {
+			return nil, err
+		}
 	case lightPlotTheme:
 		fallthrough
 	default:
@@ -40,6 +86,13 @@ func getPlotTheme(plotTheme string) (moira.PlotTheme, error) {
 	return theme, nil
 }
 
+func getCustomTheme(name string) (custom.Config, bool) {
+	customThemesMutex.RLock()
+	defer customThemesMutex.RUnlock()
+	config, ok := customThemes[name]
+	return config, ok
+}
+
 // getDefaultFont returns default font.
 func getDefaultFont() (*truetype.Font, error) {
 	ttf, err := truetype.Parse(fonts.DejaVuSans)
@@ -0,0 +1,83 @@
+package plotting
+
+import (
+	"testing"
+
+	"github.com/moira-alert/go-chart/drawing"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/moira-alert/moira/plotting/themes/colorblind"
+	"github.com/moira-alert/moira/plotting/themes/custom"
+	"github.com/moira-alert/moira/plotting/themes/dark"
+	"github.com/moira-alert/moira/plotting/themes/light"
+)
+
+func TestGetPlotTheme(t *testing.T) {
+	defer func() {
+		_ = RegisterThemes(nil)
+	}()
+
+	Convey("Built-in themes", t, func() {
+		theme, err := getPlotTheme(darkPlotTheme)
+		So(err, ShouldBeNil)
+		So(theme, ShouldHaveSameTypeAs, &dark.PlotTheme{})
+
+		theme, err = getPlotTheme("unknown")
+		So(err, ShouldBeNil)
+		So(theme, ShouldHaveSameTypeAs, &light.PlotTheme{})
+
+		theme, err = getPlotTheme(colorblind.Name)
+		So(err, ShouldBeNil)
+		So(theme, ShouldHaveSameTypeAs, &custom.PlotTheme{})
+		curveStyle, _ := theme.GetSerieStyles(0)
+		So(curveStyle.StrokeColor, ShouldResemble, drawing.ColorFromHex("0072b2").WithAlpha(90))
+	})
+
+	Convey("Custom themes", t, func() {
+		Convey("Registered theme is selectable by name", func() {
+			err := RegisterThemes([]custom.Config{{
+				Name:            "solarized",
+				BackgroundColor: "#fdf6e3",
+				CurveColors:     []string{"268bd2", "2aa198"},
+				ErrorColor:      "dc322f",
+			}})
+			So(err, ShouldBeNil)
+
+			theme, err := getPlotTheme("solarized")
+			So(err, ShouldBeNil)
+			So(theme.GetCanvasStyle().FillColor, ShouldResemble, drawing.ColorFromHex("fdf6e3"))
+			So(theme.GetThresholdStyle("ERROR").StrokeColor, ShouldResemble, drawing.ColorFromHex("dc322f").WithAlpha(90))
+			So(theme.GetThresholdStyle("WARN").StrokeColor, ShouldResemble, drawing.ColorFromHex("cccc00").WithAlpha(90))
+			curveStyle, _ := theme.GetSerieStyles(3)
+			So(curveStyle.StrokeColor, ShouldResemble, drawing.ColorFromHex("2aa198").WithAlpha(90))
+		})
+
+		Convey("Re-registration replaces previous themes", func() {
+			So(RegisterThemes([]custom.Config{{Name: "first", CurveColors: []string{"fff"}}}), ShouldBeNil)
+			So(RegisterThemes([]custom.Config{{Name: "second", CurveColors: []string{"fff"}}}), ShouldBeNil)
+
+			theme, err := getPlotTheme("first")
+			So(err, ShouldBeNil)
+			So(theme, ShouldHaveSameTypeAs, &light.PlotTheme{})
+		})
+
+		Convey("Invalid themes are rejected", func() {
+			So(RegisterThemes([]custom.Config{{Name: "valid", CurveColors: []string{"fff"}}}), ShouldBeNil)
+
+			invalid := [][]custom.Config{
+				{{Name: "", CurveColors: []string{"fff"}}},
+				{{Name: "no-curves"}},
+				{{Name: "bad-color", CurveColors: []string{"xyz123"}}},
+				{{Name: "bad-bg", BackgroundColor: "ffff", CurveColors: []string{"fff"}}},
+				{{Name: darkPlotTheme, CurveColors: []string{"fff"}}},
+				{{Name: "twice", CurveColors: []string{"fff"}}, {Name: "twice", CurveColors: []string{"000"}}},
+			}
+			for _, configs := range invalid {
+				So(RegisterThemes(configs), ShouldNotBeNil)
+			}
+
+			_, ok := getCustomTheme("valid")
+			So(ok, ShouldBeTrue)
+		})
+	})
+}
@@ -0,0 +1,25 @@
+package colorblind
+
+import (
+	"github.com/golang/freetype/truetype"
+
+	"github.com/moira-alert/moira/plotting/themes/custom"
+)
+
+// Name is the name of the built-in color-blind friendly theme.
+const Name = "colorblind"
+
+// NewTheme returns light theme using Okabe-Ito palette distinguishable with common forms of color blindness.
+func NewTheme(themeFont *truetype.Font) (*custom.PlotTheme, error) {
+	return custom.NewTheme(themeFont, custom.Config{
+		Name:            Name,
+		BackgroundColor: `ffffff`,
+		GridColor:       `1f1d1d`,
+		FontColor:       `333333`,
+		CurveColors: []string{
+			`0072b2`, `e69f00`, `009e73`, `cc79a7`, `56b4e9`, `d55e00`, `f0e442`, `000000`,
+		},
+		ErrorColor: `d55e00`,
+		WarnColor:  `e69f00`,
+	})
+}
@@ -0,0 +1,271 @@
+package custom
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/golang/freetype/truetype"
+	"github.com/moira-alert/go-chart"
+	"github.com/moira-alert/go-chart/drawing"
+)
+
+const (
+	defaultFontSizePrimary   = 10
+	defaultFontSizeSecondary = 8
+	defaultFontSizeTitle     = 15
+	defaultGridWidth         = 0.03
+	defaultErrorColor        = `8b0000`
+	defaultWarnColor         = `cccc00`
+	defaultBackgroundColor   = `ffffff`
+	defaultGridColor         = `1f1d1d`
+)
+
+// Config describes colors and font sizes of a theme defined in configuration.
+// Colors are hex strings without leading '#', e.g. `ffffff`.
+type Config struct {
+	// Name is used to select theme via plotting settings of subscription or render request
+	Name string
+	// BackgroundColor is used to fill canvas and background. Default is ffffff
+	BackgroundColor string
+	// FontColor is used for title, legend, axes and annotations. Default is chart alternate gray
+	FontColor string
+	// GridColor is used for grid strokes. Default is 1f1d1d
+	GridColor string
+	// GridWidth is grid stroke width. Default is 0.03
+	GridWidth float64
+	// FontSizePrimary is used for y axis. Default is 10
+	FontSizePrimary float64
+	// FontSizeSecondary is used for x axis, legend and annotations. Default is 8
+	FontSizeSecondary float64
+	// FontSizeTitle is used for plot title. Default is 15
+	FontSizeTitle float64
+	// CurveColors is a list of colors used for curves one by one
+	CurveColors []string
+	// ErrorColor is used for ERROR thresholds. Default is 8b0000
+	ErrorColor string
+	// WarnColor is used for WARN thresholds. Default is cccc00
+	WarnColor string
+}
+
+// PlotTheme implements moira.PlotTheme interface.
+type PlotTheme struct {
+	font              *truetype.Font
+	fontSizePrimary   float64
+	fontSizeSecondary float64
+	fontSizeTitle     float64
+	fontColor         drawing.Color
+	bgColor           drawing.Color
+	gridColor         drawing.Color
+	gridWidth         float64
+	errorColor        drawing.Color
+	warnColor         drawing.Color
+	curveColors       []drawing.Color
+}
+
+// NewTheme returns theme built from given config.
+func NewTheme(themeFont *truetype.Font, config Config) (*PlotTheme, error) {
+	if config.Name == "" {
+		return nil, fmt.Errorf("theme name is required")
+	}
+	if len(config.CurveColors) == 0 {
+		return nil, fmt.Errorf("theme %s: at least one curve color is required", config.Name)
+	}
+
+	theme := &PlotTheme{
+		font:              themeFont,
+		fontSizePrimary:   orDefaultFloat(config.FontSizePrimary, defaultFontSizePrimary),
+		fontSizeSecondary: orDefaultFloat(config.FontSizeSecondary, defaultFontSizeSecondary),
+		fontSizeTitle:     orDefaultFloat(config.FontSizeTitle, defaultFontSizeTitle),
+		gridWidth:         orDefaultFloat(config.GridWidth, defaultGridWidth),
+		fontColor:         chart.ColorAlternateGray,
+		curveColors:       make([]drawing.Color, 0, len(config.CurveColors)),
+	}
+
+	var err error
+	if theme.bgColor, err = parseColor(config.Name, "background color", config.BackgroundColor, defaultBackgroundColor); err != nil {
+		return nil, err
+	}
+	if theme.gridColor, err = parseColor(config.Name, "grid color", config.GridColor, defaultGridColor); err != nil {
+		return nil, err
+	}
+	if theme.errorColor, err = parseColor(config.Name, "error color", config.ErrorColor, defaultErrorColor); err != nil {
+		return nil, err
+	}
+	if theme.warnColor, err = parseColor(config.Name, "warn color", config.WarnColor, defaultWarnColor); err != nil {
+		return nil, err
+	}
+	if config.FontColor != "" {
+		if theme.fontColor, err = parseColor(config.Name, "font color", config.FontColor, ""); err != nil {
+			return nil, err
+		}
+	}
+	for _, curveColor := range config.CurveColors {
+		color, err := parseColor(config.Name, "curve color", curveColor, "")
+		if err != nil {
+			return nil, err
+		}
+		theme.curveColors = append(theme.curveColors, color)
+	}
+
+	return theme, nil
+}
+
+// GetTitleStyle returns title style.
+func (theme *PlotTheme) GetTitleStyle() chart.Style {
+	return chart.Style{
+		Show:        true,
+		Font:        theme.font,
+		FontSize:    theme.fontSizeTitle,
+		FontColor:   theme.fontColor,
+		FillColor:   theme.bgColor,
+		StrokeColor: theme.bgColor,
+	}
+}
+
+// GetGridStyle returns grid style.
+func (theme *PlotTheme) GetGridStyle() chart.Style {
+	return chart.Style{
+		Show:        true,
+		StrokeColor: theme.gridColor,
+		StrokeWidth: theme.gridWidth,
+	}
+}
+
+// GetCanvasStyle returns canvas style.
+func (theme *PlotTheme) GetCanvasStyle() chart.Style {
+	return chart.Style{
+		FillColor: theme.bgColor,
+	}
+}
+
+// GetBackgroundStyle returns background style.
+func (theme *PlotTheme) GetBackgroundStyle(maxMarkLen int) chart.Style {
+	verticalShift := 40
+	horizontalShift := 20
+	if maxMarkLen > 4 { //nolint
+		horizontalShift = horizontalShift / 2 //nolint
+	}
+	return chart.Style{
+		FillColor: theme.bgColor,
+		Padding: chart.Box{
+			Top:    verticalShift,
+			Bottom: verticalShift,
+			Left:   horizontalShift,
+			Right:  horizontalShift + (maxMarkLen * 6),
+		},
+	}
+}
+
+// GetThresholdStyle returns threshold style.
+func (theme *PlotTheme) GetThresholdStyle(thresholdType string) chart.Style {
+	thresholdColor := theme.getThresholdColor(thresholdType)
+	return chart.Style{
+		Show:        true,
+		StrokeWidth: 1,
+		StrokeColor: thresholdColor.WithAlpha(90), //nolint
+		FillColor:   thresholdColor.WithAlpha(20), //nolint
+	}
+}
+
+// GetAnnotationStyle returns annotation style.
+func (theme *PlotTheme) GetAnnotationStyle(thresholdType string) chart.Style {
+	var rightBoxDimension int
+	if thresholdType == "WARN" {
+		rightBoxDimension = 9
+	}
+	return chart.Style{
+		Show:        true,
+		Padding:     chart.Box{Right: rightBoxDimension},
+		Font:        theme.font,
+		FontSize:    theme.fontSizeSecondary,
+		FontColor:   theme.fontColor,
+		StrokeColor: theme.fontColor,
+		FillColor:   theme.getThresholdColor(thresholdType).WithAlpha(20), //nolint
+	}
+}
+
+// GetSerieStyles returns curve and single point styles.
+func (theme *PlotTheme) GetSerieStyles(curveInd int) (chart.Style, chart.Style) {
+	curveColor := theme.curveColors[curveInd%len(theme.curveColors)]
+	curveWidth := float64(1)
+	curveStyle := chart.Style{
+		Show:        true,
+		StrokeWidth: curveWidth,
+		StrokeColor: curveColor.WithAlpha(90), //nolint
+		FillColor:   curveColor.WithAlpha(20), //nolint
+	}
+	pointStyle := chart.Style{
+		Show:        true,
+		StrokeWidth: chart.Disabled,
+		DotWidth:    curveWidth / 2,           //nolint
+		DotColor:    curveColor.WithAlpha(90), //nolint
+	}
+	return curveStyle, pointStyle
+}
+
+// GetLegendStyle returns legend style.
+func (theme *PlotTheme) GetLegendStyle() chart.Style {
+	return chart.Style{
+		Font:        theme.font,
+		FontSize:    theme.fontSizeSecondary,
+		FontColor:   theme.fontColor,
+		FillColor:   drawing.ColorTransparent,
+		StrokeColor: drawing.ColorTransparent,
+	}
+}
+
+// GetXAxisStyle returns x axis style.
+func (theme *PlotTheme) GetXAxisStyle() chart.Style {
+	return chart.Style{
+		Show:        true,
+		Font:        theme.font,
+		FontSize:    theme.fontSizeSecondary,
+		FontColor:   theme.fontColor,
+		StrokeColor: theme.bgColor,
+	}
+}
+
+// GetYAxisStyle returns y axis style.
+func (theme *PlotTheme) GetYAxisStyle() chart.Style {
+	return chart.Style{
+		Show:        true,
+		Font:        theme.font,
+		FontSize:    theme.fontSizePrimary,
+		FontColor:   theme.fontColor,
+		StrokeColor: theme.bgColor,
+	}
+}
+
+func (theme *PlotTheme) getThresholdColor(thresholdType string) drawing.Color {
+	switch thresholdType {
+	case "ERROR":
+		return theme.errorColor
+	case "WARN":
+		return theme.warnColor
+	}
+	return drawing.ColorTransparent
+}
+
+func orDefaultFloat(value, defaultValue float64) float64 {
+	if value <= 0 {
+		return defaultValue
+	}
+	return value
+}
+
+// parseColor validates hex color and converts it to drawing.Color.
+func parseColor(themeName, field, value, defaultValue string) (drawing.Color, error) {
+	if value == "" {
+		value = defaultValue
+	}
+	hex := strings.TrimPrefix(value, "#")
+	if len(hex) != 3 && len(hex) != 6 {
+		return drawing.Color{}, fmt.Errorf("theme %s: invalid %s %q", themeName, field, value)
+	}
+	for _, char := range hex {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", char) {
+			return drawing.Color{}, fmt.Errorf("theme %s: invalid %s %q", themeName, field, value)
+		}
+	}
+	return drawing.ColorFromHex(hex), nil
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/moira-alert/go-chart"
 	"github.com/moira-alert/moira"
+	metricSource "github.com/moira-alert/moira/metric_source"
 )
 
 const (
@@ -52,6 +53,36 @@ func getThresholdSeriesList(trigger *moira.Trigger, theme moira.PlotTheme, limit
 	return thresholdSeriesList
 }
 
+// getMetricsThresholdsTrigger returns trigger with thresholds of plotted metrics, taking threshold overrides into account.
+// If plotted metrics have different thresholds, none of them are drawn.
+func getMetricsThresholdsTrigger(trigger *moira.Trigger, metricsData []metricSource.MetricData) *moira.Trigger {
+	if len(trigger.ThresholdOverrides) == 0 || len(metricsData) == 0 {
+		return trigger
+	}
+
+	thresholds := trigger.GetMetricThresholds(metricsData[0].Name)
+	for _, metricData := range metricsData[1:] {
+		metricThresholds := trigger.GetMetricThresholds(metricData.Name)
+		if !isEqualThreshold(thresholds.WarnValue, metricThresholds.WarnValue) ||
+			!isEqualThreshold(thresholds.ErrorValue, metricThresholds.ErrorValue) {
+			thresholds = moira.MetricThresholds{}
+			break
+		}
+	}
+
+	metricsTrigger := *trigger
+	metricsTrigger.WarnValue = thresholds.WarnValue
+	metricsTrigger.ErrorValue = thresholds.ErrorValue
+	return &metricsTrigger
+}
+
+func isEqualThreshold(first, second *float64) bool {
+	if first == nil || second == nil {
+		return first == second
+	}
+	return *first == *second
+}
+
 // generateThresholds returns thresholds available for plot.
 func generateThresholds(trigger *moira.Trigger, limits plotLimits) []*threshold {
 	thresholds := make([]*threshold, 0)
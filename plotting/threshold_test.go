@@ -8,6 +8,7 @@ import (
 	. "github.com/smartystreets/goconvey/convey"
 
 	"github.com/moira-alert/moira"
+	metricSource "github.com/moira-alert/moira/metric_source"
 )
 
 const (
@@ -431,3 +432,37 @@ func TestGenerateThresholds(t *testing.T) {
 		})
 	}
 }
+
+// TestGetMetricsThresholdsTrigger tests threshold overrides are drawn on plot of metrics with the same thresholds.
+func TestGetMetricsThresholdsTrigger(t *testing.T) {
+	warnValue, errorValue, overrideErrorValue := float64(10), float64(20), float64(50)
+	trigger := &moira.Trigger{
+		TriggerType: moira.RisingTrigger,
+		WarnValue:   &warnValue,
+		ErrorValue:  &errorValue,
+		ThresholdOverrides: []moira.ThresholdOverride{
+			{Metric: "servers.db-*.cpu", ErrorValue: &overrideErrorValue},
+		},
+	}
+
+	Convey("Metrics with override thresholds", t, func() {
+		metricsData := []metricSource.MetricData{
+			*metricSource.MakeMetricData("servers.db-1.cpu", []float64{1}, 60, 0),
+			*metricSource.MakeMetricData("servers.db-2.cpu", []float64{1}, 60, 0),
+		}
+		metricsTrigger := getMetricsThresholdsTrigger(trigger, metricsData)
+		So(*metricsTrigger.WarnValue, ShouldEqual, warnValue)
+		So(*metricsTrigger.ErrorValue, ShouldEqual, overrideErrorValue)
+		So(*trigger.ErrorValue, ShouldEqual, errorValue)
+	})
+
+	Convey("Metrics with different thresholds", t, func() {
+		metricsData := []metricSource.MetricData{
+			*metricSource.MakeMetricData("servers.db-1.cpu", []float64{1}, 60, 0),
+			*metricSource.MakeMetricData("servers.web-1.cpu", []float64{1}, 60, 0),
+		}
+		metricsTrigger := getMetricsThresholdsTrigger(trigger, metricsData)
+		So(metricsTrigger.WarnValue, ShouldBeNil)
+		So(metricsTrigger.ErrorValue, ShouldBeNil)
+	})
+}
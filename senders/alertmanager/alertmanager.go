@@ -0,0 +1,278 @@
+package alertmanager
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/senders"
+)
+
+const (
+	alertsPath       = "/api/v2/alerts"
+	defaultAlertTTL  = time.Hour
+	printEventsCount = 5
+
+	alertNameLabel = "alertname"
+	triggerIDLabel = "moira_trigger_id"
+	metricLabel    = "metric"
+	receiverLabel  = "receiver"
+)
+
+var (
+	invalidLabelChars = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+	reservedLabels    = map[string]bool{alertNameLabel: true, triggerIDLabel: true, metricLabel: true}
+)
+
+// Structure that represents the Alertmanager configuration in the YAML file.
+type config struct {
+	URL      string `mapstructure:"url"`
+	User     string `mapstructure:"user"`
+	Password string `mapstructure:"password"`
+	FrontURI string `mapstructure:"front_uri"`
+	DedupBy  string `mapstructure:"dedup_by"`
+	// Firing alerts are resolved by Alertmanager after this duration unless Moira sends the trigger again,
+	// endsAt is moved forward on every send, so alerts of deleted triggers do not stay firing for long
+	AlertTTL string `mapstructure:"alert_ttl"`
+}
+
+// Sender posts Moira events to Prometheus Alertmanager as alerts.
+// Contact value is a comma separated list of extra labels used for routing, e.g. "team=db,env=prod",
+// a value without labels, e.g. "db", is set as receiver label.
+type Sender struct {
+	DataBase  moira.Database
+	url       string
+	user      string
+	password  string
+	frontURI  string
+	dedupMode senders.DedupMode
+	alertTTL  time.Duration
+	logger    moira.Logger
+	location  *time.Location
+	client    *http.Client
+}
+
+// postableAlert is an alert of Alertmanager API v2.
+type postableAlert struct {
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations,omitempty"`
+	StartsAt     string            `json:"startsAt,omitempty"`
+	EndsAt       string            `json:"endsAt,omitempty"`
+	GeneratorURL string            `json:"generatorURL,omitempty"`
+}
+
+// Init read yaml config.
+func (sender *Sender) Init(senderSettings interface{}, logger moira.Logger, location *time.Location, dateTimeFormat string) error {
+	var cfg config
+	err := mapstructure.Decode(senderSettings, &cfg)
+	if err != nil {
+		return fmt.Errorf("failed to decode senderSettings to alertmanager config: %w", err)
+	}
+
+	if cfg.URL == "" {
+		return fmt.Errorf("can not read alertmanager url from config")
+	}
+
+	sender.dedupMode, err = senders.ParseDedupMode(cfg.DedupBy)
+	if err != nil {
+		return fmt.Errorf("failed to read alertmanager dedup_by: %w", err)
+	}
+
+	sender.alertTTL = defaultAlertTTL
+	if cfg.AlertTTL != "" {
+		sender.alertTTL, err = time.ParseDuration(cfg.AlertTTL)
+		if err != nil || sender.alertTTL <= 0 {
+			return fmt.Errorf("invalid alertmanager alert_ttl %q", cfg.AlertTTL)
+		}
+	}
+
+	sender.url = strings.TrimSuffix(cfg.URL, "/")
+	sender.user = cfg.User
+	sender.password = cfg.Password
+	sender.frontURI = cfg.FrontURI
+	sender.logger = logger
+	sender.location = location
+	sender.client = &http.Client{
+		Timeout: time.Duration(30) * time.Second, //nolint
+	}
+	return nil
+}
+
+// SendEvents implements Sender interface Send.
+// Incidents recovered to OK are posted with endsAt, so Alertmanager resolves the alerts.
+func (sender *Sender) SendEvents(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData, plots [][]byte, throttled bool) error {
+	contactLabels, err := parseContactLabels(contact.Value)
+	if err != nil {
+		return moira.NewSenderBrokenContactError(err)
+	}
+
+	now := time.Now()
+	dedupMode := senders.GetContactDedupMode(contact, sender.dedupMode)
+	incidents, err := senders.GroupIncidents(sender.DataBase, events, trigger, dedupMode, throttled)
+	if err != nil {
+		return err
+	}
+	alerts := make([]postableAlert, 0, len(incidents))
+	for _, incident := range incidents {
+		alerts = append(alerts, sender.buildAlert(incident, dedupMode, contactLabels, trigger, throttled, now))
+	}
+
+	requestBody, err := json.Marshal(alerts)
+	if err != nil {
+		return fmt.Errorf("failed to marshal alerts: %w", err)
+	}
+
+	request, err := http.NewRequestWithContext(context.Background(), http.MethodPost, sender.url+alertsPath, bytes.NewReader(requestBody))
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", "Moira")
+	if sender.user != "" && sender.password != "" {
+		request.SetBasicAuth(sender.user, sender.password)
+	}
+
+	response, err := sender.client.Do(request)
+	if err != nil {
+		return fmt.Errorf("failed to perform request: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode >= http.StatusOK && response.StatusCode < http.StatusMultipleChoices {
+		return nil
+	}
+	body, _ := io.ReadAll(response.Body)
+	return fmt.Errorf("failed to send %s event message to alertmanager: status %d, %s", trigger.ID, response.StatusCode, string(body))
+}
+
+// buildAlert builds alert of the incident, alert is resolved by endsAt only if the incident is resolved,
+// otherwise endsAt is set alert_ttl ahead of now.
+func (sender *Sender) buildAlert(incident senders.Incident, dedupMode senders.DedupMode, contactLabels map[string]string, trigger moira.TriggerData, throttled bool, now time.Time) postableAlert {
+	events := incident.Events
+
+	labels := make(map[string]string, len(contactLabels)+len(trigger.Tags)+3) //nolint
+	for _, tag := range trigger.Tags {
+		name, value := tagToLabel(tag)
+		if name != "" && !reservedLabels[name] {
+			labels[name] = value
+		}
+	}
+	for name, value := range contactLabels {
+		labels[name] = value
+	}
+	labels[alertNameLabel] = trigger.Name
+	if labels[alertNameLabel] == "" {
+		labels[alertNameLabel] = trigger.ID
+	}
+	labels[triggerIDLabel] = trigger.ID
+	if dedupMode == senders.DedupByMetric && len(events) > 0 {
+		labels[metricLabel] = events[0].Metric
+	}
+
+	state := events.GetCurrentState(throttled)
+	if dedupMode == senders.DedupByMetric && len(events) > 0 {
+		state = events[len(events)-1].State
+	}
+
+	annotations := map[string]string{
+		"summary": fmt.Sprintf("%s %s", state, trigger.Name),
+		"state":   string(state),
+		"events":  sender.buildEventsAnnotation(events, throttled),
+	}
+	if trigger.Desc != "" {
+		annotations["description"] = trigger.Desc
+	}
+
+	alert := postableAlert{
+		Labels:       labels,
+		Annotations:  annotations,
+		GeneratorURL: trigger.GetTriggerURI(sender.frontURI),
+	}
+	if len(events) > 0 {
+		alert.StartsAt = formatTime(time.Unix(events[0].Timestamp, 0))
+	}
+	if incident.Resolved {
+		endsAt := now
+		if len(events) > 0 {
+			endsAt = time.Unix(events[len(events)-1].Timestamp, 0)
+		}
+		alert.EndsAt = formatTime(endsAt)
+	} else {
+		alert.EndsAt = formatTime(now.Add(sender.alertTTL))
+	}
+	return alert
+}
+
+func (sender *Sender) buildEventsAnnotation(events moira.NotificationEvents, throttled bool) string {
+	lines, leftOut := senders.LimitEventLines(events, sender.location, printEventsCount, -1)
+
+	var message strings.Builder
+	for _, line := range lines {
+		message.WriteString(line + "\n")
+	}
+	if leftOut > 0 {
+		message.WriteString(senders.FormatMoreEvents(leftOut) + "\n")
+	}
+	if throttled {
+		message.WriteString("Please, fix your system or tune this trigger to generate less events.\n")
+	}
+	return strings.TrimSuffix(message.String(), "\n")
+}
+
+// tagToLabel converts trigger tag to Alertmanager label.
+// Tags like "key=value" or "key:value" become key label with value, other tags become labels with "true" value.
+func tagToLabel(tag string) (string, string) {
+	name, value, found := strings.Cut(tag, "=")
+	if !found {
+		name, value, found = strings.Cut(tag, ":")
+	}
+	if !found {
+		value = "true"
+	}
+	return labelName(name), value
+}
+
+// labelName replaces characters not allowed in Prometheus label names.
+func labelName(name string) string {
+	name = invalidLabelChars.ReplaceAllString(strings.TrimSpace(name), "_")
+	if name != "" && name[0] >= '0' && name[0] <= '9' {
+		name = "_" + name
+	}
+	return name
+}
+
+// parseContactLabels parses contact value "key=value,key2=value2" to labels.
+func parseContactLabels(value string) (map[string]string, error) {
+	labels := make(map[string]string)
+	if !strings.Contains(value, "=") {
+		if receiver := strings.TrimSpace(value); receiver != "" {
+			labels[receiverLabel] = receiver
+		}
+		return labels, nil
+	}
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		name, labelValue, found := strings.Cut(pair, "=")
+		name = strings.TrimSpace(name)
+		if !found || name == "" || labelName(name) != name || reservedLabels[name] {
+			return nil, fmt.Errorf("invalid alertmanager label %q in contact %q", pair, value)
+		}
+		labels[name] = strings.TrimSpace(labelValue)
+	}
+	return labels, nil
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
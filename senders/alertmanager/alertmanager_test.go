@@ -0,0 +1,245 @@
+package alertmanager
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/moira-alert/moira"
+	logging "github.com/moira-alert/moira/logging/zerolog_adapter"
+	mock_moira_alert "github.com/moira-alert/moira/mock/moira-alert"
+	"github.com/moira-alert/moira/senders"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// fakeAlertmanager stores alerts posted to Alertmanager API v2.
+type fakeAlertmanager struct {
+	mutex  sync.Mutex
+	status int
+	alerts []postableAlert
+	auth   string
+}
+
+func (am *fakeAlertmanager) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	am.mutex.Lock()
+	defer am.mutex.Unlock()
+	if r.Method != http.MethodPost || r.URL.Path != alertsPath {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	am.auth = r.Header.Get("Authorization")
+	if am.status != http.StatusOK {
+		w.WriteHeader(am.status)
+		w.Write([]byte(`{"code":400,"message":"bad alert"}`)) //nolint
+		return
+	}
+	var alerts []postableAlert
+	if err := json.NewDecoder(r.Body).Decode(&alerts); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	am.alerts = append(am.alerts, alerts...)
+	w.WriteHeader(http.StatusOK)
+}
+
+func TestInit(t *testing.T) {
+	logger, _ := logging.ConfigureLog("stdout", "debug", "test", true)
+	Convey("Init tests", t, func() {
+		sender := Sender{}
+		Convey("Empty url", func() {
+			err := sender.Init(map[string]interface{}{}, logger, nil, "")
+			So(err, ShouldResemble, errors.New("can not read alertmanager url from config"))
+		})
+		Convey("Invalid dedup_by", func() {
+			err := sender.Init(map[string]interface{}{"url": "http://alertmanager:9093", "dedup_by": "tag"}, logger, nil, "")
+			So(err, ShouldNotBeNil)
+		})
+		Convey("Invalid alert_ttl", func() {
+			err := sender.Init(map[string]interface{}{"url": "http://alertmanager:9093", "alert_ttl": "day"}, logger, nil, "")
+			So(err, ShouldResemble, errors.New(`invalid alertmanager alert_ttl "day"`))
+		})
+		Convey("Default settings", func() {
+			err := sender.Init(map[string]interface{}{"url": "http://alertmanager:9093/"}, logger, nil, "")
+			So(err, ShouldBeNil)
+			So(sender.url, ShouldEqual, "http://alertmanager:9093")
+			So(sender.dedupMode, ShouldEqual, senders.DedupByTrigger)
+			So(sender.alertTTL, ShouldEqual, defaultAlertTTL)
+		})
+		Convey("Full settings", func() {
+			err := sender.Init(map[string]interface{}{
+				"url":       "http://alertmanager:9093",
+				"user":      "moira",
+				"password":  "secret",
+				"dedup_by":  "metric",
+				"alert_ttl": "1h",
+				"front_uri": "http://moira.url",
+			}, logger, nil, "")
+			So(err, ShouldBeNil)
+			So(sender.dedupMode, ShouldEqual, senders.DedupByMetric)
+			So(sender.alertTTL, ShouldEqual, time.Hour)
+			So(sender.frontURI, ShouldEqual, "http://moira.url")
+		})
+	})
+}
+
+func TestSendEvents(t *testing.T) {
+	logger, _ := logging.ConfigureLog("stdout", "info", "test", true)
+	trigger := moira.TriggerData{
+		ID:   "TriggerID",
+		Name: "Disk space",
+		Desc: "Free some space",
+		Tags: []string{"prod", "team=db", "service:mysql", "alertname=override"},
+	}
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+
+	Convey("Send events to fake alertmanager", t, func() {
+		alertmanager := &fakeAlertmanager{status: http.StatusOK}
+		server := httptest.NewServer(alertmanager)
+		defer server.Close()
+
+		sender := Sender{DataBase: dataBase}
+		err := sender.Init(map[string]interface{}{
+			"url":       server.URL,
+			"user":      "moira",
+			"password":  "secret",
+			"front_uri": "http://moira.url",
+		}, logger, time.UTC, "")
+		So(err, ShouldBeNil)
+
+		Convey("Firing alert", func() {
+			events := moira.NotificationEvents{
+				{Metric: "host1.disk", Values: map[string]float64{"t1": 95}, Timestamp: 150000000, OldState: moira.StateOK, State: moira.StateERROR},
+			}
+			before := time.Now()
+			err = sender.SendEvents(events, moira.ContactData{Value: "env=prod, team=infra"}, trigger, nil, false)
+			So(err, ShouldBeNil)
+			So(alertmanager.auth, ShouldStartWith, "Basic ")
+			So(alertmanager.alerts, ShouldHaveLength, 1)
+
+			alert := alertmanager.alerts[0]
+			So(alert.Labels, ShouldResemble, map[string]string{
+				"alertname":        "Disk space",
+				"moira_trigger_id": "TriggerID",
+				"prod":             "true",
+				"team":             "infra",
+				"service":          "mysql",
+				"env":              "prod",
+			})
+			So(alert.Annotations, ShouldResemble, map[string]string{
+				"summary":     "ERROR Disk space",
+				"state":       "ERROR",
+				"description": "Free some space",
+				"events":      "02:40 (GMT+00:00): host1.disk = 95 (OK to ERROR)",
+			})
+			So(alert.GeneratorURL, ShouldEqual, "http://moira.url/trigger/TriggerID")
+			So(alert.StartsAt, ShouldEqual, "1974-10-03T02:40:00Z")
+
+			endsAt, err := time.Parse(time.RFC3339, alert.EndsAt)
+			So(err, ShouldBeNil)
+			So(endsAt, ShouldHappenOnOrAfter, before.Add(defaultAlertTTL).Truncate(time.Second))
+		})
+
+		Convey("Recovered alert has endsAt of recovery", func() {
+			dataBase.EXPECT().GetTriggerLastCheck(trigger.ID).Return(moira.CheckData{State: moira.StateOK}, nil)
+			events := moira.NotificationEvents{
+				{Metric: "host1.disk", Values: map[string]float64{"t1": 50}, Timestamp: 150000060, OldState: moira.StateERROR, State: moira.StateOK},
+			}
+			err = sender.SendEvents(events, moira.ContactData{Value: "db"}, trigger, nil, false)
+			So(err, ShouldBeNil)
+			So(alertmanager.alerts, ShouldHaveLength, 1)
+			So(alertmanager.alerts[0].Labels["receiver"], ShouldEqual, "db")
+			So(alertmanager.alerts[0].EndsAt, ShouldEqual, "1974-10-03T02:41:00Z")
+		})
+
+		Convey("Recovered metric does not resolve alert of trigger with other failing metrics", func() {
+			dataBase.EXPECT().GetTriggerLastCheck(trigger.ID).Return(moira.CheckData{
+				State: moira.StateOK,
+				Metrics: map[string]moira.MetricState{
+					"host1.disk": {State: moira.StateOK},
+					"host2.disk": {State: moira.StateERROR},
+				},
+			}, nil)
+			events := moira.NotificationEvents{
+				{Metric: "host1.disk", Values: map[string]float64{"t1": 50}, Timestamp: 150000060, OldState: moira.StateERROR, State: moira.StateOK},
+			}
+			before := time.Now()
+			err = sender.SendEvents(events, moira.ContactData{Value: "db"}, trigger, nil, false)
+			So(err, ShouldBeNil)
+			So(alertmanager.alerts, ShouldHaveLength, 1)
+
+			endsAt, err := time.Parse(time.RFC3339, alertmanager.alerts[0].EndsAt)
+			So(err, ShouldBeNil)
+			So(endsAt, ShouldHappenOnOrAfter, before.Add(defaultAlertTTL).Truncate(time.Second))
+		})
+
+		Convey("Contact dedup_by overrides sender dedup mode", func() {
+			events := moira.NotificationEvents{
+				{Metric: "host1.disk", Values: map[string]float64{"t1": 50}, Timestamp: 150000060, OldState: moira.StateERROR, State: moira.StateOK},
+			}
+			err = sender.SendEvents(events, moira.ContactData{Value: "db", DedupBy: "metric"}, trigger, nil, false)
+			So(err, ShouldBeNil)
+			So(alertmanager.alerts, ShouldHaveLength, 1)
+			So(alertmanager.alerts[0].Labels["metric"], ShouldEqual, "host1.disk")
+			So(alertmanager.alerts[0].EndsAt, ShouldEqual, "1974-10-03T02:41:00Z")
+		})
+
+		Convey("Alert per metric", func() {
+			sender.dedupMode = senders.DedupByMetric
+			events := moira.NotificationEvents{
+				{Metric: "host1.disk", Values: map[string]float64{"t1": 95}, Timestamp: 150000000, OldState: moira.StateOK, State: moira.StateERROR},
+				{Metric: "host2.disk", Values: map[string]float64{"t1": 50}, Timestamp: 150000000, OldState: moira.StateWARN, State: moira.StateOK},
+			}
+			err = sender.SendEvents(events, moira.ContactData{Value: "db"}, trigger, nil, false)
+			So(err, ShouldBeNil)
+			So(alertmanager.alerts, ShouldHaveLength, 2)
+
+			So(alertmanager.alerts[0].Labels["metric"], ShouldEqual, "host1.disk")
+			So(alertmanager.alerts[0].Annotations["state"], ShouldEqual, "ERROR")
+			So(alertmanager.alerts[1].Labels["metric"], ShouldEqual, "host2.disk")
+			So(alertmanager.alerts[1].Annotations["state"], ShouldEqual, "OK")
+			So(alertmanager.alerts[1].EndsAt, ShouldEqual, "1974-10-03T02:40:00Z")
+		})
+
+		Convey("Invalid contact labels", func() {
+			events := moira.NotificationEvents{{Metric: "host1.disk", State: moira.StateERROR}}
+			err = sender.SendEvents(events, moira.ContactData{Value: "team=db,bad label=x"}, trigger, nil, false)
+			var brokenContactErr moira.SenderBrokenContactError
+			So(errors.As(err, &brokenContactErr), ShouldBeTrue)
+			So(alertmanager.alerts, ShouldBeEmpty)
+		})
+
+		Convey("Error response", func() {
+			alertmanager.status = http.StatusBadRequest
+			events := moira.NotificationEvents{{Metric: "host1.disk", State: moira.StateERROR}}
+			err = sender.SendEvents(events, moira.ContactData{Value: "db"}, trigger, nil, false)
+			So(err, ShouldResemble, errors.New(`failed to send TriggerID event message to alertmanager: status 400, {"code":400,"message":"bad alert"}`))
+		})
+	})
+}
+
+func TestTagToLabel(t *testing.T) {
+	Convey("Tag to label", t, func() {
+		cases := map[string][2]string{
+			"prod":         {"prod", "true"},
+			"team=db":      {"team", "db"},
+			"service:db":   {"service", "db"},
+			"my-tag.name":  {"my_tag_name", "true"},
+			"1st":          {"_1st", "true"},
+			"url=a=b":      {"url", "a=b"},
+			"kv:with=both": {"kv_with", "both"},
+		}
+		for tag, expected := range cases {
+			name, value := tagToLabel(tag)
+			So(name, ShouldEqual, expected[0])
+			So(value, ShouldEqual, expected[1])
+		}
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/bwmarrin/discordgo"
 	"github.com/mitchellh/mapstructure"
 	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/senders"
 	"github.com/moira-alert/moira/worker"
 )
 
@@ -21,16 +22,19 @@ const (
 type config struct {
 	Token    string `mapstructure:"token"`
 	FrontURI string `mapstructure:"front_uri"`
+	// Go template used instead of default message format, contact template takes precedence
+	MessageTemplate string `mapstructure:"message_template"`
 }
 
 // Sender implements moira sender interface for discord.
 type Sender struct {
-	DataBase  moira.Database
-	logger    moira.Logger
-	location  *time.Location
-	session   *discordgo.Session
-	frontURI  string
-	botUserID string
+	DataBase        moira.Database
+	logger          moira.Logger
+	location        *time.Location
+	session         *discordgo.Session
+	frontURI        string
+	botUserID       string
+	messageTemplate string
 }
 
 // Init reads the yaml config.
@@ -44,12 +48,16 @@ func (sender *Sender) Init(senderSettings interface{}, logger moira.Logger, loca
 	if cfg.Token == "" {
 		return fmt.Errorf("cannot read the discord token from the config")
 	}
+	if err = senders.ValidateMessageTemplate("discord", cfg.MessageTemplate); err != nil {
+		return err
+	}
 	sender.session, err = discordgo.New("Bot " + cfg.Token)
 	if err != nil {
 		return fmt.Errorf("error creating discord session: %w", err)
 	}
 	sender.logger = logger
 	sender.frontURI = cfg.FrontURI
+	sender.messageTemplate = cfg.MessageTemplate
 	sender.location = location
 
 	handleMsg := func(s *discordgo.Session, m *discordgo.MessageCreate) {
@@ -21,7 +21,12 @@ var mdHeaderRegex = regexp.MustCompile(`(?m)^\s*#{1,}\s*(?P<headertext>[^#\n]+)$
 // SendEvents implements pushover build and send message functionality.
 func (sender *Sender) SendEvents(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData, plots [][]byte, throttled bool) error {
 	data := &discordgo.MessageSend{}
-	data.Content = sender.buildMessage(events, trigger, throttled)
+	message, ok := senders.PopulateMessageTemplate(sender.messageTemplate, events, contact, trigger, throttled, sender.frontURI, sender.logger)
+	if ok {
+		data.Content = senders.TruncateMessage(message, messageMaxCharacters)
+	} else {
+		data.Content = sender.buildMessage(events, trigger, throttled)
+	}
 	if len(plots) > 0 {
 		data.File = sender.buildPlot(plots[0])
 		data.Embed = &discordgo.MessageEmbed{
@@ -0,0 +1,44 @@
+package senders
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/moira-alert/moira"
+)
+
+// FormatEventLine formats event as "time: metric = values (old state to state)" followed by the event message if any.
+func FormatEventLine(event moira.NotificationEvent, location *time.Location) string {
+	line := fmt.Sprintf("%s: %s = %s (%s to %s)", event.FormatTimestamp(location, moira.DefaultTimeFormat), event.Metric, event.GetMetricsValues(moira.DefaultNotificationSettings), event.OldState, event.State)
+	if msg := event.CreateMessage(location); len(msg) > 0 {
+		line += fmt.Sprintf(". %s", msg)
+	}
+	return line
+}
+
+// FormatMoreEvents returns the line about events, which did not fit into the message.
+func FormatMoreEvents(count int) string {
+	return fmt.Sprintf("...and %d more events.", count)
+}
+
+// LimitEventLines formats first events with FormatEventLine and returns lines and the count of events left out.
+// At most maxEvents lines are returned, zero or negative maxEvents means no limit.
+// Every line takes one more char for separator and lines together with FormatMoreEvents line of left out events
+// fit into maxChars chars, negative maxChars means no limit.
+func LimitEventLines(events moira.NotificationEvents, location *time.Location, maxEvents, maxChars int) ([]string, int) {
+	lines := make([]string, 0, len(events))
+	linesLen := 0
+	for i, event := range events {
+		if maxEvents > 0 && i == maxEvents {
+			return lines, len(events) - i
+		}
+		line := FormatEventLine(event, location)
+		lineLen := len([]rune(line)) + 1
+		if maxChars >= 0 && linesLen+lineLen > maxChars-len([]rune(FormatMoreEvents(len(events)-i))) {
+			return lines, len(events) - i
+		}
+		lines = append(lines, line)
+		linesLen += lineLen
+	}
+	return lines, 0
+}
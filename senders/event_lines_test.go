@@ -0,0 +1,48 @@
+package senders
+
+import (
+	"testing"
+	"time"
+
+	"github.com/moira-alert/moira"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestLimitEventLines(t *testing.T) {
+	message := "message"
+	event := moira.NotificationEvent{Metric: "Metric", Values: map[string]float64{"t1": 1}, Timestamp: 150000000, OldState: moira.StateOK, State: moira.StateWARN}
+	eventWithMessage := event
+	eventWithMessage.Message = &message
+	line := "02:40 (GMT+00:00): Metric = 1 (OK to WARN)"
+	events := moira.NotificationEvents{event, event, event}
+
+	Convey("Event lines", t, func() {
+		Convey("Event line contains event message", func() {
+			So(FormatEventLine(event, time.UTC), ShouldEqual, line)
+			So(FormatEventLine(eventWithMessage, time.UTC), ShouldEqual, line+". message")
+		})
+
+		Convey("Lines are not limited", func() {
+			lines, leftOut := LimitEventLines(events, time.UTC, 0, -1)
+			So(lines, ShouldResemble, []string{line, line, line})
+			So(leftOut, ShouldEqual, 0)
+		})
+
+		Convey("Lines are limited by count", func() {
+			lines, leftOut := LimitEventLines(events, time.UTC, 2, -1)
+			So(lines, ShouldResemble, []string{line, line})
+			So(leftOut, ShouldEqual, 1)
+		})
+
+		Convey("Lines are limited by chars with tail", func() {
+			maxChars := 2*(len(line)+1) + len(FormatMoreEvents(1))
+			lines, leftOut := LimitEventLines(events, time.UTC, 0, maxChars)
+			So(lines, ShouldResemble, []string{line, line})
+			So(leftOut, ShouldEqual, 1)
+
+			lines, leftOut = LimitEventLines(events, time.UTC, 0, maxChars-1)
+			So(lines, ShouldResemble, []string{line})
+			So(leftOut, ShouldEqual, 2)
+		})
+	})
+}
@@ -0,0 +1,64 @@
+package googlechat
+
+// Message is the body of Google Chat incoming webhook request, it has either text or cards.
+// See https://developers.google.com/chat/api/reference/rest/v1/cards
+type Message struct {
+	Text    string   `json:"text,omitempty"`
+	CardsV2 []CardV2 `json:"cardsV2,omitempty"`
+}
+
+// CardV2 wraps card with its id.
+type CardV2 struct {
+	CardID string `json:"cardId"`
+	Card   Card   `json:"card"`
+}
+
+// Card models a card with header and sections of widgets.
+type Card struct {
+	Header   Header    `json:"header"`
+	Sections []Section `json:"sections"`
+}
+
+// Header models a card header.
+type Header struct {
+	Title    string `json:"title"`
+	Subtitle string `json:"subtitle,omitempty"`
+}
+
+// Section models a card section, header is optional.
+type Section struct {
+	Header  string   `json:"header,omitempty"`
+	Widgets []Widget `json:"widgets"`
+}
+
+// Widget models a card widget, only one of the fields is set.
+type Widget struct {
+	TextParagraph *TextParagraph `json:"textParagraph,omitempty"`
+	ButtonList    *ButtonList    `json:"buttonList,omitempty"`
+}
+
+// TextParagraph is a widget with text formatted with simple HTML tags.
+type TextParagraph struct {
+	Text string `json:"text"`
+}
+
+// ButtonList is a widget with buttons.
+type ButtonList struct {
+	Buttons []Button `json:"buttons"`
+}
+
+// Button models a button opening link.
+type Button struct {
+	Text    string  `json:"text"`
+	OnClick OnClick `json:"onClick"`
+}
+
+// OnClick describes the action of button.
+type OnClick struct {
+	OpenLink OpenLink `json:"openLink"`
+}
+
+// OpenLink describes the link to open.
+type OpenLink struct {
+	URL string `json:"url"`
+}
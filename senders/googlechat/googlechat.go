@@ -0,0 +1,217 @@
+package googlechat
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/senders"
+	"github.com/russross/blackfriday/v2"
+)
+
+const (
+	webhookBaseURL    = "https://chat.googleapis.com/v1/spaces/"
+	cardID            = "moira-alert"
+	openURIMessage    = "View in Moira"
+	descriptionHeader = "Description"
+	eventsHeader      = "Events"
+	defaultMaxEvents  = 20
+	// Google Chat accepts messages up to 32000 bytes, so text is limited with a margin for multibyte characters
+	messageMaxCharacters = 4096
+	throttleWarningMsg   = "Please, <b>fix your system or tune this trigger</b> to generate less events."
+)
+
+const (
+	Green  = "#008000"
+	Orange = "#ffa500"
+	Red    = "#ff0000"
+	Black  = "#000000"
+)
+
+// Google Chat text supports only a few HTML tags, so markdown rendered tags are replaced with supported ones.
+var descriptionTagsReplacer = strings.NewReplacer(
+	"<p>", "", "</p>", "<br>",
+	"<strong>", "<b>", "</strong>", "</b>",
+	"<em>", "<i>", "</em>", "</i>",
+	"<del>", "<strike>", "</del>", "</strike>",
+	"<code>", "<font face=\"monospace\">", "</code>", "</font>",
+	"<pre>", "", "</pre>", "",
+	"<br />", "<br>",
+)
+
+// Structure that represents the Google Chat configuration in the YAML file.
+type config struct {
+	FrontURI        string `mapstructure:"front_uri"`
+	MaxEvents       int    `mapstructure:"max_events"`
+	MessageTemplate string `mapstructure:"message_template"`
+}
+
+// Sender implements moira sender interface via Google Chat incoming webhooks.
+// Contact value is the webhook URL of the space.
+type Sender struct {
+	frontURI        string
+	maxEvents       int
+	messageTemplate string
+	logger          moira.Logger
+	location        *time.Location
+	client          *http.Client
+}
+
+// Init initialises settings required for full functionality.
+func (sender *Sender) Init(senderSettings interface{}, logger moira.Logger, location *time.Location, dateTimeFormat string) error {
+	var cfg config
+	err := mapstructure.Decode(senderSettings, &cfg)
+	if err != nil {
+		return fmt.Errorf("failed to decode senderSettings to googlechat config: %w", err)
+	}
+	if err = senders.ValidateMessageTemplate("googlechat", cfg.MessageTemplate); err != nil {
+		return err
+	}
+
+	sender.logger = logger
+	sender.location = location
+	sender.frontURI = cfg.FrontURI
+	sender.maxEvents = cfg.MaxEvents
+	sender.messageTemplate = cfg.MessageTemplate
+	if sender.maxEvents == 0 {
+		sender.maxEvents = defaultMaxEvents
+	}
+	sender.client = &http.Client{
+		Timeout: time.Duration(30) * time.Second, //nolint
+	}
+	return nil
+}
+
+// SendEvents implements Sender interface Send.
+func (sender *Sender) SendEvents(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData, plots [][]byte, throttled bool) error {
+	if !strings.HasPrefix(contact.Value, webhookBaseURL) {
+		return moira.NewSenderBrokenContactError(fmt.Errorf("%s is an invalid google chat webhook url", contact.Value))
+	}
+
+	message := sender.buildMessage(events, contact, trigger, throttled)
+	requestBody, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+
+	request, err := http.NewRequestWithContext(context.Background(), http.MethodPost, contact.Value, bytes.NewBuffer(requestBody))
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	request.Header.Set("Content-Type", "application/json; charset=UTF-8")
+	request.Header.Set("User-Agent", "Moira")
+
+	response, err := sender.client.Do(request)
+	if err != nil {
+		return fmt.Errorf("failed to perform request: %w", err)
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if response.StatusCode == http.StatusNotFound {
+		return moira.NewSenderBrokenContactError(fmt.Errorf("google chat space not found: %s", string(body)))
+	}
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("server responded with a non 2xx code: %d, %s", response.StatusCode, string(body))
+	}
+	return nil
+}
+
+func (sender *Sender) buildMessage(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData, throttled bool) Message {
+	if message, ok := senders.PopulateMessageTemplate(sender.messageTemplate, events, contact, trigger, throttled, sender.frontURI, sender.logger); ok {
+		return Message{Text: senders.TruncateMessage(message, messageMaxCharacters)}
+	}
+
+	state := events.GetCurrentState(throttled)
+
+	title := string(state)
+	if trigger.Name != "" {
+		title += " " + trigger.Name
+	}
+
+	widgets := []Widget{{
+		TextParagraph: &TextParagraph{
+			Text: fmt.Sprintf(`<font color="%s"><b>%s</b></font> %s`, getColourForState(state), state, html.EscapeString(trigger.Name)),
+		},
+	}}
+	if triggerURI := trigger.GetTriggerURI(sender.frontURI); triggerURI != "" {
+		widgets = append(widgets, Widget{
+			ButtonList: &ButtonList{Buttons: []Button{{
+				Text:    openURIMessage,
+				OnClick: OnClick{OpenLink: OpenLink{URL: triggerURI}},
+			}}},
+		})
+	}
+	sections := []Section{{Widgets: widgets}}
+
+	if trigger.Desc != "" {
+		sections = append(sections, Section{
+			Header:  descriptionHeader,
+			Widgets: []Widget{{TextParagraph: &TextParagraph{Text: sender.buildDescription(trigger)}}},
+		})
+	}
+
+	sections = append(sections, Section{
+		Header:  eventsHeader,
+		Widgets: []Widget{{TextParagraph: &TextParagraph{Text: sender.buildEventsString(events, throttled)}}},
+	})
+
+	return Message{
+		CardsV2: []CardV2{{
+			CardID: cardID,
+			Card: Card{
+				Header: Header{
+					Title:    title,
+					Subtitle: trigger.GetTags(),
+				},
+				Sections: sections,
+			},
+		}},
+	}
+}
+
+// buildDescription renders markdown description to HTML like msteams sender does, keeping tags supported by Google Chat.
+func (sender *Sender) buildDescription(trigger moira.TriggerData) string {
+	description := string(blackfriday.Run([]byte(trigger.Desc)))
+	description = descriptionTagsReplacer.Replace(strings.TrimSpace(description))
+	return strings.TrimSuffix(description, "<br>")
+}
+
+// buildEventsString builds lines from moira events limited by maxEvents.
+func (sender *Sender) buildEventsString(events moira.NotificationEvents, throttled bool) string {
+	lines, leftOut := senders.LimitEventLines(events, sender.location, sender.maxEvents, -1)
+	for i := range lines {
+		lines[i] = html.EscapeString(lines[i])
+	}
+	if leftOut > 0 {
+		lines = append(lines, senders.FormatMoreEvents(leftOut))
+	}
+	if throttled {
+		lines = append(lines, throttleWarningMsg)
+	}
+	return strings.Join(lines, "<br>")
+}
+
+func getColourForState(state moira.State) string {
+	switch state {
+	case moira.StateOK:
+		return Green
+	case moira.StateWARN:
+		return Orange
+	case moira.StateERROR, moira.StateEXCEPTION:
+		return Red
+	default:
+		return Black
+	}
+}
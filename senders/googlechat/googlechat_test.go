@@ -0,0 +1,115 @@
+package googlechat
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/moira-alert/moira"
+	logging "github.com/moira-alert/moira/logging/zerolog_adapter"
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/h2non/gock.v1"
+)
+
+const testWebhookURL = "https://chat.googleapis.com/v1/spaces/AAAA/messages?key=key&token=token"
+
+func TestInit(t *testing.T) {
+	logger, _ := logging.ConfigureLog("stdout", "debug", "test", true)
+	Convey("Init tests", t, func() {
+		sender := Sender{}
+		Convey("Default max events", func() {
+			err := sender.Init(map[string]interface{}{}, logger, nil, "")
+			So(err, ShouldBeNil)
+			So(sender.maxEvents, ShouldEqual, defaultMaxEvents)
+		})
+		Convey("Unlimited events", func() {
+			err := sender.Init(map[string]interface{}{"max_events": -1, "front_uri": "http://moira.url"}, logger, nil, "")
+			So(err, ShouldBeNil)
+			So(sender.maxEvents, ShouldEqual, -1)
+			So(sender.frontURI, ShouldEqual, "http://moira.url")
+		})
+	})
+}
+
+func TestSendEvents(t *testing.T) {
+	logger, _ := logging.ConfigureLog("stdout", "info", "test", true)
+	sender := Sender{}
+	_ = sender.Init(map[string]interface{}{"front_uri": "http://moira.url"}, logger, time.UTC, "")
+	events := moira.NotificationEvents{{Metric: "Metric", Values: map[string]float64{"t1": 1}, Timestamp: 150000000, OldState: moira.StateOK, State: moira.StateERROR}}
+	trigger := moira.TriggerData{ID: "TriggerID", Name: "Name"}
+
+	Convey("Send events to google chat", t, func() {
+		Convey("Successful response", func() {
+			defer gock.Off()
+			gock.New("https://chat.googleapis.com").
+				Post("/v1/spaces/AAAA/messages").
+				MatchParam("token", "token").
+				Reply(http.StatusOK).
+				BodyString("{}")
+			err := sender.SendEvents(events, moira.ContactData{Value: testWebhookURL}, trigger, nil, false)
+			So(err, ShouldBeNil)
+			So(gock.IsDone(), ShouldBeTrue)
+		})
+
+		Convey("Error response", func() {
+			defer gock.Off()
+			gock.New("https://chat.googleapis.com").
+				Post("/v1/spaces/AAAA/messages").
+				Reply(http.StatusInternalServerError).
+				BodyString("error")
+			err := sender.SendEvents(events, moira.ContactData{Value: testWebhookURL}, trigger, nil, false)
+			So(err, ShouldResemble, errors.New("server responded with a non 2xx code: 500, error"))
+		})
+
+		Convey("Invalid webhook url", func() {
+			err := sender.SendEvents(events, moira.ContactData{Value: "https://example.com/webhook"}, trigger, nil, false)
+			var brokenContactErr moira.SenderBrokenContactError
+			So(errors.As(err, &brokenContactErr), ShouldBeTrue)
+		})
+	})
+}
+
+func TestBuildMessage(t *testing.T) {
+	sender := Sender{location: time.UTC, frontURI: "http://moira.url", maxEvents: 1}
+	event := moira.NotificationEvent{Metric: "Metric", Values: map[string]float64{"t1": 1}, Timestamp: 150000000, OldState: moira.StateOK, State: moira.StateWARN}
+	trigger := moira.TriggerData{
+		ID:   "TriggerID",
+		Name: "Name",
+		Tags: []string{"tag1"},
+		Desc: "some **bold** text",
+	}
+
+	Convey("Build message", t, func() {
+		message := sender.buildMessage(moira.NotificationEvents{event, event}, moira.ContactData{}, trigger, true)
+		So(message, ShouldResemble, Message{
+			CardsV2: []CardV2{{
+				CardID: cardID,
+				Card: Card{
+					Header: Header{Title: "WARN Name", Subtitle: "[tag1]"},
+					Sections: []Section{
+						{Widgets: []Widget{
+							{TextParagraph: &TextParagraph{Text: `<font color="#ffa500"><b>WARN</b></font> Name`}},
+							{ButtonList: &ButtonList{Buttons: []Button{{
+								Text:    openURIMessage,
+								OnClick: OnClick{OpenLink: OpenLink{URL: "http://moira.url/trigger/TriggerID"}},
+							}}}},
+						}},
+						{Header: descriptionHeader, Widgets: []Widget{
+							{TextParagraph: &TextParagraph{Text: "some <b>bold</b> text"}},
+						}},
+						{Header: eventsHeader, Widgets: []Widget{
+							{TextParagraph: &TextParagraph{Text: "02:40 (GMT+00:00): Metric = 1 (OK to WARN)<br>...and 1 more events.<br>" + throttleWarningMsg}},
+						}},
+					},
+				},
+			}},
+		})
+	})
+
+	Convey("Build message with contact template", t, func() {
+		contact := moira.ContactData{Template: "{{ .Trigger.Name }}: {{ len .Events }} events"}
+		message := sender.buildMessage(moira.NotificationEvents{event, event}, contact, trigger, false)
+		So(message, ShouldResemble, Message{Text: "Name: 2 events"})
+	})
+}
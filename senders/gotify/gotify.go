@@ -0,0 +1,203 @@
+package gotify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/senders"
+)
+
+const (
+	messagePath      = "/message"
+	printEventsCount = 5
+)
+
+// Message priorities as Gotify Android client treats them:
+// 4-7 notify with sound, 8-10 notify with sound and pop up.
+const (
+	priorityDefault = 4
+	priorityHigh    = 7
+	priorityMax     = 10
+)
+
+// Structure that represents the Gotify configuration in the YAML file.
+type config struct {
+	URL             string `mapstructure:"url"`
+	FrontURI        string `mapstructure:"front_uri"`
+	MessageTemplate string `mapstructure:"message_template"`
+}
+
+// Sender implements moira sender interface for self-hosted Gotify server.
+// Contact value is a token of Gotify application the message is sent to.
+type Sender struct {
+	ImageStores          map[string]moira.ImageStore
+	imageStore           moira.ImageStore
+	imageStoreConfigured bool
+	url                  string
+	frontURI             string
+	messageTemplate      string
+	logger               moira.Logger
+	location             *time.Location
+	client               *http.Client
+}
+
+type message struct {
+	Title    string                 `json:"title"`
+	Message  string                 `json:"message"`
+	Priority int                    `json:"priority"`
+	Extras   map[string]interface{} `json:"extras,omitempty"`
+}
+
+// Init read yaml config.
+func (sender *Sender) Init(senderSettings interface{}, logger moira.Logger, location *time.Location, dateTimeFormat string) error {
+	var cfg config
+	err := mapstructure.Decode(senderSettings, &cfg)
+	if err != nil {
+		return fmt.Errorf("failed to decode senderSettings to gotify config: %w", err)
+	}
+
+	if cfg.URL == "" {
+		return fmt.Errorf("can not read gotify url from config")
+	}
+	if err = senders.ValidateMessageTemplate("gotify", cfg.MessageTemplate); err != nil {
+		return err
+	}
+
+	_, sender.imageStore, sender.imageStoreConfigured = senders.ReadImageStoreConfig(senderSettings, sender.ImageStores, logger)
+
+	sender.url = strings.TrimSuffix(cfg.URL, "/")
+	sender.frontURI = cfg.FrontURI
+	sender.messageTemplate = cfg.MessageTemplate
+	sender.logger = logger
+	sender.location = location
+	sender.client = &http.Client{
+		Timeout: time.Duration(30) * time.Second, //nolint
+	}
+	return nil
+}
+
+// SendEvents implements Sender interface Send.
+func (sender *Sender) SendEvents(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData, plots [][]byte, throttled bool) error {
+	if contact.Value == "" {
+		return moira.NewSenderBrokenContactError(fmt.Errorf("gotify application token is empty"))
+	}
+
+	requestBody, err := json.Marshal(sender.buildMessage(events, contact, trigger, plots, throttled))
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	request, err := http.NewRequestWithContext(context.Background(), http.MethodPost, sender.url+messagePath, bytes.NewReader(requestBody))
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	request.Header.Set("X-Gotify-Key", contact.Value)
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", "Moira")
+
+	response, err := sender.client.Do(request)
+	if err != nil {
+		return fmt.Errorf("failed to perform request: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode >= http.StatusOK && response.StatusCode < http.StatusMultipleChoices {
+		return nil
+	}
+	body, _ := io.ReadAll(response.Body)
+	err = fmt.Errorf("failed to send %s event message to gotify: status %d, %s", trigger.ID, response.StatusCode, string(body))
+	if response.StatusCode == http.StatusUnauthorized {
+		return moira.NewSenderBrokenContactError(err)
+	}
+	return err
+}
+
+func (sender *Sender) buildMessage(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData, plots [][]byte, throttled bool) message {
+	body, ok := senders.PopulateMessageTemplate(sender.messageTemplate, events, contact, trigger, throttled, sender.frontURI, sender.logger)
+	if !ok {
+		body = sender.buildBody(events, throttled)
+	}
+	notification := make(map[string]interface{})
+
+	if triggerURI := trigger.GetTriggerURI(sender.frontURI); triggerURI != "" {
+		notification["click"] = map[string]string{"url": triggerURI}
+	}
+
+	if len(plots) > 0 && sender.imageStoreConfigured {
+		imageLink, err := sender.imageStore.StoreImage(plots[0])
+		if err != nil {
+			sender.logger.Warning().
+				Error(err).
+				Msg("Could not store the plot image in the image store")
+		} else {
+			notification["bigImageUrl"] = imageLink
+			body += fmt.Sprintf("\n\n![plot](%s)", imageLink)
+		}
+	}
+
+	extras := map[string]interface{}{
+		"client::display": map[string]string{"contentType": "text/markdown"},
+	}
+	if len(notification) > 0 {
+		extras["client::notification"] = notification
+	}
+
+	return message{
+		Title:    sender.buildTitle(events, trigger, throttled),
+		Message:  body,
+		Priority: getMessagePriority(events),
+		Extras:   extras,
+	}
+}
+
+func (sender *Sender) buildTitle(events moira.NotificationEvents, trigger moira.TriggerData, throttled bool) string {
+	state := events.GetCurrentState(throttled)
+	title := fmt.Sprintf("%s %s", state, trigger.Name)
+	if tags := trigger.GetTags(); tags != "" {
+		title += " " + tags
+	}
+	return fmt.Sprintf("%s (%d)", title, len(events))
+}
+
+func (sender *Sender) buildBody(events moira.NotificationEvents, throttled bool) string {
+	lines, leftOut := senders.LimitEventLines(events, sender.location, printEventsCount, -1)
+
+	var body strings.Builder
+	body.WriteString("```\n")
+	for _, line := range lines {
+		body.WriteString(line + "\n")
+	}
+	body.WriteString("```")
+	if leftOut > 0 {
+		body.WriteString("\n\n" + senders.FormatMoreEvents(leftOut))
+	}
+
+	if throttled {
+		body.WriteString("\n\nPlease, **fix your system or tune this trigger** to generate less events.")
+	}
+	return body.String()
+}
+
+// getMessagePriority returns the highest priority among events states.
+func getMessagePriority(events moira.NotificationEvents) int {
+	priority := priorityDefault
+	for _, event := range events {
+		switch event.State {
+		case moira.StateERROR, moira.StateEXCEPTION:
+			priority = priorityMax
+		case moira.StateWARN, moira.StateNODATA:
+			if priority < priorityHigh {
+				priority = priorityHigh
+			}
+		}
+	}
+	return priority
+}
@@ -0,0 +1,142 @@
+package gotify
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/moira-alert/moira"
+	logging "github.com/moira-alert/moira/logging/zerolog_adapter"
+	mock_moira_alert "github.com/moira-alert/moira/mock/moira-alert"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestInit(t *testing.T) {
+	logger, _ := logging.ConfigureLog("stdout", "debug", "test", true)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	imageStore := mock_moira_alert.NewMockImageStore(mockCtrl)
+
+	Convey("Init tests", t, func() {
+		sender := Sender{ImageStores: map[string]moira.ImageStore{"s3": imageStore}}
+		Convey("Empty url", func() {
+			err := sender.Init(map[string]interface{}{}, logger, nil, "")
+			So(err, ShouldResemble, errors.New("can not read gotify url from config"))
+		})
+		Convey("Without image store", func() {
+			err := sender.Init(map[string]interface{}{"url": "https://gotify.example.com/"}, logger, nil, "")
+			So(err, ShouldBeNil)
+			So(sender.url, ShouldEqual, "https://gotify.example.com")
+			So(sender.imageStoreConfigured, ShouldBeFalse)
+		})
+		Convey("With image store", func() {
+			imageStore.EXPECT().IsEnabled().Return(true)
+			err := sender.Init(map[string]interface{}{"url": "https://gotify.example.com", "image_store": "s3"}, logger, nil, "")
+			So(err, ShouldBeNil)
+			So(sender.imageStoreConfigured, ShouldBeTrue)
+			So(sender.imageStore, ShouldEqual, imageStore)
+		})
+	})
+}
+
+func TestSendEvents(t *testing.T) {
+	logger, _ := logging.ConfigureLog("stdout", "info", "test", true)
+	events := moira.NotificationEvents{{Metric: "Metric", Values: map[string]float64{"t1": 1}, Timestamp: 150000000, OldState: moira.StateOK, State: moira.StateERROR}}
+	trigger := moira.TriggerData{ID: "TriggerID", Name: "Name"}
+
+	Convey("Send events to gotify", t, func() {
+		var request *http.Request
+		var requestMessage message
+		status := http.StatusOK
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			request = r
+			json.NewDecoder(r.Body).Decode(&requestMessage) //nolint
+			w.WriteHeader(status)
+			w.Write([]byte(`{"error":"Unauthorized"}`)) //nolint
+		}))
+		defer server.Close()
+
+		sender := Sender{}
+		err := sender.Init(map[string]interface{}{"url": server.URL}, logger, time.UTC, "")
+		So(err, ShouldBeNil)
+
+		Convey("Successful response", func() {
+			err = sender.SendEvents(events, moira.ContactData{Value: "AppToken"}, trigger, nil, false)
+			So(err, ShouldBeNil)
+			So(request.URL.Path, ShouldEqual, messagePath)
+			So(request.Header.Get("X-Gotify-Key"), ShouldEqual, "AppToken")
+			So(requestMessage.Title, ShouldEqual, "ERROR Name (1)")
+			So(requestMessage.Priority, ShouldEqual, priorityMax)
+		})
+
+		Convey("Invalid application token", func() {
+			status = http.StatusUnauthorized
+			err = sender.SendEvents(events, moira.ContactData{Value: "AppToken"}, trigger, nil, false)
+			var brokenContactErr moira.SenderBrokenContactError
+			So(errors.As(err, &brokenContactErr), ShouldBeTrue)
+		})
+
+		Convey("Error response", func() {
+			status = http.StatusInternalServerError
+			err = sender.SendEvents(events, moira.ContactData{Value: "AppToken"}, trigger, nil, false)
+			So(err, ShouldResemble, errors.New(`failed to send TriggerID event message to gotify: status 500, {"error":"Unauthorized"}`))
+		})
+	})
+}
+
+func TestBuildMessage(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	imageStore := mock_moira_alert.NewMockImageStore(mockCtrl)
+	sender := Sender{location: time.UTC, frontURI: "http://moira.url", imageStore: imageStore, imageStoreConfigured: true}
+	event := moira.NotificationEvent{Metric: "Metric", Values: map[string]float64{"t1": 1}, Timestamp: 150000000, OldState: moira.StateOK, State: moira.StateWARN}
+	trigger := moira.TriggerData{ID: "TriggerID", Name: "Name", Tags: []string{"tag1"}}
+
+	Convey("Build message", t, func() {
+		Convey("With plot", func() {
+			imageStore.EXPECT().StoreImage([]byte("plot")).Return("http://images.url/plot.png", nil)
+			msg := sender.buildMessage(moira.NotificationEvents{event}, moira.ContactData{}, trigger, [][]byte{[]byte("plot")}, true)
+			So(msg, ShouldResemble, message{
+				Title:    "WARN Name [tag1] (1)",
+				Message:  "```\n02:40 (GMT+00:00): Metric = 1 (OK to WARN)\n```\n\nPlease, **fix your system or tune this trigger** to generate less events.\n\n![plot](http://images.url/plot.png)",
+				Priority: priorityHigh,
+				Extras: map[string]interface{}{
+					"client::display": map[string]string{"contentType": "text/markdown"},
+					"client::notification": map[string]interface{}{
+						"click":       map[string]string{"url": "http://moira.url/trigger/TriggerID"},
+						"bigImageUrl": "http://images.url/plot.png",
+					},
+				},
+			})
+		})
+
+		Convey("Image store error", func() {
+			sender.logger, _ = logging.ConfigureLog("stdout", "info", "test", true)
+			imageStore.EXPECT().StoreImage([]byte("plot")).Return("", errors.New("error"))
+			msg := sender.buildMessage(moira.NotificationEvents{event}, moira.ContactData{}, trigger, [][]byte{[]byte("plot")}, false)
+			So(msg.Message, ShouldEqual, "```\n02:40 (GMT+00:00): Metric = 1 (OK to WARN)\n```")
+			So(msg.Extras["client::notification"], ShouldResemble, map[string]interface{}{
+				"click": map[string]string{"url": "http://moira.url/trigger/TriggerID"},
+			})
+		})
+
+		Convey("With contact template", func() {
+			contact := moira.ContactData{Template: "**{{ .Trigger.Name }}** {{ range .Events }}{{ .Metric }}{{ end }}"}
+			msg := sender.buildMessage(moira.NotificationEvents{event}, contact, trigger, nil, false)
+			So(msg.Title, ShouldEqual, "WARN Name [tag1] (1)")
+			So(msg.Message, ShouldEqual, "**Name** Metric")
+		})
+	})
+}
+
+func TestGetMessagePriority(t *testing.T) {
+	Convey("Get message priority", t, func() {
+		So(getMessagePriority(moira.NotificationEvents{{State: moira.StateOK}}), ShouldEqual, priorityDefault)
+		So(getMessagePriority(moira.NotificationEvents{{State: moira.StateOK}, {State: moira.StateNODATA}}), ShouldEqual, priorityHigh)
+		So(getMessagePriority(moira.NotificationEvents{{State: moira.StateEXCEPTION}, {State: moira.StateWARN}}), ShouldEqual, priorityMax)
+	})
+}
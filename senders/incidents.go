@@ -0,0 +1,114 @@
+package senders
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/database"
+)
+
+// DedupMode defines how events are grouped into incidents of on-call services.
+type DedupMode string
+
+const (
+	// DedupByTrigger opens one incident per trigger.
+	DedupByTrigger DedupMode = "trigger"
+	// DedupByMetric opens one incident per trigger metric.
+	DedupByMetric DedupMode = "metric"
+)
+
+// ParseDedupMode validates dedup mode from sender settings or contact, empty value means DedupByTrigger.
+func ParseDedupMode(mode string) (DedupMode, error) {
+	switch DedupMode(mode) {
+	case "", DedupByTrigger:
+		return DedupByTrigger, nil
+	case DedupByMetric:
+		return DedupByMetric, nil
+	}
+	return "", fmt.Errorf("unknown dedup mode %q, use %q or %q", mode, DedupByTrigger, DedupByMetric)
+}
+
+// Incident is a group of events sharing the same dedup key.
+type Incident struct {
+	// DedupKey is stable for trigger or for trigger and metric depending on DedupMode
+	DedupKey string
+	Events   moira.NotificationEvents
+	// Resolved is true if incident has recovered to OK and should be closed
+	Resolved bool
+}
+
+// GetContactDedupMode returns dedup mode of the contact, sender dedup mode is used if contact has no dedup_by.
+func GetContactDedupMode(contact moira.ContactData, senderMode DedupMode) DedupMode {
+	if contact.DedupBy == "" {
+		return senderMode
+	}
+	mode, err := ParseDedupMode(contact.DedupBy)
+	if err != nil {
+		return senderMode
+	}
+	return mode
+}
+
+// GroupIncidents splits events into incidents according to dedup mode.
+// In DedupByTrigger mode incident is resolved only if events current state is OK and trigger last check
+// has no metrics in bad state, events hold only changed metrics, so other metrics may be still failing.
+// In DedupByMetric mode each metric incident is resolved if its last event is OK.
+func GroupIncidents(dataBase moira.Database, events moira.NotificationEvents, trigger moira.TriggerData, mode DedupMode, throttled bool) ([]Incident, error) {
+	if mode != DedupByMetric {
+		resolved := events.GetCurrentState(throttled) == moira.StateOK
+		if resolved {
+			var err error
+			if resolved, err = IsTriggerRecovered(dataBase, trigger.ID); err != nil {
+				return nil, err
+			}
+		}
+		return []Incident{{
+			DedupKey: trigger.ID,
+			Events:   events,
+			Resolved: resolved,
+		}}, nil
+	}
+
+	incidents := make([]Incident, 0)
+	indexes := make(map[string]int)
+	for _, event := range events {
+		index, ok := indexes[event.Metric]
+		if !ok {
+			index = len(incidents)
+			indexes[event.Metric] = index
+			incidents = append(incidents, Incident{DedupKey: metricDedupKey(trigger.ID, event.Metric)})
+		}
+		incidents[index].Events = append(incidents[index].Events, event)
+		incidents[index].Resolved = event.State == moira.StateOK
+	}
+	return incidents, nil
+}
+
+// IsTriggerRecovered returns true if trigger and all its metrics are OK in the last check.
+// Deleted or never checked trigger is considered recovered.
+func IsTriggerRecovered(dataBase moira.Database, triggerID string) (bool, error) {
+	lastCheck, err := dataBase.GetTriggerLastCheck(triggerID)
+	if errors.Is(err, database.ErrNil) {
+		return true, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get trigger %s last check: %w", triggerID, err)
+	}
+	if lastCheck.State != moira.StateOK {
+		return false, nil
+	}
+	for _, metric := range lastCheck.Metrics {
+		if metric.State != moira.StateOK {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func metricDedupKey(triggerID, metric string) string {
+	if metric == "" {
+		return triggerID
+	}
+	return triggerID + ":" + metric
+}
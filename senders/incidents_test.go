@@ -0,0 +1,108 @@
+package senders
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/database"
+	mock_moira_alert "github.com/moira-alert/moira/mock/moira-alert"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestParseDedupMode(t *testing.T) {
+	Convey("Parse dedup mode", t, func() {
+		mode, err := ParseDedupMode("")
+		So(err, ShouldBeNil)
+		So(mode, ShouldEqual, DedupByTrigger)
+
+		mode, err = ParseDedupMode("metric")
+		So(err, ShouldBeNil)
+		So(mode, ShouldEqual, DedupByMetric)
+
+		_, err = ParseDedupMode("tag")
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestGetContactDedupMode(t *testing.T) {
+	Convey("Contact dedup mode overrides sender dedup mode", t, func() {
+		So(GetContactDedupMode(moira.ContactData{}, DedupByMetric), ShouldEqual, DedupByMetric)
+		So(GetContactDedupMode(moira.ContactData{DedupBy: "trigger"}, DedupByMetric), ShouldEqual, DedupByTrigger)
+		So(GetContactDedupMode(moira.ContactData{DedupBy: "metric"}, DedupByTrigger), ShouldEqual, DedupByMetric)
+		So(GetContactDedupMode(moira.ContactData{DedupBy: "tag"}, DedupByTrigger), ShouldEqual, DedupByTrigger)
+	})
+}
+
+func TestGroupIncidents(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+
+	trigger := moira.TriggerData{ID: "TriggerID"}
+	events := moira.NotificationEvents{
+		{Metric: "m1", State: moira.StateERROR},
+		{Metric: "m2", State: moira.StateWARN},
+		{Metric: "m1", State: moira.StateOK},
+	}
+	okEvents := moira.NotificationEvents{{Metric: "m1", State: moira.StateOK}, {Metric: "m2", State: moira.StateOK}}
+
+	Convey("Group incidents by trigger", t, func() {
+		Convey("Failing events do not read last check", func() {
+			incidents, err := GroupIncidents(dataBase, events, trigger, DedupByTrigger, false)
+			So(err, ShouldBeNil)
+			So(incidents, ShouldResemble, []Incident{{DedupKey: "TriggerID", Events: events, Resolved: false}})
+		})
+
+		Convey("Trigger is resolved if all metrics are OK", func() {
+			dataBase.EXPECT().GetTriggerLastCheck(trigger.ID).Return(moira.CheckData{
+				State:   moira.StateOK,
+				Metrics: map[string]moira.MetricState{"m1": {State: moira.StateOK}, "m2": {State: moira.StateOK}},
+			}, nil)
+			incidents, err := GroupIncidents(dataBase, okEvents, trigger, DedupByTrigger, false)
+			So(err, ShouldBeNil)
+			So(incidents, ShouldResemble, []Incident{{DedupKey: "TriggerID", Events: okEvents, Resolved: true}})
+		})
+
+		Convey("Trigger is not resolved if other metrics are still failing", func() {
+			dataBase.EXPECT().GetTriggerLastCheck(trigger.ID).Return(moira.CheckData{
+				State:   moira.StateOK,
+				Metrics: map[string]moira.MetricState{"m1": {State: moira.StateOK}, "m2": {State: moira.StateOK}, "m3": {State: moira.StateERROR}},
+			}, nil)
+			incidents, err := GroupIncidents(dataBase, okEvents, trigger, DedupByTrigger, false)
+			So(err, ShouldBeNil)
+			So(incidents, ShouldResemble, []Incident{{DedupKey: "TriggerID", Events: okEvents, Resolved: false}})
+		})
+
+		Convey("Deleted trigger is resolved", func() {
+			dataBase.EXPECT().GetTriggerLastCheck(trigger.ID).Return(moira.CheckData{}, database.ErrNil)
+			incidents, err := GroupIncidents(dataBase, okEvents, trigger, DedupByTrigger, false)
+			So(err, ShouldBeNil)
+			So(incidents[0].Resolved, ShouldBeTrue)
+		})
+
+		Convey("Last check error", func() {
+			dataBase.EXPECT().GetTriggerLastCheck(trigger.ID).Return(moira.CheckData{}, errors.New("redis is down"))
+			incidents, err := GroupIncidents(dataBase, okEvents, trigger, DedupByTrigger, false)
+			So(err, ShouldNotBeNil)
+			So(incidents, ShouldBeNil)
+		})
+	})
+
+	Convey("Group incidents by metric", t, func() {
+		incidents, err := GroupIncidents(dataBase, events, trigger, DedupByMetric, false)
+		So(err, ShouldBeNil)
+		So(incidents, ShouldResemble, []Incident{
+			{DedupKey: "TriggerID:m1", Events: moira.NotificationEvents{events[0], events[2]}, Resolved: true},
+			{DedupKey: "TriggerID:m2", Events: moira.NotificationEvents{events[1]}, Resolved: false},
+		})
+	})
+
+	Convey("Trigger level events use trigger dedup key", t, func() {
+		triggerEvents := moira.NotificationEvents{{State: moira.StateEXCEPTION}}
+		incidents, err := GroupIncidents(dataBase, triggerEvents, trigger, DedupByMetric, false)
+		So(err, ShouldBeNil)
+		So(incidents, ShouldResemble, []Incident{{DedupKey: "TriggerID", Events: triggerEvents}})
+	})
+}
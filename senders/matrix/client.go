@@ -0,0 +1,130 @@
+package matrix
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	clientAPIPrefix = "/_matrix/client/v3"
+	mediaAPIPrefix  = "/_matrix/media/v3"
+)
+
+// client is minimal Matrix client-server API client.
+type client struct {
+	homeserverURL string
+	accessToken   string
+	httpClient    *http.Client
+	txnCounter    uint64
+}
+
+type errorResponse struct {
+	ErrCode string `json:"errcode"`
+	Error   string `json:"error"`
+}
+
+// apiError is returned when homeserver responds with non 200 status.
+type apiError struct {
+	statusCode int
+	errCode    string
+	message    string
+}
+
+func (err *apiError) Error() string {
+	if err.errCode != "" {
+		return fmt.Sprintf("matrix error %s: %s", err.errCode, err.message)
+	}
+	return fmt.Sprintf("matrix responded with status %d: %s", err.statusCode, err.message)
+}
+
+type resolveAliasResponse struct {
+	RoomID string `json:"room_id"`
+}
+
+type sendEventResponse struct {
+	EventID string `json:"event_id"`
+}
+
+type uploadResponse struct {
+	ContentURI string `json:"content_uri"`
+}
+
+// resolveRoom returns room ID for contact, which is either room ID like !room:server or alias like #room:server.
+func (client *client) resolveRoom(ctx context.Context, room string) (string, error) {
+	if !strings.HasPrefix(room, "#") {
+		return room, nil
+	}
+	var response resolveAliasResponse
+	path := clientAPIPrefix + "/directory/room/" + url.PathEscape(room)
+	if err := client.do(ctx, http.MethodGet, path, "", nil, &response); err != nil {
+		return "", fmt.Errorf("failed to resolve room alias %s: %w", room, err)
+	}
+	return response.RoomID, nil
+}
+
+// sendMessage sends m.room.message event with given content to the room.
+func (client *client) sendMessage(ctx context.Context, roomID string, content interface{}) (string, error) {
+	body, err := json.Marshal(content)
+	if err != nil {
+		return "", err
+	}
+	path := fmt.Sprintf("%s/rooms/%s/send/m.room.message/%s", clientAPIPrefix, url.PathEscape(roomID), client.nextTxnID())
+	var response sendEventResponse
+	if err = client.do(ctx, http.MethodPut, path, "application/json", body, &response); err != nil {
+		return "", err
+	}
+	return response.EventID, nil
+}
+
+// upload stores data in the media repository and returns its mxc:// URI.
+func (client *client) upload(ctx context.Context, data []byte, contentType, filename string) (string, error) {
+	path := mediaAPIPrefix + "/upload?filename=" + url.QueryEscape(filename)
+	var response uploadResponse
+	if err := client.do(ctx, http.MethodPost, path, contentType, data, &response); err != nil {
+		return "", err
+	}
+	return response.ContentURI, nil
+}
+
+func (client *client) do(ctx context.Context, method, path, contentType string, body []byte, result interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, method, client.homeserverURL+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+client.accessToken)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := client.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	responseBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var matrixErr errorResponse
+		if json.Unmarshal(responseBody, &matrixErr) == nil && matrixErr.ErrCode != "" {
+			return &apiError{statusCode: resp.StatusCode, errCode: matrixErr.ErrCode, message: matrixErr.Error}
+		}
+		return &apiError{statusCode: resp.StatusCode, message: string(responseBody)}
+	}
+	return json.Unmarshal(responseBody, result)
+}
+
+// nextTxnID returns transaction id, which must be unique per access token for the homeserver to deduplicate requests.
+func (client *client) nextTxnID() string {
+	return fmt.Sprintf("moira.%d.%d", time.Now().UnixNano(), atomic.AddUint64(&client.txnCounter, 1))
+}
@@ -0,0 +1,3 @@
+// Package matrix is Moira sender for Matrix - open standard for decentralised communication.
+// It uses client-server API of homeserver with access token authentication.
+package matrix
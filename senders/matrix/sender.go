@@ -0,0 +1,258 @@
+package matrix
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"html"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/senders"
+	blackfriday "github.com/russross/blackfriday/v2"
+)
+
+const (
+	messageMaxCharacters = 16_000
+	requestTimeout       = 30 * time.Second
+	htmlFormat           = "org.matrix.custom.html"
+	throttleMsg          = "Please, fix your system or tune this trigger to generate less events."
+)
+
+// Errors which mean that contact will never receive messages.
+var brokenContactErrCodes = map[string]bool{
+	"M_FORBIDDEN": true,
+	"M_NOT_FOUND": true,
+}
+
+// Structure that represents the Matrix configuration in the YAML file.
+type config struct {
+	HomeserverURL   string `mapstructure:"homeserver_url"`
+	AccessToken     string `mapstructure:"access_token"`
+	InsecureTLS     bool   `mapstructure:"insecure_tls"`
+	FrontURI        string `mapstructure:"front_uri"`
+	MessageTemplate string `mapstructure:"message_template"`
+}
+
+// Sender posts messages to Matrix rooms.
+// It implements moira.Sender.
+// Contact value is room ID (!room:server) or room alias (#room:server), bot must be joined to the room.
+type Sender struct {
+	frontURI        string
+	messageTemplate string
+	logger          moira.Logger
+	location        *time.Location
+	client          *client
+}
+
+// textMessage is m.text message content with HTML formatted body.
+type textMessage struct {
+	MsgType       string `json:"msgtype"`
+	Body          string `json:"body"`
+	Format        string `json:"format"`
+	FormattedBody string `json:"formatted_body"`
+}
+
+// imageMessage is m.image message content referencing uploaded media.
+type imageMessage struct {
+	MsgType string    `json:"msgtype"`
+	Body    string    `json:"body"`
+	URL     string    `json:"url"`
+	Info    imageInfo `json:"info"`
+}
+
+type imageInfo struct {
+	MimeType string `json:"mimetype"`
+	Size     int    `json:"size"`
+}
+
+// Init configures Sender.
+func (sender *Sender) Init(senderSettings interface{}, logger moira.Logger, location *time.Location, _ string) error {
+	var cfg config
+	err := mapstructure.Decode(senderSettings, &cfg)
+	if err != nil {
+		return fmt.Errorf("failed to decode senderSettings to matrix config: %w", err)
+	}
+
+	if cfg.HomeserverURL == "" {
+		return fmt.Errorf("can not read Matrix homeserver_url from config")
+	}
+	if cfg.AccessToken == "" {
+		return fmt.Errorf("can not read Matrix access_token from config")
+	}
+	if err = senders.ValidateMessageTemplate("matrix", cfg.MessageTemplate); err != nil {
+		return err
+	}
+
+	sender.client = &client{
+		homeserverURL: strings.TrimSuffix(cfg.HomeserverURL, "/"),
+		accessToken:   cfg.AccessToken,
+		httpClient: &http.Client{
+			Timeout: requestTimeout,
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{
+					InsecureSkipVerify: cfg.InsecureTLS,
+				},
+			},
+		},
+	}
+	sender.frontURI = cfg.FrontURI
+	sender.messageTemplate = cfg.MessageTemplate
+	sender.location = location
+	sender.logger = logger
+	return nil
+}
+
+// SendEvents implements moira.Sender interface.
+func (sender *Sender) SendEvents(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData, plots [][]byte, throttled bool) error {
+	ctx := context.Background()
+
+	roomID, err := sender.client.resolveRoom(ctx, contact.Value)
+	if err != nil {
+		return sender.wrapError(err, trigger.ID, contact.Value)
+	}
+
+	message := sender.buildMessage(events, contact, trigger, throttled)
+	if _, err = sender.client.sendMessage(ctx, roomID, message); err != nil {
+		return sender.wrapError(err, trigger.ID, contact.Value)
+	}
+
+	if len(plots) > 0 {
+		if err = sender.sendPlots(ctx, plots, roomID, trigger.ID); err != nil {
+			sender.logger.Warning().
+				String("trigger_id", trigger.ID).
+				String("contact_value", contact.Value).
+				String("contact_type", contact.Type).
+				Error(err).
+				Msg("Failed to send plots to Matrix")
+		}
+	}
+	return nil
+}
+
+func (sender *Sender) wrapError(err error, triggerID, contact string) error {
+	var matrixErr *apiError
+	if errors.As(err, &matrixErr) && brokenContactErrCodes[matrixErr.errCode] {
+		return moira.NewSenderBrokenContactError(err)
+	}
+	return fmt.Errorf("failed to send %s event message to Matrix [%s]: %w", triggerID, contact, err)
+}
+
+func (sender *Sender) buildMessage(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData, throttled bool) textMessage {
+	if message, ok := senders.PopulateMessageTemplate(sender.messageTemplate, events, contact, trigger, throttled, sender.frontURI, sender.logger); ok {
+		message = senders.TruncateMessage(message, messageMaxCharacters)
+		return textMessage{
+			MsgType:       "m.text",
+			Body:          message,
+			Format:        htmlFormat,
+			FormattedBody: string(blackfriday.Run([]byte(message))),
+		}
+	}
+
+	title, htmlTitle := sender.buildTitle(events, trigger, throttled)
+	titleLen := len([]rune(title))
+
+	desc := trigger.Desc
+	descLen := len([]rune(desc))
+
+	eventsString := sender.buildEventsString(events, -1)
+	eventsStringLen := len([]rune(eventsString))
+
+	charsLeftAfterTitle := messageMaxCharacters - titleLen
+	if throttled {
+		charsLeftAfterTitle -= len([]rune(throttleMsg)) + 1
+	}
+
+	descNewLen, eventsNewLen := senders.CalculateMessagePartsLength(charsLeftAfterTitle, descLen, eventsStringLen)
+	if descLen != descNewLen {
+		desc = string([]rune(desc)[:descNewLen]) + "..."
+	}
+	if eventsNewLen != eventsStringLen {
+		eventsString = sender.buildEventsString(events, eventsNewLen)
+	}
+
+	var body, formattedBody strings.Builder
+	body.WriteString(title + "\n")
+	formattedBody.WriteString(htmlTitle + "<br/>\n")
+	if desc != "" {
+		body.WriteString(desc + "\n")
+		formattedBody.WriteString(string(blackfriday.Run([]byte(desc))))
+	}
+	body.WriteString(eventsString)
+	formattedBody.WriteString("<pre><code>" + html.EscapeString(eventsString) + "</code></pre>")
+	if throttled {
+		body.WriteString("\n" + throttleMsg)
+		formattedBody.WriteString("\n<b>" + throttleMsg + "</b>")
+	}
+
+	return textMessage{
+		MsgType:       "m.text",
+		Body:          body.String(),
+		Format:        htmlFormat,
+		FormattedBody: formattedBody.String(),
+	}
+}
+
+// buildTitle returns plain text and HTML formatted titles.
+func (sender *Sender) buildTitle(events moira.NotificationEvents, trigger moira.TriggerData, throttled bool) (string, string) {
+	state := string(events.GetCurrentState(throttled))
+	title := state
+	htmlTitle := "<b>" + state + "</b>"
+
+	triggerURI := trigger.GetTriggerURI(sender.frontURI)
+	if triggerURI != "" {
+		title += fmt.Sprintf(" %s (%s)", trigger.Name, triggerURI)
+		htmlTitle += fmt.Sprintf(` <a href="%s">%s</a>`, html.EscapeString(triggerURI), html.EscapeString(trigger.Name))
+	} else if trigger.Name != "" {
+		title += " " + trigger.Name
+		htmlTitle += " " + html.EscapeString(trigger.Name)
+	}
+
+	if tags := trigger.GetTags(); tags != "" {
+		title += " " + tags
+		htmlTitle += " " + html.EscapeString(tags)
+	}
+	return title, htmlTitle
+}
+
+// buildEventsString builds the string from moira events and limits it to charsForEvents.
+// If charsForEvents is negative buildEventsString does not limit the events string.
+func (sender *Sender) buildEventsString(events moira.NotificationEvents, charsForEvents int) string {
+	lines, leftOut := senders.LimitEventLines(events, sender.location, 0, charsForEvents)
+
+	var eventsString strings.Builder
+	for _, line := range lines {
+		eventsString.WriteString(line + "\n")
+	}
+	if leftOut > 0 {
+		eventsString.WriteString(senders.FormatMoreEvents(leftOut))
+	}
+	return eventsString.String()
+}
+
+func (sender *Sender) sendPlots(ctx context.Context, plots [][]byte, roomID, triggerID string) error {
+	filename := fmt.Sprintf("%s.png", triggerID)
+	for _, plot := range plots {
+		contentURI, err := sender.client.upload(ctx, plot, "image/png", filename)
+		if err != nil {
+			return fmt.Errorf("failed to upload plot: %w", err)
+		}
+		image := imageMessage{
+			MsgType: "m.image",
+			Body:    filename,
+			URL:     contentURI,
+			Info: imageInfo{
+				MimeType: "image/png",
+				Size:     len(plot),
+			},
+		}
+		if _, err = sender.client.sendMessage(ctx, roomID, image); err != nil {
+			return err
+		}
+	}
+	return nil
+}
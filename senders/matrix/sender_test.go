@@ -0,0 +1,189 @@
+package matrix
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/moira-alert/moira"
+	logging "github.com/moira-alert/moira/logging/zerolog_adapter"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type matrixRequest struct {
+	method string
+	path   string
+	auth   string
+	body   []byte
+}
+
+type testHomeserver struct {
+	*httptest.Server
+	mutex    sync.Mutex
+	requests []matrixRequest
+}
+
+func newTestHomeserver() *testHomeserver {
+	homeserver := &testHomeserver{}
+	homeserver.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		homeserver.mutex.Lock()
+		homeserver.requests = append(homeserver.requests, matrixRequest{method: r.Method, path: r.URL.EscapedPath(), auth: r.Header.Get("Authorization"), body: body})
+		homeserver.mutex.Unlock()
+
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/_matrix/client/v3/directory/room/#unknown"):
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"errcode":"M_NOT_FOUND","error":"Room alias not found"}`)
+		case strings.HasPrefix(r.URL.Path, "/_matrix/client/v3/directory/room/"):
+			fmt.Fprint(w, `{"room_id":"!room:example.org"}`)
+		case strings.HasPrefix(r.URL.Path, "/_matrix/media/v3/upload"):
+			fmt.Fprint(w, `{"content_uri":"mxc://example.org/plot"}`)
+		default:
+			fmt.Fprint(w, `{"event_id":"$event"}`)
+		}
+	}))
+	return homeserver
+}
+
+func (homeserver *testHomeserver) getRequests() []matrixRequest {
+	homeserver.mutex.Lock()
+	defer homeserver.mutex.Unlock()
+	return homeserver.requests
+}
+
+func TestInit(t *testing.T) {
+	logger, _ := logging.ConfigureLog("stdout", "debug", "test", true)
+	Convey("Init tests", t, func() {
+		sender := &Sender{}
+
+		Convey("No homeserver_url", func() {
+			err := sender.Init(map[string]interface{}{"access_token": "token"}, logger, nil, "")
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("No access_token", func() {
+			err := sender.Init(map[string]interface{}{"homeserver_url": "https://matrix.example.org"}, logger, nil, "")
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Full config", func() {
+			senderSettings := map[string]interface{}{
+				"homeserver_url": "https://matrix.example.org/",
+				"access_token":   "token",
+				"front_uri":      "http://moira.url",
+			}
+			err := sender.Init(senderSettings, logger, time.UTC, "")
+			So(err, ShouldBeNil)
+			So(sender.client.homeserverURL, ShouldEqual, "https://matrix.example.org")
+			So(sender.client.accessToken, ShouldEqual, "token")
+			So(sender.frontURI, ShouldEqual, "http://moira.url")
+		})
+	})
+}
+
+func TestSendEvents(t *testing.T) {
+	logger, _ := logging.ConfigureLog("stdout", "debug", "test", true)
+	events := moira.NotificationEvents{{
+		Metric:    "metric.name",
+		Values:    map[string]float64{"t1": 10},
+		Timestamp: 150000000,
+		OldState:  moira.StateOK,
+		State:     moira.StateERROR,
+	}}
+	trigger := moira.TriggerData{ID: "TriggerID", Name: "Name <b>", Tags: []string{"tag"}}
+
+	Convey("Send events", t, func() {
+		homeserver := newTestHomeserver()
+		defer homeserver.Close()
+		sender := &Sender{}
+		err := sender.Init(map[string]interface{}{"homeserver_url": homeserver.URL, "access_token": "token", "front_uri": "http://moira.url"}, logger, time.UTC, "")
+		So(err, ShouldBeNil)
+
+		Convey("To room ID with plot", func() {
+			err = sender.SendEvents(events, moira.ContactData{Value: "!room:example.org"}, trigger, [][]byte{[]byte("plot")}, false)
+			So(err, ShouldBeNil)
+
+			requests := homeserver.getRequests()
+			So(requests, ShouldHaveLength, 3)
+			So(requests[0].method, ShouldEqual, http.MethodPut)
+			So(requests[0].path, ShouldStartWith, "/_matrix/client/v3/rooms/%21room:example.org/send/m.room.message/moira.")
+			So(requests[0].auth, ShouldEqual, "Bearer token")
+			var message textMessage
+			So(json.Unmarshal(requests[0].body, &message), ShouldBeNil)
+			So(message.Format, ShouldEqual, htmlFormat)
+			So(message.Body, ShouldStartWith, "ERROR Name <b> (http://moira.url/trigger/TriggerID) [tag]\n")
+			So(message.FormattedBody, ShouldStartWith, `<b>ERROR</b> <a href="http://moira.url/trigger/TriggerID">Name &lt;b&gt;</a> [tag]<br/>`)
+
+			So(requests[1].method, ShouldEqual, http.MethodPost)
+			So(requests[1].path, ShouldEqual, "/_matrix/media/v3/upload")
+			So(string(requests[1].body), ShouldEqual, "plot")
+			var image imageMessage
+			So(json.Unmarshal(requests[2].body, &image), ShouldBeNil)
+			So(image.URL, ShouldEqual, "mxc://example.org/plot")
+			So(image.MsgType, ShouldEqual, "m.image")
+		})
+
+		Convey("To room alias", func() {
+			err = sender.SendEvents(events, moira.ContactData{Value: "#alerts:example.org"}, trigger, nil, false)
+			So(err, ShouldBeNil)
+
+			requests := homeserver.getRequests()
+			So(requests, ShouldHaveLength, 2)
+			So(requests[0].path, ShouldEqual, "/_matrix/client/v3/directory/room/%23alerts:example.org")
+			So(requests[1].path, ShouldStartWith, "/_matrix/client/v3/rooms/%21room:example.org/send/")
+		})
+
+		Convey("To unknown room alias returns broken contact error", func() {
+			err = sender.SendEvents(events, moira.ContactData{Value: "#unknown:example.org"}, trigger, nil, false)
+			var brokenContactErr moira.SenderBrokenContactError
+			So(errors.As(err, &brokenContactErr), ShouldBeTrue)
+		})
+	})
+}
+
+func TestBuildMessage(t *testing.T) {
+	sender := &Sender{location: time.UTC, frontURI: "http://moira.url"}
+	trigger := moira.TriggerData{ID: "TriggerID", Name: "Name", Desc: "**desc**"}
+	event := moira.NotificationEvent{
+		Metric:    "metric.name",
+		Values:    map[string]float64{"t1": 10},
+		Timestamp: 150000000,
+		OldState:  moira.StateOK,
+		State:     moira.StateWARN,
+	}
+
+	Convey("Build message", t, func() {
+		Convey("With description and throttling", func() {
+			message := sender.buildMessage(moira.NotificationEvents{event}, moira.ContactData{}, trigger, true)
+			So(message.Body, ShouldEqual, "WARN Name (http://moira.url/trigger/TriggerID)\n**desc**\n02:40 (GMT+00:00): metric.name = 10 (OK to WARN)\n\n"+throttleMsg)
+			So(message.FormattedBody, ShouldEqual, `<b>WARN</b> <a href="http://moira.url/trigger/TriggerID">Name</a><br/>`+"\n<p><strong>desc</strong></p>\n<pre><code>02:40 (GMT+00:00): metric.name = 10 (OK to WARN)\n</code></pre>\n<b>"+throttleMsg+"</b>")
+		})
+
+		Convey("Long messages are limited", func() {
+			events := make(moira.NotificationEvents, 0, 1000)
+			for i := 0; i < 1000; i++ {
+				events = append(events, event)
+			}
+			longTrigger := trigger
+			longTrigger.Desc = strings.Repeat("a", messageMaxCharacters)
+			message := sender.buildMessage(events, moira.ContactData{}, longTrigger, false)
+			So(len([]rune(message.Body)), ShouldBeLessThanOrEqualTo, messageMaxCharacters+10)
+			So(message.Body, ShouldContainSubstring, "more events.")
+		})
+
+		Convey("With contact template", func() {
+			contact := moira.ContactData{Template: "**{{ .Trigger.Name }}** {{ range .Events }}{{ .Metric }}{{ end }}"}
+			message := sender.buildMessage(moira.NotificationEvents{event}, contact, trigger, false)
+			So(message.Body, ShouldEqual, "**Name** metric.name")
+			So(message.FormattedBody, ShouldEqual, "<p><strong>Name</strong> metric.name</p>\n")
+		})
+	})
+}
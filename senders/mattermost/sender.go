@@ -15,22 +15,27 @@ import (
 	"github.com/mitchellh/mapstructure"
 )
 
+const messageMaxCharacters = 4_000
+
 // Structure that represents the Mattermost configuration in the YAML file.
 type config struct {
 	Url         string `mapstructure:"url"`
 	InsecureTLS bool   `mapstructure:"insecure_tls"`
 	APIToken    string `mapstructure:"api_token"`
 	FrontURI    string `mapstructure:"front_uri"`
+	// Go template used instead of default message format, contact template takes precedence
+	MessageTemplate string `mapstructure:"message_template"`
 }
 
 // Sender posts messages to Mattermost chat.
 // It implements moira.Sender.
 // You must call Init method before SendEvents method.
 type Sender struct {
-	frontURI string
-	logger   moira.Logger
-	location *time.Location
-	client   Client
+	frontURI        string
+	messageTemplate string
+	logger          moira.Logger
+	location        *time.Location
+	client          Client
 }
 
 // Init configures Sender.
@@ -44,6 +49,9 @@ func (sender *Sender) Init(senderSettings interface{}, logger moira.Logger, loca
 	if cfg.Url == "" {
 		return fmt.Errorf("can not read Mattermost url from config")
 	}
+	if err = senders.ValidateMessageTemplate("mattermost", cfg.MessageTemplate); err != nil {
+		return err
+	}
 	client := model.NewAPIv4Client(cfg.Url)
 
 	if err != nil {
@@ -67,6 +75,7 @@ func (sender *Sender) Init(senderSettings interface{}, logger moira.Logger, loca
 		return fmt.Errorf("can not read Mattermost front_uri from config")
 	}
 	sender.frontURI = cfg.FrontURI
+	sender.messageTemplate = cfg.MessageTemplate
 	sender.location = location
 	sender.logger = logger
 
@@ -75,7 +84,12 @@ func (sender *Sender) Init(senderSettings interface{}, logger moira.Logger, loca
 
 // SendEvents implements moira.Sender interface.
 func (sender *Sender) SendEvents(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData, plots [][]byte, throttled bool) error {
-	message := sender.buildMessage(events, trigger, throttled)
+	message, ok := senders.PopulateMessageTemplate(sender.messageTemplate, events, contact, trigger, throttled, sender.frontURI, sender.logger)
+	if ok {
+		message = senders.TruncateMessage(message, messageMaxCharacters)
+	} else {
+		message = sender.buildMessage(events, trigger, throttled)
+	}
 	ctx := context.Background()
 	post, err := sender.sendMessage(ctx, message, contact.Value, trigger.ID)
 	if err != nil {
@@ -96,8 +110,6 @@ func (sender *Sender) SendEvents(events moira.NotificationEvents, contact moira.
 }
 
 func (sender *Sender) buildMessage(events moira.NotificationEvents, trigger moira.TriggerData, throttled bool) string {
-	const messageMaxCharacters = 4_000
-
 	var message strings.Builder
 
 	title := sender.buildTitle(events, trigger, throttled)
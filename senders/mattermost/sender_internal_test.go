@@ -70,6 +70,22 @@ func TestSendEvents(t *testing.T) {
 			err = sender.SendEvents(events, contact, trigger, plots, throttled)
 			So(err, ShouldBeNil)
 		})
+
+		Convey("When contact has message template, SendEvents should post populated template", func() {
+			ctrl := gomock.NewController(t)
+			client := mock.NewMockClient(ctrl)
+			client.EXPECT().CreatePost(context.Background(), &model.Post{
+				ChannelId: "contactDataID",
+				Message:   "ERROR triggerName qwerty/trigger/triggerID",
+			}).Return(&model.Post{Id: "postID"}, nil, nil)
+			sender.client = client
+
+			events := moira.NotificationEvents{{State: moira.StateERROR, OldState: moira.StateOK}}
+			contact := moira.ContactData{Value: "contactDataID", Template: "{{ .State }} {{ .Trigger.Name }} {{ .Trigger.URI }}"}
+			trigger := moira.TriggerData{ID: "triggerID", Name: "triggerName"}
+			err = sender.SendEvents(events, contact, trigger, make([][]byte, 0), false)
+			So(err, ShouldBeNil)
+		})
 	})
 }
 
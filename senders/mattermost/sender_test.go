@@ -59,6 +59,17 @@ func TestInit(t *testing.T) {
 			So(err, ShouldNotBeNil)
 		})
 
+		Convey("Invalid message_template", func() {
+			senderSettings := map[string]interface{}{
+				"url":              "qwerty",
+				"api_token":        "qwerty",
+				"front_uri":        "qwerty",
+				"message_template": "{{ .Trigger.Unknown }}",
+			}
+			err := sender.Init(senderSettings, logger, nil, "")
+			So(err, ShouldNotBeNil)
+		})
+
 		Convey("Full config", func() {
 			senderSettings := map[string]interface{}{
 				"url":          "qwerty",
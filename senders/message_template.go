@@ -0,0 +1,73 @@
+package senders
+
+import (
+	"fmt"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/templating"
+)
+
+// PopulateMessageTemplate builds notification message from message template of the contact or,
+// if the contact has no template, from message template of the sender.
+// The second value is false when no template is set or the template can not be populated,
+// in that case the sender should build its default message.
+func PopulateMessageTemplate(
+	senderTemplate string,
+	events moira.NotificationEvents,
+	contact moira.ContactData,
+	trigger moira.TriggerData,
+	throttled bool,
+	frontURI string,
+	logger moira.Logger,
+) (string, bool) {
+	tmpl := contact.Template
+	if tmpl == "" {
+		tmpl = senderTemplate
+	}
+	if tmpl == "" {
+		return "", false
+	}
+
+	populater := templating.NewMessagePopulater(
+		trigger.ToTemplateTrigger(frontURI),
+		events.ToTemplateEvents(),
+		contact.ToTemplateContact(),
+		string(events.GetCurrentState(throttled)),
+		throttled,
+		frontURI,
+	)
+	message, err := populater.Populate(tmpl)
+	if err != nil {
+		logger.Warning().
+			String(moira.LogFieldNameContactID, contact.ID).
+			String(moira.LogFieldNameTriggerID, trigger.ID).
+			Error(err).
+			Msg("Failed to populate message template, default message is used")
+		return "", false
+	}
+	return message, true
+}
+
+// ValidateMessageTemplate checks message_template from config of the sender, empty template is valid.
+func ValidateMessageTemplate(senderName, tmpl string) error {
+	if tmpl == "" {
+		return nil
+	}
+	if err := templating.ValidateMessageTemplate(tmpl); err != nil {
+		return fmt.Errorf("%s message_template is invalid: %w", senderName, err)
+	}
+	return nil
+}
+
+// TruncateMessage cuts message to maxChars characters, the cut message ends with "...".
+func TruncateMessage(message string, maxChars int) string {
+	const ellipsis = "..."
+	runes := []rune(message)
+	if len(runes) <= maxChars {
+		return message
+	}
+	if maxChars <= len(ellipsis) {
+		return string(runes[:maxChars])
+	}
+	return string(runes[:maxChars-len(ellipsis)]) + ellipsis
+}
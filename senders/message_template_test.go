@@ -0,0 +1,62 @@
+package senders
+
+import (
+	"testing"
+
+	"github.com/moira-alert/moira"
+	logging "github.com/moira-alert/moira/logging/zerolog_adapter"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestPopulateMessageTemplate(t *testing.T) {
+	logger, _ := logging.ConfigureLog("stdout", "info", "test", true)
+	events := moira.NotificationEvents{{Metric: "metric", OldState: moira.StateOK, State: moira.StateWARN}}
+	trigger := moira.TriggerData{ID: "TriggerID", Name: "Name"}
+
+	Convey("Populate message template", t, func() {
+		Convey("No templates", func() {
+			message, ok := PopulateMessageTemplate("", events, moira.ContactData{}, trigger, false, "http://moira.url", logger)
+			So(ok, ShouldBeFalse)
+			So(message, ShouldBeEmpty)
+		})
+
+		Convey("Sender template", func() {
+			message, ok := PopulateMessageTemplate("{{ .State }} {{ .Trigger.URI }}", events, moira.ContactData{}, trigger, false, "http://moira.url", logger)
+			So(ok, ShouldBeTrue)
+			So(message, ShouldEqual, "WARN http://moira.url/trigger/TriggerID")
+		})
+
+		Convey("Contact template takes precedence", func() {
+			contact := moira.ContactData{Type: "slack", Template: "{{ .Contact.Type }}: {{ .State }}"}
+			message, ok := PopulateMessageTemplate("{{ .State }}", events, contact, trigger, true, "http://moira.url", logger)
+			So(ok, ShouldBeTrue)
+			So(message, ShouldEqual, "slack: WARN")
+		})
+
+		Convey("Broken template", func() {
+			contact := moira.ContactData{Template: "{{ .Unknown }}"}
+			message, ok := PopulateMessageTemplate("", events, contact, trigger, false, "http://moira.url", logger)
+			So(ok, ShouldBeFalse)
+			So(message, ShouldBeEmpty)
+		})
+	})
+}
+
+func TestTruncateMessage(t *testing.T) {
+	Convey("Truncate message", t, func() {
+		So(TruncateMessage("short", 10), ShouldEqual, "short")
+		So(TruncateMessage("long message", 7), ShouldEqual, "long...")
+		So(TruncateMessage("сообщение", 6), ShouldEqual, "соо...")
+		So(TruncateMessage("message", 2), ShouldEqual, "me")
+	})
+}
+
+func TestValidateMessageTemplate(t *testing.T) {
+	Convey("Validate message template of sender", t, func() {
+		So(ValidateMessageTemplate("slack", ""), ShouldBeNil)
+		So(ValidateMessageTemplate("slack", "{{ .Trigger.Name }}"), ShouldBeNil)
+		err := ValidateMessageTemplate("slack", "{{ .Unknown }}")
+		So(err, ShouldNotBeNil)
+		So(err.Error(), ShouldStartWith, "slack message_template is invalid")
+	})
+}
@@ -13,6 +13,7 @@ import (
 
 	"github.com/mitchellh/mapstructure"
 	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/senders"
 	"github.com/russross/blackfriday/v2"
 )
 
@@ -43,15 +44,18 @@ var headers = map[string]string{
 type config struct {
 	FrontURI  string `mapstructure:"front_uri"`
 	MaxEvents int    `mapstructure:"max_events"`
+	// Go template used instead of default section text, contact template takes precedence
+	MessageTemplate string `mapstructure:"message_template"`
 }
 
 // Sender implements moira sender interface via MS Teams.
 type Sender struct {
-	frontURI  string
-	maxEvents int
-	logger    moira.Logger
-	location  *time.Location
-	client    *http.Client
+	frontURI        string
+	maxEvents       int
+	messageTemplate string
+	logger          moira.Logger
+	location        *time.Location
+	client          *http.Client
 }
 
 // Init initialises settings required for full functionality.
@@ -61,11 +65,15 @@ func (sender *Sender) Init(senderSettings interface{}, logger moira.Logger, loca
 	if err != nil {
 		return fmt.Errorf("failed to decode senderSettings to msteams config: %w", err)
 	}
+	if err = senders.ValidateMessageTemplate("msteams", cfg.MessageTemplate); err != nil {
+		return err
+	}
 
 	sender.logger = logger
 	sender.location = location
 	sender.frontURI = cfg.FrontURI
 	sender.maxEvents = cfg.MaxEvents
+	sender.messageTemplate = cfg.MessageTemplate
 	sender.client = &http.Client{
 		Timeout: time.Duration(30) * time.Second, //nolint
 	}
@@ -151,6 +159,14 @@ func (sender *Sender) buildMessage(events moira.NotificationEvents, trigger moir
 
 func (sender *Sender) buildRequest(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData, throttled bool) (*http.Request, error) {
 	messageCard := sender.buildMessage(events, trigger, throttled)
+	if message, ok := senders.PopulateMessageTemplate(sender.messageTemplate, events, contact, trigger, throttled, sender.frontURI, sender.logger); ok {
+		messageCard.Sections = []Section{
+			{
+				ActivityTitle: activityTitleText,
+				ActivityText:  message,
+			},
+		}
+	}
 	requestURL := contact.Value
 	requestBody, err := json.Marshal(messageCard)
 	if err != nil {
@@ -0,0 +1,230 @@
+package ntfy
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/senders"
+)
+
+const (
+	printEventsCount = 5
+	plotFilename     = "plot.png"
+	// ntfy server turns longer messages into attachments by default
+	messageMaxCharacters = 4096
+)
+
+// Message priorities, see https://docs.ntfy.sh/publish/#message-priority.
+const (
+	priorityLow     = 2
+	priorityDefault = 3
+	priorityHigh    = 4
+	priorityMax     = 5
+)
+
+// ntfy shows emoji for tags that match emoji short codes.
+var stateTags = map[moira.State]string{
+	moira.StateOK:        "white_check_mark",
+	moira.StateWARN:      "warning",
+	moira.StateERROR:     "rotating_light",
+	moira.StateNODATA:    "ghost",
+	moira.StateEXCEPTION: "boom",
+	moira.StateTEST:      "test_tube",
+}
+
+// Structure that represents the ntfy configuration in the YAML file.
+type config struct {
+	URL             string `mapstructure:"url"`
+	Token           string `mapstructure:"token"`
+	User            string `mapstructure:"user"`
+	Password        string `mapstructure:"password"`
+	FrontURI        string `mapstructure:"front_uri"`
+	MessageTemplate string `mapstructure:"message_template"`
+}
+
+// Sender implements moira sender interface for self-hosted ntfy server.
+// Contact value is a topic the message is published to.
+type Sender struct {
+	url             string
+	token           string
+	user            string
+	password        string
+	frontURI        string
+	messageTemplate string
+	logger          moira.Logger
+	location        *time.Location
+	client          *http.Client
+}
+
+// Init read yaml config.
+func (sender *Sender) Init(senderSettings interface{}, logger moira.Logger, location *time.Location, dateTimeFormat string) error {
+	var cfg config
+	err := mapstructure.Decode(senderSettings, &cfg)
+	if err != nil {
+		return fmt.Errorf("failed to decode senderSettings to ntfy config: %w", err)
+	}
+
+	if cfg.URL == "" {
+		return fmt.Errorf("can not read ntfy url from config")
+	}
+	if cfg.Token != "" && cfg.User != "" {
+		return fmt.Errorf("ntfy token and user can not be used together")
+	}
+	if err = senders.ValidateMessageTemplate("ntfy", cfg.MessageTemplate); err != nil {
+		return err
+	}
+
+	sender.url = strings.TrimSuffix(cfg.URL, "/")
+	sender.token = cfg.Token
+	sender.user = cfg.User
+	sender.password = cfg.Password
+	sender.frontURI = cfg.FrontURI
+	sender.messageTemplate = cfg.MessageTemplate
+	sender.logger = logger
+	sender.location = location
+	sender.client = &http.Client{
+		Timeout: time.Duration(30) * time.Second, //nolint
+	}
+	return nil
+}
+
+// SendEvents implements Sender interface Send.
+func (sender *Sender) SendEvents(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData, plots [][]byte, throttled bool) error {
+	topic := strings.Trim(contact.Value, "/ ")
+	if topic == "" || strings.Contains(topic, "/") {
+		return moira.NewSenderBrokenContactError(fmt.Errorf("invalid ntfy topic %q", contact.Value))
+	}
+
+	request, err := sender.buildRequest(events, topic, contact, trigger, plots, throttled)
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+
+	sender.logger.Debug().
+		String("topic", topic).
+		String("message_title", sender.buildTitle(events, trigger, throttled)).
+		Msg("Publishing message to ntfy")
+
+	response, err := sender.client.Do(request)
+	if err != nil {
+		return fmt.Errorf("failed to perform request: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode >= http.StatusOK && response.StatusCode < http.StatusMultipleChoices {
+		return nil
+	}
+	body, _ := io.ReadAll(response.Body)
+	return fmt.Errorf("failed to send %s event message to ntfy topic %s: status %d, %s", trigger.ID, topic, response.StatusCode, string(body))
+}
+
+// buildRequest creates publish request. Message and metadata are sent in headers,
+// so the body can hold the plot attachment.
+func (sender *Sender) buildRequest(
+	events moira.NotificationEvents,
+	topic string,
+	contact moira.ContactData,
+	trigger moira.TriggerData,
+	plots [][]byte,
+	throttled bool,
+) (*http.Request, error) {
+	var body io.Reader = http.NoBody
+	if len(plots) > 0 {
+		body = bytes.NewReader(plots[0])
+	}
+
+	request, err := http.NewRequestWithContext(context.Background(), http.MethodPut, sender.url+"/"+url.PathEscape(topic), body)
+	if err != nil {
+		return nil, err
+	}
+
+	state := events.GetCurrentState(throttled)
+	request.Header.Set("Title", encodeHeader(sender.buildTitle(events, trigger, throttled)))
+	request.Header.Set("Message", encodeHeader(sender.buildMessage(events, contact, trigger, throttled)))
+	request.Header.Set("Priority", strconv.Itoa(getMessagePriority(events)))
+	if tag, ok := stateTags[state]; ok {
+		request.Header.Set("Tags", tag)
+	}
+	if triggerURI := trigger.GetTriggerURI(sender.frontURI); triggerURI != "" {
+		request.Header.Set("Click", triggerURI)
+	}
+	if len(plots) > 0 {
+		request.Header.Set("Filename", plotFilename)
+	}
+
+	switch {
+	case sender.token != "":
+		request.Header.Set("Authorization", "Bearer "+sender.token)
+	case sender.user != "":
+		request.SetBasicAuth(sender.user, sender.password)
+	}
+	request.Header.Set("User-Agent", "Moira")
+	return request, nil
+}
+
+func (sender *Sender) buildTitle(events moira.NotificationEvents, trigger moira.TriggerData, throttled bool) string {
+	state := events.GetCurrentState(throttled)
+	title := fmt.Sprintf("%s %s", state, trigger.Name)
+	if tags := trigger.GetTags(); tags != "" {
+		title += " " + tags
+	}
+	return fmt.Sprintf("%s (%d)", title, len(events))
+}
+
+func (sender *Sender) buildMessage(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData, throttled bool) string {
+	if message, ok := senders.PopulateMessageTemplate(sender.messageTemplate, events, contact, trigger, throttled, sender.frontURI, sender.logger); ok {
+		return senders.TruncateMessage(message, messageMaxCharacters)
+	}
+
+	lines, leftOut := senders.LimitEventLines(events, sender.location, printEventsCount, -1)
+
+	var message strings.Builder
+	for _, line := range lines {
+		message.WriteString(line + "\n")
+	}
+	if leftOut > 0 {
+		message.WriteString("\n" + senders.FormatMoreEvents(leftOut))
+	}
+
+	if throttled {
+		message.WriteString("\nPlease, fix your system or tune this trigger to generate less events.")
+	}
+	return strings.TrimSuffix(message.String(), "\n")
+}
+
+// getMessagePriority returns the highest priority among events states.
+func getMessagePriority(events moira.NotificationEvents) int {
+	priority := priorityLow
+	for _, event := range events {
+		switch event.State {
+		case moira.StateERROR, moira.StateEXCEPTION:
+			priority = priorityMax
+		case moira.StateWARN, moira.StateNODATA:
+			if priority < priorityHigh {
+				priority = priorityHigh
+			}
+		case moira.StateTEST:
+			if priority < priorityDefault {
+				priority = priorityDefault
+			}
+		}
+	}
+	return priority
+}
+
+// encodeHeader makes header value safe: ntfy expands "\n" to a line break and
+// decodes RFC 2047 encoded non ASCII values.
+func encodeHeader(value string) string {
+	value = strings.ReplaceAll(value, "\n", `\n`)
+	return mime.BEncoding.Encode("utf-8", value)
+}
@@ -0,0 +1,136 @@
+package ntfy
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/moira-alert/moira"
+	logging "github.com/moira-alert/moira/logging/zerolog_adapter"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestInit(t *testing.T) {
+	logger, _ := logging.ConfigureLog("stdout", "debug", "test", true)
+	Convey("Init tests", t, func() {
+		sender := Sender{}
+		Convey("Empty url", func() {
+			err := sender.Init(map[string]interface{}{}, logger, nil, "")
+			So(err, ShouldResemble, errors.New("can not read ntfy url from config"))
+		})
+		Convey("Token and user together", func() {
+			err := sender.Init(map[string]interface{}{"url": "https://ntfy.example.com", "token": "tk_token", "user": "moira"}, logger, nil, "")
+			So(err, ShouldResemble, errors.New("ntfy token and user can not be used together"))
+		})
+		Convey("Full config", func() {
+			err := sender.Init(map[string]interface{}{"url": "https://ntfy.example.com/", "token": "tk_token", "front_uri": "http://moira.url"}, logger, nil, "")
+			So(err, ShouldBeNil)
+			So(sender.url, ShouldEqual, "https://ntfy.example.com")
+			So(sender.token, ShouldEqual, "tk_token")
+			So(sender.frontURI, ShouldEqual, "http://moira.url")
+		})
+	})
+}
+
+func TestSendEvents(t *testing.T) {
+	logger, _ := logging.ConfigureLog("stdout", "info", "test", true)
+	events := moira.NotificationEvents{{Metric: "Metric", Values: map[string]float64{"t1": 1}, Timestamp: 150000000, OldState: moira.StateOK, State: moira.StateERROR}}
+	trigger := moira.TriggerData{ID: "TriggerID", Name: "Name", Tags: []string{"tag1"}}
+
+	Convey("Send events to ntfy", t, func() {
+		var request *http.Request
+		var requestBody []byte
+		status := http.StatusOK
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			request = r
+			requestBody, _ = io.ReadAll(r.Body)
+			w.WriteHeader(status)
+			w.Write([]byte(`{"error":"error"}`)) //nolint
+		}))
+		defer server.Close()
+
+		sender := Sender{}
+		err := sender.Init(map[string]interface{}{"url": server.URL, "token": "tk_token", "front_uri": "http://moira.url"}, logger, time.UTC, "")
+		So(err, ShouldBeNil)
+
+		Convey("Message with plot", func() {
+			err = sender.SendEvents(events, moira.ContactData{Value: "alerts"}, trigger, [][]byte{[]byte("plot")}, false)
+			So(err, ShouldBeNil)
+			So(request.Method, ShouldEqual, http.MethodPut)
+			So(request.URL.Path, ShouldEqual, "/alerts")
+			So(request.Header.Get("Authorization"), ShouldEqual, "Bearer tk_token")
+			So(request.Header.Get("Title"), ShouldEqual, "ERROR Name [tag1] (1)")
+			So(request.Header.Get("Message"), ShouldEqual, "02:40 (GMT+00:00): Metric = 1 (OK to ERROR)")
+			So(request.Header.Get("Priority"), ShouldEqual, "5")
+			So(request.Header.Get("Tags"), ShouldEqual, "rotating_light")
+			So(request.Header.Get("Click"), ShouldEqual, "http://moira.url/trigger/TriggerID")
+			So(request.Header.Get("Filename"), ShouldEqual, plotFilename)
+			So(string(requestBody), ShouldEqual, "plot")
+		})
+
+		Convey("Message without plot", func() {
+			err = sender.SendEvents(events, moira.ContactData{Value: "alerts"}, trigger, nil, false)
+			So(err, ShouldBeNil)
+			So(request.Header.Get("Filename"), ShouldBeEmpty)
+			So(requestBody, ShouldBeEmpty)
+		})
+
+		Convey("Error response", func() {
+			status = http.StatusForbidden
+			err = sender.SendEvents(events, moira.ContactData{Value: "alerts"}, trigger, nil, false)
+			So(err, ShouldResemble, errors.New(`failed to send TriggerID event message to ntfy topic alerts: status 403, {"error":"error"}`))
+		})
+
+		Convey("Invalid topic", func() {
+			err = sender.SendEvents(events, moira.ContactData{Value: "alerts/other"}, trigger, nil, false)
+			var brokenContactErr moira.SenderBrokenContactError
+			So(errors.As(err, &brokenContactErr), ShouldBeTrue)
+		})
+	})
+}
+
+func TestBuildMessage(t *testing.T) {
+	sender := Sender{location: time.UTC}
+	event := moira.NotificationEvent{Metric: "Metric", Values: map[string]float64{"t1": 1}, Timestamp: 150000000, OldState: moira.StateOK, State: moira.StateWARN}
+
+	Convey("Build message", t, func() {
+		Convey("Many events", func() {
+			message := sender.buildMessage(moira.NotificationEvents{event, event, event, event, event, event}, moira.ContactData{}, moira.TriggerData{}, false)
+			line := "02:40 (GMT+00:00): Metric = 1 (OK to WARN)\n"
+			So(message, ShouldEqual, line+line+line+line+line+"\n...and 1 more events.")
+		})
+		Convey("Throttled", func() {
+			message := sender.buildMessage(moira.NotificationEvents{event}, moira.ContactData{}, moira.TriggerData{}, true)
+			So(message, ShouldEqual, "02:40 (GMT+00:00): Metric = 1 (OK to WARN)\n\nPlease, fix your system or tune this trigger to generate less events.")
+		})
+		Convey("With contact template", func() {
+			contact := moira.ContactData{Template: "{{ .Trigger.Name }}: {{ range .Events }}{{ .Metric }} is {{ .State }}{{ end }}"}
+			message := sender.buildMessage(moira.NotificationEvents{event}, contact, moira.TriggerData{Name: "Name"}, false)
+			So(message, ShouldEqual, "Name: Metric is WARN")
+		})
+	})
+}
+
+func TestGetMessagePriority(t *testing.T) {
+	Convey("Get message priority", t, func() {
+		Convey("OK events", func() {
+			So(getMessagePriority(moira.NotificationEvents{{State: moira.StateOK}}), ShouldEqual, priorityLow)
+		})
+		Convey("WARN and NODATA events", func() {
+			So(getMessagePriority(moira.NotificationEvents{{State: moira.StateOK}, {State: moira.StateNODATA}}), ShouldEqual, priorityHigh)
+		})
+		Convey("ERROR has the highest priority", func() {
+			So(getMessagePriority(moira.NotificationEvents{{State: moira.StateERROR}, {State: moira.StateWARN}}), ShouldEqual, priorityMax)
+		})
+	})
+}
+
+func TestEncodeHeader(t *testing.T) {
+	Convey("Encode header", t, func() {
+		So(encodeHeader("line1\nline2"), ShouldEqual, `line1\nline2`)
+		So(encodeHeader("тест"), ShouldEqual, "=?utf-8?b?0YLQtdGB0YI=?=")
+	})
+}
@@ -15,10 +15,12 @@ import (
 type config struct {
 	APIKey   string `mapstructure:"api_key"`
 	FrontURI string `mapstructure:"front_uri"`
+	DedupBy  string `mapstructure:"dedup_by"`
 }
 
 // Sender implements the Sender interface for opsgenie.
 type Sender struct {
+	DataBase             moira.Database
 	apiKey               string
 	client               *alert.Client
 	logger               moira.Logger
@@ -28,6 +30,7 @@ type Sender struct {
 	imageStore           moira.ImageStore
 	imageStoreConfigured bool
 	frontURI             string
+	dedupMode            senders.DedupMode
 }
 
 // Init initializes the opsgenie sender.
@@ -44,6 +47,11 @@ func (sender *Sender) Init(senderSettings interface{}, logger moira.Logger, loca
 		return fmt.Errorf("cannot read the api_key from the sender settings")
 	}
 
+	sender.dedupMode, err = senders.ParseDedupMode(cfg.DedupBy)
+	if err != nil {
+		return fmt.Errorf("failed to read opsgenie dedup_by: %w", err)
+	}
+
 	sender.imageStoreID, sender.imageStore, sender.imageStoreConfigured = senders.ReadImageStoreConfig(senderSettings, sender.ImageStores, logger)
 
 	sender.client, err = alert.NewClient(&client.Config{
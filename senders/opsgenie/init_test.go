@@ -49,6 +49,15 @@ func TestInit(t *testing.T) {
 			So(sender.location, ShouldResemble, location)
 		})
 
+		Convey("Wrong dedup_by", func() {
+			senderSettings := map[string]interface{}{
+				"api_key":  "testkey",
+				"dedup_by": "tag",
+			}
+			err := sender.Init(senderSettings, logger, location, "15:04")
+			So(err, ShouldNotBeNil)
+		})
+
 		Convey("Wrong image_store name", func() {
 			senderSettings := map[string]interface{}{
 				"front_uri":   "http://moira.uri",
@@ -18,20 +18,45 @@ const (
 )
 
 // SendEvents sends the events as an alert to opsgenie.
+// Alerts recovered to OK are closed by their alias, others are created or deduplicated by opsgenie.
 func (sender *Sender) SendEvents(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData, plots [][]byte, throttled bool) error {
-	createAlertRequest := sender.makeCreateAlertRequest(events, contact, trigger, plots, throttled)
-	_, err := sender.client.Create(context.Background(), createAlertRequest)
+	dedupMode := senders.GetContactDedupMode(contact, sender.dedupMode)
+	incidents, err := senders.GroupIncidents(sender.DataBase, events, trigger, dedupMode, throttled)
 	if err != nil {
-		return fmt.Errorf("failed to send %s event message to opsgenie: %s", trigger.ID, err.Error())
+		return err
+	}
+	for _, incident := range incidents {
+		if incident.Resolved {
+			closeAlertRequest := sender.makeCloseAlertRequest(incident)
+			if _, err := sender.client.Close(context.Background(), closeAlertRequest); err != nil {
+				return fmt.Errorf("failed to close %s alert in opsgenie: %s", incident.DedupKey, err.Error())
+			}
+			continue
+		}
+
+		createAlertRequest := sender.makeCreateAlertRequest(incident, contact, trigger, plots, throttled)
+		if _, err := sender.client.Create(context.Background(), createAlertRequest); err != nil {
+			return fmt.Errorf("failed to send %s event message to opsgenie: %s", trigger.ID, err.Error())
+		}
 	}
 	return nil
 }
 
-func (sender *Sender) makeCreateAlertRequest(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData, plots [][]byte, throttled bool) *alert.CreateAlertRequest {
+func (sender *Sender) makeCloseAlertRequest(incident senders.Incident) *alert.CloseAlertRequest {
+	return &alert.CloseAlertRequest{
+		IdentifierType:  alert.ALIAS,
+		IdentifierValue: incident.DedupKey,
+		Source:          "Moira",
+		Note:            "Recovered to OK",
+	}
+}
+
+func (sender *Sender) makeCreateAlertRequest(incident senders.Incident, contact moira.ContactData, trigger moira.TriggerData, plots [][]byte, throttled bool) *alert.CreateAlertRequest {
+	events := incident.Events
 	createAlertRequest := &alert.CreateAlertRequest{
 		Message:     sender.buildTitle(events, trigger, throttled),
 		Description: sender.buildMessage(events, throttled, trigger),
-		Alias:       trigger.ID,
+		Alias:       incident.DedupKey,
 		Responders: []alert.Responder{
 			{Type: alert.EscalationResponder, Name: contact.Value},
 		},
@@ -8,6 +8,7 @@ import (
 	"github.com/moira-alert/moira"
 	logging "github.com/moira-alert/moira/logging/zerolog_adapter"
 	mock_moira_alert "github.com/moira-alert/moira/mock/moira-alert"
+	"github.com/moira-alert/moira/senders"
 	"github.com/opsgenie/opsgenie-go-sdk-v2/alert"
 	. "github.com/smartystreets/goconvey/convey"
 )
@@ -171,7 +172,7 @@ func TestMakeCreateAlertRequest(t *testing.T) {
 		contact := moira.ContactData{
 			Value: "123",
 		}
-		actual := sender.makeCreateAlertRequest(event, contact, trigger, [][]byte{[]byte(`test`)}, false)
+		actual := sender.makeCreateAlertRequest(senders.Incident{DedupKey: trigger.ID, Events: event}, contact, trigger, [][]byte{[]byte(`test`)}, false)
 		expected := &alert.CreateAlertRequest{
 			Message:     sender.buildTitle(event, trigger, false),
 			Description: sender.buildMessage(event, false, trigger),
@@ -189,3 +190,17 @@ func TestMakeCreateAlertRequest(t *testing.T) {
 		So(actual, ShouldResemble, expected)
 	})
 }
+
+func TestMakeCloseAlertRequest(t *testing.T) {
+	sender := Sender{}
+
+	Convey("Build CloseAlertRequest", t, func() {
+		actual := sender.makeCloseAlertRequest(senders.Incident{DedupKey: "SomeID:Metric", Resolved: true})
+		So(actual, ShouldResemble, &alert.CloseAlertRequest{
+			IdentifierType:  alert.ALIAS,
+			IdentifierValue: "SomeID:Metric",
+			Source:          "Moira",
+			Note:            "Recovered to OK",
+		})
+	})
+}
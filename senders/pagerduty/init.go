@@ -12,10 +12,12 @@ import (
 // Structure that represents the PagerDuty configuration in the YAML file.
 type config struct {
 	FrontURI string `mapstructure:"front_uri"`
+	DedupBy  string `mapstructure:"dedup_by"`
 }
 
 // Sender implements moira sender interface for pagerduty.
 type Sender struct {
+	DataBase             moira.Database
 	ImageStores          map[string]moira.ImageStore
 	imageStoreID         string
 	imageStore           moira.ImageStore
@@ -23,6 +25,7 @@ type Sender struct {
 	logger               moira.Logger
 	frontURI             string
 	location             *time.Location
+	dedupMode            senders.DedupMode
 }
 
 // Init loads yaml config, configures the pagerduty client.
@@ -34,6 +37,10 @@ func (sender *Sender) Init(senderSettings interface{}, logger moira.Logger, loca
 	}
 
 	sender.frontURI = cfg.FrontURI
+	sender.dedupMode, err = senders.ParseDedupMode(cfg.DedupBy)
+	if err != nil {
+		return fmt.Errorf("failed to read pagerduty dedup_by: %w", err)
+	}
 
 	sender.imageStoreID, sender.imageStore, sender.imageStoreConfigured = senders.ReadImageStoreConfig(senderSettings, sender.ImageStores, logger)
 
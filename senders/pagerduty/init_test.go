@@ -7,6 +7,7 @@ import (
 	"github.com/golang/mock/gomock"
 	"github.com/moira-alert/moira"
 	mock_moira_alert "github.com/moira-alert/moira/mock/moira-alert"
+	"github.com/moira-alert/moira/senders"
 
 	logging "github.com/moira-alert/moira/logging/zerolog_adapter"
 	. "github.com/smartystreets/goconvey/convey"
@@ -37,6 +38,18 @@ func TestInit(t *testing.T) {
 			So(sender.imageStoreConfigured, ShouldResemble, true)
 			So(sender.imageStore, ShouldResemble, imageStore)
 		})
+		Convey("Has dedup_by", func() {
+			senderSettings := map[string]interface{}{
+				"dedup_by": "metric",
+			}
+			err := sender.Init(senderSettings, logger, location, "15:04")
+			So(err, ShouldBeNil)
+			So(sender.dedupMode, ShouldEqual, senders.DedupByMetric)
+
+			senderSettings["dedup_by"] = "tag"
+			err = sender.Init(senderSettings, logger, location, "15:04")
+			So(err, ShouldNotBeNil)
+		})
 		Convey("Wrong image_store name", func() {
 			senderSettings := map[string]interface{}{
 				"front_uri":   "http://moira.uri",
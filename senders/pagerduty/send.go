@@ -11,21 +11,49 @@ import (
 	"github.com/PagerDuty/go-pagerduty"
 
 	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/senders"
 )
 
 const summaryMaxChars = 1024
 
+const (
+	triggerAction = "trigger"
+	resolveAction = "resolve"
+)
+
 // SendEvents implements Sender interface Send.
+// Incidents recovered to OK are resolved by their dedup key, others are triggered.
 func (sender *Sender) SendEvents(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData, plots [][]byte, throttled bool) error {
-	event := sender.buildEvent(events, contact, trigger, plots, throttled)
-	_, err := pagerduty.ManageEventWithContext(context.Background(), event)
+	dedupMode := senders.GetContactDedupMode(contact, sender.dedupMode)
+	incidents, err := senders.GroupIncidents(sender.DataBase, events, trigger, dedupMode, throttled)
 	if err != nil {
-		return fmt.Errorf("failed to post the event to the pagerduty contact %s : %w. ", contact.Value, err)
+		return err
+	}
+	for _, incident := range incidents {
+		var event pagerduty.V2Event
+		if incident.Resolved {
+			event = sender.buildResolveEvent(incident, contact)
+		} else {
+			event = sender.buildEvent(incident, contact, trigger, plots, throttled)
+		}
+		_, err := pagerduty.ManageEventWithContext(context.Background(), event)
+		if err != nil {
+			return fmt.Errorf("failed to post the event to the pagerduty contact %s : %w. ", contact.Value, err)
+		}
 	}
 	return nil
 }
 
-func (sender *Sender) buildEvent(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData, plots [][]byte, throttled bool) pagerduty.V2Event {
+func (sender *Sender) buildResolveEvent(incident senders.Incident, contact moira.ContactData) pagerduty.V2Event {
+	return pagerduty.V2Event{
+		RoutingKey: contact.Value,
+		Action:     resolveAction,
+		DedupKey:   incident.DedupKey,
+	}
+}
+
+func (sender *Sender) buildEvent(incident senders.Incident, contact moira.ContactData, trigger moira.TriggerData, plots [][]byte, throttled bool) pagerduty.V2Event {
+	events := incident.Events
 	summary := sender.buildSummary(events, trigger, throttled)
 	details := make(map[string]interface{})
 
@@ -64,7 +92,8 @@ func (sender *Sender) buildEvent(events moira.NotificationEvents, contact moira.
 
 	event := pagerduty.V2Event{
 		RoutingKey: contact.Value,
-		Action:     "trigger",
+		Action:     triggerAction,
+		DedupKey:   incident.DedupKey,
 		Payload:    payload,
 	}
 
@@ -9,6 +9,7 @@ import (
 	mock_moira_alert "github.com/moira-alert/moira/mock/moira-alert"
 
 	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/senders"
 	. "github.com/smartystreets/goconvey/convey"
 )
 
@@ -42,6 +43,7 @@ func TestBuildEvent(t *testing.T) {
 		baseExpected := pagerduty.V2Event{
 			RoutingKey: contact.Value,
 			Action:     "trigger",
+			DedupKey:   "TriggerID",
 			Payload: &pagerduty.V2Payload{
 				Summary:   "NODATA Trigger Name [tag1][tag2]",
 				Severity:  "warning",
@@ -56,7 +58,7 @@ func TestBuildEvent(t *testing.T) {
 		}
 
 		Convey("Build pagerduty event with one moira event", func() {
-			actual := sender.buildEvent(moira.NotificationEvents{event}, contact, trigger, [][]byte{}, false)
+			actual := sender.buildEvent(senders.Incident{DedupKey: trigger.ID, Events: moira.NotificationEvents{event}}, contact, trigger, [][]byte{}, false)
 			expected := baseExpected
 			details := map[string]interface{}{
 				"Events":       "\n02:40 (GMT+00:00): Metric name = 97.4458331200185 (OK to NODATA)",
@@ -73,7 +75,7 @@ func TestBuildEvent(t *testing.T) {
 				imageStore.EXPECT().StoreImage([]byte("test")).Return("test", nil)
 				sender.imageStore = imageStore
 				sender.imageStoreConfigured = true
-				actual := sender.buildEvent(moira.NotificationEvents{event}, contact, trigger, [][]byte{[]byte("test")}, false)
+				actual := sender.buildEvent(senders.Incident{DedupKey: trigger.ID, Events: moira.NotificationEvents{event}}, contact, trigger, [][]byte{[]byte("test")}, false)
 				expected := baseExpected
 				details := map[string]interface{}{
 					"Events":       "\n02:40 (GMT+00:00): Metric name = 97.4458331200185 (OK to NODATA)",
@@ -98,7 +100,7 @@ func TestBuildEvent(t *testing.T) {
 				sender.imageStore = imageStore
 				sender.imageStoreConfigured = true
 				actual := sender.buildEvent(
-					moira.NotificationEvents{event},
+					senders.Incident{DedupKey: trigger.ID, Events: moira.NotificationEvents{event}},
 					contact,
 					trigger,
 					[][]byte{[]byte("plot0"), []byte("plot1"), []byte("plot2")},
@@ -131,7 +133,7 @@ func TestBuildEvent(t *testing.T) {
 		})
 
 		Convey("Build pagerduty event with one event and throttled", func() {
-			actual := sender.buildEvent(moira.NotificationEvents{event}, contact, trigger, [][]byte{}, true)
+			actual := sender.buildEvent(senders.Incident{DedupKey: trigger.ID, Events: moira.NotificationEvents{event}}, contact, trigger, [][]byte{}, true)
 			expected := baseExpected
 			details := map[string]interface{}{
 				"Events":       "\n02:40 (GMT+00:00): Metric name = 97.4458331200185 (OK to NODATA)",
@@ -149,7 +151,7 @@ func TestBuildEvent(t *testing.T) {
 			for i := 0; i < 10; i++ {
 				events = append(events, event)
 			}
-			actual := sender.buildEvent(events, contact, trigger, [][]byte{}, true)
+			actual := sender.buildEvent(senders.Incident{DedupKey: trigger.ID, Events: events}, contact, trigger, [][]byte{}, true)
 			expected := baseExpected
 			details := map[string]interface{}{
 				"Events": `
@@ -173,3 +175,19 @@ func TestBuildEvent(t *testing.T) {
 		})
 	})
 }
+
+func TestBuildResolveEvent(t *testing.T) {
+	sender := Sender{}
+
+	Convey("Build pagerduty resolve event", t, func() {
+		contact := moira.ContactData{Value: "mock routing key"}
+		incident := senders.Incident{DedupKey: "TriggerID:Metric name", Resolved: true}
+
+		actual := sender.buildResolveEvent(incident, contact)
+		So(actual, ShouldResemble, pagerduty.V2Event{
+			RoutingKey: "mock routing key",
+			Action:     "resolve",
+			DedupKey:   "TriggerID:Metric name",
+		})
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/senders"
 
 	pushover_client "github.com/gregdel/pushover"
 	"github.com/mitchellh/mapstructure"
@@ -15,12 +16,14 @@ const (
 	printEventsCount = 5
 	titleLimit       = 250
 	urlLimit         = 512
+	messageLimit     = 1024
 )
 
 // Structure that represents the Pushover configuration in the YAML file.
 type config struct {
-	APIToken string `mapstructure:"api_token"`
-	FrontURI string `mapstructure:"front_uri"`
+	APIToken        string `mapstructure:"api_token"`
+	FrontURI        string `mapstructure:"front_uri"`
+	MessageTemplate string `mapstructure:"message_template"`
 }
 
 // Sender implements moira sender interface via pushover.
@@ -29,8 +32,9 @@ type Sender struct {
 	location *time.Location
 	client   *pushover_client.Pushover
 
-	apiToken string
-	frontURI string
+	apiToken        string
+	frontURI        string
+	messageTemplate string
 }
 
 // Init read yaml config.
@@ -45,16 +49,20 @@ func (sender *Sender) Init(senderSettings interface{}, logger moira.Logger, loca
 	if sender.apiToken == "" {
 		return fmt.Errorf("can not read pushover api_token from config")
 	}
+	if err = senders.ValidateMessageTemplate("pushover", cfg.MessageTemplate); err != nil {
+		return err
+	}
 	sender.client = pushover_client.New(sender.apiToken)
 	sender.logger = logger
 	sender.frontURI = cfg.FrontURI
+	sender.messageTemplate = cfg.MessageTemplate
 	sender.location = location
 	return nil
 }
 
 // SendEvents implements pushover build and send message functionality.
 func (sender *Sender) SendEvents(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData, plots [][]byte, throttled bool) error {
-	pushoverMessage := sender.makePushoverMessage(events, trigger, plots, throttled)
+	pushoverMessage := sender.makePushoverMessage(events, contact, trigger, plots, throttled)
 
 	sender.logger.Debug().
 		String("message_title", pushoverMessage.Title).
@@ -69,9 +77,22 @@ func (sender *Sender) SendEvents(events moira.NotificationEvents, contact moira.
 	return nil
 }
 
-func (sender *Sender) makePushoverMessage(events moira.NotificationEvents, trigger moira.TriggerData, plots [][]byte, throttled bool) *pushover_client.Message {
+func (sender *Sender) makePushoverMessage(
+	events moira.NotificationEvents,
+	contact moira.ContactData,
+	trigger moira.TriggerData,
+	plots [][]byte,
+	throttled bool,
+) *pushover_client.Message {
+	message, ok := senders.PopulateMessageTemplate(sender.messageTemplate, events, contact, trigger, throttled, sender.frontURI, sender.logger)
+	if ok {
+		message = senders.TruncateMessage(message, messageLimit)
+	} else {
+		message = sender.buildMessage(events, throttled)
+	}
+
 	pushoverMessage := &pushover_client.Message{
-		Message:   sender.buildMessage(events, throttled),
+		Message:   message,
 		Title:     sender.buildTitle(events, trigger, throttled),
 		Priority:  sender.getMessagePriority(events),
 		Retry:     5 * time.Minute, //nolint
@@ -198,6 +198,13 @@ func TestMakePushoverMessage(t *testing.T) {
 			Message:   "02:40 (GMT+00:00): Metric = 123 (OK to ERROR)\n",
 		}
 		expected.AddAttachment(bytes.NewReader([]byte{1, 0, 1})) //nolint
-		So(sender.makePushoverMessage(event, trigger, [][]byte{{1, 0, 1}}, false), ShouldResemble, expected)
+		So(sender.makePushoverMessage(event, moira.ContactData{}, trigger, [][]byte{{1, 0, 1}}, false), ShouldResemble, expected)
+
+		Convey("With contact template", func() {
+			contact := moira.ContactData{Template: "{{ .Trigger.Name }}: {{ range .Events }}{{ .Metric }} is {{ .State }}{{ end }}"}
+			message := sender.makePushoverMessage(event, contact, trigger, nil, false)
+			So(message.Title, ShouldEqual, "ERROR TriggerName [tag1][tag2] (1)")
+			So(message.Message, ShouldEqual, "TriggerName: Metric is ERROR")
+		})
 	})
 }
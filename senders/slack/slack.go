@@ -45,15 +45,22 @@ type config struct {
 	APIToken string `mapstructure:"api_token"`
 	UseEmoji bool   `mapstructure:"use_emoji"`
 	FrontURI string `mapstructure:"front_uri"`
+	// If true, notifications about the same trigger are posted as replies to the first message until trigger goes OK
+	ThreadUpdates bool `mapstructure:"thread_updates"`
+	// Go template used instead of default message format, contact template takes precedence
+	MessageTemplate string `mapstructure:"message_template"`
 }
 
 // Sender implements moira sender interface via slack.
 type Sender struct {
-	frontURI string
-	useEmoji bool
-	logger   moira.Logger
-	location *time.Location
-	client   *slack_client.Client
+	DataBase        moira.Database
+	frontURI        string
+	useEmoji        bool
+	threadUpdates   bool
+	messageTemplate string
+	logger          moira.Logger
+	location        *time.Location
+	client          *slack_client.Client
 }
 
 // Init read yaml config.
@@ -67,7 +74,12 @@ func (sender *Sender) Init(senderSettings interface{}, logger moira.Logger, loca
 	if cfg.APIToken == "" {
 		return fmt.Errorf("can not read slack api_token from config")
 	}
+	if err = senders.ValidateMessageTemplate("slack", cfg.MessageTemplate); err != nil {
+		return err
+	}
 	sender.useEmoji = cfg.UseEmoji
+	sender.threadUpdates = cfg.ThreadUpdates
+	sender.messageTemplate = cfg.MessageTemplate
 	sender.logger = logger
 	sender.frontURI = cfg.FrontURI
 	sender.location = location
@@ -77,15 +89,45 @@ func (sender *Sender) Init(senderSettings interface{}, logger moira.Logger, loca
 
 // SendEvents implements Sender interface Send.
 func (sender *Sender) SendEvents(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData, plots [][]byte, throttled bool) error {
-	message := sender.buildMessage(events, trigger, throttled)
+	message, ok := senders.PopulateMessageTemplate(sender.messageTemplate, events, contact, trigger, throttled, sender.frontURI, sender.logger)
+	if ok {
+		message = senders.TruncateMessage(message, messageMaxCharacters)
+	} else {
+		message = sender.buildMessage(events, trigger, throttled)
+	}
 	useDirectMessaging := useDirectMessaging(contact.Value)
 
 	state := events.GetCurrentState(throttled)
 	emoji := sender.getStateEmoji(state)
 
-	channelID, threadTimestamp, err := sender.sendMessage(message, contact.Value, trigger.ID, useDirectMessaging, emoji)
-	if err != nil {
-		return err
+	var thread *messageThread
+	if sender.threadUpdates {
+		thread = sender.getThread(trigger.ID, contact.Value)
+	}
+
+	var channelID, threadTimestamp string
+	var err error
+	if thread != nil {
+		channelID, threadTimestamp = thread.channelID, thread.timestamp
+		_, _, err = sender.sendMessage(message, channelID, trigger.ID, useDirectMessaging, emoji, threadTimestamp)
+		if err != nil {
+			return err
+		}
+		// events hold only changed metrics, so OK batch of partially recovered trigger keeps root state and thread
+		if state != moira.StateOK {
+			sender.updateThreadRoot(thread, message, state, trigger.ID)
+		} else if sender.isTriggerRecovered(trigger.ID) {
+			sender.updateThreadRoot(thread, message, state, trigger.ID)
+			sender.removeThread(trigger.ID, contact.Value)
+		}
+	} else {
+		channelID, threadTimestamp, err = sender.sendMessage(message, contact.Value, trigger.ID, useDirectMessaging, emoji, "")
+		if err != nil {
+			return err
+		}
+		if sender.threadUpdates && (state != moira.StateOK || !sender.isTriggerRecovered(trigger.ID)) {
+			sender.saveThread(trigger.ID, contact.Value, messageThread{channelID: channelID, timestamp: threadTimestamp})
+		}
 	}
 
 	if channelID != "" && len(plots) > 0 {
@@ -205,7 +247,8 @@ func (sender *Sender) buildEventsString(events moira.NotificationEvents, charsFo
 	return eventsString
 }
 
-func (sender *Sender) sendMessage(message string, contact string, triggerID string, useDirectMessaging bool, emoji string) (string, string, error) {
+// sendMessage posts message to the contact channel, if threadTimestamp is not empty message is posted as a thread reply.
+func (sender *Sender) sendMessage(message string, contact string, triggerID string, useDirectMessaging bool, emoji string, threadTimestamp string) (string, string, error) {
 	params := slack_client.PostMessageParameters{
 		Username:  "Moira",
 		AsUser:    useDirectMessaging,
@@ -217,7 +260,15 @@ func (sender *Sender) sendMessage(message string, contact string, triggerID stri
 		String("message", message).
 		Msg("Calling slack")
 
-	channelID, threadTimestamp, err := sender.client.PostMessage(contact, slack_client.MsgOptionText(message, false), slack_client.MsgOptionPostMessageParameters(params))
+	options := []slack_client.MsgOption{
+		slack_client.MsgOptionText(message, false),
+		slack_client.MsgOptionPostMessageParameters(params),
+	}
+	if threadTimestamp != "" {
+		options = append(options, slack_client.MsgOptionTS(threadTimestamp))
+	}
+
+	channelID, threadTimestamp, err := sender.client.PostMessage(contact, options...)
 	if err != nil {
 		errorText := err.Error()
 		if errorText == ErrorTextChannelArchived || errorText == ErrorTextNotInChannel ||
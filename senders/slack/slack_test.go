@@ -45,6 +45,13 @@ func TestInit(t *testing.T) {
 				So(sender.useEmoji, ShouldBeTrue)
 			})
 
+			Convey("thread_updates set to true", func() {
+				senderSettings["thread_updates"] = true
+				err := sender.Init(senderSettings, logger, nil, "")
+				So(err, ShouldBeNil)
+				So(sender.threadUpdates, ShouldBeTrue)
+			})
+
 			Convey("use_emoji set to something wrong", func() {
 				senderSettings["use_emoji"] = 123
 				err := sender.Init(senderSettings, logger, nil, "")
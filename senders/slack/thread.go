@@ -0,0 +1,104 @@
+package slack
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/database"
+	"github.com/moira-alert/moira/senders"
+
+	slack_client "github.com/slack-go/slack"
+)
+
+const messenger = "slack"
+
+// messageThread is the root message of trigger notifications in slack channel.
+type messageThread struct {
+	channelID string
+	timestamp string
+}
+
+func (thread messageThread) String() string {
+	return thread.channelID + ":" + thread.timestamp
+}
+
+func parseMessageThread(value string) (*messageThread, bool) {
+	channelID, timestamp, ok := strings.Cut(value, ":")
+	if !ok || channelID == "" || timestamp == "" {
+		return nil, false
+	}
+	return &messageThread{channelID: channelID, timestamp: timestamp}, true
+}
+
+// getThread returns opened thread of trigger in contact channel or nil if there is no one.
+func (sender *Sender) getThread(triggerID, contact string) *messageThread {
+	value, err := sender.DataBase.GetMessageThread(messenger, triggerID, contact)
+	if err != nil {
+		if !errors.Is(err, database.ErrNil) {
+			sender.logger.Warning().
+				String("trigger_id", triggerID).
+				String("contact_value", contact).
+				Error(err).
+				Msg("Failed to get slack thread, sending new message")
+		}
+		return nil
+	}
+	thread, ok := parseMessageThread(value)
+	if !ok {
+		sender.logger.Warning().
+			String("trigger_id", triggerID).
+			String("thread", value).
+			Msg("Invalid slack thread, sending new message")
+		return nil
+	}
+	return thread
+}
+
+func (sender *Sender) saveThread(triggerID, contact string, thread messageThread) {
+	if err := sender.DataBase.SetMessageThread(messenger, triggerID, contact, thread.String()); err != nil {
+		sender.logger.Warning().
+			String("trigger_id", triggerID).
+			String("contact_value", contact).
+			Error(err).
+			Msg("Failed to save slack thread")
+	}
+}
+
+func (sender *Sender) removeThread(triggerID, contact string) {
+	if err := sender.DataBase.RemoveMessageThread(messenger, triggerID, contact); err != nil {
+		sender.logger.Warning().
+			String("trigger_id", triggerID).
+			String("contact_value", contact).
+			Error(err).
+			Msg("Failed to remove slack thread")
+	}
+}
+
+// isTriggerRecovered returns true if trigger has no metrics in bad state, thread is kept open if last check can not be read.
+func (sender *Sender) isTriggerRecovered(triggerID string) bool {
+	recovered, err := senders.IsTriggerRecovered(sender.DataBase, triggerID)
+	if err != nil {
+		sender.logger.Warning().
+			String("trigger_id", triggerID).
+			Error(err).
+			Msg("Failed to check trigger recovery, keeping slack thread")
+		return false
+	}
+	return recovered
+}
+
+// updateThreadRoot edits the root message of thread to show the current trigger state.
+func (sender *Sender) updateThreadRoot(thread *messageThread, message string, state moira.State, triggerID string) {
+	if sender.useEmoji {
+		message = sender.getStateEmoji(state) + " " + message
+	}
+	_, _, _, err := sender.client.UpdateMessage(thread.channelID, thread.timestamp, slack_client.MsgOptionText(message, false))
+	if err != nil {
+		sender.logger.Warning().
+			String("trigger_id", triggerID).
+			String("thread", thread.String()).
+			Error(err).
+			Msg("Failed to update slack thread root message")
+	}
+}
@@ -0,0 +1,147 @@
+package slack
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/database"
+	logging "github.com/moira-alert/moira/logging/zerolog_adapter"
+	mock_moira_alert "github.com/moira-alert/moira/mock/moira-alert"
+	. "github.com/smartystreets/goconvey/convey"
+
+	slack_client "github.com/slack-go/slack"
+)
+
+type slackRequest struct {
+	method string
+	form   url.Values
+}
+
+func newTestSlackServer() (*httptest.Server, func() []slackRequest) {
+	var mutex sync.Mutex
+	requests := make([]slackRequest, 0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		mutex.Lock()
+		requests = append(requests, slackRequest{method: r.URL.Path, form: r.PostForm})
+		mutex.Unlock()
+		fmt.Fprint(w, `{"ok":true,"channel":"C123","ts":"1111.2222"}`)
+	}))
+	return server, func() []slackRequest {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return requests
+	}
+}
+
+func TestSendEventsWithThreadUpdates(t *testing.T) {
+	logger, _ := logging.ConfigureLog("stdout", "debug", "test", true)
+	location, _ := time.LoadLocation("UTC")
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+
+	trigger := moira.TriggerData{ID: "TriggerID", Name: "Name"}
+	contact := moira.ContactData{Type: "slack", Value: "#channel"}
+	errorEvents := moira.NotificationEvents{{Metric: "m1", OldState: moira.StateOK, State: moira.StateERROR}}
+	okEvents := moira.NotificationEvents{{Metric: "m1", OldState: moira.StateERROR, State: moira.StateOK}}
+
+	Convey("Send events with thread updates", t, func() {
+		server, getRequests := newTestSlackServer()
+		defer server.Close()
+		sender := Sender{
+			DataBase:      dataBase,
+			useEmoji:      true,
+			threadUpdates: true,
+			logger:        logger,
+			location:      location,
+			client:        slack_client.New("token", slack_client.OptionAPIURL(server.URL+"/")),
+		}
+
+		Convey("First notification opens thread", func() {
+			dataBase.EXPECT().GetMessageThread("slack", "TriggerID", "#channel").Return("", database.ErrNil)
+			dataBase.EXPECT().SetMessageThread("slack", "TriggerID", "#channel", "C123:1111.2222").Return(nil)
+
+			err := sender.SendEvents(errorEvents, contact, trigger, nil, false)
+			So(err, ShouldBeNil)
+
+			requests := getRequests()
+			So(requests, ShouldHaveLength, 1)
+			So(requests[0].method, ShouldEqual, "/chat.postMessage")
+			So(requests[0].form.Get("channel"), ShouldEqual, "#channel")
+			So(requests[0].form.Get("thread_ts"), ShouldBeEmpty)
+		})
+
+		Convey("Next notification is posted to thread and updates root message", func() {
+			dataBase.EXPECT().GetMessageThread("slack", "TriggerID", "#channel").Return("C123:1000.0001", nil)
+
+			err := sender.SendEvents(errorEvents, contact, trigger, nil, false)
+			So(err, ShouldBeNil)
+
+			requests := getRequests()
+			So(requests, ShouldHaveLength, 2)
+			So(requests[0].method, ShouldEqual, "/chat.postMessage")
+			So(requests[0].form.Get("channel"), ShouldEqual, "C123")
+			So(requests[0].form.Get("thread_ts"), ShouldEqual, "1000.0001")
+			So(requests[1].method, ShouldEqual, "/chat.update")
+			So(requests[1].form.Get("ts"), ShouldEqual, "1000.0001")
+			So(requests[1].form.Get("text"), ShouldStartWith, errorEmoji+" *ERROR*")
+		})
+
+		Convey("Recovery closes thread", func() {
+			dataBase.EXPECT().GetMessageThread("slack", "TriggerID", "#channel").Return("C123:1000.0001", nil)
+			dataBase.EXPECT().GetTriggerLastCheck("TriggerID").Return(moira.CheckData{State: moira.StateOK}, nil)
+			dataBase.EXPECT().RemoveMessageThread("slack", "TriggerID", "#channel").Return(nil)
+
+			err := sender.SendEvents(okEvents, contact, trigger, nil, false)
+			So(err, ShouldBeNil)
+
+			requests := getRequests()
+			So(requests, ShouldHaveLength, 2)
+			So(requests[1].form.Get("text"), ShouldStartWith, okEmoji+" *OK*")
+		})
+
+		Convey("Partial recovery keeps thread and root message", func() {
+			dataBase.EXPECT().GetMessageThread("slack", "TriggerID", "#channel").Return("C123:1000.0001", nil)
+			dataBase.EXPECT().GetTriggerLastCheck("TriggerID").Return(moira.CheckData{
+				State:   moira.StateOK,
+				Metrics: map[string]moira.MetricState{"m1": {State: moira.StateOK}, "m2": {State: moira.StateERROR}},
+			}, nil)
+
+			err := sender.SendEvents(okEvents, contact, trigger, nil, false)
+			So(err, ShouldBeNil)
+
+			requests := getRequests()
+			So(requests, ShouldHaveLength, 1)
+			So(requests[0].method, ShouldEqual, "/chat.postMessage")
+			So(requests[0].form.Get("thread_ts"), ShouldEqual, "1000.0001")
+		})
+
+		Convey("OK notification without thread does not open one", func() {
+			dataBase.EXPECT().GetMessageThread("slack", "TriggerID", "#channel").Return("", database.ErrNil)
+			dataBase.EXPECT().GetTriggerLastCheck("TriggerID").Return(moira.CheckData{State: moira.StateOK}, nil)
+
+			err := sender.SendEvents(okEvents, contact, trigger, nil, false)
+			So(err, ShouldBeNil)
+			So(getRequests(), ShouldHaveLength, 1)
+		})
+	})
+}
+
+func TestParseMessageThread(t *testing.T) {
+	Convey("Parse message thread", t, func() {
+		thread, ok := parseMessageThread("C123:1000.0001")
+		So(ok, ShouldBeTrue)
+		So(thread, ShouldResemble, &messageThread{channelID: "C123", timestamp: "1000.0001"})
+
+		_, ok = parseMessageThread("1000.0001")
+		So(ok, ShouldBeFalse)
+	})
+}
@@ -0,0 +1,354 @@
+package telegram
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/api/controller"
+	"github.com/moira-alert/moira/api/dto"
+	"github.com/moira-alert/moira/database"
+	"github.com/patrickmn/go-cache"
+	"gopkg.in/tucnak/telebot.v2"
+)
+
+const (
+	triggersCommand      = "/triggers"
+	stateCommand         = "/state"
+	maintenanceCommand   = "/maintenance"
+	subscriptionsCommand = "/subscriptions"
+	muteCommand          = "/mute"
+	helpCommand          = "/help"
+
+	maxTriggersInResponse = 10
+	defaultDateTimeFormat = "15:04 02.01.2006"
+
+	// contactOwnersKey is the key of telegram contact owners in contactOwners cache
+	contactOwnersKey = "owners"
+	contactOwnersTTL = time.Minute
+)
+
+const commandsHelp = `Available commands:
+/triggers <search> - find triggers by name
+/state <trigger id> - show trigger state
+/maintenance <trigger id> <duration> - set trigger maintenance, e.g. /maintenance 5f8e3a 1h
+/subscriptions - list your subscriptions
+/mute <tag> <duration> - set maintenance to all triggers with tag, e.g. /mute database 30m`
+
+var (
+	errNotLinked       = errors.New("telegram user is not linked to moira user")
+	errAmbiguousLinked = errors.New("telegram user is linked to several moira users")
+)
+
+type commandHandler func(sender *Sender, login string, args []string) (string, error)
+
+var commandHandlers = map[string]commandHandler{
+	triggersCommand:      (*Sender).triggersCommand,
+	stateCommand:         (*Sender).stateCommand,
+	maintenanceCommand:   (*Sender).maintenanceCommand,
+	subscriptionsCommand: (*Sender).subscriptionsCommand,
+	muteCommand:          (*Sender).muteCommand,
+	helpCommand:          (*Sender).helpCommand,
+}
+
+// isCommand returns true if message text is one of operator commands.
+func isCommand(text string) bool {
+	_, ok := commandHandlers[commandName(text)]
+	return ok
+}
+
+func commandName(text string) string {
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return ""
+	}
+	// Commands in groups are sent as /command@BotName
+	name, _, _ := strings.Cut(fields[0], "@")
+	return name
+}
+
+// handleCommand authorises telegram user and runs the command on behalf of the linked moira user.
+func (sender *Sender) handleCommand(message *telebot.Message) (string, error) {
+	login, err := sender.getLinkedLogin(message.Chat)
+	if errors.Is(err, errNotLinked) {
+		return "You are not linked to Moira user. Send /start and add this Telegram account as a contact in Moira.", nil
+	}
+	if errors.Is(err, errAmbiguousLinked) {
+		return "This Telegram account is added as a contact by several Moira users, commands are not allowed. Leave the contact to one user only.", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	fields := strings.Fields(message.Text)
+	return commandHandlers[commandName(message.Text)](sender, login, fields[1:])
+}
+
+// fromErrorResponse replies with controller error text, internal errors are returned to be logged.
+func fromErrorResponse(errorResponse *api.ErrorResponse) (string, error) {
+	if errorResponse.HTTPStatusCode == http.StatusInternalServerError {
+		return "", errorResponse.Err
+	}
+	return errorResponse.ErrorText, nil
+}
+
+// getLinkedLogin returns login of moira user who owns telegram contact of this chat.
+// The chat must be registered by /start, so the username can not be used from other chat.
+// Commands are refused if contacts with username or chat id are owned by several users.
+func (sender *Sender) getLinkedLogin(chat *telebot.Chat) (string, error) {
+	if chat.Username == "" {
+		return "", errNotLinked
+	}
+	username := "@" + chat.Username
+	chatID := strconv.FormatInt(chat.ID, 10)
+
+	linkedChatID, err := sender.DataBase.GetIDByUsername(messenger, username)
+	if errors.Is(err, database.ErrNil) {
+		return "", errNotLinked
+	}
+	if err != nil {
+		return "", err
+	}
+	if linkedChatID != chatID {
+		return "", errNotLinked
+	}
+
+	owners, err := sender.getContactOwners()
+	if err != nil {
+		return "", err
+	}
+	logins := make(map[string]struct{})
+	for _, value := range []string{username, chatID} {
+		for _, login := range owners[value] {
+			logins[login] = struct{}{}
+		}
+	}
+	if len(logins) > 1 {
+		return "", errAmbiguousLinked
+	}
+	for login := range logins {
+		return login, nil
+	}
+	return "", errNotLinked
+}
+
+// getContactOwners returns logins of users by values of their telegram contacts.
+// Contacts are scanned once in contactOwnersTTL instead of every incoming command.
+func (sender *Sender) getContactOwners() (map[string][]string, error) {
+	if owners, ok := sender.contactOwners.Get(contactOwnersKey); ok {
+		return owners.(map[string][]string), nil
+	}
+
+	contacts, err := sender.DataBase.GetAllContacts()
+	if err != nil {
+		return nil, err
+	}
+	owners := make(map[string][]string)
+	for _, contact := range contacts {
+		if contact == nil || contact.Type != sender.contactType || contact.User == "" {
+			continue
+		}
+		owners[contact.Value] = append(owners[contact.Value], contact.User)
+	}
+	sender.contactOwners.Set(contactOwnersKey, owners, cache.DefaultExpiration)
+	return owners, nil
+}
+
+func (sender *Sender) triggersCommand(_ string, args []string) (string, error) {
+	if len(args) == 0 {
+		return "Usage: /triggers <search>", nil
+	}
+	search := strings.ToLower(strings.Join(args, " "))
+
+	triggersList, errorResponse := controller.GetAllTriggers(sender.DataBase)
+	if errorResponse != nil {
+		return fromErrorResponse(errorResponse)
+	}
+
+	found := make([]moira.TriggerCheck, 0)
+	for _, triggerCheck := range triggersList.List {
+		if strings.Contains(strings.ToLower(triggerCheck.Name), search) {
+			found = append(found, triggerCheck)
+		}
+	}
+	if len(found) == 0 {
+		return "No triggers found", nil
+	}
+	sort.Slice(found, func(i, j int) bool {
+		return found[i].Name < found[j].Name
+	})
+
+	var response strings.Builder
+	for i, triggerCheck := range found {
+		if i == maxTriggersInResponse {
+			response.WriteString(fmt.Sprintf("...and %d more triggers", len(found)-maxTriggersInResponse))
+			break
+		}
+		response.WriteString(fmt.Sprintf("%s %s %s\n", sender.getStateEmoji(triggerCheck.LastCheck.State), triggerCheck.Name, triggerCheck.ID))
+	}
+	return strings.TrimSuffix(response.String(), "\n"), nil
+}
+
+func (sender *Sender) stateCommand(_ string, args []string) (string, error) {
+	if len(args) != 1 {
+		return "Usage: /state <trigger id>", nil
+	}
+	triggerID := args[0]
+
+	trigger, errorResponse := controller.GetTrigger(sender.DataBase, triggerID)
+	if errorResponse != nil {
+		return fromErrorResponse(errorResponse)
+	}
+	lastCheck, errorResponse := controller.GetTriggerLastCheck(sender.DataBase, triggerID)
+	if errorResponse != nil {
+		return fromErrorResponse(errorResponse)
+	}
+
+	var response strings.Builder
+	if lastCheck.CheckData == nil {
+		response.WriteString(fmt.Sprintf("%s\nTrigger has not been checked yet", trigger.Name))
+	} else {
+		checkData := lastCheck.CheckData
+		response.WriteString(fmt.Sprintf("%s %s %s", sender.getStateEmoji(checkData.State), checkData.State, trigger.Name))
+		if checkData.Maintenance > time.Now().Unix() {
+			response.WriteString(fmt.Sprintf("\nMaintenance until %s", sender.formatTime(checkData.Maintenance)))
+		}
+		response.WriteString(formatMetricStates(checkData.Metrics))
+	}
+	if uri := sender.getTriggerURI(triggerID); uri != "" {
+		response.WriteString("\n" + uri)
+	}
+	return response.String(), nil
+}
+
+func (sender *Sender) maintenanceCommand(login string, args []string) (string, error) {
+	if len(args) != 2 { //nolint
+		return "Usage: /maintenance <trigger id> <duration>", nil
+	}
+	triggerID := args[0]
+	until, err := parseMaintenanceDuration(args[1])
+	if err != nil {
+		return err.Error(), nil
+	}
+
+	trigger, errorResponse := controller.GetTrigger(sender.DataBase, triggerID)
+	if errorResponse != nil {
+		return fromErrorResponse(errorResponse)
+	}
+	if errorResponse = sender.setTriggerMaintenance(triggerID, until, login); errorResponse != nil {
+		return fromErrorResponse(errorResponse)
+	}
+	return fmt.Sprintf("Trigger %s is in maintenance until %s", trigger.Name, sender.formatTime(until)), nil
+}
+
+func (sender *Sender) subscriptionsCommand(login string, _ []string) (string, error) {
+	subscriptions, errorResponse := controller.GetUserSubscriptions(sender.DataBase, login)
+	if errorResponse != nil {
+		return fromErrorResponse(errorResponse)
+	}
+	if len(subscriptions.List) == 0 {
+		return "You have no subscriptions", nil
+	}
+
+	var response strings.Builder
+	for _, subscription := range subscriptions.List {
+		status := "enabled"
+		if !subscription.Enabled {
+			status = "disabled"
+		}
+		tags := strings.Join(subscription.Tags, ", ")
+		if subscription.AnyTags {
+			tags = "any tags"
+		}
+		response.WriteString(fmt.Sprintf("%s: %s (%s)\n", subscription.ID, tags, status))
+	}
+	return strings.TrimSuffix(response.String(), "\n"), nil
+}
+
+func (sender *Sender) muteCommand(login string, args []string) (string, error) {
+	if len(args) != 2 { //nolint
+		return "Usage: /mute <tag> <duration>", nil
+	}
+	tag := args[0]
+	until, err := parseMaintenanceDuration(args[1])
+	if err != nil {
+		return err.Error(), nil
+	}
+
+	triggerIDs, err := sender.DataBase.GetTagTriggerIDs(tag)
+	if err != nil {
+		return "", err
+	}
+	if len(triggerIDs) == 0 {
+		return fmt.Sprintf("No triggers with tag %s", tag), nil
+	}
+	for _, triggerID := range triggerIDs {
+		if errorResponse := sender.setTriggerMaintenance(triggerID, until, login); errorResponse != nil {
+			return fromErrorResponse(errorResponse)
+		}
+	}
+	return fmt.Sprintf("%d triggers with tag %s are in maintenance until %s", len(triggerIDs), tag, sender.formatTime(until)), nil
+}
+
+func (sender *Sender) helpCommand(_ string, _ []string) (string, error) {
+	return commandsHelp, nil
+}
+
+func (sender *Sender) setTriggerMaintenance(triggerID string, until int64, login string) *api.ErrorResponse {
+	maintenance := dto.TriggerMaintenance{Trigger: &until}
+	return controller.SetTriggerMaintenance(sender.DataBase, triggerID, maintenance, login, time.Now().Unix())
+}
+
+func (sender *Sender) getStateEmoji(state moira.State) string {
+	if emoji, ok := emojiStates[state]; ok {
+		return emoji
+	}
+	return string(state)
+}
+
+func (sender *Sender) getTriggerURI(triggerID string) string {
+	trigger := moira.TriggerData{ID: triggerID}
+	return trigger.GetTriggerURI(sender.frontURI)
+}
+
+func (sender *Sender) formatTime(timestamp int64) string {
+	location := sender.location
+	if location == nil {
+		location = time.UTC
+	}
+	format := sender.dateTimeFormat
+	if format == "" {
+		format = defaultDateTimeFormat
+	}
+	return time.Unix(timestamp, 0).In(location).Format(format)
+}
+
+// formatMetricStates returns count of metrics in each non OK state.
+func formatMetricStates(metrics map[string]moira.MetricState) string {
+	counts := make(map[moira.State]int)
+	for _, metric := range metrics {
+		counts[metric.State]++
+	}
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("\nMetrics: %d", len(metrics)))
+	for _, state := range []moira.State{moira.StateERROR, moira.StateWARN, moira.StateNODATA, moira.StateEXCEPTION} {
+		if counts[state] > 0 {
+			result.WriteString(fmt.Sprintf(", %s: %d", state, counts[state]))
+		}
+	}
+	return result.String()
+}
+
+func parseMaintenanceDuration(value string) (int64, error) {
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		return 0, fmt.Errorf("invalid duration %s, use e.g. 30m or 1h", value)
+	}
+	return time.Now().Add(duration).Unix(), nil
+}
@@ -0,0 +1,173 @@
+package telegram
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/database"
+	mock_moira_alert "github.com/moira-alert/moira/mock/moira-alert"
+	"github.com/patrickmn/go-cache"
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/tucnak/telebot.v2"
+)
+
+func TestHandleCommand(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+
+	linkedContacts := []*moira.ContactData{
+		{Type: "mail", Value: "@User", User: "mail-user"},
+		{Type: messenger, Value: "@User", User: "moira-user"},
+	}
+	newMessage := func(text string) *telebot.Message {
+		return &telebot.Message{
+			Chat: &telebot.Chat{ID: 123, Type: telebot.ChatPrivate, Username: "User"},
+			Text: text,
+		}
+	}
+	expectLinked := func() {
+		dataBase.EXPECT().GetIDByUsername(messenger, "@User").Return("123", nil)
+		dataBase.EXPECT().GetAllContacts().Return(linkedContacts, nil)
+	}
+
+	Convey("Test telegram bot commands", t, func() {
+		sender := Sender{DataBase: dataBase, contactType: messenger, location: time.UTC, contactOwners: cache.New(contactOwnersTTL, contactOwnersTTL)}
+
+		Convey("Unknown command is not handled", func() {
+			So(isCommand("/unknown"), ShouldBeFalse)
+			So(isCommand("/state@MoiraBot 123"), ShouldBeTrue)
+		})
+
+		Convey("Not linked user", func() {
+			Convey("chat was not registered by /start", func() {
+				dataBase.EXPECT().GetIDByUsername(messenger, "@User").Return("", database.ErrNil)
+				response, err := sender.getResponseMessage(newMessage("/subscriptions"))
+				So(err, ShouldBeNil)
+				So(response, ShouldStartWith, "You are not linked to Moira user")
+			})
+
+			Convey("username registered from other chat", func() {
+				dataBase.EXPECT().GetIDByUsername(messenger, "@User").Return("456", nil)
+				response, err := sender.getResponseMessage(newMessage("/subscriptions"))
+				So(err, ShouldBeNil)
+				So(response, ShouldStartWith, "You are not linked to Moira user")
+			})
+
+			Convey("no telegram contact in moira", func() {
+				dataBase.EXPECT().GetIDByUsername(messenger, "@User").Return("123", nil)
+				dataBase.EXPECT().GetAllContacts().Return(linkedContacts[:1], nil)
+				response, err := sender.getResponseMessage(newMessage("/subscriptions"))
+				So(err, ShouldBeNil)
+				So(response, ShouldStartWith, "You are not linked to Moira user")
+			})
+		})
+
+		Convey("Telegram account linked to several users", func() {
+			dataBase.EXPECT().GetIDByUsername(messenger, "@User").Return("123", nil)
+			dataBase.EXPECT().GetAllContacts().Return(append(linkedContacts, &moira.ContactData{Type: messenger, Value: "123", User: "other-user"}), nil)
+			response, err := sender.getResponseMessage(newMessage("/subscriptions"))
+			So(err, ShouldBeNil)
+			So(response, ShouldStartWith, "This Telegram account is added as a contact by several Moira users")
+		})
+
+		Convey("Contacts are scanned once for several commands", func() {
+			dataBase.EXPECT().GetIDByUsername(messenger, "@User").Return("123", nil).Times(2)
+			dataBase.EXPECT().GetAllContacts().Return(linkedContacts, nil)
+			dataBase.EXPECT().GetUserSubscriptionIDs("moira-user").Return([]string{}, nil).Times(2)
+			dataBase.EXPECT().GetSubscriptions([]string{}).Return([]*moira.SubscriptionData{}, nil).Times(2)
+			for i := 0; i < 2; i++ {
+				_, err := sender.getResponseMessage(newMessage("/subscriptions"))
+				So(err, ShouldBeNil)
+			}
+		})
+
+		Convey("Subscriptions command", func() {
+			expectLinked()
+			dataBase.EXPECT().GetUserSubscriptionIDs("moira-user").Return([]string{"sub1"}, nil)
+			dataBase.EXPECT().GetSubscriptions([]string{"sub1"}).Return([]*moira.SubscriptionData{
+				{ID: "sub1", Tags: []string{"db", "prod"}, Enabled: true},
+			}, nil)
+
+			response, err := sender.getResponseMessage(newMessage("/subscriptions"))
+			So(err, ShouldBeNil)
+			So(response, ShouldEqual, "sub1: db, prod (enabled)")
+		})
+
+		Convey("Triggers command", func() {
+			expectLinked()
+			dataBase.EXPECT().GetAllTriggerIDs().Return([]string{"t1", "t2"}, nil)
+			dataBase.EXPECT().GetTriggerChecks([]string{"t1", "t2"}).Return([]*moira.TriggerCheck{
+				{Trigger: moira.Trigger{ID: "t1", Name: "Database errors"}, LastCheck: moira.CheckData{State: moira.StateERROR}},
+				{Trigger: moira.Trigger{ID: "t2", Name: "Frontend latency"}, LastCheck: moira.CheckData{State: moira.StateOK}},
+			}, nil)
+
+			response, err := sender.getResponseMessage(newMessage("/triggers database"))
+			So(err, ShouldBeNil)
+			So(response, ShouldEqual, emojiStates[moira.StateERROR]+" Database errors t1")
+		})
+
+		Convey("State command", func() {
+			Convey("trigger not found", func() {
+				expectLinked()
+				dataBase.EXPECT().GetTrigger("t1").Return(moira.Trigger{}, database.ErrNil)
+				response, err := sender.getResponseMessage(newMessage("/state t1"))
+				So(err, ShouldBeNil)
+				So(response, ShouldEqual, "trigger not found")
+			})
+
+			Convey("trigger has metrics", func() {
+				sender.frontURI = "http://moira.url"
+				expectLinked()
+				dataBase.EXPECT().GetTrigger("t1").Return(moira.Trigger{ID: "t1", Name: "Database errors"}, nil)
+				dataBase.EXPECT().GetTriggerThrottling("t1").Return(time.Unix(0, 0), time.Unix(0, 0))
+				dataBase.EXPECT().GetTriggerLastCheck("t1").Return(moira.CheckData{
+					State: moira.StateERROR,
+					Metrics: map[string]moira.MetricState{
+						"m1": {State: moira.StateERROR},
+						"m2": {State: moira.StateOK},
+					},
+				}, nil)
+				response, err := sender.getResponseMessage(newMessage("/state t1"))
+				So(err, ShouldBeNil)
+				So(response, ShouldEqual, emojiStates[moira.StateERROR]+" ERROR Database errors\nMetrics: 2, ERROR: 1\nhttp://moira.url/trigger/t1")
+			})
+		})
+
+		Convey("Maintenance command", func() {
+			Convey("invalid duration", func() {
+				expectLinked()
+				response, err := sender.getResponseMessage(newMessage("/maintenance t1 forever"))
+				So(err, ShouldBeNil)
+				So(response, ShouldEqual, "invalid duration forever, use e.g. 30m or 1h")
+			})
+
+			Convey("maintenance is set by linked user", func() {
+				expectLinked()
+				dataBase.EXPECT().GetTrigger("t1").Return(moira.Trigger{ID: "t1", Name: "Database errors"}, nil)
+				dataBase.EXPECT().GetTriggerThrottling("t1").Return(time.Unix(0, 0), time.Unix(0, 0))
+				dataBase.EXPECT().AcquireTriggerCheckLock("t1", gomock.Any()).Return(nil)
+				dataBase.EXPECT().SetTriggerCheckMaintenance("t1", nil, gomock.Any(), "moira-user", gomock.Any()).Return(nil)
+				dataBase.EXPECT().ReleaseTriggerCheckLock("t1")
+				response, err := sender.getResponseMessage(newMessage("/maintenance t1 1h"))
+				So(err, ShouldBeNil)
+				So(response, ShouldStartWith, "Trigger Database errors is in maintenance until ")
+			})
+		})
+
+		Convey("Mute command sets maintenance to triggers with tag", func() {
+			expectLinked()
+			dataBase.EXPECT().GetTagTriggerIDs("db").Return([]string{"t1", "t2"}, nil)
+			for _, triggerID := range []string{"t1", "t2"} {
+				dataBase.EXPECT().AcquireTriggerCheckLock(triggerID, gomock.Any()).Return(nil)
+				dataBase.EXPECT().SetTriggerCheckMaintenance(triggerID, nil, gomock.Any(), "moira-user", gomock.Any()).Return(nil)
+				dataBase.EXPECT().ReleaseTriggerCheckLock(triggerID)
+			}
+			response, err := sender.getResponseMessage(newMessage("/mute db 30m"))
+			So(err, ShouldBeNil)
+			So(response, ShouldStartWith, "2 triggers with tag db are in maintenance until ")
+		})
+	})
+}
@@ -34,6 +34,8 @@ func (sender *Sender) getResponseMessage(message *telebot.Message) (string, erro
 			return "", err
 		}
 		return fmt.Sprintf("Okay, %s, your id is %s", strings.Trim(fmt.Sprintf("%s %s", message.Sender.FirstName, message.Sender.LastName), " "), chatID), nil
+	case message.Chat.Type == telebot.ChatPrivate && isCommand(message.Text):
+		return sender.handleCommand(message)
 	case message.Chat.Type == telebot.ChatSuperGroup || message.Chat.Type == telebot.ChatGroup:
 		err := sender.DataBase.SetUsernameID(messenger, message.Chat.Title, chatID)
 		if err != nil {
@@ -8,7 +8,9 @@ import (
 
 	"github.com/mitchellh/mapstructure"
 	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/senders"
 	"github.com/moira-alert/moira/worker"
+	"github.com/patrickmn/go-cache"
 	"gopkg.in/tucnak/telebot.v2"
 )
 
@@ -33,18 +35,25 @@ var (
 
 // Structure that represents the Telegram configuration in the YAML file.
 type config struct {
-	APIToken string `mapstructure:"api_token"`
-	FrontURI string `mapstructure:"front_uri"`
+	APIToken    string `mapstructure:"api_token"`
+	FrontURI    string `mapstructure:"front_uri"`
+	ContactType string `mapstructure:"contact_type"`
+	// Go template used instead of default message format, contact template takes precedence
+	MessageTemplate string `mapstructure:"message_template"`
 }
 
 // Sender implements moira sender interface via telegram.
 type Sender struct {
-	DataBase moira.Database
-	logger   moira.Logger
-	apiToken string
-	frontURI string
-	bot      *telebot.Bot
-	location *time.Location
+	DataBase        moira.Database
+	logger          moira.Logger
+	apiToken        string
+	frontURI        string
+	contactType     string
+	messageTemplate string
+	bot             *telebot.Bot
+	location        *time.Location
+	dateTimeFormat  string
+	contactOwners   *cache.Cache
 }
 
 func removeTokenFromError(err error, bot *telebot.Bot) error {
@@ -69,10 +78,20 @@ func (sender *Sender) Init(senderSettings interface{}, logger moira.Logger, loca
 	if cfg.APIToken == "" {
 		return fmt.Errorf("can not read telegram api_token from config")
 	}
+	if err = senders.ValidateMessageTemplate("telegram", cfg.MessageTemplate); err != nil {
+		return err
+	}
 	sender.apiToken = cfg.APIToken
 	sender.frontURI = cfg.FrontURI
+	sender.contactType = cfg.ContactType
+	sender.messageTemplate = cfg.MessageTemplate
+	if sender.contactType == "" {
+		sender.contactType = messenger
+	}
 	sender.logger = logger
 	sender.location = location
+	sender.dateTimeFormat = dateTimeFormat
+	sender.contactOwners = cache.New(contactOwnersTTL, contactOwnersTTL)
 	sender.bot, err = telebot.NewBot(telebot.Settings{
 		Token:  sender.apiToken,
 		Poller: &telebot.LongPoller{Timeout: pollerTimeout},
@@ -9,6 +9,7 @@ import (
 	"gopkg.in/tucnak/telebot.v2"
 
 	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/senders"
 )
 
 type messageType string
@@ -34,7 +35,12 @@ var characterLimits = map[messageType]int{
 // SendEvents implements Sender interface Send.
 func (sender *Sender) SendEvents(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData, plots [][]byte, throttled bool) error {
 	msgType := getMessageType(plots)
-	message := sender.buildMessage(events, trigger, throttled, characterLimits[msgType])
+	message, ok := senders.PopulateMessageTemplate(sender.messageTemplate, events, contact, trigger, throttled, sender.frontURI, sender.logger)
+	if ok {
+		message = senders.TruncateMessage(message, characterLimits[msgType])
+	} else {
+		message = sender.buildMessage(events, trigger, throttled, characterLimits[msgType])
+	}
 	sender.logger.Debug().
 		String("chat_id", contact.Value).
 		String("message", message).
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/mitchellh/mapstructure"
+	"github.com/moira-alert/moira/senders"
 	"github.com/moira-alert/moira/senders/victorops/api"
 
 	"github.com/moira-alert/moira"
@@ -15,6 +16,7 @@ type config struct {
 	RoutingURL string `mapstructure:"routing_url"`
 	ImageStore string `mapstructure:"image_store"`
 	FrontURI   string `mapstructure:"front_uri"`
+	DedupBy    string `mapstructure:"dedup_by"`
 }
 
 // Sender implements moira sender interface for victorops.
@@ -30,6 +32,7 @@ type Sender struct {
 
 	routingURL string
 	client     *api.Client
+	dedupMode  senders.DedupMode
 }
 
 // Init loads yaml config, configures the victorops sender.
@@ -45,6 +48,11 @@ func (sender *Sender) Init(senderSettings interface{}, logger moira.Logger, loca
 		return fmt.Errorf("cannot read the routing url from the yaml config")
 	}
 
+	sender.dedupMode, err = senders.ParseDedupMode(cfg.DedupBy)
+	if err != nil {
+		return fmt.Errorf("failed to read victorops dedup_by: %w", err)
+	}
+
 	sender.imageStoreID = cfg.ImageStore
 	if sender.imageStoreID == "" {
 		logger.Warning().Msg("Cannot read image_store from the config, will not be able to attach plot images to events")
@@ -49,6 +49,14 @@ func TestInit(t *testing.T) {
 			So(sender.location, ShouldResemble, location)
 			So(sender.client, ShouldResemble, api.NewClient("https://testurl.com", nil))
 		})
+		Convey("Wrong dedup_by", func() {
+			senderSettings := map[string]interface{}{
+				"routing_url": "https://testurl.com",
+				"dedup_by":    "tag",
+			}
+			err := sender.Init(senderSettings, logger, location, "15:04")
+			So(err, ShouldNotBeNil)
+		})
 		Convey("Wrong image_store name", func() {
 			senderSettings := map[string]interface{}{
 				"front_uri":   "http://moira.uri",
@@ -8,31 +8,46 @@ import (
 	stripmd "github.com/writeas/go-strip-markdown"
 
 	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/senders"
 	"github.com/moira-alert/moira/senders/victorops/api"
 )
 
 // SendEvents implements Sender interface Send.
+// Each incident is sent with its own entity id, so victorops resolves it on RECOVERY message.
 func (sender *Sender) SendEvents(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData, plots [][]byte, throttled bool) error {
-	createAlertRequest := sender.buildCreateAlertRequest(events, trigger, throttled, plots, time.Now().Unix())
-	err := sender.client.CreateAlert(contact.Value, createAlertRequest)
+	dedupMode := senders.GetContactDedupMode(contact, sender.dedupMode)
+	incidents, err := senders.GroupIncidents(sender.DataBase, events, trigger, dedupMode, throttled)
 	if err != nil {
-		return fmt.Errorf("error while sending alert to victorops: %w", err)
+		return err
+	}
+	for _, incident := range incidents {
+		createAlertRequest := sender.buildCreateAlertRequest(incident, trigger, throttled, plots, time.Now().Unix())
+		err := sender.client.CreateAlert(contact.Value, createAlertRequest)
+		if err != nil {
+			return fmt.Errorf("error while sending alert to victorops: %w", err)
+		}
 	}
 	return nil
 }
 
-func (sender *Sender) buildCreateAlertRequest(events moira.NotificationEvents, trigger moira.TriggerData, throttled bool, plots [][]byte, time int64) api.CreateAlertRequest {
+func (sender *Sender) buildCreateAlertRequest(incident senders.Incident, trigger moira.TriggerData, throttled bool, plots [][]byte, time int64) api.CreateAlertRequest {
+	events := incident.Events
 	triggerURI := trigger.GetTriggerURI(sender.frontURI)
 
+	messageType := sender.getMessageType(events)
+	if incident.Resolved {
+		messageType = api.Recovery
+	}
+
 	createAlertRequest := api.CreateAlertRequest{
-		MessageType:       sender.getMessageType(events),
+		MessageType:       messageType,
 		StateMessage:      sender.buildMessage(events, trigger, throttled),
 		EntityDisplayName: sender.buildTitle(events, trigger, throttled),
 		StateStartTime:    events[len(events)-1].Timestamp,
 		TriggerURL:        triggerURI,
 		Timestamp:         time,
 		MonitoringTool:    "Moira",
-		EntityID:          trigger.ID,
+		EntityID:          incident.DedupKey,
 	}
 
 	if len(plots) > 0 && sender.imageStoreConfigured {
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/moira-alert/moira/senders"
 	"github.com/moira-alert/moira/senders/victorops/api"
 
 	"github.com/moira-alert/moira"
@@ -99,7 +100,7 @@ func TestBuildCreateAlertRequest(t *testing.T) {
 
 		Convey("Build CreateAlertRequest with one moira event and plot", func() {
 			imageStore.EXPECT().StoreImage([]byte("test")).Return("test", nil)
-			actual := sender.buildCreateAlertRequest(moira.NotificationEvents{event}, trigger, false, [][]byte{[]byte("test")}, 150000000)
+			actual := sender.buildCreateAlertRequest(senders.Incident{DedupKey: trigger.ID, Events: moira.NotificationEvents{event}}, trigger, false, [][]byte{[]byte("test")}, 150000000)
 			expected := api.CreateAlertRequest{
 				MessageType:       api.Warning,
 				StateMessage:      sender.buildMessage(moira.NotificationEvents{event}, trigger, false),
@@ -113,6 +114,15 @@ func TestBuildCreateAlertRequest(t *testing.T) {
 			}
 			So(actual, ShouldResemble, expected)
 		})
+
+		Convey("Build recovery CreateAlertRequest for resolved metric incident", func() {
+			recovered := event
+			recovered.OldState, recovered.State = moira.StateERROR, moira.StateOK
+			incident := senders.Incident{DedupKey: "TriggerID:Metric", Events: moira.NotificationEvents{recovered}, Resolved: true}
+			actual := sender.buildCreateAlertRequest(incident, trigger, false, nil, 150000000)
+			So(actual.MessageType, ShouldEqual, api.Recovery)
+			So(actual.EntityID, ShouldEqual, "TriggerID:Metric")
+		})
 	})
 }
 
@@ -4,7 +4,6 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
-	"html"
 	"net/http"
 	"net/url"
 	"strings"
@@ -59,13 +58,45 @@ func (sender *Sender) buildRequestBody(
 		return buildDefaultRequestBody(events, contact, trigger, plots, throttled)
 	}
 
-	webhookBodyPopulater := templating.NewWebhookBodyPopulater(contact.ToTemplateContact())
+	plotURLs := make([]string, 0)
+	if sender.bodyUsesPlots {
+		plotURLs = sender.storePlots(plots)
+	}
+
+	webhookBodyPopulater := templating.NewWebhookBodyPopulater(
+		contact.ToTemplateContact(),
+		trigger.ToTemplateTrigger(sender.frontURI),
+		events.ToTemplateEvents(),
+		string(events.GetCurrentState(throttled)),
+		throttled,
+		plotURLs,
+	)
 	populatedBody, err := webhookBodyPopulater.Populate(sender.body)
 	if err != nil {
 		return nil, err
 	}
 
-	return []byte(html.UnescapeString(populatedBody)), nil
+	return []byte(populatedBody), nil
+}
+
+// storePlots uploads plots to image store and returns their URLs.
+// Plots which could not be stored are skipped.
+func (sender *Sender) storePlots(plots [][]byte) []string {
+	plotURLs := make([]string, 0, len(plots))
+	if !sender.imageStoreConfigured {
+		return plotURLs
+	}
+	for _, plot := range plots {
+		plotURL, err := sender.imageStore.StoreImage(plot)
+		if err != nil {
+			sender.log.Warning().
+				Error(err).
+				Msg("Could not store the plot image in the image store")
+			continue
+		}
+		plotURLs = append(plotURLs, plotURL)
+	}
+	return plotURLs
 }
 
 func buildDefaultRequestBody(
@@ -1,13 +1,18 @@
 package webhook
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"testing"
 
+	"github.com/golang/mock/gomock"
 	. "github.com/smartystreets/goconvey/convey"
 
 	"github.com/moira-alert/moira"
+	logging "github.com/moira-alert/moira/logging/zerolog_adapter"
+	mock_moira_alert "github.com/moira-alert/moira/mock/moira-alert"
 )
 
 const (
@@ -247,6 +252,66 @@ func TestBuildRequestBody(t *testing.T) {
 	})
 }
 
+func TestBuildTemplatedRequestBody(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	imageStore := mock_moira_alert.NewMockImageStore(mockCtrl)
+	logger, _ := logging.ConfigureLog("stdout", "info", "test", true)
+
+	sender := Sender{
+		body: `{"summary": {{ json (printf "%s %s" .State .Trigger.Name) }}, "url": {{ json .Trigger.URI }}, ` +
+			`"tags": {{ json .Trigger.Tags }}, "plots": {{ json .Plots }}, "throttled": {{ .Throttled }}, "events": [` +
+			`{{ range $i, $event := .Events }}{{ if $i }}, {{ end }}{"metric": {{ json $event.Metric }}, "value": {{ index $event.Values "t1" }}, ` +
+			`"state": {{ json (toLower $event.State) }}, "time": {{ json (formatTime $event.Timestamp "2006-01-02T15:04:05Z07:00") }}}{{ end }}]}`,
+		bodyUsesPlots: true,
+		frontURI:      "https://moira.url",
+		log:           logger,
+	}
+	events := moira.NotificationEvents{
+		{Metric: `metric "quoted"`, Values: map[string]float64{"t1": 30}, Timestamp: 15, State: moira.StateERROR, OldState: moira.StateOK},
+	}
+	trigger := moira.TriggerData{ID: "triggerID", Name: `name with "quotes" & <tags>`, Tags: []string{"tag1"}}
+
+	Convey("Test building templated request body", t, func() {
+		Convey("Without image store", func() {
+			requestBody, err := sender.buildRequestBody(events, testContact, trigger, [][]byte{[]byte("plot")}, false)
+			So(err, ShouldBeNil)
+			So(json.Valid(requestBody), ShouldBeTrue)
+			So(string(requestBody), ShouldEqual, `{"summary": "ERROR name with \"quotes\" & <tags>", "url": "https://moira.url/trigger/triggerID", `+
+				`"tags": ["tag1"], "plots": [], "throttled": false, "events": [`+
+				`{"metric": "metric \"quoted\"", "value": 30, "state": "error", "time": "1970-01-01T00:00:15Z"}]}`)
+		})
+
+		Convey("With image store", func() {
+			sender.imageStore = imageStore
+			sender.imageStoreConfigured = true
+			defer func() {
+				sender.imageStore = nil
+				sender.imageStoreConfigured = false
+			}()
+			imageStore.EXPECT().StoreImage([]byte("plot1")).Return("https://images.url/plot1.png", nil)
+			imageStore.EXPECT().StoreImage([]byte("plot2")).Return("", errors.New("error"))
+
+			requestBody, err := sender.buildRequestBody(events, testContact, trigger, [][]byte{[]byte("plot1"), []byte("plot2")}, true)
+			So(err, ShouldBeNil)
+			So(json.Valid(requestBody), ShouldBeTrue)
+			So(string(requestBody), ShouldContainSubstring, `"plots": ["https://images.url/plot1.png"], "throttled": true`)
+		})
+
+		Convey("Without plots in template", func() {
+			sender := sender
+			sender.body = `{"name": {{ json .Trigger.Name }}, "desc": "<b>{{ .Trigger.Name }}</b>"}`
+			sender.bodyUsesPlots = false
+			sender.imageStore = imageStore
+			sender.imageStoreConfigured = true
+
+			requestBody, err := sender.buildRequestBody(events, testContact, trigger, [][]byte{[]byte("plot1")}, false)
+			So(err, ShouldBeNil)
+			So(string(requestBody), ShouldEqual, `{"name": "name with \"quotes\" & <tags>", "desc": "<b>name with "quotes" & <tags></b>"}`)
+		})
+	})
+}
+
 func TestBuildRequestURL(t *testing.T) {
 	Convey("URL should contain variables values", t, func() {
 		for _, testCase := range requestURLTestCases {
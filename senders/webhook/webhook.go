@@ -9,6 +9,8 @@ import (
 
 	"github.com/mitchellh/mapstructure"
 	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/senders"
+	"github.com/moira-alert/moira/templating"
 )
 
 var ErrMissingURL = errors.New("can not read url from config")
@@ -21,17 +23,25 @@ type config struct {
 	User     string            `mapstructure:"user"`
 	Password string            `mapstructure:"password"`
 	Timeout  int               `mapstructure:"timeout"`
+	FrontURI string            `mapstructure:"front_uri"`
+	// Image store used to make plot URLs available in body template
+	ImageStore string `mapstructure:"image_store"`
 }
 
 // Sender implements moira sender interface via webhook.
 type Sender struct {
-	url      string
-	body     string
-	user     string
-	password string
-	headers  map[string]string
-	client   *http.Client
-	log      moira.Logger
+	ImageStores          map[string]moira.ImageStore
+	imageStore           moira.ImageStore
+	imageStoreConfigured bool
+	url                  string
+	body                 string
+	bodyUsesPlots        bool
+	user                 string
+	password             string
+	frontURI             string
+	headers              map[string]string
+	client               *http.Client
+	log                  moira.Logger
 }
 
 // Init read yaml config.
@@ -48,6 +58,19 @@ func (sender *Sender) Init(senderSettings interface{}, logger moira.Logger, loca
 	}
 
 	sender.body = cfg.Body
+	sender.frontURI = cfg.FrontURI
+
+	if cfg.Body != "" {
+		// Plots are uploaded to image store only if body template uses them
+		sender.bodyUsesPlots, err = templating.WebhookBodyUsesPlots(cfg.Body)
+		if err != nil {
+			return fmt.Errorf("failed to parse webhook body template: %w", err)
+		}
+	}
+
+	if cfg.ImageStore != "" {
+		_, sender.imageStore, sender.imageStoreConfigured = senders.ReadImageStoreConfig(senderSettings, sender.ImageStores, logger)
+	}
 
 	sender.user, sender.password = cfg.User, cfg.Password
 
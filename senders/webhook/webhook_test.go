@@ -95,6 +95,29 @@ func TestSender_Init(t *testing.T) {
 				log: logger,
 			})
 		})
+
+		Convey("With plots in body template", func() {
+			settings := map[string]interface{}{
+				"url":  testURL,
+				"body": `{"plots": {{ json .Plots }}}`,
+			}
+			sender := Sender{}
+
+			err := sender.Init(settings, logger, location, dateTimeFormat)
+			So(err, ShouldBeNil)
+			So(sender.bodyUsesPlots, ShouldBeTrue)
+		})
+
+		Convey("With invalid body template", func() {
+			settings := map[string]interface{}{
+				"url":  testURL,
+				"body": `{"plots": {{ json .Plots }`,
+			}
+			sender := Sender{}
+
+			err := sender.Init(settings, logger, location, dateTimeFormat)
+			So(err, ShouldNotBeNil)
+		})
 	})
 }
 
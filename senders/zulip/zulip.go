@@ -0,0 +1,241 @@
+package zulip
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/senders"
+)
+
+const (
+	messagesPath         = "/api/v1/messages"
+	messageMaxCharacters = 10_000
+	topicMaxCharacters   = 60
+	topicSeparator       = ">"
+	quotes               = "```"
+	throttleMsg          = "\nPlease, **fix your system or tune this trigger** to generate less events."
+)
+
+// Zulip markdown has no colours, so state colour is shown with emoji.
+var stateEmoji = map[moira.State]string{
+	moira.StateOK:        ":green_circle:",
+	moira.StateWARN:      ":orange_circle:",
+	moira.StateERROR:     ":red_circle:",
+	moira.StateNODATA:    ":black_circle:",
+	moira.StateEXCEPTION: ":red_circle:",
+	moira.StateTEST:      ":blue_circle:",
+}
+
+// Structure that represents the Zulip configuration in the YAML file.
+type config struct {
+	URL             string `mapstructure:"url"`
+	BotEmail        string `mapstructure:"bot_email"`
+	APIKey          string `mapstructure:"api_key"`
+	FrontURI        string `mapstructure:"front_uri"`
+	MessageTemplate string `mapstructure:"message_template"`
+}
+
+// Sender posts messages to Zulip streams on behalf of incoming webhook bot.
+// Contact value is stream name, optionally followed by topic: "stream>topic".
+// If topic is not set, trigger name is used, so every trigger gets its own topic.
+type Sender struct {
+	url             string
+	botEmail        string
+	apiKey          string
+	frontURI        string
+	messageTemplate string
+	logger          moira.Logger
+	location        *time.Location
+	client          *http.Client
+}
+
+type response struct {
+	Result string `json:"result"`
+	Msg    string `json:"msg"`
+	Code   string `json:"code"`
+}
+
+// Init initialises settings required for full functionality.
+func (sender *Sender) Init(senderSettings interface{}, logger moira.Logger, location *time.Location, dateTimeFormat string) error {
+	var cfg config
+	err := mapstructure.Decode(senderSettings, &cfg)
+	if err != nil {
+		return fmt.Errorf("failed to decode senderSettings to zulip config: %w", err)
+	}
+
+	if cfg.URL == "" {
+		return fmt.Errorf("can not read zulip url from config")
+	}
+	if cfg.BotEmail == "" || cfg.APIKey == "" {
+		return fmt.Errorf("can not read zulip bot_email and api_key from config")
+	}
+	if err = senders.ValidateMessageTemplate("zulip", cfg.MessageTemplate); err != nil {
+		return err
+	}
+
+	sender.url = strings.TrimSuffix(cfg.URL, "/")
+	sender.botEmail = cfg.BotEmail
+	sender.apiKey = cfg.APIKey
+	sender.frontURI = cfg.FrontURI
+	sender.messageTemplate = cfg.MessageTemplate
+	sender.logger = logger
+	sender.location = location
+	sender.client = &http.Client{
+		Timeout: time.Duration(30) * time.Second, //nolint
+	}
+	return nil
+}
+
+// SendEvents implements Sender interface Send.
+func (sender *Sender) SendEvents(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData, plots [][]byte, throttled bool) error {
+	stream, topic := parseContact(contact.Value, trigger)
+	if stream == "" {
+		return moira.NewSenderBrokenContactError(fmt.Errorf("zulip stream is empty in contact %s", contact.Value))
+	}
+
+	form := url.Values{
+		"type":    {"stream"},
+		"to":      {stream},
+		"topic":   {topic},
+		"content": {sender.buildMessage(events, contact, trigger, throttled)},
+	}
+	request, err := http.NewRequestWithContext(context.Background(), http.MethodPost, sender.url+messagesPath, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	request.SetBasicAuth(sender.botEmail, sender.apiKey)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("User-Agent", "Moira")
+
+	resp, err := sender.client.Do(request)
+	if err != nil {
+		return fmt.Errorf("failed to perform request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	var zulipResponse response
+	if json.Unmarshal(body, &zulipResponse) == nil && zulipResponse.Code == "STREAM_DOES_NOT_EXIST" {
+		return moira.NewSenderBrokenContactError(fmt.Errorf("zulip stream %s does not exist", stream))
+	}
+	return fmt.Errorf("failed to send %s event message to zulip stream %s: status %d, %s", trigger.ID, stream, resp.StatusCode, string(body))
+}
+
+// parseContact splits contact value to stream and topic.
+func parseContact(value string, trigger moira.TriggerData) (string, string) {
+	stream, topic, _ := strings.Cut(value, topicSeparator)
+	stream, topic = strings.TrimSpace(stream), strings.TrimSpace(topic)
+	if topic == "" {
+		topic = trigger.Name
+	}
+	if topic == "" {
+		topic = trigger.ID
+	}
+	if runes := []rune(topic); len(runes) > topicMaxCharacters {
+		topic = string(runes[:topicMaxCharacters-3]) + "..."
+	}
+	return stream, topic
+}
+
+func (sender *Sender) buildMessage(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData, throttled bool) string {
+	if message, ok := senders.PopulateMessageTemplate(sender.messageTemplate, events, contact, trigger, throttled, sender.frontURI, sender.logger); ok {
+		return senders.TruncateMessage(message, messageMaxCharacters)
+	}
+
+	var message strings.Builder
+
+	title := sender.buildTitle(events, trigger, throttled)
+	titleLen := len([]rune(title))
+
+	desc := trigger.Desc
+	if desc != "" {
+		desc += "\n"
+	}
+	descLen := len([]rune(desc))
+
+	eventsString := sender.buildEventsString(events, -1, throttled)
+	eventsStringLen := len([]rune(eventsString))
+
+	charsLeftAfterTitle := messageMaxCharacters - titleLen
+
+	descNewLen, eventsNewLen := senders.CalculateMessagePartsLength(charsLeftAfterTitle, descLen, eventsStringLen)
+
+	if descLen != descNewLen {
+		desc = string([]rune(desc)[:descNewLen]) + "...\n"
+	}
+	if eventsNewLen != eventsStringLen {
+		eventsString = sender.buildEventsString(events, eventsNewLen, throttled)
+	}
+
+	message.WriteString(title)
+	message.WriteString(desc)
+	message.WriteString(eventsString)
+	return message.String()
+}
+
+func (sender *Sender) buildTitle(events moira.NotificationEvents, trigger moira.TriggerData, throttled bool) string {
+	state := events.GetCurrentState(throttled)
+	title := fmt.Sprintf("**%s**", state)
+	if emoji, ok := stateEmoji[state]; ok {
+		title = emoji + " " + title
+	}
+
+	triggerURI := trigger.GetTriggerURI(sender.frontURI)
+	if triggerURI != "" {
+		title += fmt.Sprintf(" [%s](%s)", trigger.Name, triggerURI)
+	} else if trigger.Name != "" {
+		title += " " + trigger.Name
+	}
+
+	tags := trigger.GetTags()
+	if tags != "" {
+		title += " " + tags
+	}
+
+	title += "\n"
+	return title
+}
+
+// buildEventsString builds the string from moira events and limits it to charsForEvents.
+// If charsForEvents is negative buildEventsString does not limit the events string.
+func (sender *Sender) buildEventsString(events moira.NotificationEvents, charsForEvents int, throttled bool) string {
+	if charsForEvents >= 0 {
+		// Events are quoted and tail line is put on the new line
+		charsForEvents -= len([]rune(quotes + "\n" + quotes + "\n"))
+		if throttled {
+			charsForEvents -= len([]rune(throttleMsg))
+		}
+		charsForEvents = max(charsForEvents, 0)
+	}
+	lines, leftOut := senders.LimitEventLines(events, sender.location, 0, charsForEvents)
+
+	var eventsString strings.Builder
+	eventsString.WriteString(quotes)
+	for _, line := range lines {
+		eventsString.WriteString("\n" + line)
+	}
+	eventsString.WriteString("\n" + quotes)
+
+	if leftOut > 0 {
+		eventsString.WriteString("\n" + senders.FormatMoreEvents(leftOut))
+	}
+	if throttled {
+		eventsString.WriteString(throttleMsg)
+	}
+	return eventsString.String()
+}
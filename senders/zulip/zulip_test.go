@@ -0,0 +1,148 @@
+package zulip
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/moira-alert/moira"
+	logging "github.com/moira-alert/moira/logging/zerolog_adapter"
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestInit(t *testing.T) {
+	logger, _ := logging.ConfigureLog("stdout", "debug", "test", true)
+	Convey("Init tests", t, func() {
+		sender := Sender{}
+		Convey("Empty url", func() {
+			err := sender.Init(map[string]interface{}{"bot_email": "bot@zulip.example.com", "api_key": "key"}, logger, nil, "")
+			So(err, ShouldResemble, errors.New("can not read zulip url from config"))
+		})
+		Convey("Empty credentials", func() {
+			err := sender.Init(map[string]interface{}{"url": "https://zulip.example.com"}, logger, nil, "")
+			So(err, ShouldResemble, errors.New("can not read zulip bot_email and api_key from config"))
+		})
+		Convey("Full config", func() {
+			err := sender.Init(map[string]interface{}{
+				"url":       "https://zulip.example.com/",
+				"bot_email": "bot@zulip.example.com",
+				"api_key":   "key",
+				"front_uri": "http://moira.url",
+			}, logger, nil, "")
+			So(err, ShouldBeNil)
+			So(sender.url, ShouldEqual, "https://zulip.example.com")
+			So(sender.botEmail, ShouldEqual, "bot@zulip.example.com")
+			So(sender.apiKey, ShouldEqual, "key")
+			So(sender.frontURI, ShouldEqual, "http://moira.url")
+		})
+	})
+}
+
+func TestSendEvents(t *testing.T) {
+	logger, _ := logging.ConfigureLog("stdout", "info", "test", true)
+	sender := Sender{}
+	_ = sender.Init(map[string]interface{}{
+		"url":       "https://zulip.example.com",
+		"bot_email": "bot@zulip.example.com",
+		"api_key":   "key",
+		"front_uri": "http://moira.url",
+	}, logger, time.UTC, "")
+	events := moira.NotificationEvents{{Metric: "Metric", Values: map[string]float64{"t1": 1}, Timestamp: 150000000, OldState: moira.StateOK, State: moira.StateERROR}}
+	trigger := moira.TriggerData{ID: "TriggerID", Name: "Name"}
+
+	Convey("Send events to zulip", t, func() {
+		Convey("Successful response", func() {
+			defer gock.Off()
+			gock.New("https://zulip.example.com").
+				Post(messagesPath).
+				MatchHeader("Authorization", "^Basic ").
+				BodyString(`to=alerts&topic=databases&type=stream`).
+				Reply(http.StatusOK).
+				BodyString(`{"result":"success","msg":"","id":42}`)
+			err := sender.SendEvents(events, moira.ContactData{Value: "alerts>databases"}, trigger, nil, false)
+			So(err, ShouldBeNil)
+			So(gock.IsDone(), ShouldBeTrue)
+		})
+
+		Convey("Stream does not exist", func() {
+			defer gock.Off()
+			gock.New("https://zulip.example.com").
+				Post(messagesPath).
+				Reply(http.StatusBadRequest).
+				BodyString(`{"result":"error","msg":"Stream 'alerts' does not exist","code":"STREAM_DOES_NOT_EXIST"}`)
+			err := sender.SendEvents(events, moira.ContactData{Value: "alerts"}, trigger, nil, false)
+			var brokenContactErr moira.SenderBrokenContactError
+			So(errors.As(err, &brokenContactErr), ShouldBeTrue)
+		})
+
+		Convey("Error response", func() {
+			defer gock.Off()
+			gock.New("https://zulip.example.com").
+				Post(messagesPath).
+				Reply(http.StatusInternalServerError).
+				BodyString("error")
+			err := sender.SendEvents(events, moira.ContactData{Value: "alerts"}, trigger, nil, false)
+			So(err, ShouldResemble, errors.New("failed to send TriggerID event message to zulip stream alerts: status 500, error"))
+		})
+
+		Convey("Empty stream", func() {
+			err := sender.SendEvents(events, moira.ContactData{Value: ">topic"}, trigger, nil, false)
+			var brokenContactErr moira.SenderBrokenContactError
+			So(errors.As(err, &brokenContactErr), ShouldBeTrue)
+		})
+	})
+}
+
+func TestParseContact(t *testing.T) {
+	trigger := moira.TriggerData{ID: "TriggerID", Name: "Name"}
+	Convey("Parse contact", t, func() {
+		Convey("Stream only", func() {
+			stream, topic := parseContact("alerts", trigger)
+			So(stream, ShouldEqual, "alerts")
+			So(topic, ShouldEqual, "Name")
+		})
+		Convey("Stream and topic", func() {
+			stream, topic := parseContact("alerts > databases", trigger)
+			So(stream, ShouldEqual, "alerts")
+			So(topic, ShouldEqual, "databases")
+		})
+		Convey("Trigger without name", func() {
+			_, topic := parseContact("alerts", moira.TriggerData{ID: "TriggerID"})
+			So(topic, ShouldEqual, "TriggerID")
+		})
+	})
+}
+
+func TestBuildMessage(t *testing.T) {
+	sender := Sender{location: time.UTC, frontURI: "http://moira.url"}
+	event := moira.NotificationEvent{Metric: "Metric", Values: map[string]float64{"t1": 1}, Timestamp: 150000000, OldState: moira.StateOK, State: moira.StateWARN}
+	trigger := moira.TriggerData{
+		ID:   "TriggerID",
+		Name: "Name",
+		Tags: []string{"tag1"},
+		Desc: "some **bold** text",
+	}
+
+	Convey("Build message", t, func() {
+		Convey("With description and trigger link", func() {
+			message := sender.buildMessage(moira.NotificationEvents{event}, moira.ContactData{}, trigger, false)
+			So(message, ShouldEqual, ":orange_circle: **WARN** [Name](http://moira.url/trigger/TriggerID) [tag1]\n"+
+				"some **bold** text\n"+
+				"```\n02:40 (GMT+00:00): Metric = 1 (OK to WARN)\n```")
+		})
+
+		Convey("Throttled", func() {
+			message := sender.buildMessage(moira.NotificationEvents{event}, moira.ContactData{}, moira.TriggerData{Name: "Name"}, true)
+			So(message, ShouldEqual, ":orange_circle: **WARN** Name\n"+
+				"```\n02:40 (GMT+00:00): Metric = 1 (OK to WARN)\n```"+throttleMsg)
+		})
+
+		Convey("With contact template", func() {
+			contact := moira.ContactData{Template: "**{{ .Trigger.Name }}** {{ range .Events }}{{ .Metric }} {{ .State }}{{ end }}"}
+			message := sender.buildMessage(moira.NotificationEvents{event}, contact, trigger, false)
+			So(message, ShouldEqual, "**Name** Metric WARN")
+		})
+	})
+}
@@ -0,0 +1,75 @@
+package templating
+
+import (
+	"html"
+)
+
+// MessageTrigger represents a template trigger with fields allowed for use in message templates.
+type MessageTrigger struct {
+	ID   string
+	Name string
+	Desc string
+	Tags []string
+	URI  string
+}
+
+type messagePopulater struct {
+	Trigger   *MessageTrigger
+	Events    []Event
+	Contact   *Contact
+	State     string
+	Throttled bool
+	FrontURI  string
+}
+
+// NewMessagePopulater creates a new notification message populater with provided trigger, events and contact.
+// State is the current trigger state shown in notification, frontURI is the Moira web UI address.
+func NewMessagePopulater(trigger *MessageTrigger, events []Event, contact *Contact, state string, throttled bool, frontURI string) *messagePopulater {
+	return &messagePopulater{
+		Trigger:   trigger,
+		Events:    events,
+		Contact:   contact,
+		State:     state,
+		Throttled: throttled,
+		FrontURI:  frontURI,
+	}
+}
+
+// Populate populates the given template with notification data.
+// Message is plain text or markup of the sender, so html escaping is reverted.
+func (templateData *messagePopulater) Populate(tmpl string) (string, error) {
+	message, err := populate(tmpl, templateData)
+	if err != nil {
+		return message, err
+	}
+	return html.UnescapeString(message), nil
+}
+
+// ValidateMessageTemplate checks that the message template can be populated with notification data.
+func ValidateMessageTemplate(tmpl string) error {
+	value := float64(1)
+	populater := NewMessagePopulater(
+		&MessageTrigger{
+			ID:   "trigger-id",
+			Name: "Trigger name",
+			Desc: "Trigger description",
+			Tags: []string{"tag"},
+			URI:  "https://moira.example.com/trigger/trigger-id",
+		},
+		[]Event{{
+			Metric:         "metric.name",
+			MetricElements: []string{"metric", "name"},
+			Timestamp:      1,
+			Value:          &value,
+			Values:         map[string]float64{"t1": value},
+			OldState:       "OK",
+			State:          "ERROR",
+		}},
+		&Contact{Type: "type", Value: "value"},
+		"ERROR",
+		false,
+		"https://moira.example.com",
+	)
+	_, err := populater.Populate(tmpl)
+	return err
+}
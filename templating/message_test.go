@@ -0,0 +1,75 @@
+package templating
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func Test_TemplateMessage(t *testing.T) {
+	Convey("Test message populater", t, func() {
+		value := float64(10)
+		trigger := &MessageTrigger{
+			ID:   "trigger-id",
+			Name: "Disk <free> space",
+			Tags: []string{"disk", "prod"},
+			URI:  "https://moira.example.com/trigger/trigger-id",
+		}
+		events := []Event{
+			{Metric: "host1.disk", Value: &value, OldState: "OK", State: "WARN"},
+			{Metric: "host2.disk", Values: map[string]float64{"t1": 1}, OldState: "WARN", State: "ERROR"},
+		}
+		contact := &Contact{Type: "slack", Value: "#alerts"}
+
+		Convey("Test full data", func() {
+			template := "" +
+				"*{{ .State }}* <{{ .Trigger.URI }}|{{ .Trigger.Name }}> {{ join \", \" .Trigger.Tags }}\n" +
+				"{{ range .Events }}{{ .Metric }}: {{ .OldState }} -> {{ .State }}\n{{ end }}" +
+				"{{ if .Throttled }}throttled{{ end }} {{ .Contact.Value }} {{ .FrontURI }}"
+			populater := NewMessagePopulater(trigger, events, contact, "ERROR", true, "https://moira.example.com")
+
+			actual, err := populater.Populate(template)
+			So(err, ShouldBeNil)
+			So(actual, ShouldEqual, "*ERROR* <https://moira.example.com/trigger/trigger-id|Disk <free> space> disk, prod\n"+
+				"host1.disk: OK -> WARN\nhost2.disk: WARN -> ERROR\n"+
+				"throttled #alerts https://moira.example.com")
+		})
+
+		Convey("Test event values", func() {
+			template := "{{ range .Events }}{{ with .Value }}{{ . }}{{ end }}{{ index .Values \"t1\" }};{{ end }}"
+			populater := NewMessagePopulater(trigger, events, contact, "ERROR", false, "")
+
+			actual, err := populater.Populate(template)
+			So(err, ShouldBeNil)
+			So(actual, ShouldEqual, "100;1;")
+		})
+
+		Convey("Test unknown field", func() {
+			template := "{{ .Trigger.Unknown }}"
+			populater := NewMessagePopulater(trigger, events, contact, "ERROR", false, "")
+
+			actual, err := populater.Populate(template)
+			So(err, ShouldNotBeNil)
+			So(actual, ShouldEqual, template)
+		})
+	})
+}
+
+func Test_ValidateMessageTemplate(t *testing.T) {
+	Convey("Test message template validation", t, func() {
+		Convey("Valid template", func() {
+			err := ValidateMessageTemplate("{{ .State }} {{ .Trigger.Name }}{{ range .Events }} {{ .Metric }}{{ end }}")
+			So(err, ShouldBeNil)
+		})
+
+		Convey("Template with syntax error", func() {
+			err := ValidateMessageTemplate("{{ .State ")
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Template with unknown field", func() {
+			err := ValidateMessageTemplate("{{ .Contact.Unknown }}")
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
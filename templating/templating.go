@@ -2,9 +2,13 @@ package templating
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"html/template"
+	"io"
+	"net/url"
 	"strings"
+	textTemplate "text/template"
 	"time"
 
 	"github.com/Masterminds/sprig/v3"
@@ -20,6 +24,29 @@ func formatDate(unixTime int64, format string) string {
 	return time.Unix(unixTime, 0).Format(format)
 }
 
+// formatTime formats unix time with the given layout in UTC or in the given time zone.
+func formatTime(unixTime int64, layout string, timeZone ...string) (string, error) {
+	location := time.UTC
+	if len(timeZone) > 0 {
+		var err error
+		if location, err = time.LoadLocation(timeZone[0]); err != nil {
+			return "", err
+		}
+	}
+	return time.Unix(unixTime, 0).In(location).Format(layout), nil
+}
+
+// toJSON encodes value to JSON, so strings are quoted and escaped and the result can be put into JSON payload as is.
+func toJSON(value any) (string, error) {
+	buffer := bytes.Buffer{}
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(buffer.String(), "\n"), nil
+}
+
 func filterKeys(source template.FuncMap, keys []string) template.FuncMap {
 	result := template.FuncMap{}
 	for _, key := range keys {
@@ -33,6 +60,11 @@ func filterKeys(source template.FuncMap, keys []string) template.FuncMap {
 var funcMap = template.FuncMap{
 	"date":              date,
 	"formatDate":        formatDate,
+	"formatTime":        formatTime,
+	"json":              toJSON,
+	"toUpper":           strings.ToUpper,
+	"toLower":           strings.ToLower,
+	"urlEncode":         url.QueryEscape,
 	"stringsReplace":    strings.Replace,
 	"stringsToLower":    strings.ToLower,
 	"stringsToUpper":    strings.ToUpper,
@@ -235,7 +267,26 @@ type Populater interface {
 	Populate(template string) (string, error)
 }
 
-func populate(tmpl string, data any) (populatedTemplate string, err error) {
+// executableTemplate is a parsed html or text template.
+type executableTemplate interface {
+	Execute(writer io.Writer, data any) error
+}
+
+// populate populates html template, so values are escaped for html.
+func populate(tmpl string, data any) (string, error) {
+	return populateTemplate(tmpl, data, func(tmpl string) (executableTemplate, error) {
+		return template.New("populate-template").Funcs(sprigFuncMap).Funcs(funcMap).Parse(tmpl)
+	})
+}
+
+// populateText populates text template, values are put as is, so json and urlEncode functions should be used to escape them.
+func populateText(tmpl string, data any) (string, error) {
+	return populateTemplate(tmpl, data, func(tmpl string) (executableTemplate, error) {
+		return textTemplate.New("populate-template").Funcs(sprigFuncMap).Funcs(funcMap).Parse(tmpl)
+	})
+}
+
+func populateTemplate(tmpl string, data any, parse func(tmpl string) (executableTemplate, error)) (populatedTemplate string, err error) {
 	defer func() {
 		if errRecover := recover(); errRecover != nil {
 			populatedTemplate = tmpl
@@ -245,9 +296,8 @@ func populate(tmpl string, data any) (populatedTemplate string, err error) {
 
 	buffer := bytes.Buffer{}
 
-	template := template.New("populate-template").Funcs(sprigFuncMap).Funcs(funcMap)
-
-	if template, err = template.Parse(tmpl); err != nil {
+	template, err := parse(tmpl)
+	if err != nil {
 		return tmpl, err
 	}
 
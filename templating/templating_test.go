@@ -1,6 +1,7 @@
 package templating
 
 import (
+	"html"
 	"testing"
 
 	"github.com/google/uuid"
@@ -73,6 +74,70 @@ func Test_populate(t *testing.T) {
 			})
 		})
 
+		Convey("Test payload functions", func() {
+			// html escaping of populated payload is reverted by webhook sender
+			Convey("Test json string", func() {
+				template := "{{ json \"say \\\"hi\\\" & <bye>\" }}"
+				expected := "\"say \\\"hi\\\" & <bye>\""
+
+				actual, err := populater.Populate(template)
+				So(err, ShouldBeNil)
+				So(html.UnescapeString(actual), ShouldEqual, expected)
+			})
+
+			Convey("Test json list", func() {
+				template := "{{ json (list \"a\" 1) }}"
+				expected := "[\"a\",1]"
+
+				actual, err := populater.Populate(template)
+				So(err, ShouldBeNil)
+				So(html.UnescapeString(actual), ShouldEqual, expected)
+			})
+
+			Convey("Test toUpper and toLower", func() {
+				template := "{{ toUpper \"warn\" }} {{ toLower \"ERROR\" }}"
+				expected := "WARN error"
+
+				actual, err := populater.Populate(template)
+				So(err, ShouldBeNil)
+				So(html.UnescapeString(actual), ShouldEqual, expected)
+			})
+
+			Convey("Test formatTime in UTC", func() {
+				template := "{{ formatTime 0 \"2006-01-02T15:04:05Z07:00\" }}"
+				expected := "1970-01-01T00:00:00Z"
+
+				actual, err := populater.Populate(template)
+				So(err, ShouldBeNil)
+				So(html.UnescapeString(actual), ShouldEqual, expected)
+			})
+
+			Convey("Test formatTime in time zone", func() {
+				template := "{{ formatTime 0 \"15:04\" \"Asia/Yekaterinburg\" }}"
+				expected := "05:00"
+
+				actual, err := populater.Populate(template)
+				So(err, ShouldBeNil)
+				So(html.UnescapeString(actual), ShouldEqual, expected)
+			})
+
+			Convey("Test formatTime with unknown time zone", func() {
+				template := "{{ formatTime 0 \"15:04\" \"Unknown/Zone\" }}"
+
+				_, err := populater.Populate(template)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Test urlEncode", func() {
+				template := "text={{ urlEncode \"a b&c\" }}"
+				expected := "text=a+b%26c"
+
+				actual, err := populater.Populate(template)
+				So(err, ShouldBeNil)
+				So(html.UnescapeString(actual), ShouldEqual, expected)
+			})
+		})
+
 		Convey("Test some sprig functions", func() {
 			Convey("Test upper", func() {
 				template := "{{ \"hello!\" | upper}} "
@@ -10,6 +10,8 @@ type Event struct {
 	MetricElements []string
 	Timestamp      int64
 	Value          *float64
+	Values         map[string]float64
+	OldState       string
 	State          string
 }
 
@@ -1,23 +1,114 @@
 package templating
 
+import (
+	textTemplate "text/template"
+	"text/template/parse"
+)
+
 // Contact represents a template contact with fields allowed for use in templates.
 type Contact struct {
 	Type  string
 	Value string
+	ID    string
+	User  string
+	Team  string
 }
 
 type webhookBodyPopulater struct {
-	Contact *Contact
+	Contact   *Contact
+	Trigger   *MessageTrigger
+	Events    []Event
+	State     string
+	Throttled bool
+	Plots     []string
 }
 
-// NewWebhookBodyPopulater creates a new webhook body populater with provided template contact.
-func NewWebhookBodyPopulater(contact *Contact) *webhookBodyPopulater {
+// NewWebhookBodyPopulater creates a new webhook body populater with provided template contact, trigger and events.
+// State is the current trigger state, plots are URLs of plots stored in image store.
+func NewWebhookBodyPopulater(contact *Contact, trigger *MessageTrigger, events []Event, state string, throttled bool, plots []string) *webhookBodyPopulater {
 	return &webhookBodyPopulater{
-		Contact: contact,
+		Contact:   contact,
+		Trigger:   trigger,
+		Events:    events,
+		State:     state,
+		Throttled: throttled,
+		Plots:     plots,
 	}
 }
 
-// Populate populates the given template with contact data.
+// Populate populates the given template with notification data.
+// Template is a text template, values are not html escaped, json and urlEncode functions should be used to escape them.
 func (templateData *webhookBodyPopulater) Populate(tmpl string) (string, error) {
-	return populate(tmpl, templateData)
+	return populateText(tmpl, templateData)
+}
+
+// WebhookBodyUsesPlots returns true if webhook body template uses plots field,
+// so plots should be uploaded to image store before populating the template.
+func WebhookBodyUsesPlots(tmpl string) (bool, error) {
+	parsed, err := textTemplate.New("populate-template").Funcs(sprigFuncMap).Funcs(funcMap).Parse(tmpl)
+	if err != nil {
+		return false, err
+	}
+	for _, template := range parsed.Templates() {
+		if template.Tree != nil && usesField(template.Tree.Root, "Plots") {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// usesField walks template tree and returns true if any field, variable field or chain node refers to the field.
+func usesField(node parse.Node, field string) bool {
+	switch node := node.(type) {
+	case *parse.ListNode:
+		if node == nil {
+			return false
+		}
+		for _, child := range node.Nodes {
+			if usesField(child, field) {
+				return true
+			}
+		}
+	case *parse.ActionNode:
+		return usesField(node.Pipe, field)
+	case *parse.PipeNode:
+		if node == nil {
+			return false
+		}
+		for _, command := range node.Cmds {
+			if usesField(command, field) {
+				return true
+			}
+		}
+	case *parse.CommandNode:
+		for _, arg := range node.Args {
+			if usesField(arg, field) {
+				return true
+			}
+		}
+	case *parse.IfNode:
+		return usesField(node.Pipe, field) || usesField(node.List, field) || usesField(node.ElseList, field)
+	case *parse.RangeNode:
+		return usesField(node.Pipe, field) || usesField(node.List, field) || usesField(node.ElseList, field)
+	case *parse.WithNode:
+		return usesField(node.Pipe, field) || usesField(node.List, field) || usesField(node.ElseList, field)
+	case *parse.TemplateNode:
+		return usesField(node.Pipe, field)
+	case *parse.FieldNode:
+		return containsIdent(node.Ident, field)
+	case *parse.VariableNode:
+		return containsIdent(node.Ident, field)
+	case *parse.ChainNode:
+		return containsIdent(node.Field, field) || usesField(node.Node, field)
+	}
+	return false
+}
+
+func containsIdent(idents []string, field string) bool {
+	for _, ident := range idents {
+		if ident == field {
+			return true
+		}
+	}
+	return false
 }
@@ -13,7 +13,7 @@ func Test_TemplateWebhookBody(t *testing.T) {
 			"Contact Value: {{ .Contact.Value }}"
 
 		Convey("Test with nil data", func() {
-			webhookPopulater := NewWebhookBodyPopulater(nil)
+			webhookPopulater := NewWebhookBodyPopulater(nil, nil, nil, "", false, nil)
 
 			actual, err := webhookPopulater.Populate(template)
 			So(err, ShouldNotBeNil)
@@ -21,7 +21,7 @@ func Test_TemplateWebhookBody(t *testing.T) {
 		})
 
 		Convey("Test with empty data", func() {
-			webhookPopulater := NewWebhookBodyPopulater(&Contact{})
+			webhookPopulater := NewWebhookBodyPopulater(&Contact{}, nil, nil, "", false, nil)
 			expected := "" +
 				"Contact Type: \n" +
 				"Contact Value:"
@@ -34,7 +34,7 @@ func Test_TemplateWebhookBody(t *testing.T) {
 		Convey("Test with empty value", func() {
 			webhookPopulater := NewWebhookBodyPopulater(&Contact{
 				Type: "slack",
-			})
+			}, nil, nil, "", false, nil)
 			expected := "" +
 				"Contact Type: slack\n" +
 				"Contact Value:"
@@ -47,7 +47,7 @@ func Test_TemplateWebhookBody(t *testing.T) {
 		Convey("Test with empty type", func() {
 			webhookPopulater := NewWebhookBodyPopulater(&Contact{
 				Value: "#test_channel",
-			})
+			}, nil, nil, "", false, nil)
 			expected := "" +
 				"Contact Type: \n" +
 				"Contact Value: #test_channel"
@@ -61,7 +61,7 @@ func Test_TemplateWebhookBody(t *testing.T) {
 			webhookPopulater := NewWebhookBodyPopulater(&Contact{
 				Type:  "slack",
 				Value: "#test_channel",
-			})
+			}, nil, nil, "", false, nil)
 			expected := "" +
 				"Contact Type: slack\n" +
 				"Contact Value: #test_channel"
@@ -70,5 +70,50 @@ func Test_TemplateWebhookBody(t *testing.T) {
 			So(err, ShouldBeNil)
 			So(actual, ShouldResemble, expected)
 		})
+
+		Convey("Test with trigger, events and plots", func() {
+			webhookPopulater := NewWebhookBodyPopulater(
+				&Contact{Type: "slack", Value: "#test_channel", ID: "contact-id"},
+				&MessageTrigger{ID: "trigger-id", Name: "Trigger", Tags: []string{"tag1", "tag2"}, URI: "https://moira.url/trigger/trigger-id"},
+				[]Event{{Metric: "metric.name", Values: map[string]float64{"t1": 1.5}, OldState: "OK", State: "WARN"}},
+				"WARN",
+				true,
+				[]string{"https://images.url/plot.png"},
+			)
+			template := "" +
+				"{{ .Contact.ID }} {{ .State }} {{ .Trigger.URI }} {{ join \",\" .Trigger.Tags }}\n" +
+				"{{ range .Events }}{{ .Metric }} {{ index .Values \"t1\" }} {{ .OldState }}->{{ .State }}{{ end }}\n" +
+				"{{ .Throttled }} {{ index .Plots 0 }}"
+			expected := "" +
+				"contact-id WARN https://moira.url/trigger/trigger-id tag1,tag2\n" +
+				"metric.name 1.5 OK->WARN\n" +
+				"true https://images.url/plot.png"
+
+			actual, err := webhookPopulater.Populate(template)
+			So(err, ShouldBeNil)
+			So(actual, ShouldResemble, expected)
+		})
+	})
+}
+
+func Test_WebhookBodyUsesPlots(t *testing.T) {
+	Convey("Test webhook body uses plots", t, func() {
+		for tmpl, expected := range map[string]bool{
+			`{"contact": {{ json .Contact.Value }}}`:                         false,
+			`{"text": ".Plots are not used"}`:                                false,
+			`{"plots": {{ json .Plots }}}`:                                   true,
+			`{{ range .Plots }}{{ . }}{{ end }}`:                             true,
+			`{{ with .Events }}{{ range $.Plots }}{{ . }}{{ end }}{{ end }}`: true,
+			`{{ if .Throttled }}{{ index .Plots 0 }}{{ end }}`:               true,
+		} {
+			actual, err := WebhookBodyUsesPlots(tmpl)
+			So(err, ShouldBeNil)
+			So(actual, ShouldEqual, expected)
+		}
+	})
+
+	Convey("Test invalid webhook body", t, func() {
+		_, err := WebhookBodyUsesPlots(`{{ .Plots `)
+		So(err, ShouldNotBeNil)
 	})
 }